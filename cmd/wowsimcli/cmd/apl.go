@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+var aplWrite bool
+
+var aplCmd = &cobra.Command{
+	Use:   "apl",
+	Short: "work with APL rotations",
+	Long:  "work with APL rotations in the text format, see core.APLRotationFromText",
+}
+
+var aplFmtCmd = &cobra.Command{
+	Use:   "fmt [file]...",
+	Short: "format text APL files",
+	Long:  "formats text APL files, printing the result unless --write is set. JSON rotations are rejected, use convert for those",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		// Checked up front, so --write doesn't leave some files formatted and
+		// never turns a JSON preset into text the UI can't load.
+		for _, file := range args {
+			if isJSONAPL(file) {
+				return fmt.Errorf("%q is a JSON rotation, use apl convert to turn it into text", file)
+			}
+		}
+
+		for _, file := range args {
+			rot, err := loadAPL(file)
+			if err != nil {
+				return err
+			}
+			formatted := core.APLRotationToText(rot)
+			if !aplWrite {
+				fmt.Print(formatted)
+				continue
+			}
+			if err := os.WriteFile(file, []byte(formatted), 0666); err != nil {
+				return fmt.Errorf("failed to write %q: %w", file, err)
+			}
+		}
+		return nil
+	},
+}
+
+var aplConvertCmd = &cobra.Command{
+	Use:   "convert [file]",
+	Short: "convert APL rotations between JSON and text",
+	Long:  "converts an APL rotation in JSON (e.g. a .apl.json preset) to text, or a rotation in text to JSON",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		rot, err := loadAPL(args[0])
+		if err != nil {
+			return err
+		}
+
+		var output []byte
+		if isJSONAPL(args[0]) {
+			output = []byte(core.APLRotationToText(rot))
+		} else {
+			data, err := protojson.Marshal(rot)
+			if err != nil {
+				return fmt.Errorf("failed to marshal rotation: %w", err)
+			}
+			// Re-indent, since protojson output is deliberately unstable.
+			var indented bytes.Buffer
+			if err := json.Indent(&indented, data, "", "  "); err != nil {
+				return fmt.Errorf("failed to format rotation: %w", err)
+			}
+			output = append(indented.Bytes(), '\n')
+		}
+
+		if outfile == "" {
+			fmt.Print(string(output))
+			return nil
+		}
+		if err := os.WriteFile(outfile, output, 0666); err != nil {
+			return fmt.Errorf("failed to write %q: %w", outfile, err)
+		}
+		return nil
+	},
+}
+
+func init() {
+	aplFmtCmd.Flags().BoolVarP(&aplWrite, "write", "w", false, "write the result to the input files instead of stdout")
+	aplConvertCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+
+	aplCmd.AddCommand(aplFmtCmd)
+	aplCmd.AddCommand(aplConvertCmd)
+}
+
+// JSON rotations are told apart by their extension or leading brace.
+func isJSONAPL(file string) bool {
+	if strings.HasSuffix(file, ".json") {
+		return true
+	}
+	data, err := os.ReadFile(file)
+	return err == nil && bytes.HasPrefix(bytes.TrimSpace(data), []byte("{"))
+}
+
+func loadAPL(file string) (*proto.APLRotation, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load %q: %w", file, err)
+	}
+
+	rot := &proto.APLRotation{}
+	if isJSONAPL(file) {
+		err = protojson.Unmarshal(data, rot)
+	} else {
+		rot, err = core.APLRotationFromText(string(data))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", file, err)
+	}
+	return rot, nil
+}
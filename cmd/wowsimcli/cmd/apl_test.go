@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestAPLFmtRejectsJSON(t *testing.T) {
+	dir := t.TempDir()
+	jsonFile := filepath.Join(dir, "default.apl.json")
+	textFile := filepath.Join(dir, "default.apl")
+	jsonData := []byte(`{"type":"TypeAPL","priorityList":[]}`)
+	textData := []byte("actions {\n}\n")
+	for file, data := range map[string][]byte{jsonFile: jsonData, textFile: textData} {
+		if err := os.WriteFile(file, data, 0666); err != nil {
+			t.Fatalf("Failed to write %s: %s", file, err.Error())
+		}
+	}
+
+	aplCmd.SetArgs([]string{"fmt", "--write", textFile, jsonFile})
+	if err := aplCmd.Execute(); err == nil {
+		t.Fatalf("Expected apl fmt --write to reject a JSON rotation")
+	}
+
+	for file, data := range map[string][]byte{jsonFile: jsonData, textFile: textData} {
+		written, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %s", file, err.Error())
+		}
+		if string(written) != string(data) {
+			t.Fatalf("Expected %s to be left untouched, got %q", file, written)
+		}
+	}
+}
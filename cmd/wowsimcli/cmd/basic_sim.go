@@ -18,27 +18,20 @@ var simCmd = &cobra.Command{
 }
 
 func init() {
-	simCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
+	addSimInputFlags(simCmd)
 	simCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
 	simCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
-	simCmd.MarkFlagRequired("infile")
 }
 
 func simMain(cmd *cobra.Command, args []string) {
-	data, err := os.ReadFile(infile)
+	input, err := loadSimInputFromFlags()
 	if err != nil {
-		log.Fatalf("failed to load input json file %q: %v", infile, err)
-	}
-	input := &proto.RaidSimRequest{}
-
-	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
-	if err != nil {
-		log.Fatalf("failed to load input json file: %s", err)
+		log.Fatal(err)
 	}
 
 	var output []byte
 	reporter := make(chan *proto.ProgressMetrics, 10)
-	core.RunRaidSimConcurrentAsync(input, reporter, "cmd-raid-sim")
+	core.RunRaidSimConcurrentAsync(input.request, reporter, "cmd-raid-sim")
 
 	var finalResult *proto.RaidSimResult
 	for v := range reporter {
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"path/filepath"
 	"strconv"
 	"time"
 
@@ -19,6 +20,7 @@ var (
 	replacefile string
 	outfile     string
 	verbose     bool
+	talentTrees string
 )
 
 var bulkCmd = &cobra.Command{
@@ -29,27 +31,21 @@ var bulkCmd = &cobra.Command{
 }
 
 func init() {
-	bulkCmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format)")
+	addSimInputFlags(bulkCmd)
 	bulkCmd.Flags().StringVar(&replacefile, "replacefile", "", "location of replacement items file. Writes a CSV result of the items replaced instead of JSON")
 	bulkCmd.Flags().StringVar(&outfile, "output", "", "location of output file, defaults to stdout")
 	bulkCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
-	bulkCmd.MarkFlagRequired("infile")
+	bulkCmd.Flags().StringVar(&talentTrees, "talent-trees", "ui/core/talents/trees", "location of the UI's talent tree configs, used by talent searches which don't include the trees")
 	bulkCmd.MarkFlagRequired("replacefile")
 }
 
 func bulkSimMain(cmd *cobra.Command, args []string) {
-	data, err := os.ReadFile(infile)
+	input, err := loadSimInputFromFlags()
 	if err != nil {
-		log.Fatalf("failed to load input json file %q: %v", infile, err)
+		log.Fatal(err)
 	}
-	input := &proto.RaidSimRequest{}
 
-	err = protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, input)
-	if err != nil {
-		log.Fatalf("failed to load input json file: %s", err)
-	}
-
-	output := BulkSim(input, replacefile, verbose)
+	output := BulkSim(input.request, replacefile, talentTrees, verbose)
 
 	if outfile == "" {
 		print(string(output))
@@ -68,6 +64,8 @@ type ItemReplacementInput struct {
 	Combinations bool              `json:"combinations"`
 	FastMode     bool              `json:"fast_mode"`
 	Items        []*proto.ItemSpec // spec for replacement
+	// TalentSearchSettings in proto JSON, to also search talents and glyphs.
+	TalentSearch json.RawMessage `json:"talent_search"`
 }
 
 type ReplaceIter struct {
@@ -75,7 +73,7 @@ type ReplaceIter struct {
 	Slots []proto.ItemSlot // Slots for each sub item
 }
 
-func BulkSim(input *proto.RaidSimRequest, replaceFile string, verbose bool) string {
+func BulkSim(input *proto.RaidSimRequest, replaceFile string, talentTreesDir string, verbose bool) string {
 	// 1. Load up all the sim data we need
 	replaceData, err := os.ReadFile(replaceFile)
 	if err != nil {
@@ -96,6 +94,14 @@ func BulkSim(input *proto.RaidSimRequest, replaceFile string, verbose bool) stri
 			FastMode:           replaceInput.FastMode,
 		},
 	}
+	if len(replaceInput.TalentSearch) > 0 {
+		player := input.GetRaid().GetParties()[0].GetPlayers()[0]
+		talentSearch, err := loadTalentSearch(replaceInput.TalentSearch, talentTreesDir, player.GetClass())
+		if err != nil {
+			log.Fatalf("failed to load talent search: %s", err)
+		}
+		bsr.BulkSettings.TalentSearch = talentSearch
+	}
 	progress := make(chan *proto.ProgressMetrics, 100)
 	core.RunBulkSimAsync(bsr, progress, "cmd-bulk-sim")
 
@@ -151,11 +157,53 @@ func BulkSim(input *proto.RaidSimRequest, replaceFile string, verbose bool) stri
 	}
 }
 
+var talentTreeFiles = map[proto.Class]string{
+	proto.Class_ClassDeathKnight: "death_knight.json",
+	proto.Class_ClassDruid:       "druid.json",
+	proto.Class_ClassHunter:      "hunter.json",
+	proto.Class_ClassMage:        "mage.json",
+	proto.Class_ClassPaladin:     "paladin.json",
+	proto.Class_ClassPriest:      "priest.json",
+	proto.Class_ClassRogue:       "rogue.json",
+	proto.Class_ClassShaman:      "shaman.json",
+	proto.Class_ClassWarlock:     "warlock.json",
+	proto.Class_ClassWarrior:     "warrior.json",
+}
+
+// Reads talent search settings, taking the talent trees of the class from the
+// UI's talent configs if they aren't part of the settings.
+func loadTalentSearch(settingsJson []byte, talentTreesDir string, class proto.Class) (*proto.TalentSearchSettings, error) {
+	settings := &proto.TalentSearchSettings{}
+	if err := protojson.Unmarshal(settingsJson, settings); err != nil {
+		return nil, err
+	}
+	if len(settings.Trees) > 0 {
+		return settings, nil
+	}
+
+	fileName, ok := talentTreeFiles[class]
+	if !ok {
+		return nil, fmt.Errorf("no talent trees for class %s", class)
+	}
+	treesFile := filepath.Join(talentTreesDir, fileName)
+	treesJson, err := os.ReadFile(treesFile)
+	if err != nil {
+		return nil, err
+	}
+	trees := &proto.TalentSearchSettings{}
+	opts := protojson.UnmarshalOptions{DiscardUnknown: true}
+	if err := opts.Unmarshal(fmt.Appendf(nil, `{"trees": %s}`, treesJson), trees); err != nil {
+		return nil, fmt.Errorf("failed to parse talent trees %s: %w", treesFile, err)
+	}
+	settings.Trees = trees.Trees
+	return settings, nil
+}
+
 func printCombos(results *proto.BulkSimResult) string {
 	result := ""
 	foundBase := false
 	for i := 0; i < len(results.Results); i++ {
-		if len(results.Results[i].ItemsAdded) == 0 {
+		if len(results.Results[i].ItemsAdded) == 0 && results.Results[i].TalentLoadout == nil {
 			foundBase = true
 		}
 		result += printCombo(results.Results[i])
@@ -168,7 +216,7 @@ func printCombos(results *proto.BulkSimResult) string {
 
 func printCombo(combo *proto.BulkComboResult) string {
 	itemtext := "["
-	if len(combo.ItemsAdded) == 0 {
+	if len(combo.ItemsAdded) == 0 && combo.TalentLoadout == nil {
 		itemtext += "BASE RESULT"
 	}
 	for j, item := range combo.ItemsAdded {
@@ -177,6 +225,14 @@ func printCombo(combo *proto.BulkComboResult) string {
 		}
 		itemtext += fmt.Sprintf("%s@%s", core.ItemsByID[item.Item.Id].Name, item.Slot.String())
 	}
+	if loadout := combo.TalentLoadout; loadout != nil {
+		if len(combo.ItemsAdded) != 0 {
+			itemtext += ";"
+		}
+		g := loadout.Glyphs
+		itemtext += fmt.Sprintf("%s %d/%d/%d %d/%d/%d %d/%d/%d", loadout.TalentsString,
+			g.GetPrime1(), g.GetPrime2(), g.GetPrime3(), g.GetMajor1(), g.GetMajor2(), g.GetMajor3(), g.GetMinor1(), g.GetMinor2(), g.GetMinor3())
+	}
 	itemtext += "]"
 	return fmt.Sprintf("%s,%0.1f\n", itemtext, combo.UnitMetrics.Dps.Avg)
 }
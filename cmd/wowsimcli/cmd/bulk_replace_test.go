@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+const testTalentTreesDir = "../../../ui/core/talents/trees"
+
+func TestLoadTalentSearchClassTrees(t *testing.T) {
+	for class := range talentTreeFiles {
+		settings, err := loadTalentSearch([]byte(`{"treeIndex": 1, "flexTalents": ["someTalent"]}`), testTalentTreesDir, class)
+		if err != nil {
+			t.Fatalf("Failed to load talent trees of %s: %v", class, err)
+		}
+		if len(settings.Trees) != 3 {
+			t.Fatalf("Expected 3 talent trees for %s, got %d", class, len(settings.Trees))
+		}
+		for _, tree := range settings.Trees {
+			if len(tree.Talents) == 0 || tree.Talents[0].Location == nil {
+				t.Errorf("Expected talents with locations in tree %s of %s", tree.Name, class)
+			}
+		}
+		if settings.TreeIndex != 1 || len(settings.FlexTalents) != 1 {
+			t.Errorf("Expected the search options to be kept, got %v", settings)
+		}
+	}
+}
+
+func TestLoadTalentSearchKeepsTrees(t *testing.T) {
+	settings, err := loadTalentSearch([]byte(`{"trees": [{"name": "Custom"}]}`), testTalentTreesDir, proto.Class_ClassWarrior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(settings.Trees) != 1 || settings.Trees[0].Name != "Custom" {
+		t.Errorf("Expected the trees from the settings to be kept, got %v", settings.Trees)
+	}
+
+	if _, err := loadTalentSearch([]byte(`{}`), t.TempDir(), proto.Class_ClassWarrior); err == nil {
+		t.Errorf("Expected an error without the talent tree configs")
+	}
+}
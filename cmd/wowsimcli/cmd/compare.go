@@ -0,0 +1,220 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+var compareMetric string
+
+var compareCmd = &cobra.Command{
+	Use:   "compare [input] [input]...",
+	Short: "compare sim results of several inputs",
+	Long:  "sims each input (RaidSimRequest protojson file, wowsims export link or file containing a link) with the same seed and prints the deltas against the first input, with 95% confidence intervals",
+	Args:  cobra.MinimumNArgs(2),
+	Run:   compareMain,
+}
+
+func init() {
+	addSimOverrideFlags(compareCmd)
+	compareCmd.Flags().StringVar(&compareMetric, "metric", "dps", "metric to compare: dps, hps, tps or dtps")
+	compareCmd.Flags().StringVar(&outputFormat, "format", "table", "output format: table, csv or json")
+	compareCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	compareCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+}
+
+type comparisonEntry struct {
+	Input        string  `json:"input"`
+	Mean         float64 `json:"mean"`
+	Stdev        float64 `json:"stdev"`
+	Delta        float64 `json:"delta"`
+	DeltaCI95    float64 `json:"delta_ci95"`
+	DeltaPercent float64 `json:"delta_percent"`
+}
+
+func compareMain(cmd *cobra.Command, args []string) {
+	requests := make([]*proto.RaidSimRequest, len(args))
+	for i, arg := range args {
+		input, err := loadSimInput(arg)
+		if err != nil {
+			log.Fatal(err)
+		}
+		requests[i] = input.request
+	}
+
+	// Every sim must use the same seed and iteration count so that each
+	// iteration can be paired with the same iteration of the baseline.
+	seed := overrideSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	iterations := requests[0].SimOptions.Iterations
+	for _, request := range requests {
+		request.SimOptions.Iterations = iterations
+		request.SimOptions.RandomSeed = seed
+		request.SimOptions.SaveAllValues = true
+		request.SimOptions.UseLabeledRands = true
+		request.SimOptions.Debug = false
+		request.SimOptions.DebugFirstIteration = false
+	}
+
+	samples := make([][]float64, len(requests))
+	for i, request := range requests {
+		reporter := make(chan *proto.ProgressMetrics, 10)
+		core.RunRaidSimConcurrentAsync(request, reporter, fmt.Sprintf("cmd-compare-%d", i))
+
+		var result *proto.RaidSimResult
+		for v := range reporter {
+			if v.FinalRaidResult != nil {
+				result = v.FinalRaidResult
+				break
+			}
+			if verbose {
+				fmt.Fprintf(os.Stderr, "Sim %d / %d Progress: %d / %d\n", i+1, len(requests), v.CompletedIterations, v.TotalIterations)
+			}
+		}
+		if result.Error != nil {
+			log.Fatalf("sim of %q failed: %s", args[i], result.Error.Message)
+		}
+
+		metrics, err := comparisonMetrics(result, compareMetric)
+		if err != nil {
+			log.Fatal(err)
+		}
+		samples[i] = metrics.AllValues
+	}
+
+	entries, err := compareSamples(args, samples)
+	if err != nil {
+		log.Fatal(err)
+	}
+	output, err := formatComparison(entries, outputFormat, compareMetric)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeOutput(output)
+}
+
+// Pairs each iteration of every sample with the same iteration of the first
+// one, which was simmed with the same seed. The confidence intervals of the
+// deltas are much tighter than those of the separate means.
+func compareSamples(inputs []string, samples [][]float64) ([]comparisonEntry, error) {
+	entries := make([]comparisonEntry, len(samples))
+	baseMean, _ := meanAndStdev(samples[0])
+	for i, sample := range samples {
+		if len(sample) != len(samples[0]) {
+			return nil, fmt.Errorf("sim of %q produced %d iterations, expected %d", inputs[i], len(sample), len(samples[0]))
+		}
+
+		deltas := make([]float64, len(sample))
+		for j := range sample {
+			deltas[j] = sample[j] - samples[0][j]
+		}
+
+		mean, stdev := meanAndStdev(sample)
+		deltaMean, deltaStdev := meanAndStdev(deltas)
+		entries[i] = comparisonEntry{
+			Input:     inputs[i],
+			Mean:      mean,
+			Stdev:     stdev,
+			Delta:     deltaMean,
+			DeltaCI95: 1.96 * deltaStdev / math.Sqrt(float64(len(deltas))),
+		}
+		if baseMean != 0 {
+			entries[i].DeltaPercent = deltaMean / baseMean * 100
+		}
+	}
+	return entries, nil
+}
+
+func formatComparison(entries []comparisonEntry, format string, metric string) (string, error) {
+	var sb strings.Builder
+	switch format {
+	case "json":
+		output, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal comparison: %w", err)
+		}
+		sb.Write(output)
+		sb.WriteString("\n")
+	case "csv":
+		w := csv.NewWriter(&sb)
+		w.Write([]string{"input", "mean", "stdev", "delta", "delta_ci95", "delta_percent"})
+		for _, entry := range entries {
+			w.Write([]string{entry.Input, formatFloat(entry.Mean), formatFloat(entry.Stdev), formatFloat(entry.Delta), formatFloat(entry.DeltaCI95), formatFloat(entry.DeltaPercent)})
+		}
+		w.Flush()
+	case "table":
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "Input\t%s\tDelta\t95%% CI\tDelta %%\n", strings.ToUpper(metric))
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%.2f\t%+.2f\t± %.2f\t%+.2f%%\n", shortInputName(entry.Input), entry.Mean, entry.Delta, entry.DeltaCI95, entry.DeltaPercent)
+		}
+		w.Flush()
+	default:
+		return "", fmt.Errorf("unknown output format %q", format)
+	}
+	return sb.String(), nil
+}
+
+// Returns the metrics to compare. Single player sims use the player's
+// metrics, larger raids the raid-wide metrics.
+func comparisonMetrics(result *proto.RaidSimResult, metric string) (*proto.DistributionMetrics, error) {
+	raidMetrics := result.RaidMetrics
+	if len(raidMetrics.Parties) == 1 && len(raidMetrics.Parties[0].Players) == 1 {
+		player := raidMetrics.Parties[0].Players[0]
+		switch metric {
+		case "dps":
+			return player.Dps, nil
+		case "hps":
+			return player.Hps, nil
+		case "tps":
+			return player.Threat, nil
+		case "dtps":
+			return player.Dtps, nil
+		}
+		return nil, fmt.Errorf("unknown metric %q", metric)
+	}
+
+	switch metric {
+	case "dps":
+		return raidMetrics.Dps, nil
+	case "hps":
+		return raidMetrics.Hps, nil
+	}
+	return nil, fmt.Errorf("metric %q is only supported for single player sims", metric)
+}
+
+func meanAndStdev(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum, sumSq float64
+	for _, value := range values {
+		sum += value
+		sumSq += value * value
+	}
+	n := float64(len(values))
+	mean := sum / n
+	return mean, math.Sqrt(max(sumSq/n-mean*mean, 0))
+}
+
+// Links are long and mostly base64, so only show their start in tables.
+func shortInputName(input string) string {
+	if isSimLink(input) && len(input) > 48 {
+		return input[:45] + "..."
+	}
+	return input
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func TestCompareSamples(t *testing.T) {
+	base := []float64{100, 110, 90, 100}
+	// Always exactly 10 more than the baseline, so the paired delta has no variance.
+	shifted := []float64{110, 120, 100, 110}
+	// Alternately 4 more and 4 less than the baseline.
+	noisy := []float64{104, 106, 94, 96}
+
+	entries, err := compareSamples([]string{"base", "shifted", "noisy"}, [][]float64{base, shifted, noisy})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []comparisonEntry{
+		{Input: "base", Mean: 100, Stdev: math.Sqrt(50), Delta: 0, DeltaCI95: 0, DeltaPercent: 0},
+		{Input: "shifted", Mean: 110, Stdev: math.Sqrt(50), Delta: 10, DeltaCI95: 0, DeltaPercent: 10},
+		// Deltas are 4, -4, 4, -4: mean 0, stdev 4, so the CI is 1.96 * 4 / sqrt(4).
+		{Input: "noisy", Mean: 100, Stdev: math.Sqrt(26), Delta: 0, DeltaCI95: 1.96 * 2, DeltaPercent: 0},
+	}
+	for i, entry := range entries {
+		want := expected[i]
+		if entry.Input != want.Input ||
+			math.Abs(entry.Mean-want.Mean) > 1e-9 ||
+			math.Abs(entry.Stdev-want.Stdev) > 1e-9 ||
+			math.Abs(entry.Delta-want.Delta) > 1e-9 ||
+			math.Abs(entry.DeltaCI95-want.DeltaCI95) > 1e-9 ||
+			math.Abs(entry.DeltaPercent-want.DeltaPercent) > 1e-9 {
+			t.Errorf("Expected %+v, got %+v", want, entry)
+		}
+	}
+
+	if _, err := compareSamples([]string{"base", "short"}, [][]float64{base, base[:3]}); err == nil {
+		t.Errorf("Expected samples with different iteration counts to fail")
+	}
+}
+
+func TestComparisonMetrics(t *testing.T) {
+	player := &proto.UnitMetrics{
+		Dps:    &proto.DistributionMetrics{Avg: 1},
+		Hps:    &proto.DistributionMetrics{Avg: 2},
+		Threat: &proto.DistributionMetrics{Avg: 3},
+		Dtps:   &proto.DistributionMetrics{Avg: 4},
+	}
+	single := &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{
+		Parties: []*proto.PartyMetrics{{Players: []*proto.UnitMetrics{player}}},
+	}}
+	raid := &proto.RaidSimResult{RaidMetrics: &proto.RaidMetrics{
+		Dps:     &proto.DistributionMetrics{Avg: 5},
+		Hps:     &proto.DistributionMetrics{Avg: 6},
+		Parties: []*proto.PartyMetrics{{Players: []*proto.UnitMetrics{player, player}}},
+	}}
+
+	for _, test := range []struct {
+		result   *proto.RaidSimResult
+		metric   string
+		expected float64
+	}{
+		{single, "dps", 1},
+		{single, "hps", 2},
+		{single, "tps", 3},
+		{single, "dtps", 4},
+		{raid, "dps", 5},
+		{raid, "hps", 6},
+	} {
+		metrics, err := comparisonMetrics(test.result, test.metric)
+		if err != nil {
+			t.Errorf("Metric %s failed: %s", test.metric, err.Error())
+		} else if metrics.Avg != test.expected {
+			t.Errorf("Expected %s of %f, got %f", test.metric, test.expected, metrics.Avg)
+		}
+	}
+
+	for _, test := range []struct {
+		result *proto.RaidSimResult
+		metric string
+	}{
+		{single, "tmi"},
+		{raid, "tps"},
+		{raid, "dtps"},
+	} {
+		if _, err := comparisonMetrics(test.result, test.metric); err == nil {
+			t.Errorf("Expected metric %s to fail", test.metric)
+		}
+	}
+}
+
+func TestFormatComparison(t *testing.T) {
+	entries := []comparisonEntry{
+		{Input: "base.json", Mean: 1000, Stdev: 50},
+		{Input: "change.json", Mean: 1010.5, Stdev: 51, Delta: 10.5, DeltaCI95: 1.25, DeltaPercent: 1.05},
+	}
+
+	output, err := formatComparison(entries, "json", "dps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []comparisonEntry
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("Invalid JSON output: %s", err.Error())
+	}
+	if len(decoded) != 2 || decoded[1] != entries[1] {
+		t.Errorf("Expected %v, got %v", entries, decoded)
+	}
+
+	output, err = formatComparison(entries, "csv", "dps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV output: %s", err.Error())
+	}
+	expectedRecord := []string{"change.json", "1010.5000", "51.0000", "10.5000", "1.2500", "1.0500"}
+	if len(records) != 3 || strings.Join(records[2], ",") != strings.Join(expectedRecord, ",") {
+		t.Errorf("Unexpected CSV output:\n%s", output)
+	}
+
+	output, err = formatComparison(entries, "table", "dps")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 || !strings.HasPrefix(lines[0], "Input") || !strings.Contains(lines[0], "DPS") {
+		t.Fatalf("Unexpected table output:\n%s", output)
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "change.json 1010.50 +10.50 ± 1.25 +1.05%" {
+		t.Errorf("Unexpected table row %q", lines[2])
+	}
+
+	if _, err := formatComparison(entries, "xml", "dps"); err == nil {
+		t.Errorf("Expected an unknown format to fail")
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/base64"
 	"errors"
 	"fmt"
+	"os"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,41 +15,72 @@ import (
 	goproto "google.golang.org/protobuf/proto"
 )
 
+var linkFile string
+
 var decodeLinkCmd = &cobra.Command{
 	Use:   "decodelink [link]",
 	Short: "decode wowsims link/url",
-	Long:  "decode wowsims link/url",
-	Args:  cobra.ExactArgs(1),
+	Long:  "decode wowsims link/url, given as an argument, with --link or as a file containing the link",
+	Args:  cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return decodeLink(args[0])
+		switch {
+		case len(args) == 1:
+			return decodeLink(args[0])
+		case link != "":
+			return decodeLink(link)
+		case linkFile != "":
+			data, err := os.ReadFile(linkFile)
+			if err != nil {
+				return fmt.Errorf("failed to load input file %q: %w", linkFile, err)
+			}
+			return decodeLink(strings.TrimSpace(string(data)))
+		}
+		return errInvalidLink
 	},
 }
 
+func init() {
+	decodeLinkCmd.Flags().StringVar(&link, "link", "", "wowsims export link")
+	decodeLinkCmd.Flags().StringVar(&linkFile, "infile", "", "location of a file containing a wowsims export link")
+}
+
 var errInvalidLink = errors.New("invalid wowsims export link")
 
 func decodeLink(link string) error {
+	settings, err := decodeLinkSettings(link)
+	if err != nil {
+		return err
+	}
+
+	fmt.Println(protojson.Format(settings))
+	return nil
+}
+
+// Decodes an exported sim link into either RaidSimSettings (for raid sim
+// links) or IndividualSimSettings (for everything else).
+func decodeLinkSettings(link string) (goproto.Message, error) {
 	parts := strings.Split(link, "#")
 	switch {
 	case len(parts) != 2:
-		return errInvalidLink
+		return nil, errInvalidLink
 	case parts[1] == "":
-		return errInvalidLink
+		return nil, errInvalidLink
 	}
 
 	raw, err := base64.StdEncoding.DecodeString(parts[1])
 	if err != nil {
-		return fmt.Errorf("cannot decode proto from link: %w", err)
+		return nil, fmt.Errorf("cannot decode proto from link: %w", err)
 	}
 
 	r, err := zlib.NewReader(bytes.NewReader(raw))
 	if err != nil {
-		return fmt.Errorf("cannot create zlib reader: %w", err)
+		return nil, fmt.Errorf("cannot create zlib reader: %w", err)
 	}
 	defer r.Close()
 
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r); err != nil {
-		return fmt.Errorf("reading zlib data failed: %w", err)
+		return nil, fmt.Errorf("reading zlib data failed: %w", err)
 	}
 
 	var settings goproto.Message
@@ -59,9 +91,8 @@ func decodeLink(link string) error {
 	}
 
 	if err := goproto.Unmarshal(buf.Bytes(), settings); err != nil {
-		return fmt.Errorf("cannot unmarshal raw proto: %w", err)
+		return nil, fmt.Errorf("cannot unmarshal raw proto: %w", err)
 	}
 
-	fmt.Println(protojson.Format(goproto.Message(settings)))
-	return nil
+	return settings, nil
 }
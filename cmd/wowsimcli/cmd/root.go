@@ -5,6 +5,7 @@ import (
 	"os"
 
 	"github.com/spf13/cobra"
+	"github.com/wowsims/cata/sim/core"
 )
 
 var rootCmd = &cobra.Command{
@@ -13,11 +14,33 @@ var rootCmd = &cobra.Command{
 	Long:  "wowsims command line tool",
 }
 
+var (
+	cacheDir  string
+	cacheSize int
+)
+
 func Execute(version string) {
+	rootCmd.PersistentFlags().StringVar(&cacheDir, "cache-dir", "", "directory to store raid sim results in, reused by later runs of identical requests with a fixed seed")
+	rootCmd.PersistentFlags().IntVar(&cacheSize, "cache-size", core.DefaultResultCacheSize, "number of raid sim results to keep in memory when --cache-dir is set")
+	rootCmd.PersistentPreRunE = func(cmd *cobra.Command, args []string) error {
+		if cacheDir == "" {
+			return nil
+		}
+		cache, err := core.NewRaidSimResultCache(cacheSize, cacheDir, version)
+		if err != nil {
+			return fmt.Errorf("failed to create result cache: %w", err)
+		}
+		core.SetRaidSimResultCache(cache)
+		return nil
+	}
+
 	rootCmd.AddCommand(newVersionCommand(version))
 	rootCmd.AddCommand(simCmd)
 	rootCmd.AddCommand(bulkCmd)
 	rootCmd.AddCommand(decodeLinkCmd)
+	rootCmd.AddCommand(statWeightsCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(aplCmd)
 
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -0,0 +1,191 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// Iterations used for links which don't specify any, matching the web UI default.
+const defaultLinkIterations = 12500
+
+var (
+	link                string
+	overrideIterations  int32
+	overrideSeed        int64
+	overrideDuration    float64
+	overrideTargetCount int32
+	overrideTargetRSE   float64
+	overrideMaxIters    int32
+)
+
+// Registers the flags used to select a sim input, plus the override flags.
+func addSimInputFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&infile, "infile", "input.json", "location of input file (RaidSimRequest in protojson format, or a file containing a wowsims export link)")
+	cmd.Flags().StringVar(&link, "link", "", "wowsims export link to use instead of --infile")
+	addSimOverrideFlags(cmd)
+}
+
+// Registers flags which override parts of every loaded sim input.
+func addSimOverrideFlags(cmd *cobra.Command) {
+	cmd.Flags().Int32Var(&overrideIterations, "iterations", 0, "overrides the number of iterations")
+	cmd.Flags().Int64Var(&overrideSeed, "seed", 0, "overrides the random seed")
+	cmd.Flags().Float64Var(&overrideDuration, "duration", 0, "overrides the encounter duration, in seconds")
+	cmd.Flags().Int32Var(&overrideTargetCount, "target-count", 0, "overrides the number of targets, copying the last target when adding targets")
+	cmd.Flags().Float64Var(&overrideTargetRSE, "target-rse", 0, "keeps adding iterations until the relative standard error of the DPS mean is at most this, e.g. 0.001")
+	cmd.Flags().Int32Var(&overrideMaxIters, "max-iterations", 0, "most iterations to run with --target-rse")
+}
+
+// A sim input loaded from either a RaidSimRequest protojson file or an exported sim link.
+type simInput struct {
+	request *proto.RaidSimRequest
+
+	// Only set when the input was an individual sim link.
+	individualSettings *proto.IndividualSimSettings
+}
+
+func isSimLink(input string) bool {
+	return strings.HasPrefix(input, "https://") || strings.HasPrefix(input, "http://")
+}
+
+// Loads the sim input selected by --link or --infile, with overrides applied.
+func loadSimInputFromFlags() (*simInput, error) {
+	input := infile
+	if link != "" {
+		input = link
+	}
+	return loadSimInput(input)
+}
+
+// Loads a sim input from a protojson file, a file containing an exported sim
+// link, or the link itself. Any override flags are applied to the result.
+func loadSimInput(input string) (*simInput, error) {
+	result, err := loadSimInputWithoutOverrides(input)
+	if err != nil {
+		return nil, err
+	}
+	applySimOverrides(result.request)
+	return result, nil
+}
+
+func loadSimInputWithoutOverrides(input string) (*simInput, error) {
+	if isSimLink(input) {
+		return loadSimInputFromLink(input)
+	}
+
+	data, err := os.ReadFile(input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load input file %q: %w", input, err)
+	}
+	if content := strings.TrimSpace(string(data)); isSimLink(content) {
+		return loadSimInputFromLink(content)
+	}
+
+	request := &proto.RaidSimRequest{}
+	if err := (protojson.UnmarshalOptions{DiscardUnknown: true}).Unmarshal(data, request); err != nil {
+		return nil, fmt.Errorf("failed to load input json file %q: %w", input, err)
+	}
+	if request.SimOptions == nil {
+		request.SimOptions = &proto.SimOptions{}
+	}
+	return &simInput{request: request}, nil
+}
+
+func loadSimInputFromLink(link string) (*simInput, error) {
+	settings, err := decodeLinkSettings(link)
+	if err != nil {
+		return nil, err
+	}
+
+	switch settings := settings.(type) {
+	case *proto.IndividualSimSettings:
+		return &simInput{
+			request:            individualSettingsToRequest(settings),
+			individualSettings: settings,
+		}, nil
+	case *proto.RaidSimSettings:
+		return &simInput{request: raidSettingsToRequest(settings)}, nil
+	}
+	return nil, errInvalidLink
+}
+
+// Builds the same RaidSimRequest the individual sim UI would send for these settings.
+func individualSettingsToRequest(settings *proto.IndividualSimSettings) *proto.RaidSimRequest {
+	raid := core.SinglePlayerRaidProto(settings.Player, settings.PartyBuffs, settings.RaidBuffs, settings.Debuffs)
+	raid.Tanks = settings.Tanks
+	raid.TargetDummies = settings.TargetDummies
+
+	return &proto.RaidSimRequest{
+		Raid:       raid,
+		Encounter:  settings.Encounter,
+		SimOptions: simSettingsToOptions(settings.Settings),
+		Type:       proto.SimType_SimTypeIndividual,
+	}
+}
+
+// Builds the same RaidSimRequest the raid sim UI would send for these settings.
+func raidSettingsToRequest(settings *proto.RaidSimSettings) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		Raid:       settings.Raid,
+		Encounter:  settings.Encounter,
+		SimOptions: simSettingsToOptions(settings.Settings),
+		Type:       proto.SimType_SimTypeRaid,
+	}
+}
+
+func simSettingsToOptions(settings *proto.SimSettings) *proto.SimOptions {
+	iterations := settings.GetIterations()
+	if iterations == 0 {
+		iterations = defaultLinkIterations
+	}
+
+	return &proto.SimOptions{
+		Iterations: iterations,
+		RandomSeed: settings.GetFixedRngSeed(),
+	}
+}
+
+func applySimOverrides(request *proto.RaidSimRequest) {
+	if overrideIterations > 0 {
+		request.SimOptions.Iterations = overrideIterations
+	}
+	if overrideSeed != 0 {
+		request.SimOptions.RandomSeed = overrideSeed
+	}
+	if overrideTargetRSE > 0 {
+		request.SimOptions.TargetRelativeStandardError = overrideTargetRSE
+	}
+	if overrideMaxIters > 0 {
+		request.SimOptions.MaxIterations = overrideMaxIters
+	}
+
+	if overrideDuration <= 0 && overrideTargetCount <= 0 {
+		return
+	}
+	if request.Encounter == nil {
+		request.Encounter = &proto.Encounter{}
+	}
+	if overrideDuration > 0 {
+		request.Encounter.Duration = overrideDuration
+	}
+	if overrideTargetCount > 0 {
+		targets := request.Encounter.Targets
+		if len(targets) > int(overrideTargetCount) {
+			targets = targets[:overrideTargetCount]
+		}
+		for len(targets) < int(overrideTargetCount) {
+			template := core.NewDefaultTarget()
+			if len(targets) > 0 {
+				template = targets[len(targets)-1]
+			}
+			targets = append(targets, goproto.Clone(template).(*proto.Target))
+		}
+		request.Encounter.Targets = targets
+	}
+}
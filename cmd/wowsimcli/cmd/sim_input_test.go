@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	goproto "google.golang.org/protobuf/proto"
+)
+
+// Encodes settings the same way the web UI's export links do.
+func makeTestLink(t *testing.T, url string, settings goproto.Message) string {
+	data, err := goproto.Marshal(settings)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	w.Write(data)
+	w.Close()
+	return url + "#" + base64.StdEncoding.EncodeToString(buf.Bytes())
+}
+
+func TestLoadSimInputFromLink(t *testing.T) {
+	player := &proto.Player{Name: "Tester", Class: proto.Class_ClassMage}
+	encounter := &proto.Encounter{Duration: 240, Targets: []*proto.Target{{Level: 88}}}
+	tanks := []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}}
+
+	individual := &proto.IndividualSimSettings{
+		Player:     player,
+		RaidBuffs:  &proto.RaidBuffs{ArcaneBrilliance: true},
+		PartyBuffs: &proto.PartyBuffs{},
+		Debuffs:    &proto.Debuffs{},
+		Encounter:  encounter,
+		Tanks:      tanks,
+		Settings:   &proto.SimSettings{Iterations: 3000, FixedRngSeed: 42},
+	}
+	raid := &proto.RaidSimSettings{
+		Raid: &proto.Raid{
+			Parties: []*proto.Party{{Players: []*proto.Player{player, player}}},
+			Tanks:   tanks,
+		},
+		Encounter: encounter,
+	}
+
+	// Links can also be given in a file.
+	linkFile := filepath.Join(t.TempDir(), "link.txt")
+	if err := os.WriteFile(linkFile, []byte(makeTestLink(t, "https://wowsims.github.io/cata/mage/fire/", individual)+"\n"), 0666); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		name       string
+		input      string
+		expected   *proto.RaidSimRequest
+		individual bool
+	}{
+		{
+			name:  "individual link",
+			input: makeTestLink(t, "https://wowsims.github.io/cata/mage/fire/", individual),
+			expected: &proto.RaidSimRequest{
+				Raid: &proto.Raid{
+					Parties: []*proto.Party{{Players: []*proto.Player{player}, Buffs: &proto.PartyBuffs{}}},
+					Buffs:   individual.RaidBuffs,
+					Debuffs: individual.Debuffs,
+					Tanks:   tanks,
+				},
+				Encounter:  encounter,
+				SimOptions: &proto.SimOptions{Iterations: 3000, RandomSeed: 42},
+				Type:       proto.SimType_SimTypeIndividual,
+			},
+			individual: true,
+		},
+		{
+			name:       "individual link file",
+			input:      linkFile,
+			individual: true,
+		},
+		{
+			name:  "raid link",
+			input: makeTestLink(t, "https://wowsims.github.io/cata/raid/", raid),
+			expected: &proto.RaidSimRequest{
+				Raid:       raid.Raid,
+				Encounter:  encounter,
+				SimOptions: &proto.SimOptions{Iterations: defaultLinkIterations},
+				Type:       proto.SimType_SimTypeRaid,
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			input, err := loadSimInputWithoutOverrides(test.input)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if test.individual != (input.individualSettings != nil) {
+				t.Errorf("Expected individual settings: %t, got %v", test.individual, input.individualSettings)
+			}
+			if test.individual && !goproto.Equal(input.individualSettings, individual) {
+				t.Errorf("Expected settings %v, got %v", individual, input.individualSettings)
+			}
+			if test.expected != nil && !goproto.Equal(input.request, test.expected) {
+				t.Errorf("Expected request:\n%v\ngot:\n%v", test.expected, input.request)
+			}
+		})
+	}
+
+	if _, err := loadSimInputWithoutOverrides("https://wowsims.github.io/cata/mage/fire/"); err == nil {
+		t.Errorf("Expected a link without settings to fail")
+	}
+}
+
+func TestApplySimOverrides(t *testing.T) {
+	first := &proto.Target{Level: 88, Stats: []float64{1}}
+	last := &proto.Target{Level: 87, Stats: []float64{2}}
+	newRequest := func() *proto.RaidSimRequest {
+		return &proto.RaidSimRequest{
+			Encounter: &proto.Encounter{
+				Duration: 300,
+				Targets:  []*proto.Target{goproto.Clone(first).(*proto.Target), goproto.Clone(last).(*proto.Target)},
+			},
+			SimOptions: &proto.SimOptions{Iterations: 1000, RandomSeed: 1},
+		}
+	}
+
+	for _, test := range []struct {
+		name     string
+		set      func()
+		request  func() *proto.RaidSimRequest
+		expected func(*proto.RaidSimRequest)
+	}{
+		{
+			name: "no overrides",
+		},
+		{
+			name:     "iterations",
+			set:      func() { overrideIterations = 50 },
+			expected: func(r *proto.RaidSimRequest) { r.SimOptions.Iterations = 50 },
+		},
+		{
+			name:     "seed",
+			set:      func() { overrideSeed = 7 },
+			expected: func(r *proto.RaidSimRequest) { r.SimOptions.RandomSeed = 7 },
+		},
+		{
+			name:     "duration",
+			set:      func() { overrideDuration = 120 },
+			expected: func(r *proto.RaidSimRequest) { r.Encounter.Duration = 120 },
+		},
+		{
+			name:     "duration without encounter",
+			set:      func() { overrideDuration = 120 },
+			request:  func() *proto.RaidSimRequest { return &proto.RaidSimRequest{SimOptions: &proto.SimOptions{}} },
+			expected: func(r *proto.RaidSimRequest) { r.Encounter = &proto.Encounter{Duration: 120} },
+		},
+		{
+			name: "target rse",
+			set:  func() { overrideTargetRSE = 0.001; overrideMaxIters = 20000 },
+			expected: func(r *proto.RaidSimRequest) {
+				r.SimOptions.TargetRelativeStandardError = 0.001
+				r.SimOptions.MaxIterations = 20000
+			},
+		},
+		{
+			name: "more targets copy the last target",
+			set:  func() { overrideTargetCount = 4 },
+			expected: func(r *proto.RaidSimRequest) {
+				r.Encounter.Targets = []*proto.Target{first, last, last, last}
+			},
+		},
+		{
+			name:     "fewer targets keep the first targets",
+			set:      func() { overrideTargetCount = 1 },
+			expected: func(r *proto.RaidSimRequest) { r.Encounter.Targets = []*proto.Target{first} },
+		},
+		{
+			name:    "targets without any targets",
+			set:     func() { overrideTargetCount = 1 },
+			request: func() *proto.RaidSimRequest { return &proto.RaidSimRequest{SimOptions: &proto.SimOptions{}} },
+			expected: func(r *proto.RaidSimRequest) {
+				r.Encounter = &proto.Encounter{Targets: []*proto.Target{core.NewDefaultTarget()}}
+			},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			overrideIterations, overrideSeed, overrideDuration, overrideTargetCount, overrideTargetRSE, overrideMaxIters = 0, 0, 0, 0, 0, 0
+			defer func() {
+				overrideIterations, overrideSeed, overrideDuration, overrideTargetCount, overrideTargetRSE, overrideMaxIters = 0, 0, 0, 0, 0, 0
+			}()
+			if test.set != nil {
+				test.set()
+			}
+
+			makeRequest := newRequest
+			if test.request != nil {
+				makeRequest = test.request
+			}
+			request := makeRequest()
+			expected := makeRequest()
+			if test.expected != nil {
+				test.expected(expected)
+			}
+
+			applySimOverrides(request)
+			if !goproto.Equal(request, expected) {
+				t.Fatalf("Expected:\n%v\ngot:\n%v", expected, request)
+			}
+
+			// Added targets are copies, not shared with the last target.
+			targets := request.GetEncounter().GetTargets()
+			for i := 1; i < len(targets); i++ {
+				if targets[i] == targets[i-1] {
+					t.Errorf("Targets %d and %d are the same message", i-1, i)
+				}
+			}
+		})
+	}
+}
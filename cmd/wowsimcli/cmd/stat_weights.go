@@ -0,0 +1,302 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+var (
+	statsToWeigh []string
+	epRefStat    string
+	swMetric     string
+	outputFormat string
+)
+
+var statWeightsCmd = &cobra.Command{
+	Use:   "statweights",
+	Short: "calculate stat weights and EP values",
+	Long:  "calculate stat weights and EP values, with standard deviations, for a single player sim",
+	Run:   statWeightsMain,
+}
+
+func init() {
+	addSimInputFlags(statWeightsCmd)
+	statWeightsCmd.Flags().StringVar(&outfile, "outfile", "", "location of output file, defaults to stdout")
+	statWeightsCmd.Flags().BoolVar(&verbose, "verbose", false, "print information during runtime")
+	statWeightsCmd.Flags().StringSliceVar(&statsToWeigh, "stats", nil, "stats to weigh, e.g. Intellect,SpellPower,HasteRating. Defaults to the usual stats for the player's EP reference stat")
+	statWeightsCmd.Flags().StringVar(&epRefStat, "ep-ref", "", "EP reference stat. Defaults to RangedAttackPower for hunters, SpellPower for casters and AttackPower otherwise")
+	statWeightsCmd.Flags().StringVar(&swMetric, "metric", "dps", "metric to weigh: dps, hps, tps, dtps, tmi or pdeath")
+	statWeightsCmd.Flags().StringVar(&outputFormat, "format", "table", "output format: table, csv or json")
+}
+
+type statWeightEntry struct {
+	Stat        string  `json:"stat"`
+	Weight      float64 `json:"weight"`
+	WeightStdev float64 `json:"weight_stdev"`
+	EP          float64 `json:"ep"`
+	EPStdev     float64 `json:"ep_stdev"`
+}
+
+func statWeightsMain(cmd *cobra.Command, args []string) {
+	input, err := loadSimInputFromFlags()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	request, err := newStatWeightsRequest(input.request)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	refStat, unitStats, err := parseStatsToWeigh(statsToWeigh, epRefStat, func() proto.Stat {
+		return defaultEPReferenceStat(input.request)
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	request.EpReferenceStat = refStat
+
+	for _, unitStat := range unitStats {
+		if unitStat.IsStat() {
+			request.StatsToWeigh = append(request.StatsToWeigh, proto.Stat(unitStat.StatIdx()))
+		} else {
+			request.PseudoStatsToWeigh = append(request.PseudoStatsToWeigh, proto.PseudoStat(unitStat.PseudoStatIdx()))
+		}
+	}
+
+	reporter := make(chan *proto.ProgressMetrics, 10)
+	core.StatWeightsAsync(request, reporter, "cmd-stat-weights")
+
+	var result *proto.StatWeightsResult
+	for v := range reporter {
+		if v.FinalWeightResult != nil {
+			result = v.FinalWeightResult
+			break
+		}
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Stat Weights Progress: %d / %d sims, %d / %d iterations\n", v.CompletedSims, v.TotalSims, v.CompletedIterations, v.TotalIterations)
+		}
+	}
+	if result.Error != nil {
+		log.Fatalf("stat weights failed: %s", result.Error.Message)
+	}
+
+	values, err := statWeightValuesForMetric(result, swMetric)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	entries := make([]statWeightEntry, 0, len(unitStats))
+	for _, unitStat := range unitStats {
+		entries = append(entries, statWeightEntry{
+			Stat:        unitStatName(unitStat),
+			Weight:      getUnitStat(values.Weights, unitStat),
+			WeightStdev: getUnitStat(values.WeightsStdev, unitStat),
+			EP:          getUnitStat(values.EpValues, unitStat),
+			EPStdev:     getUnitStat(values.EpValuesStdev, unitStat),
+		})
+	}
+
+	output, err := formatStatWeights(entries, outputFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+	writeOutput(output)
+}
+
+// Parses the --stats and --ep-ref flags. The reference stat defaults to
+// defaultRefStat() and is always weighed, the stats default to the usual ones
+// for the reference stat.
+func parseStatsToWeigh(statNames []string, refStatName string, defaultRefStat func() proto.Stat) (proto.Stat, []stats.UnitStat, error) {
+	var refStat proto.Stat
+	if refStatName != "" {
+		refUnitStat, err := parseUnitStat(refStatName)
+		if err != nil || !refUnitStat.IsStat() {
+			return 0, nil, fmt.Errorf("invalid EP reference stat %q", refStatName)
+		}
+		refStat = proto.Stat(refUnitStat.StatIdx())
+	} else {
+		refStat = defaultRefStat()
+	}
+
+	unitStats := make([]stats.UnitStat, 0, len(statNames))
+	for _, name := range statNames {
+		unitStat, err := parseUnitStat(name)
+		if err != nil {
+			return 0, nil, err
+		}
+		unitStats = appendUniqueUnitStat(unitStats, unitStat)
+	}
+	if len(unitStats) == 0 {
+		unitStats = defaultStatsToWeigh(refStat)
+	}
+	return refStat, appendUniqueUnitStat(unitStats, stats.UnitStatFromStat(stats.Stat(refStat))), nil
+}
+
+func formatStatWeights(entries []statWeightEntry, format string) (string, error) {
+	var sb strings.Builder
+	switch format {
+	case "json":
+		output, err := json.MarshalIndent(entries, "", "  ")
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal stat weights: %w", err)
+		}
+		sb.Write(output)
+		sb.WriteString("\n")
+	case "csv":
+		w := csv.NewWriter(&sb)
+		w.Write([]string{"stat", "weight", "weight_stdev", "ep", "ep_stdev"})
+		for _, entry := range entries {
+			w.Write([]string{entry.Stat, formatFloat(entry.Weight), formatFloat(entry.WeightStdev), formatFloat(entry.EP), formatFloat(entry.EPStdev)})
+		}
+		w.Flush()
+	case "table":
+		w := tabwriter.NewWriter(&sb, 0, 0, 2, ' ', tabwriter.AlignRight)
+		fmt.Fprintf(w, "Stat\tWeight\t\tEP\t\t\n")
+		for _, entry := range entries {
+			fmt.Fprintf(w, "%s\t%.3f\t± %.3f\t%.3f\t± %.3f\t\n", entry.Stat, entry.Weight, entry.WeightStdev, entry.EP, entry.EPStdev)
+		}
+		w.Flush()
+	default:
+		return "", fmt.Errorf("unknown output format %q", format)
+	}
+	return sb.String(), nil
+}
+
+// Converts a single player RaidSimRequest into a StatWeightsRequest.
+func newStatWeightsRequest(rsr *proto.RaidSimRequest) (*proto.StatWeightsRequest, error) {
+	if len(rsr.Raid.GetParties()) == 0 || len(rsr.Raid.Parties[0].Players) == 0 {
+		return nil, fmt.Errorf("stat weights require a raid with a player in the first party")
+	}
+	party := rsr.Raid.Parties[0]
+
+	return &proto.StatWeightsRequest{
+		Player:     party.Players[0],
+		RaidBuffs:  rsr.Raid.Buffs,
+		PartyBuffs: party.Buffs,
+		Debuffs:    rsr.Raid.Debuffs,
+		Encounter:  rsr.Encounter,
+		SimOptions: rsr.SimOptions,
+		Tanks:      rsr.Raid.Tanks,
+	}, nil
+}
+
+func defaultEPReferenceStat(rsr *proto.RaidSimRequest) proto.Stat {
+	player := rsr.Raid.Parties[0].Players[0]
+	if player.Class == proto.Class_ClassHunter {
+		return proto.Stat_StatRangedAttackPower
+	}
+
+	computed := core.ComputeStats(&proto.ComputeStatsRequest{Raid: rsr.Raid, Encounter: rsr.Encounter})
+	if computed.ErrorResult != "" {
+		log.Fatalf("failed to compute player stats: %s", computed.ErrorResult)
+	}
+	finalStats := stats.FromUnitStatsProto(computed.RaidStats.Parties[0].Players[0].FinalStats)
+	if finalStats.GetHighestStatType([]stats.Stat{stats.Strength, stats.Agility, stats.Intellect}) == stats.Intellect {
+		return proto.Stat_StatSpellPower
+	}
+	return proto.Stat_StatAttackPower
+}
+
+func defaultStatsToWeigh(refStat proto.Stat) []stats.UnitStat {
+	var statList []stats.Stat
+	switch refStat {
+	case proto.Stat_StatSpellPower, proto.Stat_StatIntellect:
+		statList = []stats.Stat{stats.Intellect, stats.Spirit, stats.SpellPower, stats.HitRating, stats.CritRating, stats.HasteRating, stats.MasteryRating}
+	case proto.Stat_StatRangedAttackPower:
+		statList = []stats.Stat{stats.Agility, stats.RangedAttackPower, stats.HitRating, stats.CritRating, stats.HasteRating, stats.MasteryRating}
+	default:
+		statList = []stats.Stat{stats.Strength, stats.Agility, stats.AttackPower, stats.HitRating, stats.CritRating, stats.HasteRating, stats.ExpertiseRating, stats.MasteryRating}
+	}
+
+	unitStats := make([]stats.UnitStat, 0, len(statList))
+	for _, stat := range statList {
+		unitStats = append(unitStats, stats.UnitStatFromStat(stat))
+	}
+	return unitStats
+}
+
+func appendUniqueUnitStat(unitStats []stats.UnitStat, unitStat stats.UnitStat) []stats.UnitStat {
+	for _, existing := range unitStats {
+		if existing == unitStat {
+			return unitStats
+		}
+	}
+	return append(unitStats, unitStat)
+}
+
+// Parses a stat or pseudo stat name, with or without its proto prefix,
+// e.g. "HasteRating", "StatHasteRating" or "PseudoStatMainHandDps".
+func parseUnitStat(name string) (stats.UnitStat, error) {
+	for _, candidate := range []string{name, "Stat" + name} {
+		if value, ok := proto.Stat_value[candidate]; ok {
+			return stats.UnitStatFromStat(stats.Stat(value)), nil
+		}
+	}
+	for _, candidate := range []string{name, "PseudoStat" + name} {
+		if value, ok := proto.PseudoStat_value[candidate]; ok {
+			return stats.UnitStatFromPseudoStat(proto.PseudoStat(value)), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown stat %q", name)
+}
+
+func unitStatName(unitStat stats.UnitStat) string {
+	if unitStat.IsStat() {
+		return strings.TrimPrefix(proto.Stat(unitStat.StatIdx()).String(), "Stat")
+	}
+	return strings.TrimPrefix(proto.PseudoStat(unitStat.PseudoStatIdx()).String(), "PseudoStat")
+}
+
+func getUnitStat(unitStats *proto.UnitStats, unitStat stats.UnitStat) float64 {
+	if unitStat.IsStat() {
+		return unitStats.GetStats()[unitStat.StatIdx()]
+	}
+	return unitStats.GetPseudoStats()[unitStat.PseudoStatIdx()]
+}
+
+func statWeightValuesForMetric(result *proto.StatWeightsResult, metric string) (*proto.StatWeightValues, error) {
+	switch metric {
+	case "dps":
+		return result.Dps, nil
+	case "hps":
+		return result.Hps, nil
+	case "tps":
+		return result.Tps, nil
+	case "dtps":
+		return result.Dtps, nil
+	case "tmi":
+		return result.Tmi, nil
+	case "pdeath":
+		return result.PDeath, nil
+	}
+	return nil, fmt.Errorf("unknown metric %q", metric)
+}
+
+func formatFloat(value float64) string {
+	return fmt.Sprintf("%.4f", value)
+}
+
+func writeOutput(output string) {
+	if outfile == "" {
+		fmt.Print(output)
+		return
+	}
+
+	if err := os.WriteFile(outfile, []byte(output), 0666); err != nil {
+		log.Fatalf("failed to write output file: %s", err)
+	}
+	if verbose {
+		fmt.Printf("Wrote output file: `%s` successfully.\n", outfile)
+	}
+}
@@ -0,0 +1,137 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func TestParseStatsToWeigh(t *testing.T) {
+	defaultRef := func() proto.Stat { return proto.Stat_StatAttackPower }
+
+	refStat, unitStats, err := parseStatsToWeigh([]string{"HasteRating", "StatCritRating", "PseudoStatMainHandDps", "HasteRating"}, "SpellPower", defaultRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refStat != proto.Stat_StatSpellPower {
+		t.Errorf("Expected SpellPower as reference stat, got %s", refStat)
+	}
+	expected := []stats.UnitStat{
+		stats.UnitStatFromStat(stats.HasteRating),
+		stats.UnitStatFromStat(stats.CritRating),
+		stats.UnitStatFromPseudoStat(proto.PseudoStat_PseudoStatMainHandDps),
+		stats.UnitStatFromStat(stats.SpellPower),
+	}
+	if len(unitStats) != len(expected) {
+		t.Fatalf("Expected stats %v, got %v", expected, unitStats)
+	}
+	for i := range expected {
+		if unitStats[i] != expected[i] {
+			t.Errorf("Expected stats %v, got %v", expected, unitStats)
+			break
+		}
+	}
+
+	// Without flags the reference stat and its usual stats are used.
+	refStat, unitStats, err = parseStatsToWeigh(nil, "", defaultRef)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if refStat != proto.Stat_StatAttackPower {
+		t.Errorf("Expected the default reference stat, got %s", refStat)
+	}
+	if len(unitStats) != len(defaultStatsToWeigh(proto.Stat_StatAttackPower)) {
+		t.Errorf("Expected the default stats for AttackPower, got %v", unitStats)
+	}
+
+	for name, test := range map[string]struct {
+		stats   []string
+		refStat string
+	}{
+		"unknown stat":           {[]string{"Haste"}, ""},
+		"unknown reference stat": {nil, "Power"},
+		"pseudo reference stat":  {nil, "MainHandDps"},
+	} {
+		if _, _, err := parseStatsToWeigh(test.stats, test.refStat, defaultRef); err == nil {
+			t.Errorf("Expected %s to fail", name)
+		}
+	}
+}
+
+func TestStatWeightValuesForMetric(t *testing.T) {
+	result := &proto.StatWeightsResult{
+		Dps:    &proto.StatWeightValues{},
+		Hps:    &proto.StatWeightValues{},
+		Tps:    &proto.StatWeightValues{},
+		Dtps:   &proto.StatWeightValues{},
+		Tmi:    &proto.StatWeightValues{},
+		PDeath: &proto.StatWeightValues{},
+	}
+	for metric, expected := range map[string]*proto.StatWeightValues{
+		"dps":    result.Dps,
+		"hps":    result.Hps,
+		"tps":    result.Tps,
+		"dtps":   result.Dtps,
+		"tmi":    result.Tmi,
+		"pdeath": result.PDeath,
+	} {
+		if values, err := statWeightValuesForMetric(result, metric); err != nil || values != expected {
+			t.Errorf("Expected the %s values, got %v, %v", metric, values, err)
+		}
+	}
+	if _, err := statWeightValuesForMetric(result, "ehps"); err == nil {
+		t.Errorf("Expected an unknown metric to fail")
+	}
+}
+
+func TestFormatStatWeights(t *testing.T) {
+	entries := []statWeightEntry{
+		{Stat: "SpellPower", Weight: 2.5, WeightStdev: 0.1, EP: 1, EPStdev: 0},
+		{Stat: "HasteRating", Weight: 1.25, WeightStdev: 0.05, EP: 0.5, EPStdev: 0.02},
+	}
+
+	output, err := formatStatWeights(entries, "json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded []statWeightEntry
+	if err := json.Unmarshal([]byte(output), &decoded); err != nil {
+		t.Fatalf("Invalid JSON output: %s", err.Error())
+	}
+	if len(decoded) != 2 || decoded[1] != entries[1] {
+		t.Errorf("Expected %v, got %v", entries, decoded)
+	}
+
+	output, err = formatStatWeights(entries, "csv")
+	if err != nil {
+		t.Fatal(err)
+	}
+	records, err := csv.NewReader(strings.NewReader(output)).ReadAll()
+	if err != nil {
+		t.Fatalf("Invalid CSV output: %s", err.Error())
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != "stat,weight,weight_stdev,ep,ep_stdev" ||
+		strings.Join(records[2], ",") != "HasteRating,1.2500,0.0500,0.5000,0.0200" {
+		t.Errorf("Unexpected CSV output:\n%s", output)
+	}
+
+	output, err = formatStatWeights(entries, "table")
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("Unexpected table output:\n%s", output)
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "HasteRating 1.250 ± 0.050 0.500 ± 0.020" {
+		t.Errorf("Unexpected table row %q", lines[2])
+	}
+
+	if _, err := formatStatWeights(entries, "yaml"); err == nil {
+		t.Errorf("Expected an unknown format to fail")
+	}
+}
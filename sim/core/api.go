@@ -86,8 +86,22 @@ func RunRaidSimAsync(request *proto.RaidSimRequest, progress chan *proto.Progres
 }
 
 // Threading does not work in WASM!
+// If the request has an ID set, it is registered so the sim can be aborted by ID.
 func RunRaidSimConcurrent(request *proto.RaidSimRequest) *proto.RaidSimResult {
-	return runSimConcurrent(request, nil, simsignals.CreateSignals())
+	if request.RequestId == "" {
+		return runSimConcurrent(request, nil, simsignals.CreateSignals())
+	}
+
+	signals, err := simsignals.RegisterWithId(request.RequestId)
+	if err != nil {
+		return &proto.RaidSimResult{
+			Error: &proto.ErrorOutcome{
+				Message: "Couldn't register for signal API: " + err.Error(),
+			},
+		}
+	}
+	defer simsignals.UnregisterId(request.RequestId)
+	return runSimConcurrent(request, nil, signals)
 }
 
 // Threading does not work in WASM!
@@ -131,6 +145,25 @@ func RunBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.Progres
 	}()
 }
 
+// Like RunBulkSimAsync, but runs each raid sim of the bulk sim with the given runner.
+func RunBulkSimAsyncWithRunner(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string, runner ExternalRaidSimRunner) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalBulkResult: &proto.BulkSimResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		BulkSimWithRunner(signals, request, progress, runner)
+	}()
+}
+
 var runningInWasm = false
 
 func SetRunningInWasm() {
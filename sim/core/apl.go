@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"slices"
 	"time"
 
 	"github.com/wowsims/cata/sim/core/proto"
@@ -13,6 +14,10 @@ type APLRotation struct {
 	prepullActions []*APLAction
 	priorityList   []*APLAction
 
+	// Named values and action lists, which can be used anywhere in the rotation.
+	variables   []*aplVariable
+	actionLists []*aplActionList
+
 	// Action currently controlling this rotation (only used for certain actions, such as StrictSequence).
 	controllingActions []APLActionImpl
 
@@ -35,6 +40,7 @@ type APLRotation struct {
 	// Validation warnings that occur during proto parsing.
 	// We return these back to the user for display in the UI.
 	curValidations          []*proto.APLValidation
+	variableValidations     [][]*proto.APLValidation
 	prepullValidations      [][]*proto.APLValidation
 	priorityListValidations [][]*proto.APLValidation
 	uuidValidations         map[*proto.UUID][]*proto.APLValidation
@@ -42,6 +48,13 @@ type APLRotation struct {
 	// Maps indices in filtered sim lists to indices in configs.
 	prepullIdxMap      []int
 	priorityListIdxMap []int
+
+	// Used for hit counts, see apl_metrics.go.
+	lastExecutedEntry *aplItemMetrics
+	isIdle            bool
+	idleSince         time.Duration
+	idleEntry         *aplItemMetrics
+	idleTime          time.Duration
 }
 
 func (rot *APLRotation) ValidationMessage(log_level proto.LogLevel, message string, vals ...interface{}) {
@@ -94,11 +107,27 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 
 	rotation := &APLRotation{
 		unit:                    unit,
+		variableValidations:     make([][]*proto.APLValidation, len(config.Variables)),
 		prepullValidations:      make([][]*proto.APLValidation, len(config.PrepullActions)),
 		priorityListValidations: make([][]*proto.APLValidation, len(config.PriorityList)),
 		uuidValidations:         make(map[*proto.UUID][]*proto.APLValidation),
 	}
 
+	// Parse variables first, since their types are needed to parse any values
+	// that read them.
+	for i, variableConfig := range config.Variables {
+		rotation.doAndRecordWarnings(&rotation.variableValidations[i], false, func() {
+			if variable := rotation.newAPLVariable(variableConfig); variable != nil {
+				rotation.variables = append(rotation.variables, variable)
+			}
+		})
+	}
+
+	// Parse action lists
+	for _, listConfig := range config.ActionLists {
+		rotation.actionLists = append(rotation.actionLists, rotation.newAPLActionList(listConfig))
+	}
+
 	// Parse prepull actions
 	for i, prepullItem := range config.PrepullActions {
 		prepullIdx := i // Save to local variable for correct lambda capture behavior
@@ -141,7 +170,28 @@ func (unit *Unit) newAPLRotation(config *proto.APLRotation) *APLRotation {
 		})
 	}
 
+	for _, action := range rotation.priorityList {
+		action.metrics = &aplItemMetrics{}
+	}
+	for _, list := range rotation.actionLists {
+		for _, action := range list.actions {
+			action.metrics = &aplItemMetrics{}
+		}
+	}
+
 	// Finalize
+	for i, variable := range rotation.variables {
+		rotation.doAndRecordWarnings(&rotation.variableValidations[i], false, func() {
+			variable.value.Finalize(rotation)
+		})
+	}
+	for _, list := range rotation.actionLists {
+		for i, action := range list.actions {
+			rotation.doAndRecordWarnings(&list.validations[list.idxMap[i]], false, func() {
+				action.Finalize(rotation)
+			})
+		}
+	}
 	for i, action := range rotation.prepullActions {
 		rotation.doAndRecordWarnings(&rotation.prepullValidations[rotation.prepullIdxMap[i]], true, func() {
 			action.Finalize(rotation)
@@ -218,6 +268,13 @@ func (rot *APLRotation) getStats() *proto.APLStats {
 			action.impl.PostFinalize(rot)
 		})
 	}
+	for _, list := range rot.actionLists {
+		for i, action := range list.actions {
+			rot.doAndRecordWarnings(&list.validations[list.idxMap[i]], false, func() {
+				action.impl.PostFinalize(rot)
+			})
+		}
+	}
 
 	uuidValidationsArr := make([]*proto.UUIDValidations, len(rot.uuidValidations))
 	i := 0
@@ -237,15 +294,31 @@ func (rot *APLRotation) getStats() *proto.APLStats {
 			return &proto.APLActionStats{Validations: validations}
 		}),
 		UuidValidations: uuidValidationsArr,
+		Variables: MapSlice(rot.variableValidations, func(validations []*proto.APLValidation) *proto.APLActionStats {
+			return &proto.APLActionStats{Validations: validations}
+		}),
+		ActionLists: MapSlice(rot.actionLists, func(list *aplActionList) *proto.APLActionListStats {
+			return &proto.APLActionListStats{
+				PriorityList: MapSlice(list.validations, func(validations []*proto.APLValidation) *proto.APLActionStats {
+					return &proto.APLActionStats{Validations: validations}
+				}),
+			}
+		}),
 	}
 }
 
+// Returns all action objects from the priority list and action lists as an unstructured list.
 func (rot *APLRotation) allAPLActions() []*APLAction {
-	if rot == nil || rot.priorityList == nil {
+	if rot == nil {
 		return []*APLAction{}
 	}
 
-	return Flatten(MapSlice(rot.priorityList, func(action *APLAction) []*APLAction {
+	actions := slices.Clone(rot.priorityList)
+	for _, list := range rot.actionLists {
+		actions = append(actions, list.actions...)
+	}
+
+	return Flatten(MapSlice(actions, func(action *APLAction) []*APLAction {
 		// Check if action is nil before calling GetAllActions
 		if action == nil {
 			return []*APLAction{}
@@ -264,6 +337,11 @@ func (rot *APLRotation) reset(sim *Simulation) {
 	rot.inLoop = false
 	rot.interruptChannelIf = nil
 	rot.allowChannelRecastOnInterrupt = false
+	rot.lastExecutedEntry = nil
+	rot.isIdle = false
+	for _, variable := range rot.variables {
+		variable.reset()
+	}
 	for _, action := range rot.allAPLActions() {
 		action.impl.Reset(sim)
 	}
@@ -291,6 +369,7 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 
 	i := 0
 	apl.inLoop = true
+	apl.stopIdling(sim)
 
 	apl.unit.UpdatePosition(sim)
 	for nextAction := apl.getNextAction(sim); nextAction != nil; i, nextAction = i+1, apl.getNextAction(sim) {
@@ -298,9 +377,14 @@ func (apl *APLRotation) DoNextAction(sim *Simulation) {
 			panic(fmt.Sprintf("[USER_ERROR] Infinite loop detected, current action:\n%s", nextAction))
 		}
 
+		if nextAction.metrics != nil {
+			nextAction.metrics.executions++
+			apl.lastExecutedEntry = nextAction.metrics
+		}
 		nextAction.Execute(sim)
 	}
 	apl.inLoop = false
+	apl.startIdling(sim)
 
 	if sim.Log != nil && i == 0 {
 		apl.unit.Log(sim, "No available actions!")
@@ -323,13 +407,50 @@ func (apl *APLRotation) getNextAction(sim *Simulation) *APLAction {
 		return apl.controllingActions[len(apl.controllingActions)-1].GetNextAction(sim)
 	}
 
-	for _, action := range apl.priorityList {
-		if action.IsReady(sim) {
-			return action
+	nextAction, _ := apl.getNextActionInList(sim, apl.priorityList)
+	return nextAction
+}
+
+// Returns the first ready action in the list, following Call List and Run List
+// actions into named lists. Also returns whether evaluation should stop, which
+// is the case once an action is found or a Run List is reached.
+func (apl *APLRotation) getNextActionInList(sim *Simulation, actions []*APLAction) (*APLAction, bool) {
+	for _, action := range actions {
+		action.metrics.evaluations++
+		if action.condition != nil && !action.condition.GetBool(sim) {
+			continue
+		}
+		action.metrics.conditionTrue++
+
+		switch impl := action.impl.(type) {
+		case *APLActionSetVariable:
+			// Variables are set in passing, like simc's variable action.
+			action.metrics.executions++
+			action.Execute(sim)
+			continue
+		case *APLActionCallList:
+			if impl.list != nil {
+				action.metrics.executions++
+				if nextAction, done := apl.getNextActionInList(sim, impl.list.actions); done {
+					return nextAction, true
+				}
+			}
+			continue
+		case *APLActionRunList:
+			if impl.list != nil {
+				action.metrics.executions++
+				nextAction, _ := apl.getNextActionInList(sim, impl.list.actions)
+				return nextAction, true
+			}
+			continue
+		}
+
+		if action.impl.IsReady(sim) {
+			return action, true
 		}
 	}
 
-	return nil
+	return nil, false
 }
 
 func (apl *APLRotation) pushControllingAction(ca APLActionImpl) {
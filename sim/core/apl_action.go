@@ -9,6 +9,9 @@ import (
 type APLAction struct {
 	condition APLValue
 	impl      APLActionImpl
+
+	// Hit counts, only set for entries of the priority list and action lists.
+	metrics *aplItemMetrics
 }
 
 func (action *APLAction) Finalize(rot *APLRotation) {
@@ -172,9 +175,19 @@ func (rot *APLRotation) newAPLActionImpl(config *proto.APLAction) APLActionImpl
 	case *proto.APLAction_StrictSequence:
 		return rot.newActionStrictSequence(config.GetStrictSequence())
 
+	// Variables and action lists
+	case *proto.APLAction_SetVariable:
+		return rot.newActionSetVariable(config.GetSetVariable())
+	case *proto.APLAction_CallList:
+		return rot.newActionCallList(config.GetCallList())
+	case *proto.APLAction_RunList:
+		return rot.newActionRunList(config.GetRunList())
+
 	// Misc
 	case *proto.APLAction_ChangeTarget:
 		return rot.newActionChangeTarget(config.GetChangeTarget())
+	case *proto.APLAction_SelectTarget:
+		return rot.newActionSelectTarget(config.GetSelectTarget())
 	case *proto.APLAction_ActivateAura:
 		return rot.newActionActivateAura(config.GetActivateAura())
 	case *proto.APLAction_ActivateAuraWithStacks:
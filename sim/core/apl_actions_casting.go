@@ -9,8 +9,11 @@ import (
 
 type APLActionCastSpell struct {
 	defaultAPLActionImpl
-	spell  *Spell
-	target UnitReference
+	spell          *Spell
+	target         UnitReference
+	targetSelector *APLTargetSelector
+
+	nextTarget *Unit
 }
 
 func (rot *APLRotation) newActionCastSpell(config *proto.APLActionCastSpell) APLActionImpl {
@@ -23,15 +26,36 @@ func (rot *APLRotation) newActionCastSpell(config *proto.APLActionCastSpell) APL
 		return nil
 	}
 	return &APLActionCastSpell{
-		spell:  spell,
-		target: target,
+		spell:          spell,
+		target:         target,
+		targetSelector: rot.getActionTargetSelector(config.TargetSelector, config.Target),
 	}
 }
+func (action *APLActionCastSpell) GetAPLValues() []APLValue {
+	return action.targetSelector.GetAPLValues()
+}
+func (action *APLActionCastSpell) Reset(*Simulation) {
+	action.nextTarget = nil
+}
 func (action *APLActionCastSpell) IsReady(sim *Simulation) bool {
-	return action.spell.CanCastOrQueue(sim, action.target.Get()) && (!action.spell.Flags.Matches(SpellFlagMCD) || action.spell.Flags.Matches(SpellFlagReactive) || action.spell.Unit.GCD.IsReady(sim) || action.spell.Unit.Rotation.inSequence)
+	if action.targetSelector != nil {
+		action.nextTarget = action.targetSelector.Select(sim, sim.Encounter.ActiveTargets, func(target *Unit) bool {
+			return action.spell.CanCastOrQueue(sim, target)
+		})
+		if action.nextTarget == nil {
+			return false
+		}
+	} else if !action.spell.CanCastOrQueue(sim, action.target.Get()) {
+		return false
+	}
+	return !action.spell.Flags.Matches(SpellFlagMCD) || action.spell.Flags.Matches(SpellFlagReactive) || action.spell.Unit.GCD.IsReady(sim) || action.spell.Unit.Rotation.inSequence
 }
 func (action *APLActionCastSpell) Execute(sim *Simulation) {
-	action.spell.CastOrQueue(sim, action.target.Get())
+	if action.targetSelector != nil {
+		action.spell.CastOrQueue(sim, action.nextTarget)
+	} else {
+		action.spell.CastOrQueue(sim, action.target.Get())
+	}
 }
 func (action *APLActionCastSpell) String() string {
 	return fmt.Sprintf("Cast Spell(%s)", action.spell.ActionID)
@@ -69,18 +93,22 @@ func (action *APLActionCastFriendlySpell) String() string {
 
 type APLActionChannelSpell struct {
 	defaultAPLActionImpl
-	spell       *Spell
-	target      UnitReference
-	interruptIf APLValue
-	allowRecast bool
+	spell          *Spell
+	target         UnitReference
+	targetSelector *APLTargetSelector
+	interruptIf    APLValue
+	allowRecast    bool
+
+	nextTarget *Unit
 }
 
 func (rot *APLRotation) newActionChannelSpell(config *proto.APLActionChannelSpell) APLActionImpl {
 	interruptIf := rot.coerceTo(rot.newAPLValue(config.InterruptIf), proto.APLValueType_ValueTypeBool)
 	if interruptIf == nil {
 		return rot.newActionCastSpell(&proto.APLActionCastSpell{
-			SpellId: config.SpellId,
-			Target:  config.Target,
+			SpellId:        config.SpellId,
+			Target:         config.Target,
+			TargetSelector: config.TargetSelector,
 		})
 	}
 
@@ -98,20 +126,34 @@ func (rot *APLRotation) newActionChannelSpell(config *proto.APLActionChannelSpel
 	}
 
 	return &APLActionChannelSpell{
-		spell:       spell,
-		target:      target,
-		interruptIf: interruptIf,
-		allowRecast: config.AllowRecast,
+		spell:          spell,
+		target:         target,
+		targetSelector: rot.getActionTargetSelector(config.TargetSelector, config.Target),
+		interruptIf:    interruptIf,
+		allowRecast:    config.AllowRecast,
 	}
 }
 func (action *APLActionChannelSpell) GetAPLValues() []APLValue {
-	return []APLValue{action.interruptIf}
+	return append([]APLValue{action.interruptIf}, action.targetSelector.GetAPLValues()...)
+}
+func (action *APLActionChannelSpell) Reset(*Simulation) {
+	action.nextTarget = nil
 }
 func (action *APLActionChannelSpell) IsReady(sim *Simulation) bool {
+	if action.targetSelector != nil {
+		action.nextTarget = action.targetSelector.Select(sim, sim.Encounter.ActiveTargets, func(target *Unit) bool {
+			return action.spell.CanCastOrQueue(sim, target)
+		})
+		return action.nextTarget != nil
+	}
 	return action.spell.CanCastOrQueue(sim, action.target.Get())
 }
 func (action *APLActionChannelSpell) Execute(sim *Simulation) {
-	action.spell.CastOrQueue(sim, action.target.Get())
+	if action.targetSelector != nil {
+		action.spell.CastOrQueue(sim, action.nextTarget)
+	} else {
+		action.spell.CastOrQueue(sim, action.target.Get())
+	}
 	action.spell.Unit.Rotation.interruptChannelIf = action.interruptIf
 	action.spell.Unit.Rotation.allowChannelRecastOnInterrupt = action.allowRecast
 }
@@ -121,9 +163,10 @@ func (action *APLActionChannelSpell) String() string {
 
 type APLActionMultidot struct {
 	defaultAPLActionImpl
-	spell      *Spell
-	maxDots    int32
-	maxOverlap APLValue
+	spell          *Spell
+	maxDots        int32
+	maxOverlap     APLValue
+	targetSelector *APLTargetSelector
 
 	nextTarget *Unit
 }
@@ -142,7 +185,7 @@ func (rot *APLRotation) newActionMultidot(config *proto.APLActionMultidot) APLAc
 	}
 
 	maxDots := config.MaxDots
-	numTargets := unit.Env.GetNumTargets()
+	numTargets := int32(len(unit.Env.Encounter.TargetUnits))
 	if spell.Flags.Matches(SpellFlagHelpful) {
 		numTargets = int32(len(unit.Env.Raid.AllPlayerUnits))
 	}
@@ -151,14 +194,21 @@ func (rot *APLRotation) newActionMultidot(config *proto.APLActionMultidot) APLAc
 		maxDots = numTargets
 	}
 
+	targetSelector := rot.newTargetSelector(config.TargetSelector)
+	if targetSelector != nil && spell.Flags.Matches(SpellFlagHelpful) {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Target Selector only picks enemy targets, ignoring it for a helpful spell")
+		targetSelector = nil
+	}
+
 	return &APLActionMultidot{
-		spell:      spell,
-		maxDots:    maxDots,
-		maxOverlap: maxOverlap,
+		spell:          spell,
+		maxDots:        maxDots,
+		maxOverlap:     maxOverlap,
+		targetSelector: targetSelector,
 	}
 }
 func (action *APLActionMultidot) GetAPLValues() []APLValue {
-	return []APLValue{action.maxOverlap}
+	return append([]APLValue{action.maxOverlap}, action.targetSelector.GetAPLValues()...)
 }
 func (action *APLActionMultidot) Reset(*Simulation) {
 	action.nextTarget = nil
@@ -175,9 +225,17 @@ func (action *APLActionMultidot) IsReady(sim *Simulation) bool {
 				return true
 			}
 		}
+	} else if action.targetSelector != nil {
+		activeTargets := sim.Encounter.ActiveTargets
+		action.nextTarget = action.targetSelector.Select(sim, activeTargets[:min(int(action.maxDots), len(activeTargets))], func(target *Unit) bool {
+			dot := action.spell.Dot(target)
+			return (!dot.IsActive() || dot.RemainingDuration(sim) < maxOverlap) && action.spell.CanCastOrQueue(sim, target)
+		})
+		return action.nextTarget != nil
 	} else {
-		for i := int32(0); i < action.maxDots; i++ {
-			target := sim.Encounter.TargetUnits[i]
+		activeTargets := sim.Encounter.ActiveTargets
+		for _, activeTarget := range activeTargets[:min(int(action.maxDots), len(activeTargets))] {
+			target := &activeTarget.Unit
 			dot := action.spell.Dot(target)
 			if (!dot.IsActive() || dot.RemainingDuration(sim) < maxOverlap) && action.spell.CanCastOrQueue(sim, target) {
 				action.nextTarget = target
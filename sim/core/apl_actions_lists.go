@@ -0,0 +1,170 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+type aplActionList struct {
+	name    string
+	actions []*APLAction
+
+	validations [][]*proto.APLValidation
+	idxMap      []int // Maps indices in actions to indices in the config.
+}
+
+func (rot *APLRotation) newAPLActionList(config *proto.APLActionList) *aplActionList {
+	list := &aplActionList{
+		name:        config.Name,
+		validations: make([][]*proto.APLValidation, len(config.PriorityList)),
+	}
+
+	for i, aplItem := range config.PriorityList {
+		rot.doAndRecordWarnings(&list.validations[i], false, func() {
+			if !aplItem.Hide {
+				action := rot.newAPLAction(aplItem.Action)
+				if action != nil {
+					list.actions = append(list.actions, action)
+					list.idxMap = append(list.idxMap, i)
+				}
+			}
+		})
+	}
+
+	return list
+}
+
+func (rot *APLRotation) getActionList(name string) *aplActionList {
+	for _, list := range rot.actionLists {
+		if list.name == name {
+			return list
+		}
+	}
+	return nil
+}
+
+// Whether the named list can end up invoking itself through Call List or Run
+// List actions, which would loop forever.
+func (rot *APLRotation) actionListInvokesItself(name string) bool {
+	visited := make(map[string]bool)
+	var invokes func(list *aplActionList) bool
+	invokes = func(list *aplActionList) bool {
+		for _, action := range list.actions {
+			for _, subaction := range action.GetAllActions() {
+				call := getActionListCall(subaction)
+				if call == nil {
+					continue
+				} else if call.listName == name {
+					return true
+				} else if visited[call.listName] {
+					continue
+				}
+
+				visited[call.listName] = true
+				if callee := rot.getActionList(call.listName); callee != nil && invokes(callee) {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	list := rot.getActionList(name)
+	return list != nil && invokes(list)
+}
+
+// Shared implementation of the Call List and Run List actions. These are
+// resolved by APLRotation.getNextActionInList when part of a priority list,
+// and only use IsReady / Execute when nested in other actions, e.g. sequences.
+type aplActionListCall struct {
+	defaultAPLActionImpl
+	rot      *APLRotation
+	listName string
+	list     *aplActionList
+
+	nextAction *APLAction
+}
+
+func (rot *APLRotation) newActionListCall(listName string) aplActionListCall {
+	return aplActionListCall{
+		rot:      rot,
+		listName: listName,
+	}
+}
+
+func getActionListCall(action *APLAction) *aplActionListCall {
+	switch impl := action.impl.(type) {
+	case *APLActionCallList:
+		return &impl.aplActionListCall
+	case *APLActionRunList:
+		return &impl.aplActionListCall
+	}
+	return nil
+}
+
+func (call *aplActionListCall) Finalize(rot *APLRotation) {
+	list := rot.getActionList(call.listName)
+	if list == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "No action list with name: '%s'", call.listName)
+		return
+	}
+	if rot.actionListInvokesItself(call.listName) {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Action list '%s' invokes itself, ignoring this action", call.listName)
+		return
+	}
+	call.list = list
+}
+func (call *aplActionListCall) IsReady(sim *Simulation) bool {
+	if call.list == nil {
+		return false
+	}
+	call.nextAction, _ = call.rot.getNextActionInList(sim, call.list.actions)
+	return call.nextAction != nil
+}
+func (call *aplActionListCall) Execute(sim *Simulation) {
+	call.nextAction.metrics.executions++
+	call.nextAction.Execute(sim)
+}
+
+type APLActionCallList struct {
+	aplActionListCall
+}
+
+func (rot *APLRotation) newActionCallList(config *proto.APLActionCallList) APLActionImpl {
+	if rot.parsingPrepull {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Call Action List is not supported in the prepull")
+		return nil
+	}
+	if config.ListName == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Call Action List must provide a list name")
+		return nil
+	}
+	return &APLActionCallList{
+		aplActionListCall: rot.newActionListCall(config.ListName),
+	}
+}
+func (action *APLActionCallList) String() string {
+	return fmt.Sprintf("Call Action List(%s)", action.listName)
+}
+
+type APLActionRunList struct {
+	aplActionListCall
+}
+
+func (rot *APLRotation) newActionRunList(config *proto.APLActionRunList) APLActionImpl {
+	if rot.parsingPrepull {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Run Action List is not supported in the prepull")
+		return nil
+	}
+	if config.ListName == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Run Action List must provide a list name")
+		return nil
+	}
+	return &APLActionRunList{
+		aplActionListCall: rot.newActionListCall(config.ListName),
+	}
+}
+func (action *APLActionRunList) String() string {
+	return fmt.Sprintf("Run Action List(%s)", action.listName)
+}
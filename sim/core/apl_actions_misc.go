@@ -35,6 +35,45 @@ func (action *APLActionChangeTarget) String() string {
 	return fmt.Sprintf("Change Target(%s)", action.newTarget.Get().Label)
 }
 
+type APLActionSelectTarget struct {
+	defaultAPLActionImpl
+	unit     *Unit
+	selector *APLTargetSelector
+
+	nextTarget *Unit
+}
+
+func (rot *APLRotation) newActionSelectTarget(config *proto.APLActionSelectTarget) APLActionImpl {
+	selector := rot.newTargetSelector(config.Selector)
+	if selector == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Select Target must provide a value or a condition")
+		return nil
+	}
+	return &APLActionSelectTarget{
+		unit:     rot.unit,
+		selector: selector,
+	}
+}
+func (action *APLActionSelectTarget) GetAPLValues() []APLValue {
+	return action.selector.GetAPLValues()
+}
+func (action *APLActionSelectTarget) Reset(*Simulation) {
+	action.nextTarget = nil
+}
+func (action *APLActionSelectTarget) IsReady(sim *Simulation) bool {
+	action.nextTarget = action.selector.Select(sim, sim.Encounter.ActiveTargets, func(*Unit) bool { return true })
+	return action.nextTarget != nil && action.nextTarget != action.unit.CurrentTarget
+}
+func (action *APLActionSelectTarget) Execute(sim *Simulation) {
+	if sim.Log != nil {
+		action.unit.Log(sim, "Changing target to %s", action.nextTarget.Label)
+	}
+	action.unit.CurrentTarget = action.nextTarget
+}
+func (action *APLActionSelectTarget) String() string {
+	return fmt.Sprintf("Select Target(%s)", action.selector)
+}
+
 type APLActionCancelAura struct {
 	defaultAPLActionImpl
 	aura *Aura
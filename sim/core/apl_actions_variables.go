@@ -0,0 +1,44 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+type APLActionSetVariable struct {
+	defaultAPLActionImpl
+	variable *aplVariable
+	value    APLValue
+}
+
+func (rot *APLRotation) newActionSetVariable(config *proto.APLActionSetVariable) APLActionImpl {
+	variable := rot.getVariable(config.Name)
+	if variable == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "No variable with name: '%s'", config.Name)
+		return nil
+	}
+
+	value := rot.coerceTo(rot.newAPLValue(config.Value), variable.value.Type())
+	if value == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Set Variable must provide a value")
+		return nil
+	}
+
+	return &APLActionSetVariable{
+		variable: variable,
+		value:    value,
+	}
+}
+func (action *APLActionSetVariable) GetAPLValues() []APLValue {
+	return []APLValue{action.value}
+}
+func (action *APLActionSetVariable) IsReady(sim *Simulation) bool {
+	return true
+}
+func (action *APLActionSetVariable) Execute(sim *Simulation) {
+	action.variable.set(sim, action.value)
+}
+func (action *APLActionSetVariable) String() string {
+	return fmt.Sprintf("Set Variable(%s = %s)", action.variable.name, action.value)
+}
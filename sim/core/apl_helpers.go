@@ -195,19 +195,43 @@ func (rot *APLRotation) GetTargetAPLSpell(spellId *proto.ActionID, targetUnit Un
 	return spell
 }
 
-func (rot *APLRotation) GetAPLDot(targetUnit UnitReference, spellId *proto.ActionID) *Dot {
+// Struct for handling dot references, to account for dots on the current
+// target, which can change dynamically.
+type DotReference struct {
+	fixedDot *Dot
+
+	curTargetSource *Unit
+	spell           *Spell
+}
+
+func (dr *DotReference) Get() *Dot {
+	if dr.fixedDot != nil {
+		return dr.fixedDot
+	} else if dr.curTargetSource != nil {
+		return dr.spell.Dot(dr.curTargetSource.CurrentTarget)
+	} else {
+		return nil
+	}
+}
+
+func (rot *APLRotation) GetAPLDot(targetUnit UnitReference, spellId *proto.ActionID) DotReference {
 	spell := rot.GetAPLSpell(spellId)
 
 	if spell == nil {
-		return nil
+		return DotReference{}
 	} else if spell.AOEDot() != nil {
-		return spell.AOEDot()
+		return DotReference{fixedDot: spell.AOEDot()}
+	} else if targetUnit.curTargetSource != nil && spell.CurDot() != nil {
+		return DotReference{
+			curTargetSource: targetUnit.curTargetSource,
+			spell:           spell,
+		}
 	} else {
 		target := targetUnit.Get()
 		if target != nil {
-			return spell.Dot(target)
+			return DotReference{fixedDot: spell.Dot(target)}
 		} else {
-			return spell.CurDot()
+			return DotReference{fixedDot: spell.CurDot()}
 		}
 	}
 }
@@ -0,0 +1,128 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func callList(name string, condition *proto.APLValue) *proto.APLListItem {
+	return &proto.APLListItem{Action: &proto.APLAction{
+		Condition: condition,
+		Action:    &proto.APLAction_CallList{CallList: &proto.APLActionCallList{ListName: name}},
+	}}
+}
+
+func runList(name string) *proto.APLListItem {
+	return &proto.APLListItem{Action: &proto.APLAction{
+		Action: &proto.APLAction_RunList{RunList: &proto.APLActionRunList{ListName: name}},
+	}}
+}
+
+func variable(name string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Variable{Variable: &proto.APLValueVariable{Name: name}}}
+}
+
+func constValue(val string) *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+}
+
+// Moves the whole priority list of the rotation into an action list named 'main'.
+func withMainList(rsr *proto.RaidSimRequest, priorityList ...*proto.APLListItem) *proto.RaidSimRequest {
+	player := rsr.Raid.Parties[0].Players[0]
+	player.Rotation.ActionLists = []*proto.APLActionList{{
+		Name:         "main",
+		PriorityList: player.Rotation.PriorityList,
+	}}
+	player.Rotation.PriorityList = priorityList
+	return rsr
+}
+
+func TestAPLActionLists(t *testing.T) {
+	baseDps := core.RunRaidSim(makeTestCase(getTestPlayerMM())).RaidMetrics.Dps.Avg
+
+	for name, rsr := range map[string]*proto.RaidSimRequest{
+		"Call List": withMainList(makeTestCase(getTestPlayerMM()), callList("main", nil)),
+		"Run List":  withMainList(makeTestCase(getTestPlayerMM()), runList("main"), callList("main", nil)),
+	} {
+		if dps := core.RunRaidSim(rsr).RaidMetrics.Dps.Avg; dps != baseDps {
+			t.Errorf("%s: expected the same %0.3f DPS as the flat list, got %0.3f", name, baseDps, dps)
+		}
+	}
+}
+
+func TestAPLVariables(t *testing.T) {
+	runWithDisableAt := func(disableAt string) float64 {
+		rsr := withMainList(makeTestCase(getTestPlayerMM()),
+			&proto.APLListItem{Action: &proto.APLAction{
+				Condition: &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+					Op:  proto.APLValueCompare_OpGe,
+					Lhs: &proto.APLValue{Value: &proto.APLValue_CurrentTime{CurrentTime: &proto.APLValueCurrentTime{}}},
+					Rhs: constValue(disableAt),
+				}}},
+				Action: &proto.APLAction_SetVariable{SetVariable: &proto.APLActionSetVariable{
+					Name:  "enabled",
+					Value: constValue("false"),
+				}},
+			}},
+			callList("main", variable("enabled")),
+		)
+		rsr.Raid.Parties[0].Players[0].Rotation.Variables = []*proto.APLVariable{{
+			Name:  "enabled",
+			Value: constValue("true"),
+		}}
+		return core.RunRaidSim(rsr).RaidMetrics.Dps.Avg
+	}
+
+	alwaysDps := runWithDisableAt("1000s")
+	halfDps := runWithDisableAt("150s")
+	neverDps := runWithDisableAt("0s")
+
+	if alwaysDps != core.RunRaidSim(makeTestCase(getTestPlayerMM())).RaidMetrics.Dps.Avg {
+		t.Errorf("Expected a variable that is never overwritten to keep its declared value")
+	}
+	if neverDps > 0.9*alwaysDps {
+		t.Errorf("Expected overwriting the variable to disable the rotation, got %0.0f DPS instead of %0.0f", neverDps, alwaysDps)
+	}
+	// Variables are reset every iteration, so disabling the rotation half way
+	// should land roughly half way between the two extremes.
+	if spread := alwaysDps - neverDps; halfDps < neverDps+0.3*spread || halfDps > alwaysDps-0.3*spread {
+		t.Errorf("Expected DPS roughly half way between %0.0f and %0.0f, got %0.0f", neverDps, alwaysDps, halfDps)
+	}
+}
+
+func TestAPLActionListLoopDetection(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	player := rsr.Raid.Parties[0].Players[0]
+	player.Rotation.ActionLists = []*proto.APLActionList{
+		{Name: "a", PriorityList: []*proto.APLListItem{callList("b", nil)}},
+		{Name: "b", PriorityList: []*proto.APLListItem{runList("a")}},
+	}
+	player.Rotation.PriorityList = append(player.Rotation.PriorityList, callList("a", nil), callList("missing", nil))
+
+	result := core.ComputeStats(&proto.ComputeStatsRequest{Raid: rsr.Raid, Encounter: rsr.Encounter})
+	stats := result.RaidStats.Parties[0].Players[0].RotationStats
+
+	hasValidation := func(actionStats *proto.APLActionStats, text string) bool {
+		for _, validation := range actionStats.Validations {
+			if strings.Contains(validation.Validation, text) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if !hasValidation(stats.ActionLists[0].PriorityList[0], "invokes itself") || !hasValidation(stats.ActionLists[1].PriorityList[0], "invokes itself") {
+		t.Errorf("Expected both lists to be flagged as invoking themselves, got %v", stats.ActionLists)
+	}
+	if numItems := len(stats.PriorityList); !hasValidation(stats.PriorityList[numItems-1], "No action list with name: 'missing'") {
+		t.Errorf("Expected a warning for the missing list, got %v", stats.PriorityList[numItems-1])
+	}
+
+	// Loops are ignored rather than hanging the sim.
+	if result := core.RunRaidSim(rsr); result.Error != nil {
+		t.Errorf("Sim failed: %s", result.Error.Message)
+	}
+}
@@ -0,0 +1,100 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Hit counts for a priority list entry, summed over all iterations. Similar to
+// the sample sequence of simc APLs, these show which entries fire and which
+// are stuck on their condition or on the action itself.
+type aplItemMetrics struct {
+	evaluations   int
+	conditionTrue int
+	executions    int
+	waitTime      time.Duration
+}
+
+func (metrics *aplItemMetrics) toProto(validations []*proto.APLValidation, numIterations float64) *proto.APLActionStats {
+	itemStats := &proto.APLActionStats{Validations: validations}
+	if metrics != nil {
+		itemStats.EvaluationsAvg = float64(metrics.evaluations) / numIterations
+		itemStats.ConditionTrueAvg = float64(metrics.conditionTrue) / numIterations
+		itemStats.ExecutionsAvg = float64(metrics.executions) / numIterations
+		itemStats.WaitSecondsAvg = metrics.waitTime.Seconds() / numIterations
+	}
+	return itemStats
+}
+
+// Tracks time during which the rotation could act, but didn't. This is
+// attributed to the entry controlling the rotation (e.g. a Wait action), or
+// to the rotation as a whole if nothing was available.
+func (rot *APLRotation) startIdling(sim *Simulation) {
+	unit := rot.unit
+	if !unit.GCD.IsReady(sim) || unit.Hardcast.Expires > sim.CurrentTime || unit.ChanneledDot != nil {
+		return
+	}
+
+	rot.isIdle = true
+	rot.idleSince = sim.CurrentTime
+	rot.idleEntry = nil
+	if len(rot.controllingActions) > 0 {
+		rot.idleEntry = rot.lastExecutedEntry
+	}
+}
+
+func (rot *APLRotation) stopIdling(sim *Simulation) {
+	if !rot.isIdle {
+		return
+	}
+
+	rot.isIdle = false
+	idleTime := max(sim.CurrentTime-rot.idleSince, 0)
+	if rot.idleEntry != nil {
+		rot.idleEntry.waitTime += idleTime
+	} else {
+		rot.idleTime += idleTime
+	}
+}
+
+func (rot *APLRotation) doneIteration(sim *Simulation) {
+	rot.stopIdling(sim)
+	rot.lastExecutedEntry = nil
+}
+
+// Returns the rotation's validations along with its hit counts, averaged over
+// numIterations. Unlike getStats, this doesn't re-validate or include the
+// UUID validations, which are unordered. Prepull validations are left out too,
+// since running the prepull adds warnings which differ between runs.
+func (rot *APLRotation) getMetrics(numIterations int) *proto.APLStats {
+	n := float64(max(numIterations, 1))
+
+	// Entries which failed to parse have no metrics.
+	listStats := func(actions []*APLAction, idxMap []int, validations [][]*proto.APLValidation) []*proto.APLActionStats {
+		metrics := make([]*aplItemMetrics, len(validations))
+		for i, action := range actions {
+			metrics[idxMap[i]] = action.metrics
+		}
+		itemStats := make([]*proto.APLActionStats, len(validations))
+		for i := range itemStats {
+			itemStats[i] = metrics[i].toProto(validations[i], n)
+		}
+		return itemStats
+	}
+
+	validationsOnly := func(validations []*proto.APLValidation) *proto.APLActionStats {
+		return &proto.APLActionStats{Validations: validations}
+	}
+
+	return &proto.APLStats{
+		PriorityList: listStats(rot.priorityList, rot.priorityListIdxMap, rot.priorityListValidations),
+		Variables:    MapSlice(rot.variableValidations, validationsOnly),
+		ActionLists: MapSlice(rot.actionLists, func(list *aplActionList) *proto.APLActionListStats {
+			return &proto.APLActionListStats{
+				PriorityList: listStats(list.actions, list.idxMap, list.validations),
+			}
+		}),
+		IdleSecondsAvg: rot.idleTime.Seconds() / n,
+	}
+}
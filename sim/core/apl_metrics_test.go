@@ -0,0 +1,59 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func TestAPLRotationMetrics(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	player := rsr.Raid.Parties[0].Players[0]
+	waitEntry := &proto.APLListItem{Action: &proto.APLAction{
+		Condition: &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+			Op:  proto.APLValueCompare_OpLt,
+			Lhs: &proto.APLValue{Value: &proto.APLValue_CurrentTime{CurrentTime: &proto.APLValueCurrentTime{}}},
+			Rhs: constValue("1s"),
+		}}},
+		Action: &proto.APLAction_Wait{Wait: &proto.APLActionWait{Duration: constValue("5s")}},
+	}}
+	player.Rotation.PriorityList = append([]*proto.APLListItem{waitEntry}, player.Rotation.PriorityList...)
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	stats := result.RaidMetrics.Parties[0].Players[0].RotationStats
+	if stats == nil || len(stats.PriorityList) != len(player.Rotation.PriorityList) {
+		t.Fatalf("Expected stats for each of the %d priority list entries, got %v", len(player.Rotation.PriorityList), stats)
+	}
+
+	if wait := stats.PriorityList[0]; wait.ExecutionsAvg != 1 || wait.WaitSecondsAvg < 4.5 || wait.WaitSecondsAvg > 5 {
+		t.Errorf("Expected the wait to execute once per iteration and wait almost 5s, got %0.2f executions and %0.2fs", wait.ExecutionsAvg, wait.WaitSecondsAvg)
+	}
+	if stats.PriorityList[1].EvaluationsAvg == 0 {
+		t.Errorf("Expected the entries after the wait to be evaluated")
+	}
+
+	totalExecutions := 0.0
+	for i, itemStats := range stats.PriorityList {
+		if itemStats.ExecutionsAvg > itemStats.ConditionTrueAvg || itemStats.ConditionTrueAvg > itemStats.EvaluationsAvg {
+			t.Errorf("Entry %d: expected executions <= condition true <= evaluations, got %0.2f, %0.2f and %0.2f",
+				i, itemStats.ExecutionsAvg, itemStats.ConditionTrueAvg, itemStats.EvaluationsAvg)
+		}
+		totalExecutions += itemStats.ExecutionsAvg
+	}
+	if totalExecutions < 10 {
+		t.Errorf("Expected the rotation to execute actions, got %0.2f executions per iteration", totalExecutions)
+	}
+	if stats.IdleSecondsAvg < 0 || stats.IdleSecondsAvg > 300 {
+		t.Errorf("Expected idle time within the fight duration, got %0.2fs", stats.IdleSecondsAvg)
+	}
+
+	// The concurrent sim averages the stats of its runs.
+	concurrentStats := core.RunRaidSimConcurrent(rsr).RaidMetrics.Parties[0].Players[0].RotationStats
+	if concurrentStats == nil || concurrentStats.PriorityList[0].ExecutionsAvg < 0.999 || concurrentStats.PriorityList[0].ExecutionsAvg > 1.001 {
+		t.Errorf("Expected the concurrent sim to combine rotation stats, got %v", concurrentStats)
+	}
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Picks one of the active enemy targets, like simc's target_if. The value and
+// condition are evaluated with each candidate as the current target, so e.g.
+// a Dot Remaining Time without a target refers to the candidate.
+type APLTargetSelector struct {
+	unit      *Unit
+	mode      proto.APLTargetSelector_SelectionMode
+	value     APLValue
+	condition APLValue
+}
+
+// Returns nil if the config has neither a value nor a condition, since the
+// UI creates empty selectors.
+func (rot *APLRotation) newTargetSelector(config *proto.APLTargetSelector) *APLTargetSelector {
+	if config == nil {
+		return nil
+	}
+
+	value := rot.coerceTo(rot.newAPLValue(config.Value), proto.APLValueType_ValueTypeFloat)
+	condition := rot.coerceTo(rot.newAPLValue(config.Condition), proto.APLValueType_ValueTypeBool)
+	if value == nil && condition == nil {
+		return nil
+	}
+
+	return &APLTargetSelector{
+		unit:      rot.unit,
+		mode:      config.Mode,
+		value:     value,
+		condition: condition,
+	}
+}
+
+// Selectors of casting actions take precedence over their target.
+func (rot *APLRotation) getActionTargetSelector(config *proto.APLTargetSelector, targetConfig *proto.UnitReference) *APLTargetSelector {
+	selector := rot.newTargetSelector(config)
+	if selector == nil {
+		return nil
+	}
+	if rot.parsingPrepull {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Target Selector is not supported in the prepull, ignoring it")
+		return nil
+	}
+	if targetConfig != nil && targetConfig.Type != proto.UnitReference_Unknown {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Target is ignored when a Target Selector is set")
+	}
+	return selector
+}
+
+func (selector *APLTargetSelector) GetAPLValues() []APLValue {
+	if selector == nil {
+		return nil
+	}
+	return FilterSlice([]APLValue{selector.value, selector.condition}, func(value APLValue) bool { return value != nil })
+}
+
+// Returns the best of the candidates which pass both the condition and
+// canUse, or nil if there are none. Candidates must be ordered by target
+// index, so ties go to the lowest index.
+func (selector *APLTargetSelector) Select(sim *Simulation, candidates []*Target, canUse func(*Unit) bool) *Unit {
+	unit := selector.unit
+	curTarget := unit.CurrentTarget
+
+	var best *Unit
+	bestValue := 0.0
+	for _, target := range candidates {
+		candidate := &target.Unit
+
+		unit.CurrentTarget = candidate
+		passes := selector.condition == nil || selector.condition.GetBool(sim)
+		candidateValue := 0.0
+		if passes && selector.value != nil {
+			candidateValue = selector.value.GetFloat(sim)
+		}
+		unit.CurrentTarget = curTarget
+
+		if !passes || !canUse(candidate) {
+			continue
+		}
+		if selector.value == nil {
+			return candidate
+		}
+		if best == nil || selector.isBetter(candidateValue, bestValue) {
+			best = candidate
+			bestValue = candidateValue
+		}
+	}
+	return best
+}
+
+func (selector *APLTargetSelector) isBetter(value float64, bestValue float64) bool {
+	if selector.mode == proto.APLTargetSelector_SelectHighest {
+		return value > bestValue
+	}
+	return value < bestValue
+}
+
+func (selector *APLTargetSelector) String() string {
+	if selector.value == nil {
+		return fmt.Sprintf("First(%s)", selector.condition)
+	}
+
+	mode := "Lowest"
+	if selector.mode == proto.APLTargetSelector_SelectHighest {
+		mode = "Highest"
+	}
+	if selector.condition == nil {
+		return fmt.Sprintf("%s(%s)", mode, selector.value)
+	}
+	return fmt.Sprintf("%s(%s, if=%s)", mode, selector.value, selector.condition)
+}
@@ -0,0 +1,76 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+var rakeID = &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 1822}}
+
+func rakeIsActive() *proto.APLValue {
+	return &proto.APLValue{Value: &proto.APLValue_DotIsActive{DotIsActive: &proto.APLValueDotIsActive{SpellId: rakeID}}}
+}
+
+// Returns the number of Rake casts on each target.
+func rakeCastsByTarget(t *testing.T, condition *proto.APLValue, selector *proto.APLTargetSelector) []int32 {
+	rsr := makeTestCase(getTestPlayerFeralCat())
+	rsr.Encounter.Targets = []*proto.Target{core.NewDefaultTarget(), core.NewDefaultTarget(), core.NewDefaultTarget()}
+	rsr.SimOptions.Iterations = 10
+	player := rsr.Raid.Parties[0].Players[0]
+	player.Rotation.PriorityList = append([]*proto.APLListItem{{Action: &proto.APLAction{
+		Condition: condition,
+		Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{
+			SpellId:        rakeID,
+			TargetSelector: selector,
+		}},
+	}}}, player.Rotation.PriorityList...)
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	casts := make([]int32, len(rsr.Encounter.Targets))
+	for _, action := range result.RaidMetrics.Parties[0].Players[0].Actions {
+		if action.Id.GetSpellId() != 1822 {
+			continue
+		}
+		for _, targetMetrics := range action.Targets {
+			// Targets come first in the unit indices.
+			if int(targetMetrics.UnitIndex) < len(casts) {
+				casts[targetMetrics.UnitIndex] += targetMetrics.Casts
+			}
+		}
+	}
+	return casts
+}
+
+func TestAPLTargetSelectorSpreadsDots(t *testing.T) {
+	casts := rakeCastsByTarget(t, nil, &proto.APLTargetSelector{
+		Condition: &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: rakeIsActive()}}},
+	})
+	for i, numCasts := range casts {
+		if numCasts == 0 {
+			t.Errorf("Expected Rake to be cast on target %d, got casts %v", i, casts)
+		}
+	}
+}
+
+func TestAPLCountTargets(t *testing.T) {
+	// Without a value, the first target without the dot is picked, so limiting
+	// the number of dotted targets to 2 leaves the last target untouched.
+	casts := rakeCastsByTarget(t,
+		&proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+			Op:  proto.APLValueCompare_OpLt,
+			Lhs: &proto.APLValue{Value: &proto.APLValue_CountTargets{CountTargets: &proto.APLValueCountTargets{Condition: rakeIsActive()}}},
+			Rhs: constValue("2"),
+		}}},
+		&proto.APLTargetSelector{
+			Condition: &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: rakeIsActive()}}},
+		})
+	if casts[1] == 0 || casts[2] != 0 {
+		t.Errorf("Expected Rake on the first two targets only, got casts %v", casts)
+	}
+}
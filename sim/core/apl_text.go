@@ -0,0 +1,1092 @@
+package core
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	goproto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Text format for APL rotations, which is easier to read, write and diff by
+// hand than protojson. For example:
+//
+//	variable burst = 20s
+//
+//	prepull -1.5s: cast_spell(spell:686)
+//
+//	# Keep Corruption up.
+//	multidot(spell:172, max_dots=3, max_overlap=0ms)
+//	cast_spell(spell:686) if aura_remaining_time(spell:17941) > 1s and not is_execute_phase(E25)
+//	call_list("aoe") if number_targets >= 3
+//
+//	list aoe:
+//		cast_spell(spell:1949)
+//	end
+//
+// Actions and values are written as calls named after their proto fields. The
+// first argument may be positional, all others are named. Messages without any
+// arguments can leave out the parentheses. Constants, and, or, not, comparisons
+// and arithmetic are written as expressions, and action IDs and unit references
+// have short forms (spell:686/1, item:58091, other:OtherActionPotion, Target:1).
+// Tunable constants are written as calls, e.g.
+// const("3s", tunable={name="refresh", max=5}).
+// Notes are written as comments before the item. UUIDs are not kept.
+//
+// Lines which reference spells or items end with a comment naming them, when
+// their names are known, e.g.
+//
+//	cast_spell(spell:686) if aura_remaining_time(spell:17941) > 1s  # Shadow Bolt, Shadow Trance
+//
+// Comments after an item are ignored when parsing.
+
+// APLRotationFromText parses the text format described above.
+func APLRotationFromText(text string) (rot *proto.APLRotation, err error) {
+	tokens, err := lexAPLText(text)
+	if err != nil {
+		return nil, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			if parseErr, ok := r.(aplTextError); ok {
+				rot, err = nil, parseErr
+				return
+			}
+			panic(r)
+		}
+	}()
+
+	parser := &aplTextParser{tokens: tokens}
+	return parser.parseRotation(), nil
+}
+
+// APLRotationToText prints rot in the text format described above, such that
+// APLRotationFromText returns an equal rotation (apart from UUIDs).
+func APLRotationToText(rot *proto.APLRotation) string {
+	var sb strings.Builder
+	startSection := func() {
+		if sb.Len() > 0 {
+			sb.WriteString("\n")
+		}
+	}
+
+	rotMessage := rot.ProtoReflect()
+	rotFields := rotMessage.Descriptor().Fields()
+	for i := 0; i < rotFields.Len(); i++ {
+		if fd := rotFields.Get(i); rotMessage.Has(fd) && !aplTextRotationStatements[fd.Name()] {
+			fmt.Fprintf(&sb, "%s = %s\n", fd.Name(), formatAPLTextField(fd, rotMessage.Get(fd)))
+		}
+	}
+
+	if len(rot.Variables) > 0 {
+		startSection()
+		for _, variable := range rot.Variables {
+			fmt.Fprintf(&sb, "variable %s = %s%s\n", formatAPLTextName(variable.Name), formatAPLTextValue(variable.Value), formatAPLTextNamesComment(variable.ProtoReflect()))
+		}
+	}
+
+	if len(rot.PrepullActions) > 0 {
+		startSection()
+		for _, prepullAction := range rot.PrepullActions {
+			if prepullAction.Hide {
+				sb.WriteString("hidden ")
+			}
+			sb.WriteString("prepull")
+			if prepullAction.DoAtValue != nil {
+				sb.WriteString(" " + formatAPLTextValue(prepullAction.DoAtValue))
+			}
+			sb.WriteString(": " + formatAPLTextAction(prepullAction.Action) + formatAPLTextNamesComment(prepullAction.ProtoReflect()) + "\n")
+		}
+	}
+
+	writeListItems := func(items []*proto.APLListItem, indent string) {
+		for _, item := range items {
+			if item.Notes != "" {
+				for _, line := range strings.Split(item.Notes, "\n") {
+					if line == "" {
+						sb.WriteString(indent + "#\n")
+					} else {
+						sb.WriteString(indent + "# " + line + "\n")
+					}
+				}
+			}
+			sb.WriteString(indent)
+			if item.Hide {
+				sb.WriteString("hidden ")
+			}
+			sb.WriteString(formatAPLTextAction(item.Action) + formatAPLTextNamesComment(item.Action.ProtoReflect()) + "\n")
+		}
+	}
+
+	if len(rot.PriorityList) > 0 {
+		startSection()
+		writeListItems(rot.PriorityList, "")
+	}
+
+	for _, actionList := range rot.ActionLists {
+		startSection()
+		sb.WriteString("list " + formatAPLTextName(actionList.Name) + ":\n")
+		writeListItems(actionList.PriorityList, "\t")
+		sb.WriteString("end\n")
+	}
+
+	return sb.String()
+}
+
+// Rotation fields with their own statements. All other fields are written as
+// 'name = value'.
+var aplTextRotationStatements = map[protoreflect.Name]bool{
+	"variables":       true,
+	"prepull_actions": true,
+	"priority_list":   true,
+	"action_lists":    true,
+}
+
+var aplTextKeywords = map[string]bool{
+	"and":      true,
+	"or":       true,
+	"not":      true,
+	"if":       true,
+	"true":     true,
+	"false":    true,
+	"hidden":   true,
+	"prepull":  true,
+	"variable": true,
+	"list":     true,
+	"end":      true,
+}
+
+// Matches constants which can be written without quotes, e.g. 3, -1.5s or 20%.
+var aplTextLiteralRegex = regexp.MustCompile(`^-?[0-9]+(\.[0-9]+)?[a-zA-Z%]*$`)
+
+var aplTextIdentRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
+
+var aplTextCmpOps = map[proto.APLValueCompare_ComparisonOperator]string{
+	proto.APLValueCompare_OpEq: "==",
+	proto.APLValueCompare_OpNe: "!=",
+	proto.APLValueCompare_OpGe: ">=",
+	proto.APLValueCompare_OpGt: ">",
+	proto.APLValueCompare_OpLe: "<=",
+	proto.APLValueCompare_OpLt: "<",
+}
+
+var aplTextMathOps = map[proto.APLValueMath_MathOperator]string{
+	proto.APLValueMath_OpAdd: "+",
+	proto.APLValueMath_OpSub: "-",
+	proto.APLValueMath_OpMul: "*",
+	proto.APLValueMath_OpDiv: "/",
+}
+
+// Operator precedence, from loosest to tightest binding.
+const (
+	aplTextPrecedenceOr = iota + 1
+	aplTextPrecedenceAnd
+	aplTextPrecedenceNot
+	aplTextPrecedenceCmp
+	aplTextPrecedenceSum
+	aplTextPrecedenceProduct
+	aplTextPrecedencePrimary
+)
+
+func formatAPLTextName(name string) string {
+	if aplTextIdentRegex.MatchString(name) && !aplTextKeywords[name] {
+		return name
+	}
+	return strconv.Quote(name)
+}
+
+func formatAPLTextAction(action *proto.APLAction) string {
+	if action == nil {
+		return "{}"
+	}
+
+	actionMessage := action.ProtoReflect()
+	str := "{}"
+	if fd := actionMessage.WhichOneof(actionMessage.Descriptor().Oneofs().ByName("action")); fd != nil {
+		str = formatAPLTextCall(fd, actionMessage.Get(fd).Message())
+	}
+	if action.Condition != nil {
+		str += " if " + formatAPLTextValue(action.Condition)
+	}
+	return str
+}
+
+func formatAPLTextValue(value *proto.APLValue) string {
+	str, _ := formatAPLTextExpr(value)
+	return str
+}
+
+// Returns value as an expression, along with the precedence of its outermost
+// operator.
+func formatAPLTextExpr(value *proto.APLValue) (string, int) {
+	if value == nil {
+		return "{}", aplTextPrecedencePrimary
+	}
+
+	switch v := value.Value.(type) {
+	case *proto.APLValue_Const:
+		if v.Const.Tunable != nil {
+			break
+		}
+		if aplTextLiteralRegex.MatchString(v.Const.Val) || v.Const.Val == "true" || v.Const.Val == "false" {
+			return v.Const.Val, aplTextPrecedencePrimary
+		}
+		return strconv.Quote(v.Const.Val), aplTextPrecedencePrimary
+	case *proto.APLValue_Or:
+		if isAPLTextInfix(v.Or.Vals) {
+			return formatAPLTextOperands(v.Or.Vals, " or ", aplTextPrecedenceAnd), aplTextPrecedenceOr
+		}
+	case *proto.APLValue_And:
+		if isAPLTextInfix(v.And.Vals) {
+			return formatAPLTextOperands(v.And.Vals, " and ", aplTextPrecedenceNot), aplTextPrecedenceAnd
+		}
+	case *proto.APLValue_Not:
+		if v.Not.Val != nil {
+			return "not " + formatAPLTextOperand(v.Not.Val, aplTextPrecedenceNot), aplTextPrecedenceNot
+		}
+	case *proto.APLValue_Cmp:
+		if op, ok := aplTextCmpOps[v.Cmp.Op]; ok && v.Cmp.Lhs != nil && v.Cmp.Rhs != nil {
+			return formatAPLTextOperand(v.Cmp.Lhs, aplTextPrecedenceSum) + " " + op + " " + formatAPLTextOperand(v.Cmp.Rhs, aplTextPrecedenceSum), aplTextPrecedenceCmp
+		}
+	case *proto.APLValue_Math:
+		if op, ok := aplTextMathOps[v.Math.Op]; ok && v.Math.Lhs != nil && v.Math.Rhs != nil {
+			// Both sides of +/- and * and / share a precedence, and are left
+			// associative.
+			precedence := aplTextPrecedenceSum
+			if v.Math.Op == proto.APLValueMath_OpMul || v.Math.Op == proto.APLValueMath_OpDiv {
+				precedence = aplTextPrecedenceProduct
+			}
+			return formatAPLTextOperand(v.Math.Lhs, precedence) + " " + op + " " + formatAPLTextOperand(v.Math.Rhs, precedence+1), precedence
+		}
+	}
+
+	valueMessage := value.ProtoReflect()
+	if fd := valueMessage.WhichOneof(valueMessage.Descriptor().Oneofs().ByName("value")); fd != nil {
+		return formatAPLTextCall(fd, valueMessage.Get(fd).Message()), aplTextPrecedencePrimary
+	}
+	return "{}", aplTextPrecedencePrimary
+}
+
+func isAPLTextInfix(vals []*proto.APLValue) bool {
+	if len(vals) < 2 {
+		return false
+	}
+	for _, val := range vals {
+		if val == nil {
+			return false
+		}
+	}
+	return true
+}
+
+// Formats value, adding parentheses if its operator binds looser than
+// minPrecedence.
+func formatAPLTextOperand(value *proto.APLValue, minPrecedence int) string {
+	str, precedence := formatAPLTextExpr(value)
+	if precedence < minPrecedence {
+		return "(" + str + ")"
+	}
+	return str
+}
+
+func formatAPLTextOperands(vals []*proto.APLValue, separator string, minPrecedence int) string {
+	operands := make([]string, len(vals))
+	for i, val := range vals {
+		operands[i] = formatAPLTextOperand(val, minPrecedence)
+	}
+	return strings.Join(operands, separator)
+}
+
+func formatAPLTextCall(fd protoreflect.FieldDescriptor, message protoreflect.Message) string {
+	args := formatAPLTextArgs(message)
+	if len(args) == 0 {
+		return string(fd.Name())
+	}
+	return string(fd.Name()) + "(" + strings.Join(args, ", ") + ")"
+}
+
+// The first field is positional (all elements, if it is repeated), the others
+// are named.
+func formatAPLTextArgs(message protoreflect.Message) []string {
+	var args []string
+	fields := message.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		fd := fields.Get(i)
+		if !message.Has(fd) {
+			continue
+		}
+		value := message.Get(fd)
+		if i > 0 {
+			args = append(args, string(fd.Name())+"="+formatAPLTextField(fd, value))
+		} else if fd.IsList() {
+			for j := 0; j < value.List().Len(); j++ {
+				args = append(args, formatAPLTextSingular(fd, value.List().Get(j)))
+			}
+		} else {
+			args = append(args, formatAPLTextSingular(fd, value))
+		}
+	}
+	return args
+}
+
+func formatAPLTextField(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	if !fd.IsList() {
+		return formatAPLTextSingular(fd, value)
+	}
+	elements := make([]string, value.List().Len())
+	for i := range elements {
+		elements[i] = formatAPLTextSingular(fd, value.List().Get(i))
+	}
+	return "[" + strings.Join(elements, ", ") + "]"
+}
+
+func formatAPLTextSingular(fd protoreflect.FieldDescriptor, value protoreflect.Value) string {
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		switch m := value.Message().Interface().(type) {
+		case *proto.APLValue:
+			return formatAPLTextValue(m)
+		case *proto.APLAction:
+			return formatAPLTextAction(m)
+		case *proto.ActionID:
+			return formatAPLTextActionID(m)
+		case *proto.UnitReference:
+			if m.Owner == nil {
+				if m.Index != 0 {
+					return fmt.Sprintf("%s:%d", m.Type, m.Index)
+				}
+				return m.Type.String()
+			}
+		}
+		return formatAPLTextMessage(value.Message())
+	case protoreflect.EnumKind:
+		if enumValue := fd.Enum().Values().ByNumber(value.Enum()); enumValue != nil {
+			return string(enumValue.Name())
+		}
+		return strconv.Itoa(int(value.Enum()))
+	case protoreflect.StringKind:
+		return strconv.Quote(value.String())
+	case protoreflect.FloatKind:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 32)
+	case protoreflect.DoubleKind:
+		return strconv.FormatFloat(value.Float(), 'f', -1, 64)
+	default:
+		// Bools and integers.
+		return value.String()
+	}
+}
+
+func formatAPLTextActionID(id *proto.ActionID) string {
+	var str string
+	switch rawID := id.RawId.(type) {
+	case *proto.ActionID_SpellId:
+		str = fmt.Sprintf("spell:%d", rawID.SpellId)
+	case *proto.ActionID_ItemId:
+		str = fmt.Sprintf("item:%d", rawID.ItemId)
+	case *proto.ActionID_OtherId:
+		str = "other:" + rawID.OtherId.String()
+	default:
+		return formatAPLTextMessage(id.ProtoReflect())
+	}
+	if id.Tag != 0 {
+		str += fmt.Sprintf("/%d", id.Tag)
+	}
+	return str
+}
+
+// A trailing comment with the names of the spells and items referenced in
+// message, or "" if there are none with known names.
+func formatAPLTextNamesComment(message protoreflect.Message) string {
+	var names []string
+	var addNames func(message protoreflect.Message)
+	addFieldNames := func(message protoreflect.Message, fd protoreflect.FieldDescriptor) {
+		if fd.Kind() != protoreflect.MessageKind || fd.IsMap() || !message.Has(fd) {
+			return
+		}
+		if fd.IsList() {
+			list := message.Get(fd).List()
+			for i := 0; i < list.Len(); i++ {
+				addNames(list.Get(i).Message())
+			}
+		} else {
+			addNames(message.Get(fd).Message())
+		}
+	}
+	addNames = func(message protoreflect.Message) {
+		if id, ok := message.Interface().(*proto.ActionID); ok {
+			var name string
+			switch rawID := id.RawId.(type) {
+			case *proto.ActionID_SpellId:
+				name = SpellNamesByID[rawID.SpellId]
+			case *proto.ActionID_ItemId:
+				name = ItemNamesByID[rawID.ItemId]
+			}
+			if name != "" && !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+			return
+		}
+
+		// Conditions are written after their action, so their names go last.
+		_, isAction := message.Interface().(*proto.APLAction)
+		fields := message.Descriptor().Fields()
+		for i := 0; i < fields.Len(); i++ {
+			if fd := fields.Get(i); !isAction || fd.Name() != "condition" {
+				addFieldNames(message, fd)
+			}
+		}
+		if isAction {
+			addFieldNames(message, fields.ByName("condition"))
+		}
+	}
+	addNames(message)
+
+	if len(names) == 0 {
+		return ""
+	}
+	return "  # " + strings.Join(names, ", ")
+}
+
+func formatAPLTextMessage(message protoreflect.Message) string {
+	var fields []string
+	messageFields := message.Descriptor().Fields()
+	for i := 0; i < messageFields.Len(); i++ {
+		if fd := messageFields.Get(i); message.Has(fd) {
+			fields = append(fields, string(fd.Name())+"="+formatAPLTextField(fd, message.Get(fd)))
+		}
+	}
+	return "{" + strings.Join(fields, ", ") + "}"
+}
+
+type aplTextTokenKind int
+
+const (
+	aplTextEOF aplTextTokenKind = iota
+	aplTextNewline
+	aplTextComment
+	aplTextIdent
+	aplTextNumber
+	aplTextString
+	aplTextSymbol
+)
+
+type aplTextToken struct {
+	kind aplTextTokenKind
+	text string // Unquoted value for strings, text after the '#' for comments.
+	line int
+	col  int
+}
+
+func (token aplTextToken) String() string {
+	switch token.kind {
+	case aplTextEOF:
+		return "end of input"
+	case aplTextNewline:
+		return "end of line"
+	case aplTextComment:
+		return "comment"
+	case aplTextString:
+		return strconv.Quote(token.text)
+	default:
+		return "'" + token.text + "'"
+	}
+}
+
+type aplTextError struct {
+	line int
+	col  int
+	msg  string
+}
+
+func (err aplTextError) Error() string {
+	return fmt.Sprintf("line %d, col %d: %s", err.line, err.col, err.msg)
+}
+
+// Splits text into tokens. Newlines are only significant outside of brackets,
+// and comments only at the start of a line outside of brackets, where they
+// hold notes. Other comments are dropped.
+func lexAPLText(text string) ([]aplTextToken, error) {
+	var tokens []aplTextToken
+	line, lineStart := 1, 0
+	depth := 0
+	atLineStart := true
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		token := aplTextToken{line: line, col: i - lineStart + 1}
+		errorf := func(format string, args ...any) error {
+			return aplTextError{line: token.line, col: token.col, msg: fmt.Sprintf(format, args...)}
+		}
+
+		switch {
+		case c == '\n':
+			i++
+			if depth == 0 {
+				token.kind = aplTextNewline
+				tokens = append(tokens, token)
+				atLineStart = true
+			}
+			line, lineStart = line+1, i
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			i++
+			continue
+		case c == '#':
+			end := strings.IndexByte(text[i:], '\n')
+			if end == -1 {
+				end = len(text) - i
+			}
+			if depth == 0 && atLineStart {
+				token.kind = aplTextComment
+				token.text = strings.TrimSuffix(text[i+1:i+end], "\r")
+				token.text = strings.TrimPrefix(token.text, " ")
+				tokens = append(tokens, token)
+			}
+			i += end
+			continue
+		case c == '_' || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z':
+			end := i + 1
+			for end < len(text) && (text[end] == '_' || 'a' <= text[end] && text[end] <= 'z' || 'A' <= text[end] && text[end] <= 'Z' || '0' <= text[end] && text[end] <= '9') {
+				end++
+			}
+			token.kind = aplTextIdent
+			token.text = text[i:end]
+			i = end
+		case '0' <= c && c <= '9':
+			match := aplTextNumberRegex.FindString(text[i:])
+			token.kind = aplTextNumber
+			token.text = match
+			i += len(match)
+		case c == '"':
+			end := i + 1
+			for end < len(text) && text[end] != '"' && text[end] != '\n' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) || text[end] != '"' {
+				return nil, errorf("unterminated string")
+			}
+			str, err := strconv.Unquote(text[i : end+1])
+			if err != nil {
+				return nil, errorf("invalid string %s", text[i:end+1])
+			}
+			token.kind = aplTextString
+			token.text = str
+			i = end + 1
+		default:
+			token.kind = aplTextSymbol
+			if i+1 < len(text) && text[i+1] == '=' && strings.IndexByte("=!<>", c) != -1 {
+				token.text = text[i : i+2]
+			} else if strings.IndexByte("()[]{},=:/+-*><", c) != -1 {
+				token.text = text[i : i+1]
+			} else {
+				return nil, errorf("unexpected character %q", c)
+			}
+			i += len(token.text)
+
+			switch c {
+			case '(', '[', '{':
+				depth++
+			case ')', ']', '}':
+				depth = max(depth-1, 0)
+			}
+		}
+
+		tokens = append(tokens, token)
+		atLineStart = false
+	}
+
+	tokens = append(tokens, aplTextToken{kind: aplTextEOF, line: line, col: len(text) - lineStart + 1})
+	return tokens, nil
+}
+
+var aplTextNumberRegex = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[a-zA-Z%]*`)
+
+// Recursive descent parser. Errors are raised as aplTextError panics, which
+// APLRotationFromText recovers.
+type aplTextParser struct {
+	tokens []aplTextToken
+	pos    int
+}
+
+func (parser *aplTextParser) peek(offset int) aplTextToken {
+	return parser.tokens[min(parser.pos+offset, len(parser.tokens)-1)]
+}
+
+func (parser *aplTextParser) next() aplTextToken {
+	token := parser.peek(0)
+	if token.kind != aplTextEOF {
+		parser.pos++
+	}
+	return token
+}
+
+func (parser *aplTextParser) failAt(token aplTextToken, format string, args ...any) {
+	panic(aplTextError{line: token.line, col: token.col, msg: fmt.Sprintf(format, args...)})
+}
+
+func (parser *aplTextParser) unexpected(expected string) {
+	parser.failAt(parser.peek(0), "expected %s, got %s", expected, parser.peek(0))
+}
+
+// Whether the token at offset is the given symbol or identifier.
+func (parser *aplTextParser) isAt(offset int, text string) bool {
+	token := parser.peek(offset)
+	return (token.kind == aplTextSymbol || token.kind == aplTextIdent) && token.text == text
+}
+
+func (parser *aplTextParser) is(text string) bool {
+	return parser.isAt(0, text)
+}
+
+func (parser *aplTextParser) accept(text string) bool {
+	if parser.is(text) {
+		parser.next()
+		return true
+	}
+	return false
+}
+
+func (parser *aplTextParser) expect(text string) {
+	if !parser.accept(text) {
+		parser.unexpected("'" + text + "'")
+	}
+}
+
+func (parser *aplTextParser) expectKind(kind aplTextTokenKind, expected string) aplTextToken {
+	if parser.peek(0).kind != kind {
+		parser.unexpected(expected)
+	}
+	return parser.next()
+}
+
+func (parser *aplTextParser) expectLineEnd() {
+	if kind := parser.peek(0).kind; kind != aplTextNewline && kind != aplTextEOF {
+		parser.unexpected("end of line")
+	}
+	parser.next()
+}
+
+func (parser *aplTextParser) parseName() string {
+	if parser.peek(0).kind == aplTextString {
+		return parser.next().text
+	}
+	return parser.expectKind(aplTextIdent, "a name").text
+}
+
+func (parser *aplTextParser) parseRotation() *proto.APLRotation {
+	rot := &proto.APLRotation{}
+	rotMessage := rot.ProtoReflect()
+
+	// Comments before an item become its notes, other comments are dropped.
+	var notes []string
+	for parser.peek(0).kind != aplTextEOF {
+		switch {
+		case parser.peek(0).kind == aplTextNewline:
+			parser.next()
+			continue
+		case parser.peek(0).kind == aplTextComment:
+			notes = append(notes, parser.next().text)
+			parser.expectLineEnd()
+			continue
+		case parser.is("variable"):
+			parser.next()
+			variable := &proto.APLVariable{Name: parser.parseName()}
+			parser.expect("=")
+			variable.Value = parser.parseExpr()
+			rot.Variables = append(rot.Variables, variable)
+		case parser.is("list"):
+			rot.ActionLists = append(rot.ActionLists, parser.parseActionList())
+		case parser.peek(0).kind == aplTextIdent && parser.isAt(1, "="):
+			token := parser.next()
+			fd := rotMessage.Descriptor().Fields().ByName(protoreflect.Name(token.text))
+			if fd == nil || aplTextRotationStatements[fd.Name()] {
+				parser.failAt(token, "unknown rotation field '%s'", token.text)
+			}
+			parser.next()
+			parser.parseField(rotMessage, fd, token)
+		case parser.is("prepull") || parser.is("hidden") && parser.isAt(1, "prepull"):
+			prepullAction := &proto.APLPrepullAction{Hide: parser.accept("hidden")}
+			parser.expect("prepull")
+			if !parser.is(":") {
+				prepullAction.DoAtValue = parser.parseExpr()
+			}
+			parser.expect(":")
+			prepullAction.Action = parser.parseAction()
+			rot.PrepullActions = append(rot.PrepullActions, prepullAction)
+		default:
+			rot.PriorityList = append(rot.PriorityList, parser.parseListItem(notes))
+		}
+		notes = nil
+		parser.expectLineEnd()
+	}
+
+	return rot
+}
+
+func (parser *aplTextParser) parseListItem(notes []string) *proto.APLListItem {
+	return &proto.APLListItem{
+		Notes:  strings.Join(notes, "\n"),
+		Hide:   parser.accept("hidden"),
+		Action: parser.parseAction(),
+	}
+}
+
+func (parser *aplTextParser) parseActionList() *proto.APLActionList {
+	parser.expect("list")
+	actionList := &proto.APLActionList{Name: parser.parseName()}
+	parser.expect(":")
+	parser.expectLineEnd()
+
+	var notes []string
+	for !parser.accept("end") {
+		switch parser.peek(0).kind {
+		case aplTextEOF:
+			parser.unexpected("'end'")
+		case aplTextNewline:
+			parser.next()
+		case aplTextComment:
+			notes = append(notes, parser.next().text)
+			parser.expectLineEnd()
+		default:
+			actionList.PriorityList = append(actionList.PriorityList, parser.parseListItem(notes))
+			notes = nil
+			parser.expectLineEnd()
+		}
+	}
+	return actionList
+}
+
+func (parser *aplTextParser) parseAction() *proto.APLAction {
+	action := &proto.APLAction{}
+	if parser.is("{") {
+		parser.parseMessageLiteral(action.ProtoReflect())
+	} else {
+		parser.parseCall(action.ProtoReflect(), "action", "an action")
+	}
+	if parser.accept("if") {
+		action.Condition = parser.parseExpr()
+	}
+	return action
+}
+
+// Parses a call to one of the kinds in the given oneof of container, e.g.
+// cast_spell(spell:686) for the action oneof of APLAction.
+func (parser *aplTextParser) parseCall(container protoreflect.Message, oneofName protoreflect.Name, expected string) {
+	token := parser.expectKind(aplTextIdent, expected)
+	fd := container.Descriptor().Oneofs().ByName(oneofName).Fields().ByName(protoreflect.Name(token.text))
+	if fd == nil {
+		parser.failAt(token, "unknown %s '%s'", oneofName, token.text)
+	}
+	message := container.Mutable(fd).Message()
+	if !parser.accept("(") {
+		return
+	}
+
+	fields := message.Descriptor().Fields()
+	for numArgs := 0; !parser.is(")"); numArgs++ {
+		if parser.peek(0).kind == aplTextIdent && parser.isAt(1, "=") {
+			parser.parseNamedField(message)
+		} else if numArgs > 0 && !(fields.Get(0).IsList() && message.Has(fields.Get(0))) {
+			parser.failAt(parser.peek(0), "only the first argument of '%s' can be positional", token.text)
+		} else if fields.Len() == 0 {
+			parser.failAt(parser.peek(0), "'%s' does not take arguments", token.text)
+		} else if fd := fields.Get(0); fd.IsList() {
+			list := message.Mutable(fd).List()
+			list.Append(parser.parseSingular(fd, list.NewElement))
+		} else {
+			parser.parseField(message, fd, parser.peek(0))
+		}
+		if !parser.accept(",") {
+			break
+		}
+	}
+	parser.expect(")")
+}
+
+func (parser *aplTextParser) parseNamedField(message protoreflect.Message) {
+	token := parser.expectKind(aplTextIdent, "a field name")
+	fd := message.Descriptor().Fields().ByName(protoreflect.Name(token.text))
+	if fd == nil {
+		parser.failAt(token, "unknown field '%s' for %s", token.text, message.Descriptor().Name())
+	}
+	parser.expect("=")
+	parser.parseField(message, fd, token)
+}
+
+func (parser *aplTextParser) parseField(message protoreflect.Message, fd protoreflect.FieldDescriptor, token aplTextToken) {
+	if message.Has(fd) {
+		parser.failAt(token, "field '%s' is set twice", fd.Name())
+	}
+	if fd.IsMap() {
+		parser.failAt(token, "map fields are not supported")
+	}
+	if !fd.IsList() {
+		message.Set(fd, parser.parseSingular(fd, func() protoreflect.Value { return message.NewField(fd) }))
+		return
+	}
+
+	list := message.Mutable(fd).List()
+	parser.expect("[")
+	for !parser.is("]") {
+		list.Append(parser.parseSingular(fd, list.NewElement))
+		if !parser.accept(",") {
+			break
+		}
+	}
+	parser.expect("]")
+}
+
+// Parses a single value of fd's type. newValue returns an empty value of that
+// type, and is only used for messages.
+func (parser *aplTextParser) parseSingular(fd protoreflect.FieldDescriptor, newValue func() protoreflect.Value) protoreflect.Value {
+	token := parser.peek(0)
+	switch fd.Kind() {
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		if parser.is("{") {
+			message := newValue().Message()
+			parser.parseMessageLiteral(message)
+			return protoreflect.ValueOfMessage(message)
+		}
+		var message goproto.Message
+		switch fd.Message().FullName() {
+		case "proto.APLValue":
+			message = parser.parseExpr()
+		case "proto.APLAction":
+			message = parser.parseAction()
+		case "proto.ActionID":
+			message = parser.parseActionID()
+		case "proto.UnitReference":
+			enumValue := parser.parseEnum((&proto.UnitReference{}).ProtoReflect().Descriptor().Fields().ByName("type").Enum())
+			unitRef := &proto.UnitReference{Type: proto.UnitReference_Type(enumValue)}
+			if parser.accept(":") {
+				unitRef.Index = int32(parser.parseInt(32))
+			}
+			message = unitRef
+		default:
+			parser.unexpected("'{'")
+		}
+		return protoreflect.ValueOfMessage(message.ProtoReflect())
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(parser.parseEnum(fd.Enum()))
+	case protoreflect.BoolKind:
+		if parser.accept("true") {
+			return protoreflect.ValueOfBool(true)
+		} else if parser.accept("false") {
+			return protoreflect.ValueOfBool(false)
+		}
+		parser.unexpected("true or false")
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(parser.expectKind(aplTextString, "a string").text)
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(parser.parseInt(32)))
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(parser.parseInt(64))
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(parser.parseUint(32)))
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(parser.parseUint(64))
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(float32(parser.parseFloat(32)))
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(parser.parseFloat(64))
+	}
+	parser.failAt(token, "fields of type %s are not supported", fd.Kind())
+	return protoreflect.Value{}
+}
+
+func (parser *aplTextParser) parseMessageLiteral(message protoreflect.Message) {
+	parser.expect("{")
+	for !parser.is("}") {
+		parser.parseNamedField(message)
+		if !parser.accept(",") {
+			break
+		}
+	}
+	parser.expect("}")
+}
+
+func (parser *aplTextParser) parseActionID() *proto.ActionID {
+	id := &proto.ActionID{}
+	token := parser.expectKind(aplTextIdent, "spell, item or other")
+	parser.expect(":")
+	switch token.text {
+	case "spell":
+		id.RawId = &proto.ActionID_SpellId{SpellId: int32(parser.parseInt(32))}
+	case "item":
+		id.RawId = &proto.ActionID_ItemId{ItemId: int32(parser.parseInt(32))}
+	case "other":
+		otherID := parser.parseEnum(id.ProtoReflect().Descriptor().Fields().ByName("other_id").Enum())
+		id.RawId = &proto.ActionID_OtherId{OtherId: proto.OtherAction(otherID)}
+	default:
+		parser.failAt(token, "expected spell, item or other, got %s", token)
+	}
+	if parser.accept("/") {
+		id.Tag = int32(parser.parseInt(32))
+	}
+	return id
+}
+
+func (parser *aplTextParser) parseEnum(enum protoreflect.EnumDescriptor) protoreflect.EnumNumber {
+	if token := parser.peek(0); token.kind == aplTextNumber || parser.is("-") {
+		return protoreflect.EnumNumber(parser.parseInt(32))
+	}
+	token := parser.expectKind(aplTextIdent, string(enum.Name()))
+	enumValue := enum.Values().ByName(protoreflect.Name(token.text))
+	if enumValue == nil {
+		parser.failAt(token, "unknown %s '%s'", enum.Name(), token.text)
+	}
+	return enumValue.Number()
+}
+
+// Returns the text of a number, including its sign.
+func (parser *aplTextParser) parseNumberText() (aplTextToken, string) {
+	token := parser.peek(0)
+	sign := ""
+	if parser.accept("-") {
+		sign = "-"
+	}
+	return token, sign + parser.expectKind(aplTextNumber, "a number").text
+}
+
+func (parser *aplTextParser) parseInt(bitSize int) int64 {
+	token, text := parser.parseNumberText()
+	value, err := strconv.ParseInt(text, 10, bitSize)
+	if err != nil {
+		parser.failAt(token, "invalid integer %s", text)
+	}
+	return value
+}
+
+func (parser *aplTextParser) parseUint(bitSize int) uint64 {
+	token, text := parser.parseNumberText()
+	value, err := strconv.ParseUint(text, 10, bitSize)
+	if err != nil {
+		parser.failAt(token, "invalid unsigned integer %s", text)
+	}
+	return value
+}
+
+func (parser *aplTextParser) parseFloat(bitSize int) float64 {
+	token, text := parser.parseNumberText()
+	value, err := strconv.ParseFloat(text, bitSize)
+	if err != nil {
+		parser.failAt(token, "invalid number %s", text)
+	}
+	return value
+}
+
+func (parser *aplTextParser) parseExpr() *proto.APLValue {
+	vals := []*proto.APLValue{parser.parseAnd()}
+	for parser.accept("or") {
+		vals = append(vals, parser.parseAnd())
+	}
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return &proto.APLValue{Value: &proto.APLValue_Or{Or: &proto.APLValueOr{Vals: vals}}}
+}
+
+func (parser *aplTextParser) parseAnd() *proto.APLValue {
+	vals := []*proto.APLValue{parser.parseNot()}
+	for parser.accept("and") {
+		vals = append(vals, parser.parseNot())
+	}
+	if len(vals) == 1 {
+		return vals[0]
+	}
+	return &proto.APLValue{Value: &proto.APLValue_And{And: &proto.APLValueAnd{Vals: vals}}}
+}
+
+func (parser *aplTextParser) parseNot() *proto.APLValue {
+	// 'not()' is an empty call rather than an operator.
+	if parser.is("not") && !(parser.isAt(1, "(") && parser.isAt(2, ")")) {
+		parser.next()
+		return &proto.APLValue{Value: &proto.APLValue_Not{Not: &proto.APLValueNot{Val: parser.parseNot()}}}
+	}
+	return parser.parseCmp()
+}
+
+func (parser *aplTextParser) parseCmp() *proto.APLValue {
+	lhs := parser.parseSum()
+	if parser.peek(0).kind != aplTextSymbol {
+		return lhs
+	}
+	for op, symbol := range aplTextCmpOps {
+		if parser.peek(0).text == symbol {
+			parser.next()
+			return &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+				Op:  op,
+				Lhs: lhs,
+				Rhs: parser.parseSum(),
+			}}}
+		}
+	}
+	return lhs
+}
+
+func (parser *aplTextParser) parseSum() *proto.APLValue {
+	return parser.parseMath(parser.parseProduct, proto.APLValueMath_OpAdd, proto.APLValueMath_OpSub)
+}
+
+func (parser *aplTextParser) parseProduct() *proto.APLValue {
+	return parser.parseMath(parser.parsePrimary, proto.APLValueMath_OpMul, proto.APLValueMath_OpDiv)
+}
+
+// Parses a left associative chain of operands joined by any of ops.
+func (parser *aplTextParser) parseMath(parseOperand func() *proto.APLValue, ops ...proto.APLValueMath_MathOperator) *proto.APLValue {
+	lhs := parseOperand()
+	for {
+		matched := false
+		for _, op := range ops {
+			if parser.accept(aplTextMathOps[op]) {
+				lhs = &proto.APLValue{Value: &proto.APLValue_Math{Math: &proto.APLValueMath{
+					Op:  op,
+					Lhs: lhs,
+					Rhs: parseOperand(),
+				}}}
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return lhs
+		}
+	}
+}
+
+func (parser *aplTextParser) parsePrimary() *proto.APLValue {
+	token := parser.peek(0)
+	constValue := func(val string) *proto.APLValue {
+		return &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{Val: val}}}
+	}
+
+	switch {
+	case parser.accept("("):
+		value := parser.parseExpr()
+		parser.expect(")")
+		return value
+	case parser.is("{"):
+		value := &proto.APLValue{}
+		parser.parseMessageLiteral(value.ProtoReflect())
+		return value
+	case token.kind == aplTextNumber || parser.is("-"):
+		_, text := parser.parseNumberText()
+		return constValue(text)
+	case token.kind == aplTextString:
+		return constValue(parser.next().text)
+	case parser.is("true") || parser.is("false"):
+		return constValue(parser.next().text)
+	}
+
+	value := &proto.APLValue{}
+	parser.parseCall(value.ProtoReflect(), "value", "a value")
+	return value
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	goproto "google.golang.org/protobuf/proto"
+)
+
+func TestAPLTextRoundTripsPresets(t *testing.T) {
+	files, err := filepath.Glob("../../ui/*/*/apls/*.apl.json")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find preset APLs: %v", err)
+	}
+
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %s", file, err)
+		}
+		rot := APLRotationFromJsonString(string(data))
+
+		text := APLRotationToText(rot)
+		parsed, err := APLRotationFromText(text)
+		if err != nil {
+			t.Errorf("%s: failed to parse printed rotation: %s\n%s", file, err, text)
+			continue
+		}
+		if !goproto.Equal(rot, parsed) {
+			t.Errorf("%s: rotation changed after round trip through\n%s", file, text)
+		}
+		if reprinted := APLRotationToText(parsed); reprinted != text {
+			t.Errorf("%s: printing is not stable, got\n%s\nthen\n%s", file, text, reprinted)
+		}
+	}
+}
+
+func TestAPLTextSyntax(t *testing.T) {
+	text := `
+variable "burst window" = 20s
+
+hidden prepull -1.5s: cast_spell(spell:686)
+
+# Line one
+#
+#  indented
+cast_spell(spell:686, target=Target:1) if not gcd_is_ready or current_time and 1 + 2 * 3 > 4 - 5 - 6
+hidden strict_sequence(cast_spell(item:58091) if true, cast_spell(other:OtherActionPotion/2))
+call_list("aoe") if not (number_targets > 1) and (current_focus or current_rage)
+
+list aoe:
+	# Note
+	multidot(spell:172, max_dots=3, max_overlap=0ms)
+end
+`
+	expected := APLRotationFromJsonString(`{
+		"variables": [{"name": "burst window", "value": {"const": {"val": "20s"}}}],
+		"prepullActions": [{"hide": true, "doAtValue": {"const": {"val": "-1.5s"}}, "action": {"castSpell": {"spellId": {"spellId": 686}}}}],
+		"priorityList": [
+			{"notes": "Line one\n\n indented", "action": {
+				"condition": {"or": {"vals": [
+					{"not": {"val": {"gcdIsReady": {}}}},
+					{"and": {"vals": [
+						{"currentTime": {}},
+						{"cmp": {"op": "OpGt",
+							"lhs": {"math": {"op": "OpAdd", "lhs": {"const": {"val": "1"}}, "rhs": {"math": {"op": "OpMul", "lhs": {"const": {"val": "2"}}, "rhs": {"const": {"val": "3"}}}}}},
+							"rhs": {"math": {"op": "OpSub", "lhs": {"math": {"op": "OpSub", "lhs": {"const": {"val": "4"}}, "rhs": {"const": {"val": "5"}}}}, "rhs": {"const": {"val": "6"}}}}
+						}}
+					]}}
+				]}},
+				"castSpell": {"spellId": {"spellId": 686}, "target": {"type": "Target", "index": 1}}
+			}},
+			{"hide": true, "action": {"strictSequence": {"actions": [
+				{"condition": {"const": {"val": "true"}}, "castSpell": {"spellId": {"itemId": 58091}}},
+				{"castSpell": {"spellId": {"otherId": "OtherActionPotion", "tag": 2}}}
+			]}}},
+			{"action": {
+				"condition": {"and": {"vals": [
+					{"not": {"val": {"cmp": {"op": "OpGt", "lhs": {"numberTargets": {}}, "rhs": {"const": {"val": "1"}}}}}},
+					{"or": {"vals": [{"currentFocus": {}}, {"currentRage": {}}]}}
+				]}},
+				"callList": {"listName": "aoe"}
+			}}
+		],
+		"actionLists": [{"name": "aoe", "priorityList": [
+			{"notes": "Note", "action": {"multidot": {"spellId": {"spellId": 172}, "maxDots": 3, "maxOverlap": {"const": {"val": "0ms"}}}}}
+		]}]
+	}`)
+
+	rot, err := APLRotationFromText(text)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if !goproto.Equal(rot, expected) {
+		t.Errorf("Parsed rotation differs from the expected one:\n%s", APLRotationToText(rot))
+	}
+}
+
+func TestAPLTextErrors(t *testing.T) {
+	for text, expectedErr := range map[string]string{
+		"cast_spell(spell:686":                 "line 1, col 21: expected ')', got end of input",
+		"cast_spel(spell:686)":                 "line 1, col 1: unknown action 'cast_spel'",
+		"\ncast_spell(spell:686, foo=1)":       "line 2, col 23: unknown field 'foo' for APLActionCastSpell",
+		"cast_spell(spell:686) if 1 +":         "line 1, col 29: expected a value, got end of input",
+		"list aoe:\n\tcast_spell(spell:686)\n": "line 3, col 1: expected 'end', got end of input",
+		"wait(\"1s)":                           "line 1, col 6: unterminated string",
+	} {
+		_, err := APLRotationFromText(text)
+		if err == nil || err.Error() != expectedErr {
+			t.Errorf("Parsing %q: expected error %q, got %v", text, expectedErr, err)
+		}
+	}
+}
+
+func TestAPLTextNamesComment(t *testing.T) {
+	for id, name := range map[int32]string{686: "Shadow Bolt", 17941: "Shadow Trance"} {
+		oldName, ok := SpellNamesByID[id]
+		SpellNamesByID[id] = name
+		defer func() {
+			if ok {
+				SpellNamesByID[id] = oldName
+			} else {
+				delete(SpellNamesByID, id)
+			}
+		}()
+	}
+
+	text := "cast_spell(spell:686) if aura_remaining_time(aura_id=spell:17941) > 1s and aura_is_active(aura_id=spell:686)  # Shadow Bolt, Shadow Trance\n"
+	rot, err := APLRotationFromText(text)
+	if err != nil {
+		t.Fatalf("Failed to parse: %s", err)
+	}
+	if printed := APLRotationToText(rot); printed != text {
+		t.Errorf("Expected\n%s\ngot\n%s", text, printed)
+	}
+}
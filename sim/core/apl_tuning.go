@@ -0,0 +1,470 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"regexp"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+const defaultAPLTuningMaxEvaluations = 50
+
+// Matches the constants which can be tuned, capturing the number and its unit.
+var aplTunableConstRegex = regexp.MustCompile(`^(-?[0-9]+(?:\.[0-9]+)?)([a-zA-Z%]*)$`)
+
+// Returned by the search once max_evaluations parameter sets were simmed.
+var errAPLTuningBudgetExhausted = errors.New("apl tuning: evaluation budget exhausted")
+
+// A constant of the rotation which is part of a tuning parameter.
+type aplTunableConst struct {
+	config *proto.APLValueConst
+	unit   string // Suffix of the original value, e.g. "s" or "%".
+}
+
+type aplTuningParameter struct {
+	config *proto.APLTuningParameter
+	consts []*aplTunableConst
+}
+
+type aplTuner struct {
+	baseSettings *proto.RaidSimRequest
+	// Rotation of the tuned player inside baseSettings, whose tunable
+	// constants are overwritten for each simmed parameter set.
+	rotation    *proto.APLRotation
+	parameters  []*aplTuningParameter
+	maximizeHps bool
+	seed        int64
+
+	maxEvaluations int
+	simFunc        func(*proto.RaidSimRequest) *proto.RaidSimResult
+
+	result    *proto.TuneAPLResult
+	evaluated map[string]*proto.APLTuningEvaluation
+}
+
+// TuneAPL searches for the values of the tunable constants in a player's APL
+// rotation which give the most raid DPS (or HPS). Constants are marked as
+// tunable with an APLTunableRange, and constants sharing a name are tuned as a
+// single parameter.
+//
+// Every parameter set is simmed with the same random seed and labeled rands,
+// so that differences between them come from the parameters rather than from
+// luck, and each set is simmed at most once.
+func TuneAPL(request *proto.TuneAPLRequest) (result *proto.TuneAPLResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.TuneAPLResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	if request.GetBaseSettings().GetRaid() == nil {
+		return &proto.TuneAPLResult{Error: &proto.ErrorOutcome{Message: "apl tuning: request has no raid"}}
+	}
+
+	tuner, err := newAPLTuner(request)
+	if err != nil {
+		return &proto.TuneAPLResult{Error: &proto.ErrorOutcome{Message: err.Error()}}
+	}
+
+	if err := tuner.run(request.GetSettings().GetMethod()); err != nil && err != errAPLTuningBudgetExhausted {
+		tuner.result.Error = &proto.ErrorOutcome{Message: err.Error()}
+		return tuner.result
+	}
+
+	tuner.setValues(tuner.result.Best.Values)
+	tuner.result.BestRotation = googleProto.Clone(tuner.rotation).(*proto.APLRotation)
+	return tuner.result
+}
+
+func newAPLTuner(request *proto.TuneAPLRequest) (*aplTuner, error) {
+	settings := request.GetSettings()
+	baseSettings := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if baseSettings.SimOptions == nil {
+		baseSettings.SimOptions = &proto.SimOptions{}
+	}
+	if settings.GetIterations() > 0 {
+		baseSettings.SimOptions.Iterations = settings.Iterations
+	}
+	if baseSettings.SimOptions.RandomSeed == 0 {
+		baseSettings.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseSettings.SimOptions.UseLabeledRands = true
+
+	playerIndex := 0
+	if ref := settings.GetPlayer(); ref != nil && ref.Type != proto.UnitReference_Unknown {
+		if ref.Type != proto.UnitReference_Player {
+			return nil, fmt.Errorf("apl tuning: player must be a player reference, got %s", ref.Type)
+		}
+		playerIndex = int(ref.Index)
+	}
+	parties := baseSettings.Raid.GetParties()
+	if playerIndex/5 >= len(parties) || playerIndex%5 >= len(parties[playerIndex/5].GetPlayers()) {
+		return nil, fmt.Errorf("apl tuning: no player with index %d", playerIndex)
+	}
+	player := parties[playerIndex/5].Players[playerIndex%5]
+	if player.GetRotation().GetType() != proto.APLRotation_TypeAPL {
+		return nil, fmt.Errorf("apl tuning: player %d does not use an APL rotation", playerIndex)
+	}
+
+	tuner := &aplTuner{
+		baseSettings:   baseSettings,
+		rotation:       player.Rotation,
+		maximizeHps:    settings.GetMaximizeHps(),
+		seed:           baseSettings.SimOptions.RandomSeed,
+		maxEvaluations: defaultAPLTuningMaxEvaluations,
+		simFunc:        RunRaidSimConcurrent,
+		evaluated:      map[string]*proto.APLTuningEvaluation{},
+	}
+	if settings.GetMaxEvaluations() > 0 {
+		tuner.maxEvaluations = int(settings.MaxEvaluations)
+	}
+	if IsRunningInWasm() {
+		tuner.simFunc = RunRaidSim
+	}
+
+	if err := tuner.collectParameters(); err != nil {
+		return nil, err
+	}
+	if len(tuner.parameters) == 0 {
+		return nil, errors.New("apl tuning: the rotation has no tunable constants")
+	}
+
+	tuner.result = &proto.TuneAPLResult{}
+	for _, parameter := range tuner.parameters {
+		tuner.result.Parameters = append(tuner.result.Parameters, parameter.config)
+	}
+	return tuner, nil
+}
+
+func (tuner *aplTuner) collectParameters() error {
+	byName := map[string]*aplTuningParameter{}
+
+	var walk func(message protoreflect.Message) error
+	walk = func(message protoreflect.Message) error {
+		if config, ok := message.Interface().(*proto.APLValueConst); ok && config.Tunable != nil {
+			match := aplTunableConstRegex.FindStringSubmatch(config.Val)
+			if match == nil {
+				return fmt.Errorf("apl tuning: constant '%s' is not a number", config.Val)
+			}
+			value, _ := strconv.ParseFloat(match[1], 64)
+			tunableConst := &aplTunableConst{config: config, unit: match[2]}
+
+			name := config.Tunable.Name
+			if parameter, ok := byName[name]; ok {
+				if parameter.consts[0].unit != tunableConst.unit {
+					return fmt.Errorf("apl tuning: constants of parameter '%s' have different units", name)
+				}
+				parameter.consts = append(parameter.consts, tunableConst)
+				return nil
+			}
+
+			tunable := config.Tunable
+			if name == "" {
+				return fmt.Errorf("apl tuning: tunable constant '%s' has no name", config.Val)
+			}
+			if tunable.Min >= tunable.Max || tunable.Step < 0 {
+				return fmt.Errorf("apl tuning: parameter '%s' has an invalid range", name)
+			}
+			parameter := &aplTuningParameter{
+				config: &proto.APLTuningParameter{
+					Name:          name,
+					Range:         tunable,
+					OriginalValue: value,
+				},
+				consts: []*aplTunableConst{tunableConst},
+			}
+			byName[name] = parameter
+			tuner.parameters = append(tuner.parameters, parameter)
+			return nil
+		}
+
+		var err error
+		message.Range(func(fd protoreflect.FieldDescriptor, value protoreflect.Value) bool {
+			if fd.Kind() != protoreflect.MessageKind {
+				return true
+			}
+			if fd.IsList() {
+				for i := 0; i < value.List().Len() && err == nil; i++ {
+					err = walk(value.List().Get(i).Message())
+				}
+			} else {
+				err = walk(value.Message())
+			}
+			return err == nil
+		})
+		return err
+	}
+
+	return walk(tuner.rotation.ProtoReflect())
+}
+
+func (tuner *aplTuner) run(method proto.APLTuningSettings_Method) error {
+	original := make([]float64, len(tuner.parameters))
+	for i, parameter := range tuner.parameters {
+		original[i] = parameter.config.OriginalValue
+	}
+	// The original rotation is simmed as is, even if it is outside the ranges.
+	if _, err := tuner.score(original); err != nil {
+		return err
+	}
+	tuner.result.Original = tuner.result.Evaluations[0]
+
+	start := tuner.round(original)
+	switch method {
+	case proto.APLTuningSettings_MethodCmaEs:
+		return tuner.cmaEs(start)
+	default:
+		return tuner.coordinateDescent(start)
+	}
+}
+
+// Clamps values to their ranges and rounds them to their steps.
+func (tuner *aplTuner) round(values []float64) []float64 {
+	rounded := make([]float64, len(values))
+	for i, value := range values {
+		tunable := tuner.parameters[i].config.Range
+		value = max(tunable.Min, min(tunable.Max, value))
+		if tunable.Step > 0 {
+			value = min(tunable.Max, tunable.Min+math.Round((value-tunable.Min)/tunable.Step)*tunable.Step)
+		}
+		// Avoid float noise such as 0.30000000000000004 in the rotation.
+		rounded[i] = math.Round(value*1e6) / 1e6
+	}
+	return rounded
+}
+
+func (tuner *aplTuner) setValues(values []float64) {
+	for i, parameter := range tuner.parameters {
+		for _, tunableConst := range parameter.consts {
+			tunableConst.config.Val = strconv.FormatFloat(values[i], 'f', -1, 64) + tunableConst.unit
+		}
+	}
+}
+
+func (tuner *aplTuner) objective(evaluation *proto.APLTuningEvaluation) float64 {
+	if tuner.maximizeHps {
+		return evaluation.Hps.GetAvg()
+	}
+	return evaluation.Dps.GetAvg()
+}
+
+// Sims the rotation with the given values, unless they were simmed before,
+// and returns the objective.
+func (tuner *aplTuner) score(values []float64) (float64, error) {
+	key := fmt.Sprint(values)
+	if evaluation, ok := tuner.evaluated[key]; ok {
+		return tuner.objective(evaluation), nil
+	}
+	if len(tuner.evaluated) >= tuner.maxEvaluations {
+		return 0, errAPLTuningBudgetExhausted
+	}
+
+	tuner.setValues(values)
+	simResult := tuner.simFunc(tuner.baseSettings)
+	if simResult.Error != nil {
+		return 0, errors.New(simResult.Error.Message)
+	}
+
+	evaluation := &proto.APLTuningEvaluation{
+		Values: slices.Clone(values),
+		Dps:    simResult.RaidMetrics.Dps,
+		Hps:    simResult.RaidMetrics.Hps,
+	}
+	tuner.evaluated[key] = evaluation
+	tuner.result.Evaluations = append(tuner.result.Evaluations, evaluation)
+	if tuner.result.Best == nil || tuner.objective(evaluation) > tuner.objective(tuner.result.Best) {
+		tuner.result.Best = evaluation
+	}
+	return tuner.objective(evaluation), nil
+}
+
+// Tries a step up and down for each parameter in turn, moving to the first
+// improvement. Once no step improves, the step sizes are halved, until they
+// drop below the parameters' steps.
+func (tuner *aplTuner) coordinateDescent(start []float64) error {
+	current := start
+	currentScore, err := tuner.score(current)
+	if err != nil {
+		return err
+	}
+
+	stepSizes := make([]float64, len(tuner.parameters))
+	minStepSizes := make([]float64, len(tuner.parameters))
+	for i, parameter := range tuner.parameters {
+		tunable := parameter.config.Range
+		stepSizes[i] = (tunable.Max - tunable.Min) / 4
+		minStepSizes[i] = TernaryFloat64(tunable.Step > 0, tunable.Step/2, (tunable.Max-tunable.Min)/64)
+	}
+
+	for {
+		improved := false
+		for i := range tuner.parameters {
+			for _, direction := range []float64{1, -1} {
+				candidate := slices.Clone(current)
+				candidate[i] += direction * stepSizes[i]
+				candidate = tuner.round(candidate)
+				if candidate[i] == current[i] {
+					continue
+				}
+
+				score, err := tuner.score(candidate)
+				if err != nil {
+					return err
+				}
+				if score > currentScore {
+					current, currentScore = candidate, score
+					improved = true
+					break
+				}
+			}
+		}
+
+		if improved {
+			continue
+		}
+		converged := true
+		for i := range stepSizes {
+			stepSizes[i] /= 2
+			converged = converged && stepSizes[i] < minStepSizes[i]
+		}
+		if converged {
+			return nil
+		}
+	}
+}
+
+// Separable CMA-ES (Ros & Hansen, 2008), i.e. CMA-ES with a diagonal
+// covariance matrix, on the parameters scaled to [0, 1].
+func (tuner *aplTuner) cmaEs(start []float64) error {
+	n := len(tuner.parameters)
+	fn := float64(n)
+
+	lambda := 4 + int(3*math.Log(fn))
+	mu := lambda / 2
+	weights := make([]float64, mu)
+	weightSum := 0.0
+	for i := range weights {
+		weights[i] = math.Log(float64(mu)+0.5) - math.Log(float64(i+1))
+		weightSum += weights[i]
+	}
+	muEff := 0.0
+	for i := range weights {
+		weights[i] /= weightSum
+		muEff += weights[i] * weights[i]
+	}
+	muEff = 1 / muEff
+
+	cSigma := (muEff + 2) / (fn + muEff + 5)
+	dSigma := 1 + 2*max(0, math.Sqrt((muEff-1)/(fn+1))-1) + cSigma
+	cc := (4 + muEff/fn) / (fn + 4 + 2*muEff/fn)
+	c1 := 2 / ((fn+1.3)*(fn+1.3) + muEff) * (fn + 2) / 3
+	cMu := min(1-c1, 2*(muEff-2+1/muEff)/((fn+2)*(fn+2)+muEff)*(fn+2)/3)
+	chiN := math.Sqrt(fn) * (1 - 1/(4*fn) + 1/(21*fn*fn))
+
+	toValues := func(x []float64) []float64 {
+		values := make([]float64, n)
+		for i, parameter := range tuner.parameters {
+			tunable := parameter.config.Range
+			values[i] = tunable.Min + x[i]*(tunable.Max-tunable.Min)
+		}
+		return tuner.round(values)
+	}
+
+	mean := make([]float64, n)
+	for i, parameter := range tuner.parameters {
+		tunable := parameter.config.Range
+		mean[i] = (start[i] - tunable.Min) / (tunable.Max - tunable.Min)
+	}
+	sigma := 0.3
+	diagC := make([]float64, n)
+	for i := range diagC {
+		diagC[i] = 1
+	}
+	pSigma := make([]float64, n)
+	pc := make([]float64, n)
+
+	if _, err := tuner.score(toValues(mean)); err != nil {
+		return err
+	}
+
+	rng := rand.New(rand.NewSource(tuner.seed))
+	type sample struct {
+		y     []float64
+		score float64
+	}
+
+	// Samples rounded to already simmed values cost nothing, so also limit the
+	// number of generations.
+	for generation := 1; generation <= 10*tuner.maxEvaluations; generation++ {
+		samples := make([]sample, lambda)
+		for k := range samples {
+			x := make([]float64, n)
+			y := make([]float64, n)
+			for i := range x {
+				// Samples outside the ranges are repaired by clamping.
+				x[i] = max(0, min(1, mean[i]+sigma*math.Sqrt(diagC[i])*rng.NormFloat64()))
+				y[i] = (x[i] - mean[i]) / sigma
+			}
+			score, err := tuner.score(toValues(x))
+			if err != nil {
+				return err
+			}
+			samples[k] = sample{y: y, score: score}
+		}
+		sort.SliceStable(samples, func(a, b int) bool {
+			return samples[a].score > samples[b].score
+		})
+
+		yw := make([]float64, n)
+		for j, s := range samples[:mu] {
+			for i := range yw {
+				yw[i] += weights[j] * s.y[i]
+			}
+		}
+
+		pSigmaNorm := 0.0
+		for i := range mean {
+			mean[i] += sigma * yw[i]
+			pSigma[i] = (1-cSigma)*pSigma[i] + math.Sqrt(cSigma*(2-cSigma)*muEff)*yw[i]/math.Sqrt(diagC[i])
+			pSigmaNorm += pSigma[i] * pSigma[i]
+		}
+		pSigmaNorm = math.Sqrt(pSigmaNorm)
+
+		hSigma := 0.0
+		if pSigmaNorm/math.Sqrt(1-math.Pow(1-cSigma, 2*float64(generation))) < (1.4+2/(fn+1))*chiN {
+			hSigma = 1
+		}
+
+		maxStdDev := 0.0
+		for i := range diagC {
+			pc[i] = (1-cc)*pc[i] + hSigma*math.Sqrt(cc*(2-cc)*muEff)*yw[i]
+
+			rankMu := 0.0
+			for j, s := range samples[:mu] {
+				rankMu += weights[j] * s.y[i] * s.y[i]
+			}
+			diagC[i] = (1-c1-cMu)*diagC[i] + c1*(pc[i]*pc[i]+(1-hSigma)*cc*(2-cc)*diagC[i]) + cMu*rankMu
+			maxStdDev = max(maxStdDev, math.Sqrt(diagC[i]))
+		}
+		sigma *= math.Exp((cSigma / dSigma) * (pSigmaNorm/chiN - 1))
+
+		if sigma*maxStdDev < 1e-3 {
+			return nil
+		}
+	}
+	return nil
+}
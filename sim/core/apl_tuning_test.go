@@ -0,0 +1,62 @@
+package core_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Only runs the rotation from a tunable start time, which is best at 0s.
+func makeTuningTestCase() *proto.RaidSimRequest {
+	rsr := withMainList(makeTestCase(getTestPlayerFeralCat()), callList("main", &proto.APLValue{Value: &proto.APLValue_Cmp{Cmp: &proto.APLValueCompare{
+		Op:  proto.APLValueCompare_OpGe,
+		Lhs: &proto.APLValue{Value: &proto.APLValue_CurrentTime{CurrentTime: &proto.APLValueCurrentTime{}}},
+		Rhs: &proto.APLValue{Value: &proto.APLValue_Const{Const: &proto.APLValueConst{
+			Val:     "60s",
+			Tunable: &proto.APLTunableRange{Name: "start", Min: 0, Max: 120, Step: 5},
+		}}},
+	}}}))
+	rsr.SimOptions.Iterations = 20
+	return rsr
+}
+
+func TestTuneAPL(t *testing.T) {
+	for name, method := range map[string]proto.APLTuningSettings_Method{
+		"Coordinate Descent": proto.APLTuningSettings_MethodCoordinateDescent,
+		"CMA-ES":             proto.APLTuningSettings_MethodCmaEs,
+	} {
+		result := core.TuneAPL(&proto.TuneAPLRequest{
+			BaseSettings: makeTuningTestCase(),
+			Settings: &proto.APLTuningSettings{
+				Method:         method,
+				MaxEvaluations: 20,
+			},
+		})
+		if result.Error != nil {
+			t.Fatalf("%s: tuning failed: %s", name, result.Error.Message)
+		}
+
+		if len(result.Parameters) != 1 || result.Parameters[0].Name != "start" || result.Parameters[0].OriginalValue != 60 {
+			t.Errorf("%s: expected the start parameter with original value 60, got %v", name, result.Parameters)
+		}
+		if numEvaluations := len(result.Evaluations); numEvaluations < 3 || numEvaluations > 20 {
+			t.Errorf("%s: expected between 3 and 20 evaluations, got %d", name, numEvaluations)
+		}
+		if best := result.Best.Values[0]; best > 20 || result.Best.Dps.Avg <= result.Original.Dps.Avg {
+			t.Errorf("%s: expected a start time near 0s with more than the original %0.0f DPS, got %gs with %0.0f DPS",
+				name, result.Original.Dps.Avg, best, result.Best.Dps.Avg)
+		}
+		for _, evaluation := range result.Evaluations {
+			if value := evaluation.Values[0]; value < 0 || value > 120 || value != float64(int(value/5))*5 {
+				t.Errorf("%s: expected values to be multiples of 5 within the range, got %g", name, value)
+			}
+		}
+
+		if text := core.APLRotationToText(result.BestRotation); !strings.Contains(text, fmt.Sprintf(`const("%gs"`, result.Best.Values[0])) {
+			t.Errorf("%s: expected the best rotation to use the best value, got\n%s", name, text)
+		}
+	}
+}
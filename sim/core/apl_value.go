@@ -96,6 +96,8 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 		value = rot.newValueIsExecutePhase(config.GetIsExecutePhase(), config.Uuid)
 	case *proto.APLValue_NumberTargets:
 		value = rot.newValueNumberTargets(config.GetNumberTargets(), config.Uuid)
+	case *proto.APLValue_CountTargets:
+		value = rot.newValueCountTargets(config.GetCountTargets(), config.Uuid)
 
 	// Boss
 	case *proto.APLValue_BossSpellIsCasting:
@@ -239,6 +241,10 @@ func (rot *APLRotation) newAPLValue(config *proto.APLValue) APLValue {
 	case *proto.APLValue_SequenceTimeToReady:
 		value = rot.newValueSequenceTimeToReady(config.GetSequenceTimeToReady(), config.Uuid)
 
+	// Variables
+	case *proto.APLValue_Variable:
+		value = rot.newValueVariable(config.GetVariable(), config.Uuid)
+
 	// Properties
 	case *proto.APLValue_ChannelClipDelay:
 		value = rot.newValueChannelClipDelay(config.GetChannelClipDelay(), config.Uuid)
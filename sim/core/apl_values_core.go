@@ -9,12 +9,12 @@ import (
 
 type APLValueDotIsActive struct {
 	DefaultAPLValueImpl
-	dot *Dot
+	dot DotReference
 }
 
 func (rot *APLRotation) newValueDotIsActive(config *proto.APLValueDotIsActive, _ *proto.UUID) APLValue {
 	dot := rot.GetAPLDot(rot.GetTargetUnit(config.TargetUnit), config.SpellId)
-	if dot == nil {
+	if dot.Get() == nil {
 		return nil
 	}
 	return &APLValueDotIsActive{
@@ -25,20 +25,20 @@ func (value *APLValueDotIsActive) Type() proto.APLValueType {
 	return proto.APLValueType_ValueTypeBool
 }
 func (value *APLValueDotIsActive) GetBool(sim *Simulation) bool {
-	return value.dot.IsActive()
+	return value.dot.Get().IsActive()
 }
 func (value *APLValueDotIsActive) String() string {
-	return fmt.Sprintf("Dot Is Active(%s)", value.dot.Spell.ActionID)
+	return fmt.Sprintf("Dot Is Active(%s)", value.dot.Get().Spell.ActionID)
 }
 
 type APLValueDotRemainingTime struct {
 	DefaultAPLValueImpl
-	dot *Dot
+	dot DotReference
 }
 
 func (rot *APLRotation) newValueDotRemainingTime(config *proto.APLValueDotRemainingTime, _ *proto.UUID) APLValue {
 	dot := rot.GetAPLDot(rot.GetTargetUnit(config.TargetUnit), config.SpellId)
-	if dot == nil {
+	if dot.Get() == nil {
 		return nil
 	}
 	return &APLValueDotRemainingTime{
@@ -49,20 +49,20 @@ func (value *APLValueDotRemainingTime) Type() proto.APLValueType {
 	return proto.APLValueType_ValueTypeDuration
 }
 func (value *APLValueDotRemainingTime) GetDuration(sim *Simulation) time.Duration {
-	return TernaryDuration(value.dot.IsActive(), value.dot.RemainingDuration(sim), 0)
+	return TernaryDuration(value.dot.Get().IsActive(), value.dot.Get().RemainingDuration(sim), 0)
 }
 func (value *APLValueDotRemainingTime) String() string {
-	return fmt.Sprintf("Dot Remaining Time(%s)", value.dot.Spell.ActionID)
+	return fmt.Sprintf("Dot Remaining Time(%s)", value.dot.Get().Spell.ActionID)
 }
 
 type APLValueDotTickFrequency struct {
 	DefaultAPLValueImpl
-	dot *Dot
+	dot DotReference
 }
 
 func (rot *APLRotation) newValueDotTickFrequency(config *proto.APLValueDotTickFrequency, _ *proto.UUID) APLValue {
 	dot := rot.GetAPLDot(rot.GetTargetUnit(config.TargetUnit), config.SpellId)
-	if dot == nil {
+	if dot.Get() == nil {
 		return nil
 	}
 	return &APLValueDotTickFrequency{
@@ -74,8 +74,8 @@ func (value *APLValueDotTickFrequency) Type() proto.APLValueType {
 	return proto.APLValueType_ValueTypeDuration
 }
 func (value *APLValueDotTickFrequency) GetDuration(_ *Simulation) time.Duration {
-	return value.dot.tickPeriod
+	return value.dot.Get().tickPeriod
 }
 func (value *APLValueDotTickFrequency) String() string {
-	return fmt.Sprintf("Dot Tick Frequency(%s)", value.dot.tickPeriod)
+	return fmt.Sprintf("Dot Tick Frequency(%s)", value.dot.Get().tickPeriod)
 }
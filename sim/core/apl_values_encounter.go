@@ -92,6 +92,46 @@ func (value *APLValueNumberTargets) String() string {
 	return "Num Targets"
 }
 
+type APLValueCountTargets struct {
+	DefaultAPLValueImpl
+	unit      *Unit
+	condition APLValue
+}
+
+func (rot *APLRotation) newValueCountTargets(config *proto.APLValueCountTargets, _ *proto.UUID) APLValue {
+	condition := rot.coerceTo(rot.newAPLValue(config.Condition), proto.APLValueType_ValueTypeBool)
+	if condition == nil {
+		return nil
+	}
+	return &APLValueCountTargets{
+		unit:      rot.unit,
+		condition: condition,
+	}
+}
+func (value *APLValueCountTargets) GetInnerValues() []APLValue {
+	return []APLValue{value.condition}
+}
+func (value *APLValueCountTargets) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeInt
+}
+func (value *APLValueCountTargets) GetInt(sim *Simulation) int32 {
+	unit := value.unit
+	curTarget := unit.CurrentTarget
+
+	count := int32(0)
+	for _, target := range sim.Encounter.ActiveTargets {
+		unit.CurrentTarget = &target.Unit
+		if value.condition.GetBool(sim) {
+			count++
+		}
+	}
+	unit.CurrentTarget = curTarget
+	return count
+}
+func (value *APLValueCountTargets) String() string {
+	return fmt.Sprintf("Count Targets(%s)", value.condition)
+}
+
 type APLValueIsExecutePhase struct {
 	DefaultAPLValueImpl
 	threshold proto.APLValueIsExecutePhase_ExecutePhaseThreshold
@@ -0,0 +1,115 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+type aplVariable struct {
+	name  string
+	value APLValue // Declared value, which is read until overwritten.
+
+	isSet   bool
+	current APLValueConst // Overwritten value, with the same type as value.
+}
+
+func (rot *APLRotation) newAPLVariable(config *proto.APLVariable) *aplVariable {
+	if config.Name == "" {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Variables must have a name")
+		return nil
+	}
+	if rot.getVariable(config.Name) != nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Multiple variables with name: '%s'", config.Name)
+		return nil
+	}
+
+	value := rot.newAPLValue(config.Value)
+	if value == nil {
+		rot.ValidationMessage(proto.LogLevel_Warning, "Variable '%s' must have a value", config.Name)
+		return nil
+	}
+
+	return &aplVariable{
+		name:    config.Name,
+		value:   value,
+		current: APLValueConst{valType: value.Type()},
+	}
+}
+
+func (rot *APLRotation) getVariable(name string) *aplVariable {
+	for _, variable := range rot.variables {
+		if variable.name == name {
+			return variable
+		}
+	}
+	return nil
+}
+
+func (variable *aplVariable) reset() {
+	variable.isSet = false
+}
+
+// Stores the current result of value, which must have the variable's type.
+func (variable *aplVariable) set(sim *Simulation, value APLValue) {
+	variable.isSet = true
+	switch variable.current.valType {
+	case proto.APLValueType_ValueTypeBool:
+		variable.current.boolVal = value.GetBool(sim)
+	case proto.APLValueType_ValueTypeInt:
+		variable.current.intVal = value.GetInt(sim)
+	case proto.APLValueType_ValueTypeFloat:
+		variable.current.floatVal = value.GetFloat(sim)
+	case proto.APLValueType_ValueTypeDuration:
+		variable.current.durationVal = value.GetDuration(sim)
+	case proto.APLValueType_ValueTypeString:
+		variable.current.stringVal = value.GetString(sim)
+	}
+}
+
+func (variable *aplVariable) get() APLValue {
+	if variable.isSet {
+		return &variable.current
+	}
+	return variable.value
+}
+
+type APLValueVariable struct {
+	DefaultAPLValueImpl
+	variable *aplVariable
+}
+
+func (rot *APLRotation) newValueVariable(config *proto.APLValueVariable, uuid *proto.UUID) APLValue {
+	// Variables are resolved immediately, since their type is needed to build
+	// the surrounding values.
+	variable := rot.getVariable(config.Name)
+	if variable == nil {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "No variable with name: '%s'", config.Name)
+		return nil
+	}
+	return &APLValueVariable{
+		variable: variable,
+	}
+}
+func (value *APLValueVariable) Type() proto.APLValueType {
+	return value.variable.value.Type()
+}
+func (value *APLValueVariable) GetBool(sim *Simulation) bool {
+	return value.variable.get().GetBool(sim)
+}
+func (value *APLValueVariable) GetInt(sim *Simulation) int32 {
+	return value.variable.get().GetInt(sim)
+}
+func (value *APLValueVariable) GetFloat(sim *Simulation) float64 {
+	return value.variable.get().GetFloat(sim)
+}
+func (value *APLValueVariable) GetDuration(sim *Simulation) time.Duration {
+	return value.variable.get().GetDuration(sim)
+}
+func (value *APLValueVariable) GetString(sim *Simulation) string {
+	return value.variable.get().GetString(sim)
+}
+func (value *APLValueVariable) String() string {
+	return fmt.Sprintf("Variable(%s)", value.variable.name)
+}
@@ -199,6 +199,9 @@ func (aura *Aura) SetStacks(sim *Simulation, newStacks int32) {
 		aura.Unit.Log(sim, "%s stacks: %d --> %d", aura.ActionID, oldStacks, newStacks)
 	}
 	aura.stacks = newStacks
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() && newStacks != 0 {
+		sim.CombatLog.LogAura(sim, aura, Ternary(newStacks > oldStacks, proto.CombatLogEventType_CombatLogEventAuraAppliedDose, proto.CombatLogEventType_CombatLogEventAuraRemovedDose))
+	}
 	if aura.OnStacksChange != nil {
 		aura.OnStacksChange(aura, sim, oldStacks, newStacks)
 	}
@@ -518,6 +521,8 @@ restart:
 	return at.minExpires
 }
 
+// Deactivates every aura with a duration. Permanent auras aren't tracked in
+// activeAuras, so they stay up.
 func (at *auraTracker) expireAll(sim *Simulation) {
 restart:
 	for _, aura := range at.activeAuras {
@@ -554,6 +559,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 		if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 			aura.Unit.Log(sim, "Aura refreshed: %s", aura.ActionID)
 		}
+		if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+			sim.CombatLog.LogAura(sim, aura, proto.CombatLogEventType_CombatLogEventAuraRefresh)
+		}
 		aura.Refresh(sim)
 		return
 	}
@@ -639,6 +647,9 @@ func (aura *Aura) Activate(sim *Simulation) {
 	if sim.Log != nil && !aura.ActionID.IsEmptyAction() {
 		aura.Unit.Log(sim, "Aura gained: %s", aura.ActionID)
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		sim.CombatLog.LogAura(sim, aura, proto.CombatLogEventType_CombatLogEventAuraApplied)
+	}
 
 	// don't invoke possible callbacks until the internal state is consistent
 	if aura.OnGain != nil {
@@ -670,6 +681,12 @@ func (aura *Aura) Deactivate(sim *Simulation) {
 		}
 		sim.CurrentTime = oldTime
 	}
+	if sim.CombatLog != nil && !aura.ActionID.IsEmptyAction() {
+		oldTime := sim.CurrentTime
+		sim.CurrentTime = min(sim.CurrentTime, aura.expires)
+		sim.CombatLog.LogAura(sim, aura, proto.CombatLogEventType_CombatLogEventAuraRemoved)
+		sim.CurrentTime = oldTime
+	}
 
 	aura.expires = 0
 	aura.fadeTime = sim.CurrentTime
@@ -0,0 +1,506 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Target AIs with distinct fight phases, e.g. ScriptedTargetAI. The phases of
+// the primary target are used for the damage breakdown.
+type PhasedTargetAI interface {
+	TargetAI
+
+	// Name of the current phase, or an empty string if there is none.
+	CurrentPhaseName() string
+}
+
+// Records damage over time and by fight phase, when enabled in the sim options.
+type BreakdownTracker struct {
+	options     *proto.BreakdownOptions
+	bucketWidth time.Duration
+
+	units  []*unitBreakdown // Indexed by unit index.
+	bossAI PhasedTargetAI
+
+	// Aggregate values, over all iterations.
+	numIterations    int32
+	bucketTotal      []time.Duration
+	bucketIterations []int32
+	phases           []*phaseBreakdown
+
+	// State tracking
+	trackedUntil time.Duration
+	nextSample   int
+}
+
+type unitBreakdown struct {
+	unit *Unit
+
+	damage    []float64
+	healing   []float64
+	resources []*resourceBreakdown
+	actions   map[ActionID][]float64
+	actionIDs []ActionID // In order of first use.
+}
+
+type resourceBreakdown struct {
+	resourceType proto.ResourceType
+	current      func() float64
+	sums         []float64
+}
+
+type phaseBreakdown struct {
+	phaseType proto.PhaseType
+	name      string
+	total     time.Duration
+
+	damage       []float64   // By unit index.
+	targetDamage [][]float64 // By unit index, then target index.
+}
+
+func newBreakdownTracker(env *Environment, options *proto.BreakdownOptions) *BreakdownTracker {
+	tracker := &BreakdownTracker{
+		options:     options,
+		bucketWidth: DurationFromSeconds(options.BucketSeconds),
+	}
+
+	for _, unit := range env.AllUnits {
+		tracker.units = append(tracker.units, newUnitBreakdown(unit))
+	}
+
+	if len(env.Encounter.Targets) > 0 {
+		if ai, ok := env.Encounter.Targets[0].AI.(PhasedTargetAI); ok {
+			tracker.bossAI = ai
+		}
+	}
+
+	return tracker
+}
+
+func newUnitBreakdown(unit *Unit) *unitBreakdown {
+	ub := &unitBreakdown{
+		unit:    unit,
+		actions: make(map[ActionID][]float64),
+	}
+
+	addResource := func(resourceType proto.ResourceType, current func() float64) {
+		ub.resources = append(ub.resources, &resourceBreakdown{
+			resourceType: resourceType,
+			current:      current,
+		})
+	}
+	if unit.HasManaBar() {
+		addResource(proto.ResourceType_ResourceTypeMana, unit.CurrentMana)
+	}
+	if unit.HasRageBar() {
+		addResource(proto.ResourceType_ResourceTypeRage, unit.CurrentRage)
+	}
+	if unit.HasEnergyBar() {
+		addResource(proto.ResourceType_ResourceTypeEnergy, unit.CurrentEnergy)
+	}
+	if unit.HasFocusBar() {
+		addResource(proto.ResourceType_ResourceTypeFocus, unit.CurrentFocus)
+	}
+	if unit.HasRunicPowerBar() {
+		addResource(proto.ResourceType_ResourceTypeRunicPower, unit.CurrentRunicPower)
+	}
+	if unit.HasHealthBar() {
+		addResource(proto.ResourceType_ResourceTypeHealth, unit.CurrentHealth)
+	}
+
+	return ub
+}
+
+func (tracker *BreakdownTracker) hasTimelines() bool {
+	return tracker.bucketWidth > 0
+}
+
+func (tracker *BreakdownTracker) reset() {
+	tracker.trackedUntil = 0
+	tracker.nextSample = 0
+}
+
+// Events exactly on a bucket boundary count towards the earlier bucket, so
+// that damage dealt as the fight ends is part of the last bucket.
+func (tracker *BreakdownTracker) bucketIndex(sim *Simulation) int {
+	return int(max(sim.CurrentTime-1, 0) / tracker.bucketWidth)
+}
+
+// Accounts for fight time up to nextTime, before the sim advances to it. Game
+// state doesn't change between events, so the current state holds until then.
+func (tracker *BreakdownTracker) advance(sim *Simulation, nextTime time.Duration) {
+	if nextTime <= tracker.trackedUntil {
+		return
+	}
+
+	if tracker.options.IncludePhases {
+		elapsed := nextTime - tracker.trackedUntil
+		tracker.executePhase(sim).total += elapsed
+		if bossPhase := tracker.bossPhase(); bossPhase != nil {
+			bossPhase.total += elapsed
+		}
+	}
+
+	if tracker.hasTimelines() {
+		tracker.sampleResources(func(bucketStart time.Duration) bool {
+			return bucketStart <= nextTime
+		})
+	}
+
+	tracker.trackedUntil = nextTime
+}
+
+func (tracker *BreakdownTracker) sampleResources(reached func(bucketStart time.Duration) bool) {
+	for ; reached(time.Duration(tracker.nextSample) * tracker.bucketWidth); tracker.nextSample++ {
+		for _, ub := range tracker.units {
+			for _, resource := range ub.resources {
+				resource.sums = addAtIndex(resource.sums, tracker.nextSample, resource.current())
+			}
+		}
+	}
+}
+
+func (tracker *BreakdownTracker) doneIteration(sim *Simulation) {
+	tracker.advance(sim, sim.Duration)
+	tracker.numIterations++
+
+	if !tracker.hasTimelines() {
+		return
+	}
+
+	tracker.sampleResources(func(bucketStart time.Duration) bool {
+		return bucketStart < sim.Duration
+	})
+
+	for i := 0; time.Duration(i)*tracker.bucketWidth < sim.Duration; i++ {
+		bucketEnd := min(time.Duration(i+1)*tracker.bucketWidth, sim.Duration)
+		covered := bucketEnd - time.Duration(i)*tracker.bucketWidth
+		if i < len(tracker.bucketTotal) {
+			tracker.bucketTotal[i] += covered
+			tracker.bucketIterations[i]++
+		} else {
+			tracker.bucketTotal = append(tracker.bucketTotal, covered)
+			tracker.bucketIterations = append(tracker.bucketIterations, 1)
+		}
+	}
+}
+
+func (tracker *BreakdownTracker) recordDamage(sim *Simulation, spell *Spell, target *Unit, damage float64) {
+	if (sim.CurrentTime < 0) || !spell.Unit.IsOpponent(target) {
+		return
+	}
+
+	unitIndex := spell.Unit.UnitIndex
+	if tracker.hasTimelines() {
+		ub := tracker.units[unitIndex]
+		bucket := tracker.bucketIndex(sim)
+		ub.damage = addAtIndex(ub.damage, bucket, damage)
+
+		if tracker.options.IncludeActions {
+			actionDamage, ok := ub.actions[spell.ActionID]
+			if !ok {
+				ub.actionIDs = append(ub.actionIDs, spell.ActionID)
+			}
+			ub.actions[spell.ActionID] = addAtIndex(actionDamage, bucket, damage)
+		}
+	}
+
+	if tracker.options.IncludePhases {
+		tracker.executePhase(sim).addDamage(unitIndex, target, damage)
+		if bossPhase := tracker.bossPhase(); bossPhase != nil {
+			bossPhase.addDamage(unitIndex, target, damage)
+		}
+	}
+}
+
+func (tracker *BreakdownTracker) recordHealing(sim *Simulation, spell *Spell, healing float64) {
+	if (sim.CurrentTime < 0) || !tracker.hasTimelines() {
+		return
+	}
+
+	ub := tracker.units[spell.Unit.UnitIndex]
+	ub.healing = addAtIndex(ub.healing, tracker.bucketIndex(sim), healing)
+}
+
+func (tracker *BreakdownTracker) executePhase(sim *Simulation) *phaseBreakdown {
+	var name string
+	switch {
+	case sim.IsExecutePhase90():
+		name = "Above 90%"
+	case sim.IsExecutePhase35():
+		name = fmt.Sprintf("Execute <%d%%", sim.executePhase)
+	default:
+		name = "Pre-Execute"
+	}
+	return tracker.getOrCreatePhase(proto.PhaseType_PhaseTypeExecute, name)
+}
+
+func (tracker *BreakdownTracker) bossPhase() *phaseBreakdown {
+	if tracker.bossAI == nil {
+		return nil
+	}
+
+	name := tracker.bossAI.CurrentPhaseName()
+	if name == "" {
+		return nil
+	}
+	return tracker.getOrCreatePhase(proto.PhaseType_PhaseTypeBoss, name)
+}
+
+func (tracker *BreakdownTracker) getOrCreatePhase(phaseType proto.PhaseType, name string) *phaseBreakdown {
+	for _, phase := range tracker.phases {
+		if (phase.phaseType == phaseType) && (phase.name == name) {
+			return phase
+		}
+	}
+
+	phase := &phaseBreakdown{
+		phaseType:    phaseType,
+		name:         name,
+		damage:       make([]float64, len(tracker.units)),
+		targetDamage: make([][]float64, len(tracker.units)),
+	}
+	tracker.phases = append(tracker.phases, phase)
+	return phase
+}
+
+func (phase *phaseBreakdown) addDamage(unitIndex int32, target *Unit, damage float64) {
+	phase.damage[unitIndex] += damage
+	phase.targetDamage[unitIndex] = addAtIndex(phase.targetDamage[unitIndex], int(target.Index), damage)
+}
+
+func addAtIndex(values []float64, idx int, value float64) []float64 {
+	if idx >= len(values) {
+		values = append(values, make([]float64, idx+1-len(values))...)
+	}
+	values[idx] += value
+	return values
+}
+
+func (tracker *BreakdownTracker) ToProto() *proto.BreakdownMetrics {
+	metrics := &proto.BreakdownMetrics{
+		BucketSeconds:    tracker.options.BucketSeconds,
+		BucketIterations: tracker.bucketIterations,
+		Iterations:       tracker.numIterations,
+	}
+	for _, total := range tracker.bucketTotal {
+		metrics.BucketTotalSeconds = append(metrics.BucketTotalSeconds, total.Seconds())
+	}
+
+	if tracker.hasTimelines() {
+		perSecond := func(totals []float64) []float64 {
+			values := make([]float64, len(metrics.BucketTotalSeconds))
+			for i := range min(len(totals), len(values)) {
+				values[i] = totals[i] / metrics.BucketTotalSeconds[i]
+			}
+			return values
+		}
+
+		for _, ub := range tracker.units {
+			unitMetrics := &proto.UnitBreakdown{
+				UnitIndex: ub.unit.UnitIndex,
+				Name:      ub.unit.Label,
+				Dps:       perSecond(ub.damage),
+				Hps:       perSecond(ub.healing),
+			}
+
+			for _, resource := range ub.resources {
+				values := make([]float64, len(tracker.bucketIterations))
+				for i := range min(len(resource.sums), len(values)) {
+					values[i] = resource.sums[i] / float64(tracker.bucketIterations[i])
+				}
+				unitMetrics.Resources = append(unitMetrics.Resources, &proto.ResourceTimeline{
+					Type:   resource.resourceType,
+					Values: values,
+				})
+			}
+
+			for _, actionID := range ub.actionIDs {
+				unitMetrics.Actions = append(unitMetrics.Actions, &proto.ActionTimeline{
+					Id:  actionID.ToProto(),
+					Dps: perSecond(ub.actions[actionID]),
+				})
+			}
+
+			metrics.Units = append(metrics.Units, unitMetrics)
+		}
+	}
+
+	for _, phase := range tracker.phases {
+		phaseMetrics := &proto.PhaseBreakdown{
+			Type:               phase.phaseType,
+			Name:               phase.name,
+			TotalSeconds:       phase.total.Seconds(),
+			AvgDurationSeconds: phase.total.Seconds() / float64(tracker.numIterations),
+		}
+
+		for unitIndex, damage := range phase.damage {
+			if damage == 0 {
+				continue
+			}
+
+			unitDamage := &proto.PhaseUnitDamage{
+				UnitIndex: int32(unitIndex),
+				Dps:       damage / phase.total.Seconds(),
+			}
+			for _, targetDamage := range phase.targetDamage[unitIndex] {
+				unitDamage.TargetDps = append(unitDamage.TargetDps, targetDamage/phase.total.Seconds())
+			}
+			phaseMetrics.Units = append(phaseMetrics.Units, unitDamage)
+		}
+
+		metrics.Phases = append(metrics.Phases, phaseMetrics)
+	}
+
+	return metrics
+}
+
+// Merges the breakdown of another result into base. Averages are weighted by
+// the fight time or number of iterations they cover, and values missing from
+// either side count as 0.
+func combineBreakdownMetrics(base *proto.BreakdownMetrics, add *proto.BreakdownMetrics) {
+	baseSeconds := weightsOf(base.BucketTotalSeconds)
+	addSeconds := weightsOf(add.BucketTotalSeconds)
+	baseIterations := weightsOf(toFloats(base.BucketIterations))
+	addIterations := weightsOf(toFloats(add.BucketIterations))
+
+	for _, unit := range unionBy(&base.Units, add.Units, func(a, b *proto.UnitBreakdown) bool {
+		return a.UnitIndex == b.UnitIndex
+	}, func(u *proto.UnitBreakdown) *proto.UnitBreakdown {
+		return &proto.UnitBreakdown{UnitIndex: u.UnitIndex, Name: u.Name}
+	}) {
+		baseUnit, addUnit := unit.base, unit.add
+
+		baseUnit.Dps = combineAverages(baseUnit.Dps, baseSeconds, addUnit.GetDps(), addSeconds)
+		baseUnit.Hps = combineAverages(baseUnit.Hps, baseSeconds, addUnit.GetHps(), addSeconds)
+
+		for _, resource := range unionBy(&baseUnit.Resources, addUnit.GetResources(), func(a, b *proto.ResourceTimeline) bool {
+			return a.Type == b.Type
+		}, func(r *proto.ResourceTimeline) *proto.ResourceTimeline {
+			return &proto.ResourceTimeline{Type: r.Type}
+		}) {
+			resource.base.Values = combineAverages(resource.base.Values, baseIterations, resource.add.GetValues(), addIterations)
+		}
+
+		for _, action := range unionBy(&baseUnit.Actions, addUnit.GetActions(), func(a, b *proto.ActionTimeline) bool {
+			return googleProto.Equal(a.Id, b.Id)
+		}, func(a *proto.ActionTimeline) *proto.ActionTimeline {
+			return &proto.ActionTimeline{Id: a.Id}
+		}) {
+			action.base.Dps = combineAverages(action.base.Dps, baseSeconds, action.add.GetDps(), addSeconds)
+		}
+	}
+
+	for _, phase := range unionBy(&base.Phases, add.Phases, func(a, b *proto.PhaseBreakdown) bool {
+		return (a.Type == b.Type) && (a.Name == b.Name)
+	}, func(p *proto.PhaseBreakdown) *proto.PhaseBreakdown {
+		return &proto.PhaseBreakdown{Type: p.Type, Name: p.Name}
+	}) {
+		combinePhaseBreakdowns(phase.base, phase.add)
+	}
+
+	bucketTotalSeconds := make([]float64, max(len(base.BucketTotalSeconds), len(add.BucketTotalSeconds)))
+	bucketIterations := make([]int32, max(len(base.BucketIterations), len(add.BucketIterations)))
+	for i := range bucketTotalSeconds {
+		bucketTotalSeconds[i] = baseSeconds(i) + addSeconds(i)
+	}
+	for i := range bucketIterations {
+		bucketIterations[i] = int32(baseIterations(i) + addIterations(i))
+	}
+	base.BucketTotalSeconds = bucketTotalSeconds
+	base.BucketIterations = bucketIterations
+
+	base.Iterations += add.Iterations
+	for _, phase := range base.Phases {
+		phase.AvgDurationSeconds = phase.TotalSeconds / float64(base.Iterations)
+	}
+}
+
+// add is nil for phases the added result never reached.
+func combinePhaseBreakdowns(base *proto.PhaseBreakdown, add *proto.PhaseBreakdown) {
+	baseSeconds := constantWeight(base.TotalSeconds)
+	addSeconds := constantWeight(add.GetTotalSeconds())
+
+	for _, unit := range unionBy(&base.Units, add.GetUnits(), func(a, b *proto.PhaseUnitDamage) bool {
+		return a.UnitIndex == b.UnitIndex
+	}, func(u *proto.PhaseUnitDamage) *proto.PhaseUnitDamage {
+		return &proto.PhaseUnitDamage{UnitIndex: u.UnitIndex}
+	}) {
+		unit.base.Dps = combineAverages([]float64{unit.base.Dps}, baseSeconds, []float64{unit.add.GetDps()}, addSeconds)[0]
+		unit.base.TargetDps = combineAverages(unit.base.TargetDps, baseSeconds, unit.add.GetTargetDps(), addSeconds)
+	}
+
+	base.TotalSeconds += add.GetTotalSeconds()
+}
+
+type matchedPair[T any] struct {
+	base T
+	add  T // Zero value if missing from the added values.
+}
+
+// Pairs up each entry of base with the matching entry of add, appending new
+// entries to base for those only in add.
+func unionBy[T comparable](base *[]T, add []T, matches func(a, b T) bool, newEntry func(T) T) []matchedPair[T] {
+	var zero T
+	pairs := make([]matchedPair[T], 0, len(*base))
+	for _, baseEntry := range *base {
+		pair := matchedPair[T]{base: baseEntry}
+		if idx := slices.IndexFunc(add, func(addEntry T) bool { return matches(baseEntry, addEntry) }); idx != -1 {
+			pair.add = add[idx]
+		}
+		pairs = append(pairs, pair)
+	}
+
+	for _, addEntry := range add {
+		if slices.ContainsFunc(pairs, func(pair matchedPair[T]) bool { return (pair.add != zero) && (pair.add == addEntry) }) {
+			continue
+		}
+		baseEntry := newEntry(addEntry)
+		*base = append(*base, baseEntry)
+		pairs = append(pairs, matchedPair[T]{base: baseEntry, add: addEntry})
+	}
+	return pairs
+}
+
+// Weighted average of two series of averages, element by element.
+func combineAverages(a []float64, aWeight func(int) float64, b []float64, bWeight func(int) float64) []float64 {
+	combined := make([]float64, max(len(a), len(b)))
+	for i := range combined {
+		totalWeight := aWeight(i) + bWeight(i)
+		if totalWeight > 0 {
+			combined[i] = (valueAt(a, i)*aWeight(i) + valueAt(b, i)*bWeight(i)) / totalWeight
+		}
+	}
+	return combined
+}
+
+func valueAt(values []float64, i int) float64 {
+	if i < len(values) {
+		return values[i]
+	}
+	return 0
+}
+
+func weightsOf(weights []float64) func(int) float64 {
+	return func(i int) float64 {
+		return valueAt(weights, i)
+	}
+}
+
+func constantWeight(weight float64) func(int) float64 {
+	return func(int) float64 {
+		return weight
+	}
+}
+
+func toFloats(values []int32) []float64 {
+	floats := make([]float64, len(values))
+	for i, value := range values {
+		floats[i] = float64(value)
+	}
+	return floats
+}
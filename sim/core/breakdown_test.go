@@ -0,0 +1,93 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func TestDamageBreakdown(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.Encounter.ExecuteProportion_90 = 0.9
+	rsr.Encounter.ExecuteProportion_20 = 0.2
+	rsr.Encounter.ExecuteProportion_25 = 0.25
+	rsr.Encounter.ExecuteProportion_35 = 0.35
+	rsr.SimOptions.Breakdown = &proto.BreakdownOptions{
+		BucketSeconds:  10,
+		IncludeActions: true,
+		IncludePhases:  true,
+	}
+
+	checkBreakdown := func(name string, result *proto.RaidSimResult) {
+		if result.Error != nil {
+			t.Fatalf("%s: sim failed: %s", name, result.Error.Message)
+		}
+		breakdown := result.Breakdown
+		if breakdown == nil {
+			t.Fatalf("%s: expected a breakdown", name)
+		}
+		if breakdown.Iterations != rsr.SimOptions.Iterations {
+			t.Errorf("%s: expected %d iterations, got %d", name, rsr.SimOptions.Iterations, breakdown.Iterations)
+		}
+
+		// Player DPS includes the pet's damage.
+		player := result.RaidMetrics.Parties[0].Players[0]
+		getUnit := func(unitIndex int32) *proto.UnitBreakdown {
+			for _, ub := range breakdown.Units {
+				if ub.UnitIndex == unitIndex {
+					return ub
+				}
+			}
+			t.Fatalf("%s: no breakdown for unit %d", name, unitIndex)
+			return nil
+		}
+		unit := getUnit(player.UnitIndex)
+		units := []*proto.UnitBreakdown{unit}
+		for _, pet := range player.Pets {
+			units = append(units, getUnit(pet.UnitIndex))
+		}
+
+		// Averaging the timeline over the fight time gives back the overall DPS.
+		totalSeconds, totalDamage, actionDamage := 0.0, 0.0, 0.0
+		for i, bucketSeconds := range breakdown.BucketTotalSeconds {
+			totalSeconds += bucketSeconds
+			for _, ub := range units {
+				totalDamage += ub.Dps[i] * bucketSeconds
+				for _, action := range ub.Actions {
+					actionDamage += action.Dps[i] * bucketSeconds
+				}
+			}
+		}
+		if dps := totalDamage / totalSeconds; math.Abs(dps-player.Dps.Avg) > 0.001 {
+			t.Errorf("%s: expected the timeline to average %0.3f DPS, got %0.3f", name, player.Dps.Avg, dps)
+		}
+		if math.Abs(actionDamage-totalDamage) > 0.001*totalDamage {
+			t.Errorf("%s: expected actions to add up to %0.0f damage, got %0.0f", name, totalDamage, actionDamage)
+		}
+		if len(unit.Resources) == 0 || unit.Resources[0].Type != proto.ResourceType_ResourceTypeFocus {
+			t.Errorf("%s: expected a focus timeline", name)
+		}
+
+		executeSeconds := 0.0
+		executeNames := make(map[string]bool)
+		for _, phase := range breakdown.Phases {
+			if phase.Type == proto.PhaseType_PhaseTypeExecute {
+				executeSeconds += phase.TotalSeconds
+				executeNames[phase.Name] = true
+			}
+		}
+		if math.Abs(executeSeconds-totalSeconds) > 0.001 {
+			t.Errorf("%s: expected execute phases to cover %0.0fs, got %0.0fs", name, totalSeconds, executeSeconds)
+		}
+		for _, phaseName := range []string{"Above 90%", "Pre-Execute", "Execute <35%", "Execute <25%", "Execute <20%"} {
+			if !executeNames[phaseName] {
+				t.Errorf("%s: missing phase %s", name, phaseName)
+			}
+		}
+	}
+
+	checkBreakdown("single", core.RunRaidSim(rsr))
+	checkBreakdown("concurrent", core.RunRaidSimConcurrent(rsr))
+}
@@ -5,6 +5,7 @@ import (
 	"math"
 	"runtime"
 	"runtime/debug"
+	"slices"
 	"sort"
 	"strings"
 	"sync/atomic"
@@ -18,6 +19,8 @@ import (
 
 const (
 	defaultIterationsPerCombo = 1000
+	// Precision of each combo when the sim core decides the iterations.
+	defaultBulkTargetRelativeStandardError = 0.002
 )
 
 // raidSimRunner runs a standard raid simulation.
@@ -32,8 +35,33 @@ type bulkSimRunner struct {
 }
 
 func BulkSim(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics) *proto.BulkSimResult {
+	return bulkSimWithRunner(signals, request, progress, func(rsr *proto.RaidSimRequest, simProgress chan *proto.ProgressMetrics, _ bool, signals simsignals.Signals) *proto.RaidSimResult {
+		return RunSim(rsr, simProgress, signals)
+	})
+}
+
+// ExternalRaidSimRunner runs a complete raid sim outside of the bulk sim, e.g. on another machine.
+type ExternalRaidSimRunner func(*proto.RaidSimRequest, simsignals.Signals) *proto.RaidSimResult
+
+// BulkSimWithRunner runs a bulk sim, using runner for each of its raid sims.
+func BulkSimWithRunner(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, runner ExternalRaidSimRunner) *proto.BulkSimResult {
+	return bulkSimWithRunner(signals, request, progress, func(rsr *proto.RaidSimRequest, simProgress chan *proto.ProgressMetrics, _ bool, signals simsignals.Signals) *proto.RaidSimResult {
+		result := runner(rsr, signals)
+		if simProgress != nil {
+			simProgress <- &proto.ProgressMetrics{
+				TotalIterations:     rsr.SimOptions.Iterations,
+				CompletedIterations: rsr.SimOptions.Iterations,
+				FinalRaidResult:     result,
+			}
+			close(simProgress)
+		}
+		return result
+	})
+}
+
+func bulkSimWithRunner(signals simsignals.Signals, request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, runner raidSimRunner) *proto.BulkSimResult {
 	bulk := &bulkSimRunner{
-		SingleRaidSimRunner: runSim,
+		SingleRaidSimRunner: runner,
 		Request:             request,
 	}
 
@@ -88,6 +116,12 @@ func BulkSimCombos(signals simsignals.Signals, req *proto.BulkSimCombosRequest)
 		NumCombinations: int32(len(validCombos)),
 		NumIterations:   int32(len(validCombos)) * iterations,
 	}
+	if search := req.BulkSettings.GetTalentSearch(); search != nil {
+		pruneIterations, numKept := talentSearchPruning(search)
+		if len(validCombos) > numKept+1 {
+			result.NumIterations = int32(len(validCombos))*pruneIterations + int32(numKept+1)*iterations
+		}
+	}
 
 	return result
 }
@@ -153,6 +187,27 @@ func (b *bulkSimRunner) Run(signals simsignals.Signals, progress chan *proto.Pro
 	// TODO(Riotdog-GehennasEU): Make this configurable?
 	maxResults := 30
 
+	// Talent searches have many loadouts, so first sim all of them quickly
+	// and only keep the best ones.
+	if search := b.Request.BulkSettings.GetTalentSearch(); search != nil {
+		pruneIterations, numKept := talentSearchPruning(search)
+		if len(validCombos) > numKept+1 {
+			// The pruning pass only needs a rough estimate, even if combos are simmed to a target precision.
+			target := b.Request.BaseSettings.SimOptions.GetTargetRelativeStandardError()
+			for _, combo := range validCombos {
+				combo.req.SimOptions.TargetRelativeStandardError = 0
+			}
+			prunedResults, _, errorOutcome := b.getRankedResults(signals, validCombos, pruneIterations, progress)
+			if errorOutcome != nil {
+				return &proto.BulkSimResult{Error: errorOutcome}
+			}
+			validCombos = keepBestTalentSearchCombos(prunedResults, numKept)
+			for _, combo := range validCombos {
+				combo.req.SimOptions.TargetRelativeStandardError = target
+			}
+		}
+	}
+
 	var rankedResults []*itemSubstitutionSimResult
 	var baseResult *itemSubstitutionSimResult
 
@@ -388,6 +443,22 @@ func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest,
 	iterations := bulkSettings.GetIterationsPerCombo()
 	if iterations <= 0 {
 		iterations = defaultIterationsPerCombo
+		// Fast mode already refines the iterations of the best combos itself.
+		if options := baseSettings.GetSimOptions(); options != nil && !bulkSettings.FastMode && options.TargetRelativeStandardError == 0 {
+			options.TargetRelativeStandardError = defaultBulkTargetRelativeStandardError
+		}
+	}
+
+	talentsToSim := bulkSettings.GetTalentsToSim()
+	if !bulkSettings.SimTalents {
+		talentsToSim = nil
+	}
+	if bulkSettings.TalentSearch != nil {
+		searchedTalents, err := searchTalentLoadouts(bulkSettings.TalentSearch, player)
+		if err != nil {
+			return nil, 0, err
+		}
+		talentsToSim = append(slices.Clone(talentsToSim), searchedTalents...)
 	}
 
 	items := bulkSettings.GetItems()
@@ -432,24 +503,18 @@ func buildCombos(signals simsignals.Signals, baseSettings *proto.RaidSimRequest,
 			// Need to sim base dps of gear loudout
 			validCombos = append(validCombos, singleBulkSim{req: substitutedRequest, cl: changeLog, eq: sub})
 			// Todo(Netzone-GehennasEU): Make this its own step?
-			if !bulkSettings.SimTalents {
-
-			} else {
-				var talentsToSim = bulkSettings.GetTalentsToSim()
-
-				if len(talentsToSim) > 0 {
-					for _, talent := range talentsToSim {
-						sr := goproto.Clone(substitutedRequest).(*proto.RaidSimRequest)
-						cl := *changeLog
-						if sr.Raid.Parties[0].Players[0].TalentsString == talent.TalentsString && goproto.Equal(talent.Glyphs, sr.Raid.Parties[0].Players[0].Glyphs) {
-							continue
-						}
-
-						sr.Raid.Parties[0].Players[0].TalentsString = talent.TalentsString
-						sr.Raid.Parties[0].Players[0].Glyphs = talent.Glyphs
-						cl.TalentLoadout = talent
-						validCombos = append(validCombos, singleBulkSim{req: sr, cl: &cl, eq: sub})
+			if len(talentsToSim) > 0 {
+				for _, talent := range talentsToSim {
+					sr := goproto.Clone(substitutedRequest).(*proto.RaidSimRequest)
+					cl := *changeLog
+					if sr.Raid.Parties[0].Players[0].TalentsString == talent.TalentsString && goproto.Equal(talent.Glyphs, sr.Raid.Parties[0].Players[0].Glyphs) {
+						continue
 					}
+
+					sr.Raid.Parties[0].Players[0].TalentsString = talent.TalentsString
+					sr.Raid.Parties[0].Players[0].Glyphs = talent.Glyphs
+					cl.TalentLoadout = talent
+					validCombos = append(validCombos, singleBulkSim{req: sr, cl: &cl, eq: sub})
 				}
 			}
 		}
@@ -0,0 +1,335 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+const (
+	defaultTalentPointsPerRow    = 5
+	defaultMaxTalentLoadouts     = 10000
+	defaultTalentPruneIterations = 100
+	defaultTalentSearchNumKept   = 30
+)
+
+// talentBuildSearch enumerates the legal ways of spending points in a single talent tree.
+type talentBuildSearch struct {
+	tree         *proto.TalentTreeConfig
+	pointsPerRow int32
+	maxBuilds    int
+
+	// Whether each talent may be changed, and the most points the flexible
+	// talents from each index on can take.
+	flex        []bool
+	maxFlexFrom []int32
+
+	byLocation map[[2]int32]int
+	points     []int32
+	rowPoints  []int32
+	builds     [][]int32
+}
+
+func newTalentBuildSearch(settings *proto.TalentSearchSettings, tree *proto.TalentTreeConfig, basePoints []int32, maxBuilds int) (*talentBuildSearch, error) {
+	search := &talentBuildSearch{
+		tree:         tree,
+		pointsPerRow: settings.PointsPerRow,
+		maxBuilds:    maxBuilds,
+		flex:         make([]bool, len(tree.Talents)),
+		maxFlexFrom:  make([]int32, len(tree.Talents)+1),
+		byLocation:   make(map[[2]int32]int, len(tree.Talents)),
+		points:       slices.Clone(basePoints),
+	}
+	if search.pointsPerRow <= 0 {
+		search.pointsPerRow = defaultTalentPointsPerRow
+	}
+
+	numRows := int32(0)
+	for i, talent := range tree.Talents {
+		loc := talent.GetLocation()
+		if i > 0 {
+			prev := tree.Talents[i-1].GetLocation()
+			if loc.GetRowIdx() < prev.GetRowIdx() || (loc.GetRowIdx() == prev.GetRowIdx() && loc.GetColIdx() <= prev.GetColIdx()) {
+				return nil, fmt.Errorf("talents of tree %s are not in talent string order", tree.Name)
+			}
+		}
+		search.byLocation[[2]int32{loc.GetRowIdx(), loc.GetColIdx()}] = i
+		numRows = max(numRows, loc.GetRowIdx()+1)
+		search.flex[i] = len(settings.FlexTalents) == 0 || slices.Contains(settings.FlexTalents, talent.FieldName)
+	}
+	for _, name := range settings.FlexTalents {
+		if !slices.ContainsFunc(tree.Talents, func(talent *proto.TalentConfig) bool { return talent.FieldName == name }) {
+			return nil, fmt.Errorf("unknown talent %s in tree %s", name, tree.Name)
+		}
+	}
+	for i := len(tree.Talents) - 1; i >= 0; i-- {
+		search.maxFlexFrom[i] = search.maxFlexFrom[i+1] + Ternary(search.flex[i], tree.Talents[i].MaxPoints, 0)
+	}
+	search.rowPoints = make([]int32, numRows)
+
+	return search, nil
+}
+
+// Returns every legal build which spends the same number of points in the
+// flexible talents as basePoints did, keeping the other talents unchanged.
+func (search *talentBuildSearch) run() ([][]int32, error) {
+	var flexPoints int32
+	for i, points := range search.points {
+		if search.flex[i] {
+			flexPoints += points
+		}
+	}
+	if !search.visit(0, flexPoints) {
+		return nil, fmt.Errorf("more than %d talent builds in tree %s, choose fewer talents to change", search.maxBuilds, search.tree.Name)
+	}
+	return search.builds, nil
+}
+
+// Assigns points to the talent at idx and all talents after it. Returns false
+// once too many builds were found.
+func (search *talentBuildSearch) visit(idx int, remaining int32) bool {
+	if idx == len(search.tree.Talents) {
+		if remaining != 0 || !search.isLegal() {
+			return true
+		}
+		search.builds = append(search.builds, slices.Clone(search.points))
+		return len(search.builds) <= search.maxBuilds
+	}
+
+	talent := search.tree.Talents[idx]
+	row := talent.Location.GetRowIdx()
+	options := []int32{search.points[idx]}
+	if search.flex[idx] {
+		options = options[:0]
+		for points := int32(0); points <= min(talent.MaxPoints, remaining); points++ {
+			if remaining-points <= search.maxFlexFrom[idx+1] {
+				options = append(options, points)
+			}
+		}
+	}
+
+	for _, points := range options {
+		// Talents are in row order, so all points below this row are already spent.
+		if points > 0 && search.pointsBelowRow(row) < row*search.pointsPerRow {
+			continue
+		}
+		search.points[idx] = points
+		search.rowPoints[row] += points
+		ok := search.visit(idx+1, remaining-Ternary(search.flex[idx], points, 0))
+		search.rowPoints[row] -= points
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func (search *talentBuildSearch) pointsBelowRow(row int32) int32 {
+	var points int32
+	for _, rowPoints := range search.rowPoints[:row] {
+		points += rowPoints
+	}
+	return points
+}
+
+func (search *talentBuildSearch) isLegal() bool {
+	for i, talent := range search.tree.Talents {
+		if search.points[i] == 0 {
+			continue
+		}
+		if search.pointsBelowRow(talent.Location.GetRowIdx()) < talent.Location.GetRowIdx()*search.pointsPerRow {
+			return false
+		}
+		if prereq := talent.PrereqLocation; prereq != nil {
+			prereqIdx, ok := search.byLocation[[2]int32{prereq.RowIdx, prereq.ColIdx}]
+			if !ok || search.points[prereqIdx] != search.tree.Talents[prereqIdx].MaxPoints {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Splits a talents string into the points spent in each talent of each tree.
+func parseTalentPoints(talentsStr string, trees []*proto.TalentTreeConfig) ([][]int32, error) {
+	treeStrs := strings.Split(talentsStr, "-")
+	if len(treeStrs) > len(trees) {
+		return nil, fmt.Errorf("talents string %s has more than %d trees", talentsStr, len(trees))
+	}
+
+	points := make([][]int32, len(trees))
+	for treeIdx, tree := range trees {
+		points[treeIdx] = make([]int32, len(tree.Talents))
+		if treeIdx >= len(treeStrs) {
+			continue
+		}
+		if len(treeStrs[treeIdx]) > len(tree.Talents) {
+			return nil, fmt.Errorf("talents string %s has too many talents in tree %s", talentsStr, tree.Name)
+		}
+		for talentIdx, char := range treeStrs[treeIdx] {
+			if char < '0' || char > '9' {
+				return nil, fmt.Errorf("invalid talents string %s", talentsStr)
+			}
+			points[treeIdx][talentIdx] = int32(char - '0')
+		}
+	}
+	return points, nil
+}
+
+// Inverse of parseTalentPoints, dropping the trailing 0's of each tree like the UI does.
+func formatTalentPoints(points [][]int32) string {
+	treeStrs := make([]string, len(points))
+	for treeIdx, treePoints := range points {
+		var sb strings.Builder
+		for _, p := range treePoints {
+			sb.WriteByte(byte('0' + p))
+		}
+		treeStrs[treeIdx] = strings.TrimRight(sb.String(), "0")
+	}
+	return strings.Join(treeStrs, "-")
+}
+
+// Returns all ways of filling the 3 glyph slots of one type with candidates.
+// If there are fewer than 3 candidates, the remaining slots keep the current
+// glyphs which are not candidates.
+func glyphCombinations(candidates []int32, current [3]int32) [][3]int32 {
+	candidates = slices.Compact(slices.Sorted(slices.Values(candidates)))
+	if len(candidates) == 0 {
+		return [][3]int32{current}
+	}
+
+	var kept []int32
+	for _, glyph := range current {
+		if glyph != 0 && !slices.Contains(candidates, glyph) {
+			kept = append(kept, glyph)
+		}
+	}
+
+	numChosen := min(len(candidates), 3)
+	var combos [][3]int32
+	var visit func(start int, chosen []int32)
+	visit = func(start int, chosen []int32) {
+		if len(chosen) == numChosen {
+			var combo [3]int32
+			copy(combo[:], append(slices.Clone(chosen), kept...))
+			combos = append(combos, combo)
+			return
+		}
+		for i := start; i < len(candidates); i++ {
+			visit(i+1, append(chosen, candidates[i]))
+		}
+	}
+	visit(0, nil)
+	return combos
+}
+
+func sameGlyphs(a [3]int32, b [3]int32) bool {
+	slices.Sort(a[:])
+	slices.Sort(b[:])
+	return a == b
+}
+
+// Enumerates the talent loadouts requested by a talent search, without the
+// player's current loadout.
+func searchTalentLoadouts(settings *proto.TalentSearchSettings, player *proto.Player) ([]*proto.TalentLoadout, error) {
+	if len(settings.Trees) == 0 {
+		return nil, fmt.Errorf("talent search needs the talent trees of the player's class")
+	}
+	if settings.TreeIndex < 0 || int(settings.TreeIndex) >= len(settings.Trees) {
+		return nil, fmt.Errorf("talent search tree %d does not exist", settings.TreeIndex)
+	}
+	maxLoadouts := int(settings.MaxLoadouts)
+	if maxLoadouts <= 0 {
+		maxLoadouts = defaultMaxTalentLoadouts
+	}
+
+	basePoints, err := parseTalentPoints(player.TalentsString, settings.Trees)
+	if err != nil {
+		return nil, err
+	}
+	tree := settings.Trees[settings.TreeIndex]
+	search, err := newTalentBuildSearch(settings, tree, basePoints[settings.TreeIndex], maxLoadouts)
+	if err != nil {
+		return nil, err
+	}
+	builds, err := search.run()
+	if err != nil {
+		return nil, err
+	}
+
+	glyphs := player.GetGlyphs()
+	currentPrimes := [3]int32{glyphs.GetPrime1(), glyphs.GetPrime2(), glyphs.GetPrime3()}
+	currentMajors := [3]int32{glyphs.GetMajor1(), glyphs.GetMajor2(), glyphs.GetMajor3()}
+	currentMinors := [3]int32{glyphs.GetMinor1(), glyphs.GetMinor2(), glyphs.GetMinor3()}
+	primes := glyphCombinations(settings.PrimeGlyphs, currentPrimes)
+	majors := glyphCombinations(settings.MajorGlyphs, currentMajors)
+	minors := glyphCombinations(settings.MinorGlyphs, currentMinors)
+
+	if numLoadouts := len(builds) * len(primes) * len(majors) * len(minors); numLoadouts > maxLoadouts {
+		return nil, fmt.Errorf("talent search has %d loadouts, more than the maximum of %d", numLoadouts, maxLoadouts)
+	}
+
+	var loadouts []*proto.TalentLoadout
+	for _, build := range builds {
+		points := slices.Clone(basePoints)
+		points[settings.TreeIndex] = build
+		talentsStr := formatTalentPoints(points)
+		sameTalents := slices.Equal(build, basePoints[settings.TreeIndex])
+
+		var changes []string
+		for i, talent := range tree.Talents {
+			if build[i] != basePoints[settings.TreeIndex][i] {
+				changes = append(changes, fmt.Sprintf("%s %d/%d", talent.FieldName, build[i], talent.MaxPoints))
+			}
+		}
+
+		for _, prime := range primes {
+			for _, major := range majors {
+				for _, minor := range minors {
+					if sameTalents && sameGlyphs(prime, currentPrimes) && sameGlyphs(major, currentMajors) && sameGlyphs(minor, currentMinors) {
+						continue
+					}
+					loadouts = append(loadouts, &proto.TalentLoadout{
+						TalentsString: talentsStr,
+						Glyphs: &proto.Glyphs{
+							Prime1: prime[0], Prime2: prime[1], Prime3: prime[2],
+							Major1: major[0], Major2: major[1], Major3: major[2],
+							Minor1: minor[0], Minor2: minor[1], Minor3: minor[2],
+						},
+						Name: strings.Join(changes, ", "),
+					})
+				}
+			}
+		}
+	}
+	return loadouts, nil
+}
+
+// Keeps the best numKept combos of a pruning pass, plus the unchanged base
+// combo so the final results can be compared against it.
+func keepBestTalentSearchCombos(rankedResults []*itemSubstitutionSimResult, numKept int) []singleBulkSim {
+	var kept []singleBulkSim
+	for i, r := range rankedResults {
+		isBase := !r.Substitution.HasItemReplacements() && r.ChangeLog.TalentLoadout == nil
+		if i < numKept || isBase {
+			kept = append(kept, singleBulkSim{req: r.Request, cl: r.ChangeLog, eq: r.Substitution})
+		}
+	}
+	return kept
+}
+
+// Returns the iterations of the pruning pass of a talent search, and how many
+// combos it keeps.
+func talentSearchPruning(settings *proto.TalentSearchSettings) (int32, int) {
+	pruneIterations := settings.PruneIterations
+	if pruneIterations <= 0 {
+		pruneIterations = defaultTalentPruneIterations
+	}
+	numKept := int(settings.NumKept)
+	if numKept <= 0 {
+		numKept = defaultTalentSearchNumKept
+	}
+	return pruneIterations, numKept
+}
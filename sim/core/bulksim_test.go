@@ -1,12 +1,14 @@
 package core
 
 import (
+	"sync"
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/simsignals"
 	"google.golang.org/protobuf/encoding/protojson"
+	goproto "google.golang.org/protobuf/proto"
 )
 
 const (
@@ -474,3 +476,160 @@ func TestGenerateAllEquipmentSubstitutions(t *testing.T) {
 		})
 	}
 }
+
+// A tiny talent tree with 2 points per row:
+//
+//	talentA (2)  talentB (3)
+//	talentC (2, needs talentA)
+//	talentD (1)
+var (
+	tinyTalentTrees = []*proto.TalentTreeConfig{
+		{
+			Name: "Tiny",
+			Talents: []*proto.TalentConfig{
+				{FieldName: "talentA", Location: &proto.TalentLocation{RowIdx: 0, ColIdx: 0}, MaxPoints: 2},
+				{FieldName: "talentB", Location: &proto.TalentLocation{RowIdx: 0, ColIdx: 1}, MaxPoints: 3},
+				{FieldName: "talentC", Location: &proto.TalentLocation{RowIdx: 1, ColIdx: 0}, MaxPoints: 2, PrereqLocation: &proto.TalentLocation{RowIdx: 0, ColIdx: 0}},
+				{FieldName: "talentD", Location: &proto.TalentLocation{RowIdx: 2, ColIdx: 0}, MaxPoints: 1},
+			},
+		},
+		{
+			Name: "Other",
+			Talents: []*proto.TalentConfig{
+				{FieldName: "talentE", Location: &proto.TalentLocation{RowIdx: 0, ColIdx: 0}, MaxPoints: 1},
+			},
+		},
+	}
+	tinyTalentsPlayer = &proto.Player{
+		TalentsString: "2111-1",
+		Glyphs:        &proto.Glyphs{Major1: 5, Major2: 6, Major3: 7},
+	}
+)
+
+func TestSearchTalentLoadouts(t *testing.T) {
+	loadouts, err := searchTalentLoadouts(&proto.TalentSearchSettings{
+		Trees:        tinyTalentTrees,
+		PointsPerRow: 2,
+	}, tinyTalentsPlayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got []string
+	for _, loadout := range loadouts {
+		got = append(got, loadout.TalentsString)
+	}
+	want := []string{"1301-1", "2021-1", "212-1", "2201-1", "221-1", "23-1"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Fatalf("searchTalentLoadouts() returned diff (-want +got):\n%s", diff)
+	}
+	if loadouts[0].Name != "talentA 1/2, talentB 3/3, talentC 0/2" {
+		t.Errorf("Unexpected loadout name %s", loadouts[0].Name)
+	}
+}
+
+func TestSearchTalentLoadoutsWithGlyphs(t *testing.T) {
+	loadouts, err := searchTalentLoadouts(&proto.TalentSearchSettings{
+		Trees:        tinyTalentTrees,
+		PointsPerRow: 2,
+		FlexTalents:  []string{"talentB", "talentC"},
+		MajorGlyphs:  []int32{8, 7, 6, 5},
+	}, tinyTalentsPlayer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 3 talent builds times 4 major glyph combinations, without the current loadout.
+	if len(loadouts) != 11 {
+		t.Fatalf("Expected 11 loadouts, got %d", len(loadouts))
+	}
+	for _, loadout := range loadouts {
+		if loadout.TalentsString == tinyTalentsPlayer.TalentsString && loadout.Glyphs.Major3 == 7 {
+			t.Errorf("Current loadout was not skipped: %v", loadout)
+		}
+	}
+
+	_, err = searchTalentLoadouts(&proto.TalentSearchSettings{
+		Trees:        tinyTalentTrees,
+		PointsPerRow: 2,
+		MajorGlyphs:  []int32{8, 7, 6, 5},
+		MaxLoadouts:  10,
+	}, tinyTalentsPlayer)
+	if err == nil {
+		t.Errorf("Expected an error for too many loadouts")
+	}
+}
+
+func TestBulkSimTalentSearch(t *testing.T) {
+	addToDatabase(tinyItemDatabase)
+
+	var mut sync.Mutex
+	simmedIterations := map[string][]int32{}
+	fakeRunSim := func(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, signals simsignals.Signals) *proto.RaidSimResult {
+		defer close(progress)
+		talents := rsr.Raid.Parties[0].Players[0].TalentsString
+
+		mut.Lock()
+		simmedIterations[talents] = append(simmedIterations[talents], rsr.SimOptions.Iterations)
+		mut.Unlock()
+
+		// Points in talentB are worth the most.
+		dps := &proto.DistributionMetrics{Avg: 1000 + 100*float64(talents[1]-'0') + float64(talents[0]-'0')}
+		return &proto.RaidSimResult{
+			RaidMetrics: &proto.RaidMetrics{
+				Dps:     dps,
+				Parties: []*proto.PartyMetrics{{Players: []*proto.UnitMetrics{{Dps: dps}}}},
+			},
+		}
+	}
+
+	player := goproto.Clone(tinyTalentsPlayer).(*proto.Player)
+	player.Name = "Player"
+	player.Equipment = createEquipmentFromItems(starshardEdge1)
+	bulk := &bulkSimRunner{
+		SingleRaidSimRunner: fakeRunSim,
+		Request: &proto.BulkSimRequest{
+			BaseSettings: &proto.RaidSimRequest{
+				Raid:       &proto.Raid{Parties: []*proto.Party{{Players: []*proto.Player{player}}}},
+				SimOptions: &proto.SimOptions{},
+			},
+			BulkSettings: &proto.BulkSettings{
+				IterationsPerCombo: 1000,
+				TalentSearch: &proto.TalentSearchSettings{
+					Trees:           tinyTalentTrees,
+					PointsPerRow:    2,
+					PruneIterations: 10,
+					NumKept:         2,
+				},
+			},
+		},
+	}
+
+	progress := make(chan *proto.ProgressMetrics, 100)
+	got := bulk.Run(simsignals.CreateSignals(), progress)
+	if got.Error != nil {
+		t.Fatalf("BulkSim() returned error: %v", got.Error.Message)
+	}
+
+	var gotTalents []string
+	for _, r := range got.Results {
+		gotTalents = append(gotTalents, r.TalentLoadout.GetTalentsString())
+	}
+	// The 2 best loadouts and the current one.
+	if diff := cmp.Diff([]string{"23-1", "1301-1", ""}, gotTalents); diff != "" {
+		t.Errorf("BulkSim() returned diff (-want +got):\n%s", diff)
+	}
+
+	for talents, iterations := range simmedIterations {
+		want := []int32{10}
+		if talents == "23-1" || talents == "1301-1" || talents == "2111-1" {
+			want = []int32{10, 1000}
+		}
+		if diff := cmp.Diff(want, iterations); diff != "" {
+			t.Errorf("Iterations for %s differ (-want +got):\n%s", talents, diff)
+		}
+	}
+	if len(simmedIterations) != 7 {
+		t.Errorf("Expected 7 simmed loadouts, got %d", len(simmedIterations))
+	}
+}
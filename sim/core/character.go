@@ -680,6 +680,9 @@ func (character *Character) GetMetricsProto() *proto.UnitMetrics {
 	metrics.Name = character.Name
 	metrics.UnitIndex = character.UnitIndex
 	metrics.Auras = character.auraTracker.GetMetricsProto()
+	if character.Type == PlayerUnit && character.Rotation != nil {
+		metrics.RotationStats = character.Rotation.getMetrics(character.Metrics.dps.n)
+	}
 
 	metrics.Pets = make([]*proto.UnitMetrics, len(character.Pets))
 	for i, pet := range character.Pets {
@@ -0,0 +1,204 @@
+package core
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// CombatLogger records typed combat log events for a single iteration.
+//
+// Like sim.Log, sim.CombatLog is nil whenever events should not be recorded,
+// so callers should always check for nil before building an event.
+type CombatLogger struct {
+	events []*proto.CombatLogEvent
+}
+
+func newCombatLogger() *CombatLogger {
+	return &CombatLogger{}
+}
+
+func (cl *CombatLogger) Events() []*proto.CombatLogEvent {
+	return cl.events
+}
+
+func (cl *CombatLogger) addEvent(sim *Simulation, eventType proto.CombatLogEventType, source *Unit, target *Unit, actionID ActionID) *proto.CombatLogEvent {
+	event := &proto.CombatLogEvent{
+		Timestamp: sim.CurrentTime.Seconds(),
+		Type:      eventType,
+		ActionId:  actionID.ToProto(),
+	}
+	if source != nil {
+		event.Source = source.Label
+		event.SourceIndex = source.UnitIndex
+	}
+	if target != nil {
+		event.Target = target.Label
+		event.TargetIndex = target.UnitIndex
+	}
+	cl.events = append(cl.events, event)
+	return event
+}
+
+func (cl *CombatLogger) LogCastStart(sim *Simulation, spell *Spell, target *Unit, castTime time.Duration) {
+	event := cl.addEvent(sim, proto.CombatLogEventType_CombatLogEventCastStart, spell.Unit, target, spell.ActionID)
+	event.SpellSchool = int32(spell.SpellSchool)
+	event.CastTime = castTime.Seconds()
+}
+
+func (cl *CombatLogger) LogCastSuccess(sim *Simulation, spell *Spell, target *Unit) {
+	event := cl.addEvent(sim, proto.CombatLogEventType_CombatLogEventCastSuccess, spell.Unit, target, spell.ActionID)
+	event.SpellSchool = int32(spell.SpellSchool)
+}
+
+func (cl *CombatLogger) LogDamage(sim *Simulation, spell *Spell, result *SpellResult, isPeriodic bool) {
+	eventType := Ternary(isPeriodic, proto.CombatLogEventType_CombatLogEventPeriodicDamage, proto.CombatLogEventType_CombatLogEventDamage)
+	event := cl.addEvent(sim, eventType, spell.Unit, result.Target, spell.ActionID)
+	event.SpellSchool = int32(spell.SpellSchool)
+	event.Amount = result.Damage
+	event.Outcome = result.Outcome.String()
+	event.Crit = result.DidCrit()
+}
+
+func (cl *CombatLogger) LogHealing(sim *Simulation, spell *Spell, result *SpellResult, isPeriodic bool, overheal float64) {
+	eventType := Ternary(isPeriodic, proto.CombatLogEventType_CombatLogEventPeriodicHeal, proto.CombatLogEventType_CombatLogEventHeal)
+	event := cl.addEvent(sim, eventType, spell.Unit, result.Target, spell.ActionID)
+	event.SpellSchool = int32(spell.SpellSchool)
+	event.Amount = result.Damage
+	event.Overheal = overheal
+	event.Outcome = result.Outcome.String()
+	event.Crit = result.DidCrit()
+}
+
+func (cl *CombatLogger) LogAura(sim *Simulation, aura *Aura, eventType proto.CombatLogEventType) {
+	event := cl.addEvent(sim, eventType, nil, aura.Unit, aura.ActionID)
+	event.Stacks = aura.stacks
+}
+
+// Logs a resource gain or spend. The amount should be negative for spends.
+func (cl *CombatLogger) LogResource(sim *Simulation, unit *Unit, metrics *ResourceMetrics, amount float64, newValue float64) {
+	eventType := Ternary(amount < 0, proto.CombatLogEventType_CombatLogEventResourceSpend, proto.CombatLogEventType_CombatLogEventResourceGain)
+	event := cl.addEvent(sim, eventType, unit, unit, metrics.ActionID)
+	event.Amount = amount
+	event.ResourceType = metrics.Type
+	event.ResourceValue = newValue
+}
+
+func (cl *CombatLogger) LogSummon(sim *Simulation, pet *Pet) {
+	cl.addEvent(sim, proto.CombatLogEventType_CombatLogEventSummon, &pet.Owner.Unit, &pet.Unit, ActionID{})
+}
+
+var combatLogSubevents = map[proto.CombatLogEventType]string{
+	proto.CombatLogEventType_CombatLogEventCastStart:       "SPELL_CAST_START",
+	proto.CombatLogEventType_CombatLogEventCastSuccess:     "SPELL_CAST_SUCCESS",
+	proto.CombatLogEventType_CombatLogEventDamage:          "SPELL_DAMAGE",
+	proto.CombatLogEventType_CombatLogEventPeriodicDamage:  "SPELL_PERIODIC_DAMAGE",
+	proto.CombatLogEventType_CombatLogEventHeal:            "SPELL_HEAL",
+	proto.CombatLogEventType_CombatLogEventPeriodicHeal:    "SPELL_PERIODIC_HEAL",
+	proto.CombatLogEventType_CombatLogEventAuraApplied:     "SPELL_AURA_APPLIED",
+	proto.CombatLogEventType_CombatLogEventAuraRefresh:     "SPELL_AURA_REFRESH",
+	proto.CombatLogEventType_CombatLogEventAuraAppliedDose: "SPELL_AURA_APPLIED_DOSE",
+	proto.CombatLogEventType_CombatLogEventAuraRemovedDose: "SPELL_AURA_REMOVED_DOSE",
+	proto.CombatLogEventType_CombatLogEventAuraRemoved:     "SPELL_AURA_REMOVED",
+	proto.CombatLogEventType_CombatLogEventResourceGain:    "SPELL_ENERGIZE",
+	proto.CombatLogEventType_CombatLogEventResourceSpend:   "SPELL_POWER_SPEND",
+	proto.CombatLogEventType_CombatLogEventSummon:          "SPELL_SUMMON",
+}
+
+// Maps our SpellSchool flags onto the school bitmask used by the game's combat log.
+var combatLogSchoolMasks = []struct {
+	school SpellSchool
+	mask   int32
+}{
+	{SpellSchoolPhysical, 0x1},
+	{SpellSchoolHoly, 0x2},
+	{SpellSchoolFire, 0x4},
+	{SpellSchoolNature, 0x8},
+	{SpellSchoolFrost, 0x10},
+	{SpellSchoolShadow, 0x20},
+	{SpellSchoolArcane, 0x40},
+}
+
+// The game's combat log timestamps are wall clock times, so pretend every
+// pull happens at noon. This keeps prepull events on the same day.
+const combatLogPullTime = time.Hour * 12
+
+// Converts combat log events into Warcraft Logs style text, one event per line:
+//
+//	1/1 12:00:05.123  SUBEVENT,sourceGUID,"source",targetGUID,"target",actionID,school,<event specific fields>
+func CombatLogText(events []*proto.CombatLogEvent) string {
+	var sb strings.Builder
+	for _, event := range events {
+		ts := combatLogPullTime + DurationFromSeconds(event.Timestamp)
+		sb.WriteString(fmt.Sprintf("1/1 %02d:%02d:%02d.%03d  ",
+			int(ts.Hours()), int(ts.Minutes())%60, int(ts.Seconds())%60, ts.Milliseconds()%1000))
+
+		sb.WriteString(combatLogSubevents[event.Type])
+		sb.WriteString(",")
+		sb.WriteString(combatLogUnit(event.Source, event.SourceIndex))
+		sb.WriteString(",")
+		sb.WriteString(combatLogUnit(event.Target, event.TargetIndex))
+		sb.WriteString(",")
+		sb.WriteString(combatLogActionID(event.ActionId))
+		sb.WriteString(",")
+		sb.WriteString(fmt.Sprintf("0x%x", combatLogSchoolMask(SpellSchool(event.SpellSchool))))
+
+		switch event.Type {
+		case proto.CombatLogEventType_CombatLogEventCastStart:
+			sb.WriteString(fmt.Sprintf(",%0.3f", event.CastTime))
+		case proto.CombatLogEventType_CombatLogEventDamage, proto.CombatLogEventType_CombatLogEventPeriodicDamage:
+			sb.WriteString(fmt.Sprintf(",%0.0f,%s,%s", event.Amount, combatLogBool(event.Crit), strings.ToUpper(event.Outcome)))
+		case proto.CombatLogEventType_CombatLogEventHeal, proto.CombatLogEventType_CombatLogEventPeriodicHeal:
+			sb.WriteString(fmt.Sprintf(",%0.0f,%0.0f,%s", event.Amount, event.Overheal, combatLogBool(event.Crit)))
+		case proto.CombatLogEventType_CombatLogEventAuraAppliedDose, proto.CombatLogEventType_CombatLogEventAuraRemovedDose:
+			sb.WriteString(fmt.Sprintf(",%d", event.Stacks))
+		case proto.CombatLogEventType_CombatLogEventResourceGain, proto.CombatLogEventType_CombatLogEventResourceSpend:
+			resourceName := strings.TrimPrefix(event.ResourceType.String(), "ResourceType")
+			sb.WriteString(fmt.Sprintf(",%0.3f,%s,%0.3f", event.Amount, resourceName, event.ResourceValue))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+func combatLogUnit(label string, unitIndex int32) string {
+	if label == "" {
+		return "0000000000000000,nil"
+	}
+	return fmt.Sprintf("Unit-0-%d,%q", unitIndex, label)
+}
+
+func combatLogActionID(actionID *proto.ActionID) string {
+	if actionID == nil {
+		return "0"
+	}
+	switch id := actionID.RawId.(type) {
+	case *proto.ActionID_SpellId:
+		return strconv.Itoa(int(id.SpellId))
+	case *proto.ActionID_ItemId:
+		return "item:" + strconv.Itoa(int(id.ItemId))
+	case *proto.ActionID_OtherId:
+		return "other:" + strconv.Itoa(int(id.OtherId))
+	}
+	return "0"
+}
+
+func combatLogSchoolMask(school SpellSchool) int32 {
+	var mask int32
+	for _, sm := range combatLogSchoolMasks {
+		if school.Matches(sm.school) {
+			mask |= sm.mask
+		}
+	}
+	return mask
+}
+
+func combatLogBool(value bool) string {
+	if value {
+		return "1"
+	}
+	return "nil"
+}
@@ -0,0 +1,68 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestCombatLog(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	textRsr := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+
+	rsr.SimOptions.Iterations = 20
+	rsr.SimOptions.CombatLog = &proto.CombatLogOptions{
+		Format:    proto.CombatLogFormat_CombatLogFormatEvents,
+		Iteration: 15,
+	}
+
+	stRes := core.RunRaidSim(rsr)
+	if len(stRes.CombatLog) == 0 {
+		t.Fatalf("Expected combat log events, got none")
+	}
+
+	seen := make(map[proto.CombatLogEventType]bool)
+	for _, event := range stRes.CombatLog {
+		seen[event.Type] = true
+	}
+	for _, eventType := range []proto.CombatLogEventType{
+		proto.CombatLogEventType_CombatLogEventCastStart,
+		proto.CombatLogEventType_CombatLogEventCastSuccess,
+		proto.CombatLogEventType_CombatLogEventDamage,
+		proto.CombatLogEventType_CombatLogEventAuraApplied,
+		proto.CombatLogEventType_CombatLogEventAuraRemoved,
+		proto.CombatLogEventType_CombatLogEventResourceGain,
+		proto.CombatLogEventType_CombatLogEventResourceSpend,
+	} {
+		if !seen[eventType] {
+			t.Errorf("Missing combat log events of type %s", eventType)
+		}
+	}
+
+	// The concurrent sim must record the same iteration.
+	mtRes := core.RunRaidSimConcurrent(rsr)
+	if len(mtRes.CombatLog) != len(stRes.CombatLog) {
+		t.Fatalf("Concurrent combat log has %d events, expected %d", len(mtRes.CombatLog), len(stRes.CombatLog))
+	}
+	for i := range stRes.CombatLog {
+		if !googleProto.Equal(stRes.CombatLog[i], mtRes.CombatLog[i]) {
+			t.Fatalf("Concurrent combat log differs at event %d: %v vs %v", i, mtRes.CombatLog[i], stRes.CombatLog[i])
+		}
+	}
+
+	textRsr.SimOptions.Iterations = 5
+	textRsr.SimOptions.CombatLog = &proto.CombatLogOptions{
+		Format: proto.CombatLogFormat_CombatLogFormatText,
+	}
+
+	result := core.RunRaidSim(textRsr)
+	if len(result.CombatLog) != 0 {
+		t.Errorf("Expected no combat log events in text mode, got %d", len(result.CombatLog))
+	}
+	if !strings.Contains(result.CombatLogText, "  SPELL_CAST_SUCCESS,") || !strings.Contains(result.CombatLogText, "  SPELL_DAMAGE,") {
+		t.Fatalf("Combat log text is missing expected subevents:\n%s", result.CombatLogText[:min(len(result.CombatLogText), 2000)])
+	}
+}
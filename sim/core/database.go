@@ -18,6 +18,12 @@ var GemsByID = map[int32]Gem{}
 var RandomSuffixesByID = map[int32]RandomSuffix{}
 var EnchantsByEffectID = map[int32]Enchant{}
 var ReforgeStatsByID = map[int32]ReforgeStat{}
+
+// Names of spells and items, only used for display. Filled from the UI
+// database when built with the 'with_db' tag.
+var SpellNamesByID = map[int32]string{}
+var ItemNamesByID = map[int32]string{}
+
 var mutex = &sync.Mutex{}
 
 func addToDatabase(newDB *proto.SimDatabase) {
@@ -187,14 +193,19 @@ type Gem struct {
 	Name  string
 	Stats stats.Stats
 	Color proto.GemColor
+
+	Unique             bool
+	RequiredProfession proto.Profession
 }
 
 func GemFromProto(pData *proto.SimGem) Gem {
 	return Gem{
-		ID:    pData.Id,
-		Name:  pData.Name,
-		Stats: stats.FromProtoArray(pData.Stats),
-		Color: pData.Color,
+		ID:                 pData.Id,
+		Name:               pData.Name,
+		Stats:              stats.FromProtoArray(pData.Stats),
+		Color:              pData.Color,
+		Unique:             pData.Unique,
+		RequiredProfession: pData.RequiredProfession,
 	}
 }
 
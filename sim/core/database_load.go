@@ -59,10 +59,12 @@ func init() {
 
 	for i, gem := range db.Gems {
 		simDB.Gems[i] = &proto.SimGem{
-			Id:    gem.Id,
-			Name:  gem.Name,
-			Color: gem.Color,
-			Stats: gem.Stats,
+			Id:                 gem.Id,
+			Name:               gem.Name,
+			Color:              gem.Color,
+			Stats:              gem.Stats,
+			Unique:             gem.Unique,
+			RequiredProfession: gem.RequiredProfession,
 		}
 	}
 
@@ -76,4 +78,14 @@ func init() {
 	}
 
 	addToDatabase(simDB)
+
+	for _, spell := range db.SpellIcons {
+		SpellNamesByID[spell.Id] = spell.Name
+	}
+	for _, item := range db.ItemIcons {
+		ItemNamesByID[item.Id] = item.Name
+	}
+	for _, item := range db.Items {
+		ItemNamesByID[item.Id] = item.Name
+	}
 }
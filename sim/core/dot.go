@@ -71,7 +71,7 @@ func (dot *Dot) TakeSnapshot(sim *Simulation, doRollover bool) {
 // If the Dot is already active it's duration will be refreshed and the last tick from the previous application will be
 // transfered to the new one
 func (dot *Dot) Apply(sim *Simulation) {
-	if dot.Spell.Flags&SpellFlagSupressDoTApply > 0 {
+	if dot.Spell.Flags&SpellFlagSupressDoTApply > 0 || dot.Unit.isDespawnedEnemy() {
 		return
 	}
 
@@ -84,7 +84,7 @@ func (dot *Dot) Apply(sim *Simulation) {
 // If the Dot is already active it's duration will be refreshed and the last tick from the previous application will be
 // transfered to the new one
 func (dot *Dot) ApplyRollover(sim *Simulation) {
-	if dot.Spell.Flags&SpellFlagSupressDoTApply > 0 {
+	if dot.Spell.Flags&SpellFlagSupressDoTApply > 0 || dot.Unit.isDespawnedEnemy() {
 		return
 	}
 
@@ -93,6 +93,9 @@ func (eb *energyBar) AddEnergy(sim *Simulation, amount float64, metrics *Resourc
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, eb.unit, metrics, amount, newEnergy)
+	}
 
 	eb.currentEnergy = newEnergy
 }
@@ -108,6 +111,9 @@ func (eb *energyBar) SpendEnergy(sim *Simulation, amount float64, metrics *Resou
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %0.3f energy from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, eb.currentEnergy, newEnergy, eb.maxEnergy)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, eb.unit, metrics, -amount, newEnergy)
+	}
 
 	eb.currentEnergy = newEnergy
 }
@@ -176,6 +182,9 @@ func (eb *energyBar) AddComboPoints(sim *Simulation, pointsToAdd int32, metrics
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Gained %d combo points from %s (%d --> %d) of %0.0f total.", pointsToAdd, metrics.ActionID, eb.comboPoints, newComboPoints, 5.0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, eb.unit, metrics, float64(pointsToAdd), float64(newComboPoints))
+	}
 
 	eb.comboPoints = newComboPoints
 }
@@ -184,6 +193,9 @@ func (eb *energyBar) SpendComboPoints(sim *Simulation, metrics *ResourceMetrics)
 	if sim.Log != nil {
 		eb.unit.Log(sim, "Spent %d combo points from %s (%d --> %d) of %0.0f total.", eb.comboPoints, metrics.ActionID, eb.comboPoints, 0, 5.0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, eb.unit, metrics, float64(-eb.comboPoints), 0)
+	}
 	metrics.AddEvent(float64(-eb.comboPoints), float64(-eb.comboPoints))
 	eb.comboPoints = 0
 }
@@ -52,6 +52,10 @@ func NewEnvironment(raidProto *proto.Raid, encounterProto *proto.Encounter, runF
 		State: Created,
 	}
 
+	if raidProto.DeriveBuffsFromRoster {
+		raidProto = withRosterBuffs(raidProto)
+	}
+
 	env.construct(raidProto, encounterProto)
 	raidStats := env.initialize(raidProto, encounterProto)
 	env.finalize(raidProto, encounterProto, raidStats, runFakePrepull)
@@ -125,7 +129,10 @@ func (env *Environment) initialize(raidProto *proto.Raid, encounterProto *proto.
 		}
 	}
 
+	env.setupTargetWaves()
+
 	raidStats := env.Raid.applyCharacterEffects(raidProto)
+	env.setupRaidDamageModel()
 
 	for _, party := range env.Raid.Parties {
 		for _, playerOrPet := range party.PlayersAndPets {
@@ -266,8 +273,10 @@ func (env *Environment) reset(sim *Simulation) {
 	for _, target := range env.Encounter.Targets {
 		target.Reset(sim)
 	}
+	env.Encounter.resetActiveTargets()
 
 	env.Raid.reset(sim)
+	env.Encounter.resetWaves(sim)
 }
 
 // The maximum possible duration for any iteration.
@@ -275,6 +284,9 @@ func (env *Environment) GetMaxDuration() time.Duration {
 	return env.BaseDuration + env.DurationVariation
 }
 
+// The number of targets which are currently spawned. Targets which spawn
+// mid-fight only count once they're up, so AoE spells should check this when
+// cast rather than when they're registered.
 func (env *Environment) GetNumTargets() int32 {
 	return int32(len(env.Encounter.ActiveTargets))
 }
@@ -289,6 +301,15 @@ func (env *Environment) GetTarget(index int32) *Target {
 func (env *Environment) GetTargetUnit(index int32) *Unit {
 	return &env.Encounter.Targets[index].Unit
 }
+
+// Indexes into the currently spawned targets, i.e. index < GetNumTargets(),
+// for AoE spells which hit every active target.
+func (env *Environment) GetActiveTarget(index int32) *Target {
+	return env.Encounter.ActiveTargets[index]
+}
+func (env *Environment) GetActiveTargetUnit(index int32) *Unit {
+	return &env.Encounter.ActiveTargets[index].Unit
+}
 func (env *Environment) NextTarget(target *Unit) *Target {
 	return env.Encounter.Targets[target.Index].NextTarget()
 }
@@ -102,6 +102,9 @@ func (fb *focusBar) AddFocus(sim *Simulation, amount float64, metrics *ResourceM
 		if sim.Log != nil {
 			fb.unit.Log(sim, "Gained %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
 		}
+		if sim.CombatLog != nil {
+			sim.CombatLog.LogResource(sim, fb.unit, metrics, amount, newFocus)
+		}
 		metrics.AddEvent(amount, newFocus-fb.currentFocus)
 	}
 
@@ -123,6 +126,9 @@ func (fb *focusBar) SpendFocus(sim *Simulation, amount float64, metrics *Resourc
 	if sim.Log != nil {
 		fb.unit.Log(sim, "Spent %0.3f focus from %s (%0.3f --> %0.3f) of %0.0f total.", amount, metrics.ActionID, fb.currentFocus, newFocus, fb.maxFocus)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, fb.unit, metrics, -amount, newFocus)
+	}
 
 	fb.currentFocus = newFocus
 }
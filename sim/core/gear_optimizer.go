@@ -0,0 +1,731 @@
+package core
+
+import (
+	"fmt"
+	"math"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Jewelcrafters can use at most this many of their profession gems.
+const maxJewelcraftingGems = 3
+
+// The optimizer prefers solutions with less violation of its constraints over
+// solutions with more EP, so differences below this are treated as equal.
+const optimizerEpsilon = 1e-9
+
+// One item for the gear optimizer, with every reforge and gem it may use.
+type optimizerSlot struct {
+	spec *proto.ItemSpec
+	item Item // Without reforging and gems.
+
+	reforges   []*ReforgeStat // nil for no reforge.
+	gemOptions [][]Gem        // Candidates for each socket.
+
+	// Current choice.
+	reforge int
+	gems    []int
+
+	// Cached results for the current choice.
+	stats     stats.Stats
+	chosen    []Gem
+	numRed    int
+	numYellow int
+	numBlue   int
+}
+
+// A decision of the optimizer, i.e. the reforge of an item or the gem in one of its sockets.
+type optimizerVariable struct {
+	slot   int
+	socket int // -1 for the reforge.
+}
+
+type optimizerCap struct {
+	cap       float64
+	postCapEP float64
+	required  bool
+}
+
+type optimizerSolution struct {
+	key       string
+	choices   []int
+	value     float64
+	violation float64
+}
+
+type gearOptimizer struct {
+	settings *proto.GearOptimizerSettings
+
+	weights stats.Stats
+	caps    [stats.SimStatsLen]*optimizerCap
+
+	baseSettings *proto.RaidSimRequest
+
+	// Stats of the gear with the current choice, and the player's remaining stats.
+	gear       stats.Stats
+	otherStats stats.Stats
+
+	slots     []*optimizerSlot
+	variables []optimizerVariable
+
+	prunedGems map[proto.GemColor][]Gem
+
+	// Meta gem location, or a slot of -1 if there is none.
+	metaSlot   int
+	metaSocket int
+}
+
+// OptimizeGear chooses reforges and gems for the equipped gear of the first
+// player in the request, maximizing EP under the configured stat caps.
+//
+// The search starts from both the equipped gear and a greedy gemming which
+// ignores caps, and improves each by changing one or two reforges or gems at a
+// time until no such change helps anymore.
+func OptimizeGear(request *proto.OptimizeGearRequest) (result *proto.OptimizeGearResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.OptimizeGearResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	player := request.GetBaseSettings().GetRaid().GetParties()[0].GetPlayers()[0]
+	if player.GetEquipment() == nil {
+		return &proto.OptimizeGearResult{Error: &proto.ErrorOutcome{Message: "gear optimizer: player has no equipment"}}
+	}
+	if player.GetDatabase() != nil {
+		addToDatabase(player.GetDatabase())
+	}
+
+	opt := newGearOptimizer(request, player)
+
+	original := opt.snapshot()
+	solutions := map[string]*optimizerSolution{original.key: original}
+
+	for _, start := range []func(){opt.restore(original), opt.greedyStart} {
+		start()
+		opt.search()
+		best := opt.snapshot()
+		solutions[best.key] = best
+		for _, neighbor := range opt.neighbors() {
+			if _, ok := solutions[neighbor.key]; !ok {
+				solutions[neighbor.key] = neighbor
+			}
+		}
+	}
+
+	ranked := make([]*optimizerSolution, 0, len(solutions))
+	for _, solution := range solutions {
+		if solution.violation == 0 || solution == original {
+			ranked = append(ranked, solution)
+		}
+	}
+	sort.Slice(ranked, func(i, j int) bool {
+		return opt.better(ranked[i], ranked[j])
+	})
+
+	numCandidates := max(int(opt.settings.NumCandidates), 1)
+	result = &proto.OptimizeGearResult{
+		Original: opt.toCandidate(player, original),
+	}
+	for _, solution := range ranked[:min(numCandidates, len(ranked))] {
+		if solution.violation > 0 {
+			// Only the original gear can be infeasible.
+			continue
+		}
+		result.Candidates = append(result.Candidates, opt.toCandidate(player, solution))
+	}
+	if len(result.Candidates) == 0 {
+		result.Error = &proto.ErrorOutcome{Message: "gear optimizer: no gear meets the required caps and meta gem requirements"}
+		return result
+	}
+
+	if opt.settings.ValidationIterations > 0 {
+		validateGearCandidates(request, result)
+	}
+	return result
+}
+
+func newGearOptimizer(request *proto.OptimizeGearRequest, player *proto.Player) *gearOptimizer {
+	settings := request.GetSettings()
+	if settings == nil {
+		settings = &proto.GearOptimizerSettings{}
+	}
+
+	opt := &gearOptimizer{
+		settings:     settings,
+		baseSettings: request.BaseSettings,
+		weights:      stats.FromProtoArray(settings.GetEpWeights().GetStats()),
+		prunedGems:   map[proto.GemColor][]Gem{},
+		metaSlot:     -1,
+	}
+	for _, statCap := range settings.StatCaps {
+		opt.caps[statCap.Stat] = &optimizerCap{
+			cap:       statCap.Cap,
+			postCapEP: statCap.PostCapEp,
+			required:  statCap.Required,
+		}
+	}
+
+	professions := []proto.Profession{player.Profession1, player.Profession2}
+	candidateGems := opt.candidateGems(professions)
+
+	for slotIdx, spec := range player.Equipment.Items {
+		if spec.GetId() == 0 {
+			continue
+		}
+		slot := opt.newSlot(spec, candidateGems)
+		for socketIdx, socketColor := range slot.socketColors() {
+			if socketColor == proto.GemColor_GemColorMeta && proto.ItemSlot(slotIdx) == proto.ItemSlot_ItemSlotHead {
+				opt.metaSlot = len(opt.slots)
+				opt.metaSocket = socketIdx
+			}
+		}
+		opt.slots = append(opt.slots, slot)
+	}
+
+	for slotIdx, slot := range opt.slots {
+		if len(slot.reforges) > 1 {
+			opt.variables = append(opt.variables, optimizerVariable{slot: slotIdx, socket: -1})
+		}
+		for socketIdx, options := range slot.gemOptions {
+			if len(options) > 1 {
+				opt.variables = append(opt.variables, optimizerVariable{slot: slotIdx, socket: socketIdx})
+			}
+		}
+	}
+
+	for _, slot := range opt.slots {
+		opt.update(slot)
+		opt.gear = opt.gear.Add(slot.stats)
+	}
+	// Everything but the gear stays the same, so take the rest of the player's stats from the equipped gear.
+	opt.otherStats = opt.finalStats(player.Equipment).Subtract(opt.gear)
+
+	return opt
+}
+
+// Gems to choose from for a player with the given professions.
+func (opt *gearOptimizer) candidateGems(professions []proto.Profession) []Gem {
+	var gems []Gem
+	if len(opt.settings.GemIds) > 0 {
+		for _, gemID := range opt.settings.GemIds {
+			if gem, ok := GemsByID[gemID]; ok {
+				gems = append(gems, gem)
+			}
+		}
+	} else {
+		for _, gem := range GemsByID {
+			gems = append(gems, gem)
+		}
+	}
+
+	gems = slices.DeleteFunc(gems, func(gem Gem) bool {
+		return gem.RequiredProfession != proto.Profession_ProfessionUnknown && !slices.Contains(professions, gem.RequiredProfession)
+	})
+	slices.SortFunc(gems, func(a, b Gem) int { return int(a.ID - b.ID) })
+	return gems
+}
+
+func (opt *gearOptimizer) newSlot(spec *proto.ItemSpec, candidateGems []Gem) *optimizerSlot {
+	item := NewItem(ItemSpec{
+		ID:           spec.Id,
+		RandomSuffix: spec.RandomSuffix,
+		Enchant:      spec.Enchant,
+		Gems:         spec.Gems,
+		Reforging:    spec.Reforging,
+	})
+	currentReforge := item.Reforging
+	currentGems := item.Gems
+	item.Reforging = nil
+	item.Gems = nil
+
+	slot := &optimizerSlot{
+		spec:     spec,
+		item:     item,
+		reforges: []*ReforgeStat{nil},
+	}
+
+	if currentReforge != nil {
+		slot.reforges = append(slot.reforges, currentReforge)
+		slot.reforge = 1
+	}
+	if opt.settings.OptimizeReforges {
+		reforgeIDs := make([]int32, 0, len(ReforgeStatsByID))
+		for id := range ReforgeStatsByID {
+			reforgeIDs = append(reforgeIDs, id)
+		}
+		slices.Sort(reforgeIDs)
+		for _, id := range reforgeIDs {
+			reforge := ReforgeStatsByID[id]
+			if (currentReforge == nil || currentReforge.ID != id) && validateReforging(&item, reforge) {
+				slot.reforges = append(slot.reforges, &reforge)
+			}
+		}
+	} else {
+		slot.reforges = slot.reforges[slot.reforge:]
+		slot.reforge = 0
+	}
+
+	socketColors := slot.socketColors()
+	slot.gemOptions = make([][]Gem, len(socketColors))
+	slot.gems = make([]int, len(socketColors))
+	slot.chosen = make([]Gem, len(socketColors))
+	for socketIdx, socketColor := range socketColors {
+		// The equipped gem is always the first option.
+		var current Gem
+		if socketIdx < len(currentGems) {
+			current = currentGems[socketIdx]
+		}
+		slot.gemOptions[socketIdx] = []Gem{current}
+
+		optimize := opt.settings.OptimizeGems && (socketColor != proto.GemColor_GemColorMeta || opt.settings.OptimizeMetaGem)
+		if !optimize {
+			continue
+		}
+		for _, gem := range opt.pruneGems(candidateGems, socketColor) {
+			if gem.ID != current.ID {
+				slot.gemOptions[socketIdx] = append(slot.gemOptions[socketIdx], gem)
+			}
+		}
+	}
+
+	return slot
+}
+
+// Socket colors of the item. Extra sockets, e.g. from a belt buckle, are prismatic.
+func (slot *optimizerSlot) socketColors() []proto.GemColor {
+	colors := slices.Clone(slot.item.GemSockets)
+	for len(colors) < len(slot.spec.Gems) {
+		colors = append(colors, proto.GemColor_GemColorPrismatic)
+	}
+	return colors
+}
+
+// Returns the gems eligible for a socket, without gems which are never better
+// than another gem of the same color with the same restrictions.
+func (opt *gearOptimizer) pruneGems(gems []Gem, socketColor proto.GemColor) []Gem {
+	if pruned, ok := opt.prunedGems[socketColor]; ok {
+		return pruned
+	}
+
+	eligible := slices.DeleteFunc(slices.Clone(gems), func(gem Gem) bool {
+		return !GemEligibleForSocket(gem, socketColor)
+	})
+
+	var pruned []Gem
+	for i, gem := range eligible {
+		dominated := false
+		for j, other := range eligible {
+			if i == j || other.Color != gem.Color || !gemRestrictionsWithin(other, gem) {
+				continue
+			}
+			// Of two equivalent gems, keep the first.
+			if better := opt.compareGemStats(other, gem); better > 0 || (better == 0 && j < i) {
+				dominated = true
+				break
+			}
+		}
+		if !dominated {
+			pruned = append(pruned, gem)
+		}
+	}
+
+	opt.prunedGems[socketColor] = pruned
+	return pruned
+}
+
+// Whether gem a is usable whenever gem b is.
+func gemRestrictionsWithin(a Gem, b Gem) bool {
+	if a.Unique && !b.Unique {
+		return false
+	}
+	return a.RequiredProfession == proto.Profession_ProfessionUnknown || a.RequiredProfession == b.RequiredProfession
+}
+
+// Returns 1 if gem a is at least as good as gem b for every stat the optimizer
+// cares about and better for some, 0 if they are equivalent, and -1 otherwise.
+func (opt *gearOptimizer) compareGemStats(a Gem, b Gem) int {
+	anyBetter := false
+	for s := 0; s < int(stats.ProtoStatsLen); s++ {
+		if a.Stats[s] == b.Stats[s] {
+			continue
+		}
+
+		slopes := []float64{opt.weights[s]}
+		if statCap := opt.caps[s]; statCap != nil {
+			slopes = append(slopes, statCap.postCapEP)
+			if statCap.required {
+				// Reaching the cap matters on its own.
+				slopes = append(slopes, 1)
+			}
+		}
+		increasing := !slices.ContainsFunc(slopes, func(slope float64) bool { return slope < 0 })
+		decreasing := !slices.ContainsFunc(slopes, func(slope float64) bool { return slope > 0 })
+
+		switch {
+		case increasing && decreasing:
+			// Irrelevant stat.
+		case increasing && a.Stats[s] > b.Stats[s], decreasing && a.Stats[s] < b.Stats[s]:
+			anyBetter = true
+		default:
+			return -1
+		}
+	}
+	return Ternary(anyBetter, 1, 0)
+}
+
+// Recomputes the cached stats and gem colors of a slot after its choice changed.
+func (opt *gearOptimizer) update(slot *optimizerSlot) {
+	item := slot.item
+	item.Reforging = slot.reforges[slot.reforge]
+	for socketIdx, option := range slot.gems {
+		slot.chosen[socketIdx] = slot.gemOptions[socketIdx][option]
+	}
+	item.Gems = slot.chosen
+
+	slot.stats = ItemEquipmentStats(item)
+	slot.numRed, slot.numYellow, slot.numBlue = GemColorCounts(slot.chosen)
+}
+
+func (opt *gearOptimizer) choice(v optimizerVariable) int {
+	slot := opt.slots[v.slot]
+	if v.socket < 0 {
+		return slot.reforge
+	}
+	return slot.gems[v.socket]
+}
+
+func (opt *gearOptimizer) numOptions(v optimizerVariable) int {
+	slot := opt.slots[v.slot]
+	if v.socket < 0 {
+		return len(slot.reforges)
+	}
+	return len(slot.gemOptions[v.socket])
+}
+
+// Changes a variable, returning its previous value.
+func (opt *gearOptimizer) set(v optimizerVariable, option int) int {
+	slot := opt.slots[v.slot]
+	var previous int
+	if v.socket < 0 {
+		previous, slot.reforge = slot.reforge, option
+	} else {
+		previous, slot.gems[v.socket] = slot.gems[v.socket], option
+	}
+	if previous != option {
+		opt.gear = opt.gear.Subtract(slot.stats)
+		opt.update(slot)
+		opt.gear = opt.gear.Add(slot.stats)
+	}
+	return previous
+}
+
+// EP of the current gear. Capped stats are worth their normal EP only up to
+// the cap of the player's total stat.
+//
+// Caps compare against the player's other stats plus the gear's stats, which
+// is exact for ratings but ignores multipliers on e.g. primary stats.
+func (opt *gearOptimizer) value() float64 {
+	value := 0.0
+	for s := 0; s < int(stats.ProtoStatsLen); s++ {
+		if statCap := opt.caps[s]; statCap != nil {
+			amount := opt.otherStats[s] + opt.gear[s]
+			value += opt.weights[s]*min(amount, statCap.cap) + statCap.postCapEP*max(amount-statCap.cap, 0)
+		} else {
+			value += opt.weights[s] * opt.gear[s]
+		}
+	}
+	return value
+}
+
+// How far the current choice is from meeting the optimizer's constraints. 0 if it meets all of them.
+func (opt *gearOptimizer) violation() float64 {
+	violation := 0.0
+	for s, statCap := range opt.caps {
+		if statCap != nil && statCap.required {
+			violation += max(statCap.cap-(opt.otherStats[s]+opt.gear[s]), 0)
+		}
+	}
+
+	if opt.settings.EnsureMetaReqMet && opt.metaSlot >= 0 {
+		if condition, ok := MetaGemConditions[opt.slots[opt.metaSlot].chosen[opt.metaSocket].ID]; ok {
+			numRed, numYellow, numBlue := opt.colorCounts()
+			violation += float64(max(condition.MinRed-numRed, 0) + max(condition.MinYellow-numYellow, 0) + max(condition.MinBlue-numBlue, 0))
+		}
+	}
+
+	numJewelcrafting := 0
+	var uniqueIDs []int32
+	for _, slot := range opt.slots {
+		for _, gem := range slot.chosen {
+			if gem.RequiredProfession == proto.Profession_Jewelcrafting {
+				numJewelcrafting++
+			}
+			if gem.Unique {
+				if slices.Contains(uniqueIDs, gem.ID) {
+					violation++
+				}
+				uniqueIDs = append(uniqueIDs, gem.ID)
+			}
+		}
+	}
+	violation += float64(max(numJewelcrafting-maxJewelcraftingGems, 0))
+
+	return violation
+}
+
+func (opt *gearOptimizer) colorCounts() (numRed int, numYellow int, numBlue int) {
+	for _, slot := range opt.slots {
+		numRed += slot.numRed
+		numYellow += slot.numYellow
+		numBlue += slot.numBlue
+	}
+	return
+}
+
+// Whether solution a is strictly better than b.
+func (opt *gearOptimizer) improves(a *optimizerSolution, b *optimizerSolution) bool {
+	if math.Abs(a.violation-b.violation) > optimizerEpsilon {
+		return a.violation < b.violation
+	}
+	return a.value > b.value+optimizerEpsilon
+}
+
+// Orders solutions from best to worst, breaking ties so the order is deterministic.
+func (opt *gearOptimizer) better(a *optimizerSolution, b *optimizerSolution) bool {
+	if opt.improves(a, b) || opt.improves(b, a) {
+		return opt.improves(a, b)
+	}
+	return a.key < b.key
+}
+
+func (opt *gearOptimizer) snapshot() *optimizerSolution {
+	choices := make([]int, len(opt.variables))
+	keyParts := make([]string, len(opt.variables))
+	for i, v := range opt.variables {
+		choices[i] = opt.choice(v)
+		keyParts[i] = strconv.Itoa(choices[i])
+	}
+	return &optimizerSolution{
+		key:       strings.Join(keyParts, ","),
+		choices:   choices,
+		value:     opt.value(),
+		violation: opt.violation(),
+	}
+}
+
+func (opt *gearOptimizer) restore(solution *optimizerSolution) func() {
+	return func() {
+		for i, v := range opt.variables {
+			opt.set(v, solution.choices[i])
+		}
+	}
+}
+
+// Gems each socket with the gem with the most EP, ignoring caps and constraints.
+func (opt *gearOptimizer) greedyStart() {
+	for _, v := range opt.variables {
+		slot := opt.slots[v.slot]
+		bestOption, bestEP := 0, math.Inf(-1)
+		for option := 0; option < opt.numOptions(v); option++ {
+			var ep float64
+			if v.socket < 0 {
+				if reforge := slot.reforges[option]; reforge != nil {
+					ep = opt.weights[reforge.ToStat] - opt.weights[reforge.FromStat]
+				}
+			} else {
+				for s, value := range slot.gemOptions[v.socket][option].Stats {
+					ep += value * opt.weights[s]
+				}
+			}
+			if ep > bestEP+optimizerEpsilon {
+				bestOption, bestEP = option, ep
+			}
+		}
+		opt.set(v, bestOption)
+	}
+}
+
+// Improves the current choice until no change of one or two variables makes it better.
+func (opt *gearOptimizer) search() {
+	current := opt.snapshot()
+	for {
+		if improved := opt.improveSingle(current); improved != nil {
+			current = improved
+			continue
+		}
+		if improved := opt.improvePairs(current); improved != nil {
+			current = improved
+			continue
+		}
+		return
+	}
+}
+
+func (opt *gearOptimizer) improveSingle(current *optimizerSolution) *optimizerSolution {
+	var best *optimizerSolution
+	bestMove := [2]int{-1, -1}
+	for i, v := range opt.variables {
+		previous := opt.choice(v)
+		for option := 0; option < opt.numOptions(v); option++ {
+			if option == previous {
+				continue
+			}
+			opt.set(v, option)
+			candidate := &optimizerSolution{value: opt.value(), violation: opt.violation()}
+			if opt.improves(candidate, Ternary(best != nil, best, current)) {
+				best = candidate
+				bestMove = [2]int{i, option}
+			}
+		}
+		opt.set(v, previous)
+	}
+
+	if best == nil {
+		return nil
+	}
+	opt.set(opt.variables[bestMove[0]], bestMove[1])
+	return opt.snapshot()
+}
+
+func (opt *gearOptimizer) improvePairs(current *optimizerSolution) *optimizerSolution {
+	for i, v1 := range opt.variables {
+		previous1 := opt.choice(v1)
+		for j := i + 1; j < len(opt.variables); j++ {
+			v2 := opt.variables[j]
+			previous2 := opt.choice(v2)
+			for option1 := 0; option1 < opt.numOptions(v1); option1++ {
+				if option1 == previous1 {
+					continue
+				}
+				opt.set(v1, option1)
+				for option2 := 0; option2 < opt.numOptions(v2); option2++ {
+					if option2 == previous2 {
+						continue
+					}
+					opt.set(v2, option2)
+					candidate := &optimizerSolution{value: opt.value(), violation: opt.violation()}
+					if opt.improves(candidate, current) {
+						return opt.snapshot()
+					}
+				}
+				opt.set(v2, previous2)
+			}
+			opt.set(v1, previous1)
+		}
+	}
+	return nil
+}
+
+// Solutions differing from the current one in a single variable, which meet all constraints.
+func (opt *gearOptimizer) neighbors() []*optimizerSolution {
+	var neighbors []*optimizerSolution
+	for _, v := range opt.variables {
+		previous := opt.choice(v)
+		for option := 0; option < opt.numOptions(v); option++ {
+			if option == previous {
+				continue
+			}
+			opt.set(v, option)
+			if opt.violation() == 0 {
+				neighbors = append(neighbors, opt.snapshot())
+			}
+		}
+		opt.set(v, previous)
+	}
+	return neighbors
+}
+
+func (opt *gearOptimizer) toCandidate(player *proto.Player, solution *optimizerSolution) *proto.GearOptimizerCandidate {
+	opt.restore(solution)()
+
+	equipment := googleProto.Clone(player.Equipment).(*proto.EquipmentSpec)
+	slotIdx := 0
+	for _, spec := range equipment.Items {
+		if spec.GetId() == 0 {
+			continue
+		}
+		slot := opt.slots[slotIdx]
+		slotIdx++
+
+		spec.Reforging = 0
+		if reforge := slot.reforges[slot.reforge]; reforge != nil {
+			spec.Reforging = reforge.ID
+		}
+		spec.Gems = MapSlice(slot.chosen, func(gem Gem) int32 { return gem.ID })
+	}
+
+	metaGemActive := false
+	if opt.metaSlot >= 0 {
+		metaGem := opt.slots[opt.metaSlot].chosen[opt.metaSocket]
+		if condition, ok := MetaGemConditions[metaGem.ID]; ok {
+			metaGemActive = condition.IsMet(opt.colorCounts())
+		} else {
+			metaGemActive = metaGem.ID != 0
+		}
+	}
+
+	return &proto.GearOptimizerCandidate{
+		Equipment:     equipment,
+		Ep:            solution.value,
+		Stats:         &proto.UnitStats{Stats: opt.finalStats(equipment).ToProtoArray()},
+		MetaGemActive: metaGemActive,
+	}
+}
+
+// The player's total stats with the given equipment.
+func (opt *gearOptimizer) finalStats(equipment *proto.EquipmentSpec) stats.Stats {
+	raid := googleProto.Clone(opt.baseSettings.Raid).(*proto.Raid)
+	raid.Parties[0].Players[0].Equipment = equipment
+	computeStatsResult := ComputeStats(&proto.ComputeStatsRequest{
+		Raid:      raid,
+		Encounter: opt.baseSettings.Encounter,
+	})
+	return stats.FromProtoArray(computeStatsResult.RaidStats.Parties[0].Players[0].FinalStats.Stats)
+}
+
+// Sims every candidate and the original gear, and ranks the candidates by DPS,
+// or by HPS if the original gear does more healing than damage.
+func validateGearCandidates(request *proto.OptimizeGearRequest, result *proto.OptimizeGearResult) {
+	simFunc := RunRaidSimConcurrent
+	if IsRunningInWasm() {
+		simFunc = RunRaidSim
+	}
+
+	for _, candidate := range append([]*proto.GearOptimizerCandidate{result.Original}, result.Candidates...) {
+		rsr := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+		rsr.Raid.Parties[0].Players[0].Equipment = candidate.Equipment
+		if rsr.SimOptions == nil {
+			rsr.SimOptions = &proto.SimOptions{}
+		}
+		rsr.SimOptions.Iterations = request.Settings.ValidationIterations
+
+		simResult := simFunc(rsr)
+		if simResult.Error != nil {
+			result.Error = simResult.Error
+			return
+		}
+		playerMetrics := simResult.RaidMetrics.Parties[0].Players[0]
+		candidate.Dps = playerMetrics.Dps
+		candidate.Hps = playerMetrics.Hps
+	}
+
+	useHps := result.Original.Hps.GetAvg() > result.Original.Dps.GetAvg()
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		if useHps {
+			return result.Candidates[i].Hps.GetAvg() > result.Candidates[j].Hps.GetAvg()
+		}
+		return result.Candidates[i].Dps.GetAvg() > result.Candidates[j].Dps.GetAvg()
+	})
+}
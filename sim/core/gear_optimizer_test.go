@@ -0,0 +1,70 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func TestOptimizeGear(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 100
+
+	weights := stats.Stats{}
+	weights[stats.Agility] = 3
+	weights[stats.RangedAttackPower] = 1
+	weights[stats.HitRating] = 2.5
+	weights[stats.CritRating] = 1.2
+	weights[stats.HasteRating] = 1
+	weights[stats.MasteryRating] = 1.1
+	const hitCap = 961
+
+	result := core.OptimizeGear(&proto.OptimizeGearRequest{
+		BaseSettings: rsr,
+		Settings: &proto.GearOptimizerSettings{
+			EpWeights: &proto.UnitStats{Stats: weights.ToProtoArray()},
+			StatCaps: []*proto.StatCap{
+				{Stat: proto.Stat_StatHitRating, Cap: hitCap, PostCapEp: 0, Required: true},
+			},
+			OptimizeReforges:     true,
+			OptimizeGems:         true,
+			EnsureMetaReqMet:     true,
+			NumCandidates:        3,
+			ValidationIterations: 100,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Optimizer failed: %s", result.Error.Message)
+	}
+	if len(result.Candidates) != 3 {
+		t.Fatalf("Expected 3 candidates, got %d", len(result.Candidates))
+	}
+
+	best := result.Candidates[0]
+	if best.Ep <= result.Original.Ep {
+		t.Errorf("Expected more than the original %0.2f EP, got %0.2f", result.Original.Ep, best.Ep)
+	}
+	if !best.MetaGemActive {
+		t.Errorf("Expected an active meta gem")
+	}
+	if best.Dps.GetAvg() == 0 {
+		t.Errorf("Expected validated candidates to have DPS")
+	}
+
+	// The reported stats must match what the sim computes for the new gear.
+	statsRequest := makeTestCase(getTestPlayerMM())
+	statsRequest.Raid.Parties[0].Players[0].Equipment = best.Equipment
+	finalStats := core.ComputeStats(&proto.ComputeStatsRequest{Raid: statsRequest.Raid, Encounter: statsRequest.Encounter}).
+		RaidStats.Parties[0].Players[0].FinalStats.Stats
+	for _, stat := range []proto.Stat{proto.Stat_StatAgility, proto.Stat_StatHitRating, proto.Stat_StatCritRating, proto.Stat_StatMasteryRating} {
+		if math.Abs(finalStats[stat]-best.Stats.Stats[stat]) > 1e-6 {
+			t.Errorf("Expected %s of %0.2f, sim computed %0.2f", stat, best.Stats.Stats[stat], finalStats[stat])
+		}
+	}
+	if hit := best.Stats.Stats[proto.Stat_StatHitRating]; hit < hitCap {
+		t.Errorf("Expected hit rating of at least %d, got %0.0f", hitCap, hit)
+	}
+}
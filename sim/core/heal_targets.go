@@ -0,0 +1,54 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+)
+
+// Healing specs default to healing the first target dummy, i.e. the tank.
+func (character *Character) GetMainTarget() *Unit {
+	target := character.Env.Raid.GetFirstTargetDummy()
+	if target == nil {
+		return &character.Unit
+	}
+	return &target.Unit
+}
+
+// Heals from effects like Atonement go to the lowest health ally.
+func (character *Character) GetLowestHealthAlly() *Unit {
+	var lowest *Unit
+	for _, unit := range character.Env.Raid.AllPlayerUnits {
+		if !unit.HasHealthBar() {
+			continue
+		}
+		if lowest == nil || unit.CurrentHealthPercent() < lowest.CurrentHealthPercent() {
+			lowest = unit
+		}
+	}
+	if lowest == nil {
+		return &character.Unit
+	}
+	return lowest
+}
+
+// Smart heals like Circle of Healing hit their target and the most injured
+// allies around it.
+func (character *Character) GetSmartHealTargets(target *Unit, numTargets int) []*Unit {
+	targets := []*Unit{target}
+	candidates := make([]*Unit, 0, len(character.Env.Raid.AllPlayerUnits))
+	for _, unit := range character.Env.Raid.AllPlayerUnits {
+		if unit != target && unit.HasHealthBar() {
+			candidates = append(candidates, unit)
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b *Unit) int {
+		return cmp.Compare(a.CurrentHealthPercent(), b.CurrentHealthPercent())
+	})
+	for _, unit := range candidates {
+		if len(targets) >= numTargets {
+			break
+		}
+		targets = append(targets, unit)
+	}
+	return targets
+}
@@ -0,0 +1,105 @@
+package core
+
+import (
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Number of gems of each color a meta gem needs to be active.
+// See MetaGemCondition in proto_utils/gems.ts.
+type MetaGemCondition struct {
+	MinRed    int
+	MinYellow int
+	MinBlue   int
+}
+
+func (mgc MetaGemCondition) IsMet(numRed int, numYellow int, numBlue int) bool {
+	return numRed >= mgc.MinRed && numYellow >= mgc.MinYellow && numBlue >= mgc.MinBlue
+}
+
+// Keep this list in order by item ID.
+var MetaGemConditions = map[int32]MetaGemCondition{
+	// Cata gems
+	52289: {MinYellow: 2},             // Fleet Shadowspirit Diamond
+	52291: {MinRed: 3},                // Chaotic Shadowspirit Diamond
+	52292: {MinYellow: 1, MinBlue: 1}, // Bracing Shadowspirit Diamond
+	52293: {MinBlue: 3},               // Eternal Shadowspirit Diamond
+	52294: {MinYellow: 2},             // Austere Shadowspirit Diamond
+	52295: {MinRed: 1, MinYellow: 1},  // Effulgent Shadowspirit Diamond
+	52296: {MinYellow: 2},             // Ember Shadowspirit Diamond
+	52297: {MinYellow: 1, MinBlue: 1}, // Revitalizing Shadowspirit Diamond
+	52298: {MinRed: 2},                // Destructive Shadowspirit Diamond
+	52299: {MinBlue: 2},               // Powerful Shadowspirit Diamond
+	52300: {MinYellow: 1, MinBlue: 1}, // Enigmatic Shadowspirit Diamond
+	52301: {MinYellow: 1, MinBlue: 1}, // Impassive Shadowspirit Diamond
+	52302: {MinYellow: 1, MinBlue: 1}, // Forlorn Shadowspirit Diamond
+	68778: {MinRed: 3},                // Agile Shadowspirit Diamond
+	68779: {MinRed: 3},                // Reverberating Shadowspirit Diamond
+	68780: {MinRed: 3},                // Burning Shadowspirit Diamond
+
+	// WotLK gems
+	41285: {MinRed: 3},                           // Chaotic Skyflare Diamond
+	41307: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Destructive Skyflare Diamond
+	41333: {MinRed: 3},                           // Ember Skyflare Diamond
+	41335: {MinRed: 2, MinYellow: 1},             // Enigmatic Skyflare Diamond
+	41339: {MinRed: 1, MinYellow: 2},             // Swift Skyflare Diamond
+	41375: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Tireless Skyflare Diamond
+	41376: {MinRed: 2},                           // Revitalizing Skyflare Diamond
+	41377: {MinRed: 1, MinBlue: 2},               // Effulgent Skyflare Diamond
+	41378: {MinYellow: 2, MinBlue: 1},            // Forlorn Skyflare Diamond
+	41379: {MinRed: 2, MinBlue: 1},               // Impassive Skyflare Diamond
+	41380: {MinRed: 1, MinBlue: 2},               // Austere Earthsiege Diamond
+	41381: {MinYellow: 2, MinBlue: 1},            // Persistent Earthsiege Diamond
+	41382: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Trenchant Earthsiege Diamond
+	41385: {MinRed: 1, MinBlue: 2},               // Invigorating Earthsiege Diamond
+	41389: {MinRed: 2, MinYellow: 1},             // Beaming Earthsiege Diamond
+	41395: {MinRed: 2, MinBlue: 1},               // Bracing Earthsiege Diamond
+	41396: {MinRed: 2, MinBlue: 1},               // Eternal Earthsiege Diamond
+	41397: {MinBlue: 3},                          // Powerful Earthsiege Diamond
+	41398: {MinRed: 3},                           // Relentless Earthsiege Diamond
+	41400: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Thundering Skyflare Diamond
+	41401: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Insightful Earthsiege Diamond
+	44076: {MinRed: 1, MinYellow: 2},             // Swift Starflare Diamond
+	44078: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Tireless Starflare Diamond
+	44081: {MinRed: 2, MinBlue: 1},               // Enigmatic Starflare Diamond
+	44082: {MinRed: 1, MinBlue: 2},               // Impassive Starflare Diamond
+	44084: {MinYellow: 2, MinBlue: 1},            // Forlorn Starflare Diamond
+	44087: {MinBlue: 3},                          // Persistent Earthshatter Diamond
+	44088: {MinYellow: 1, MinBlue: 2},            // Powerful Earthshatter Diamond
+	44089: {MinRed: 1, MinYellow: 1, MinBlue: 1}, // Trenchant Earthshatter Diamond
+
+	// TBC gems
+	25899: {MinRed: 2, MinYellow: 2, MinBlue: 2}, // Brutal Earthstorm Diamond
+}
+
+// Counts gems towards the red, yellow and blue requirements of meta gems.
+// Hybrid gems count for both of their colors, prismatic gems for all three.
+func GemColorCounts(gems []Gem) (numRed int, numYellow int, numBlue int) {
+	for _, gem := range gems {
+		if gem.Color == proto.GemColor_GemColorMeta || gem.Color == proto.GemColor_GemColorCogwheel || gem.Color == proto.GemColor_GemColorUnknown {
+			continue
+		}
+		if ColorIntersects(proto.GemColor_GemColorRed, gem.Color) {
+			numRed++
+		}
+		if ColorIntersects(proto.GemColor_GemColorYellow, gem.Color) {
+			numYellow++
+		}
+		if ColorIntersects(proto.GemColor_GemColorBlue, gem.Color) {
+			numBlue++
+		}
+	}
+	return
+}
+
+// Returns whether gem can be put into a socket of the given color at all,
+// regardless of whether it matches the socket for the socket bonus.
+func GemEligibleForSocket(gem Gem, socketColor proto.GemColor) bool {
+	switch socketColor {
+	case proto.GemColor_GemColorMeta:
+		return gem.Color == proto.GemColor_GemColorMeta
+	case proto.GemColor_GemColorCogwheel:
+		return gem.Color == proto.GemColor_GemColorCogwheel
+	default:
+		return gem.Color != proto.GemColor_GemColorMeta && gem.Color != proto.GemColor_GemColorCogwheel
+	}
+}
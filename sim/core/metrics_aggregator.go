@@ -24,6 +24,7 @@ type DistributionMetrics struct {
 	minSeed int64
 	hist    map[int32]int32 // rounded DPS to count
 	sample  []float64
+	digest  *quantileDigest
 }
 
 func (distMetrics *DistributionMetrics) reset() {
@@ -53,12 +54,14 @@ func (distMetrics *DistributionMetrics) doneIteration(sim *Simulation) {
 
 	dpsRounded := int32(math.Round(dps/25) * 25)
 	distMetrics.hist[dpsRounded]++
+
+	distMetrics.digest.add(dps)
 }
 
 func (distMetrics *DistributionMetrics) ToProto() *proto.DistributionMetrics {
 	mean, stdev := distMetrics.meanAndStdDev()
 
-	return &proto.DistributionMetrics{
+	metrics := &proto.DistributionMetrics{
 		Avg:       mean,
 		Stdev:     stdev,
 		Max:       distMetrics.max,
@@ -72,13 +75,17 @@ func (distMetrics *DistributionMetrics) ToProto() *proto.DistributionMetrics {
 			N:     int32(distMetrics.n),
 			SumSq: distMetrics.sumSq,
 		},
+		Digest: distMetrics.digest.ToProto(),
 	}
+	distMetrics.digest.fillPercentiles(metrics)
+	return metrics
 }
 
 func NewDistributionMetrics() DistributionMetrics {
 	return DistributionMetrics{
-		hist: make(map[int32]int32),
-		min:  -1,
+		hist:   make(map[int32]int32),
+		min:    -1,
+		digest: newQuantileDigest(),
 	}
 }
 
@@ -88,6 +95,7 @@ type UnitMetrics struct {
 	dtps   DistributionMetrics
 	tmi    DistributionMetrics
 	hps    DistributionMetrics
+	ehps   DistributionMetrics
 	tto    DistributionMetrics
 
 	tmiList   []tmiListItem
@@ -172,6 +180,8 @@ type SpellMetrics struct {
 	TotalHealing         float64 // Healing done by all casts of this spell.
 	TotalCritHealing     float64 // Healing done by all critical casts of this spell.
 	TotalShielding       float64 // Shielding done by all casts of this spell.
+	TotalOverhealing     float64 // Healing by all casts of this spell which went over max health.
+	TotalEffective       float64 // Healing and absorbs by all casts of this spell which weren't wasted.
 	TotalCastTime        time.Duration
 }
 
@@ -201,6 +211,7 @@ type TargetedActionMetrics struct {
 	Healing         float64
 	CritHealing     float64
 	Shielding       float64
+	Overhealing     float64
 	CastTime        time.Duration
 }
 
@@ -230,6 +241,7 @@ func (tam *TargetedActionMetrics) ToProto() *proto.TargetedActionMetrics {
 		Healing:         tam.Healing,
 		CritHealing:     tam.CritHealing,
 		Shielding:       tam.Shielding,
+		Overhealing:     tam.Overhealing,
 		CastTimeMs:      float64(tam.CastTime.Milliseconds()),
 	}
 }
@@ -241,6 +253,7 @@ func NewUnitMetrics() UnitMetrics {
 		dtps:    NewDistributionMetrics(),
 		tmi:     NewDistributionMetrics(),
 		hps:     NewDistributionMetrics(),
+		ehps:    NewDistributionMetrics(),
 		tto:     NewDistributionMetrics(),
 		actions: make(map[ActionID]*ActionMetrics),
 	}
@@ -254,8 +267,8 @@ type ResourceMetrics struct {
 	Gain       float64
 	ActualGain float64
 
-	EventsFromPreviousIterations     int32
-	ActualGainFromPreviousIterations float64
+	EventsFromPreviousIterations  int32
+	actualGainForCurrentIteration float64
 }
 
 func (resourceMetrics *ResourceMetrics) ToProto() *proto.ResourceMetrics {
@@ -271,19 +284,22 @@ func (resourceMetrics *ResourceMetrics) ToProto() *proto.ResourceMetrics {
 
 func (resourceMetrics *ResourceMetrics) reset() {
 	resourceMetrics.EventsFromPreviousIterations = resourceMetrics.Events
-	resourceMetrics.ActualGainFromPreviousIterations = resourceMetrics.ActualGain
+	resourceMetrics.actualGainForCurrentIteration = 0
 }
 func (resourceMetrics *ResourceMetrics) EventsForCurrentIteration() int32 {
 	return resourceMetrics.Events - resourceMetrics.EventsFromPreviousIterations
 }
 func (resourceMetrics *ResourceMetrics) ActualGainForCurrentIteration() float64 {
-	return resourceMetrics.ActualGain - resourceMetrics.ActualGainFromPreviousIterations
+	// Tracked separately rather than as a difference of running totals, so that
+	// rounding doesn't depend on how many iterations came before.
+	return resourceMetrics.actualGainForCurrentIteration
 }
 
 func (resourceMetrics *ResourceMetrics) AddEvent(gain float64, actualGain float64) {
 	resourceMetrics.Events++
 	resourceMetrics.Gain += gain
 	resourceMetrics.ActualGain += actualGain
+	resourceMetrics.actualGainForCurrentIteration += actualGain
 }
 
 func (unitMetrics *UnitMetrics) NewResourceMetrics(actionID ActionID, resourceType proto.ResourceType) *ResourceMetrics {
@@ -382,6 +398,7 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 		tam.Healing += spellTargetMetrics.TotalHealing
 		tam.CritHealing += spellTargetMetrics.TotalCritHealing
 		tam.Shielding += spellTargetMetrics.TotalShielding
+		tam.Overhealing += spellTargetMetrics.TotalOverhealing
 		if !spell.Flags.Matches(SpellFlagPassiveSpell) {
 			tam.CastTime += spellTargetMetrics.TotalCastTime
 		}
@@ -394,6 +411,7 @@ func (unitMetrics *UnitMetrics) addSpellMetrics(spell *Spell, actionID ActionID,
 			unitMetrics.threat.Total += spellTargetMetrics.TotalThreat
 		} else {
 			unitMetrics.hps.Total += spellTargetMetrics.TotalHealing + spellTargetMetrics.TotalShielding
+			unitMetrics.ehps.Total += spellTargetMetrics.TotalEffective
 		}
 	}
 }
@@ -428,6 +446,7 @@ func (unitMetrics *UnitMetrics) reset() {
 	unitMetrics.tmi.reset()
 	unitMetrics.tmiList = nil
 	unitMetrics.hps.reset()
+	unitMetrics.ehps.reset()
 	unitMetrics.tto.reset()
 	unitMetrics.CharacterIterationMetrics = CharacterIterationMetrics{}
 
@@ -471,6 +490,7 @@ func (unitMetrics *UnitMetrics) doneIteration(unit *Unit, sim *Simulation) {
 	unitMetrics.dtps.doneIteration(sim)
 	unitMetrics.tmi.doneIteration(sim)
 	unitMetrics.hps.doneIteration(sim)
+	unitMetrics.ehps.doneIteration(sim)
 	unitMetrics.tto.doneIteration(sim)
 
 	unitMetrics.oomTimeSum += unitMetrics.OOMTime.Seconds()
@@ -559,6 +579,7 @@ func (unitMetrics *UnitMetrics) ToProto() *proto.UnitMetrics {
 		Dtps:          unitMetrics.dtps.ToProto(),
 		Tmi:           unitMetrics.tmi.ToProto(),
 		Hps:           unitMetrics.hps.ToProto(),
+		Ehps:          unitMetrics.ehps.ToProto(),
 		Tto:           unitMetrics.tto.ToProto(),
 		SecondsOomAvg: unitMetrics.oomTimeSum / n,
 		ChanceOfDeath: float64(unitMetrics.numItersDead) / n,
@@ -193,6 +193,9 @@ func (pet *Pet) Enable(sim *Simulation, petAgent PetAgent) {
 		pet.Log(sim, "Pet inherited stats: %s", pet.ApplyStatDependencies(pet.inheritedStats).FlatString())
 		pet.Log(sim, "Pet summoned")
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogSummon(sim, pet)
+	}
 
 	sim.addTracker(&pet.auraTracker)
 
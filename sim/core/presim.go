@@ -69,6 +69,8 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 	presimRequest.SimOptions.RandomSeed = 1
 	presimRequest.SimOptions.Debug = false
 	presimRequest.SimOptions.DebugFirstIteration = false
+	presimRequest.SimOptions.CombatLog = nil
+	presimRequest.SimOptions.Breakdown = nil
 	presimRequest.SimOptions.Iterations = numPresimIterations
 	duration := DurationFromSeconds(presimRequest.Encounter.Duration)
 
@@ -94,7 +96,7 @@ func (sim *Simulation) runPresims(request *proto.RaidSimRequest) *proto.RaidSimR
 		}
 
 		// Run the presim.
-		presimResult := runSim(presimRequest, nil, true, sim.Signals)
+		presimResult := runSim(presimRequest, nil, &presimState{done: true}, sim.Signals)
 		lastResult = presimResult
 
 		if presimResult.Error != nil {
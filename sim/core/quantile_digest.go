@@ -0,0 +1,221 @@
+package core
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Controls the accuracy / size tradeoff of quantile digests. Compressed
+// digests hold roughly this many centroids.
+const digestCompression = 100
+
+// Digests are only compressed once they hold more than this many centroids,
+// so small samples keep exact values.
+const digestBufferSize = 5 * digestCompression
+
+type centroid struct {
+	mean   float64
+	weight float64
+}
+
+// Merging t-digest (Dunning & Ertl), which estimates quantiles of a stream of
+// values in bounded memory. Unlike exact order statistics, digests from
+// separate sims can be merged.
+type quantileDigest struct {
+	centroids []centroid // Sorted by mean.
+	unmerged  []centroid
+	total     float64
+	min       float64
+	max       float64
+}
+
+func newQuantileDigest() *quantileDigest {
+	return &quantileDigest{
+		min: math.Inf(1),
+		max: math.Inf(-1),
+	}
+}
+
+func (digest *quantileDigest) add(value float64) {
+	digest.addCentroid(centroid{mean: value, weight: 1})
+	digest.min = min(digest.min, value)
+	digest.max = max(digest.max, value)
+}
+
+func (digest *quantileDigest) addCentroid(c centroid) {
+	digest.unmerged = append(digest.unmerged, c)
+	digest.total += c.weight
+	if len(digest.centroids)+len(digest.unmerged) > digestBufferSize {
+		digest.compress()
+	}
+}
+
+func (digest *quantileDigest) merge(other *quantileDigest) {
+	for _, c := range other.centroids {
+		digest.addCentroid(c)
+	}
+	for _, c := range other.unmerged {
+		digest.addCentroid(c)
+	}
+	digest.min = min(digest.min, other.min)
+	digest.max = max(digest.max, other.max)
+}
+
+// Sorts pending values into the centroids, without merging any.
+func (digest *quantileDigest) flush() {
+	if len(digest.unmerged) == 0 {
+		return
+	}
+
+	digest.centroids = append(digest.centroids, digest.unmerged...)
+	digest.unmerged = digest.unmerged[:0]
+	slices.SortStableFunc(digest.centroids, func(a, b centroid) int {
+		return cmp.Compare(a.mean, b.mean)
+	})
+}
+
+// Merges neighboring centroids as long as each covers at most 1 unit of the
+// k1 scale function, which keeps centroids near the tails small.
+func (digest *quantileDigest) compress() {
+	digest.flush()
+	if len(digest.centroids) <= 1 {
+		return
+	}
+
+	scale := func(q float64) float64 {
+		return digestCompression / (2 * math.Pi) * math.Asin(2*min(q, 1)-1)
+	}
+
+	compressed := digest.centroids[:1]
+	weightBefore := 0.0
+	kLow := scale(0)
+	for _, c := range digest.centroids[1:] {
+		last := &compressed[len(compressed)-1]
+		if scale((weightBefore+last.weight+c.weight)/digest.total)-kLow <= 1 {
+			last.weight += c.weight
+			last.mean += (c.mean - last.mean) * c.weight / last.weight
+		} else {
+			weightBefore += last.weight
+			kLow = scale(weightBefore / digest.total)
+			compressed = append(compressed, c)
+		}
+	}
+	digest.centroids = compressed
+}
+
+// Estimates the value at quantile q (between 0 and 1), interpolating between
+// centroid means.
+func (digest *quantileDigest) quantile(q float64) float64 {
+	digest.flush()
+	centroids := digest.centroids
+	if len(centroids) == 0 {
+		return 0
+	} else if len(centroids) == 1 {
+		return centroids[0].mean
+	}
+
+	target := q * digest.total
+
+	first := centroids[0]
+	if target < first.weight/2 {
+		return digest.min + (first.mean-digest.min)*target/(first.weight/2)
+	}
+
+	last := centroids[len(centroids)-1]
+	if lastCenter := digest.total - last.weight/2; target > lastCenter {
+		return last.mean + (digest.max-last.mean)*(target-lastCenter)/(last.weight/2)
+	}
+
+	center := first.weight / 2
+	for i := 0; i < len(centroids)-1; i++ {
+		gap := (centroids[i].weight + centroids[i+1].weight) / 2
+		if target <= center+gap {
+			return centroids[i].mean + (centroids[i+1].mean-centroids[i].mean)*(target-center)/gap
+		}
+		center += gap
+	}
+	return last.mean
+}
+
+func (digest *quantileDigest) fillPercentiles(metrics *proto.DistributionMetrics) {
+	metrics.P5 = digest.quantile(0.05)
+	metrics.P25 = digest.quantile(0.25)
+	metrics.Median = digest.quantile(0.5)
+	metrics.P75 = digest.quantile(0.75)
+	metrics.P95 = digest.quantile(0.95)
+}
+
+// Compresses the digest before exporting it, so the proto holds at most
+// around digestCompression centroids rather than a full buffer.
+func (digest *quantileDigest) ToProto() *proto.QuantileDigest {
+	digest.compress()
+	digestProto := &proto.QuantileDigest{
+		Means:   make([]float64, len(digest.centroids)),
+		Weights: make([]float64, len(digest.centroids)),
+	}
+	for i, c := range digest.centroids {
+		digestProto.Means[i] = c.mean
+		digestProto.Weights[i] = c.weight
+	}
+	if len(digest.centroids) > 0 {
+		digestProto.Min = digest.min
+		digestProto.Max = digest.max
+	}
+	return digestProto
+}
+
+func quantileDigestFromProto(digestProto *proto.QuantileDigest) *quantileDigest {
+	digest := newQuantileDigest()
+	if len(digestProto.GetMeans()) == 0 {
+		return digest
+	}
+
+	for i, mean := range digestProto.Means {
+		digest.addCentroid(centroid{mean: mean, weight: digestProto.Weights[i]})
+	}
+	digest.min = digestProto.Min
+	digest.max = digestProto.Max
+	return digest
+}
+
+// ClearQuantileDigests removes the digests from all distribution metrics of a
+// result. Digests are only needed to merge the results of split sims, so they
+// are dropped once a result is final.
+func ClearQuantileDigests(result *proto.RaidSimResult) {
+	clearDistributions := func(distributions ...*proto.DistributionMetrics) {
+		for _, dist := range distributions {
+			if dist != nil {
+				dist.Digest = nil
+			}
+		}
+	}
+
+	var clearUnit func(unit *proto.UnitMetrics)
+	clearUnit = func(unit *proto.UnitMetrics) {
+		clearDistributions(unit.Dps, unit.Threat, unit.Dtps, unit.Tmi, unit.Hps, unit.Ehps, unit.Tto)
+		for _, pet := range unit.Pets {
+			clearUnit(pet)
+		}
+	}
+
+	if raid := result.GetRaidMetrics(); raid != nil {
+		clearDistributions(raid.Dps, raid.Hps)
+		for _, party := range raid.Parties {
+			clearDistributions(party.Dps, party.Hps)
+			for _, player := range party.Players {
+				clearUnit(player)
+			}
+		}
+	}
+	if encounter := result.GetEncounterMetrics(); encounter != nil {
+		for _, target := range encounter.Targets {
+			clearUnit(target)
+		}
+		for _, wave := range encounter.Waves {
+			clearDistributions(wave.Dps)
+		}
+	}
+}
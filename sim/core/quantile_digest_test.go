@@ -0,0 +1,75 @@
+package core
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+var testQuantiles = []float64{0.01, 0.05, 0.25, 0.5, 0.75, 0.95, 0.99}
+
+func TestQuantileDigestSmallSampleIsExact(t *testing.T) {
+	x := SplitMix64{state: 1234567}
+	digest := newQuantileDigest()
+	values := make([]float64, 101)
+	for i := range values {
+		values[i] = float64(i)
+	}
+	// Fisher-Yates shuffle, so values don't arrive in order.
+	for i := len(values) - 1; i > 0; i-- {
+		j := int(x.NextFloat64() * float64(i+1))
+		values[i], values[j] = values[j], values[i]
+	}
+	for _, value := range values {
+		digest.add(value)
+	}
+
+	if median := digest.quantile(0.5); median != 50 {
+		t.Errorf("Expected a median of 50, got %f", median)
+	}
+	if p0, p100 := digest.quantile(0), digest.quantile(1); p0 != 0 || p100 != 100 {
+		t.Errorf("Expected extremes of 0 and 100, got %f and %f", p0, p100)
+	}
+}
+
+func TestQuantileDigestAccuracy(t *testing.T) {
+	x := SplitMix64{state: 1234567}
+	n := 100_000
+	values := make([]float64, n)
+	digest := newQuantileDigest()
+	parts := []*quantileDigest{newQuantileDigest(), newQuantileDigest(), newQuantileDigest()}
+	for i := range values {
+		// Product of uniforms, for a skewed distribution.
+		values[i] = x.NextFloat64() * x.NextFloat64() * 1000
+		digest.add(values[i])
+		parts[i%len(parts)].add(values[i])
+	}
+	slices.Sort(values)
+
+	merged := quantileDigestFromProto(parts[0].ToProto())
+	for _, part := range parts[1:] {
+		partProto := part.ToProto()
+		if len(partProto.Means) > digestCompression {
+			t.Errorf("Expected at most %d exported centroids, got %d", digestCompression, len(partProto.Means))
+		}
+		merged.merge(quantileDigestFromProto(partProto))
+	}
+
+	if len(digest.centroids) > digestBufferSize {
+		t.Errorf("Expected at most %d centroids, got %d", digestBufferSize, len(digest.centroids))
+	}
+
+	for _, q := range testQuantiles {
+		exact := values[int(q*float64(n))]
+		for name, d := range map[string]*quantileDigest{"single": digest, "merged": merged} {
+			// Compare in terms of rank, since that is what t-digest bounds: at most
+			// half the width of a centroid, which is smallest near the tails.
+			tolerance := math.Pi * math.Sqrt(q*(1-q)) / digestCompression
+			estimate := d.quantile(q)
+			rank, _ := slices.BinarySearch(values, estimate)
+			if rankError := math.Abs(float64(rank)/float64(n) - q); rankError > tolerance {
+				t.Errorf("%s: quantile %0.2f estimated as %0.3f (exact %0.3f), rank error %0.4f", name, q, estimate, exact, rankError)
+			}
+		}
+	}
+}
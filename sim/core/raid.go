@@ -2,6 +2,7 @@ package core
 
 import (
 	"slices"
+	"time"
 
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/stats"
@@ -221,6 +222,65 @@ func (raid *Raid) GetFirstTargetDummy() *TargetDummy {
 	return nil
 }
 
+func (raid *Raid) GetTargetDummies() []*TargetDummy {
+	var dummies []*TargetDummy
+	for _, party := range raid.Parties {
+		for _, player := range party.Players {
+			if dummy, ok := player.(*TargetDummy); ok {
+				dummies = append(dummies, dummy)
+			}
+		}
+	}
+	return dummies
+}
+
+// Picks the players hit by a boss ability that strikes count random members of
+// a raidSize-player raid. If the simulated raid is smaller than that, each
+// player is instead hit independently with probability count/raidSize.
+func (raid *Raid) GetRandomPlayerUnits(sim *Simulation, label string, count int, raidSize int) []*Unit {
+	if count <= 0 {
+		return nil
+	}
+
+	if len(raid.AllPlayerUnits) < raidSize {
+		chanceToBeHit := float64(count) / float64(raidSize)
+		var hitUnits []*Unit
+		for _, unit := range raid.AllPlayerUnits {
+			if sim.Proc(chanceToBeHit, label) {
+				hitUnits = append(hitUnits, unit)
+			}
+		}
+		return hitUnits
+	}
+
+	candidates := slices.Clone(raid.AllPlayerUnits)
+	count = min(count, len(candidates))
+	for i := 0; i < count; i++ {
+		j := i + int(sim.RandomFloat(label)*float64(len(candidates)-i))
+		candidates[i], candidates[j] = candidates[j], candidates[i]
+	}
+	return candidates[:count]
+}
+
+// Makes every player move for the given duration, e.g. to dodge a boss
+// mechanic. Players in the middle of a hardcast finish it before moving.
+func (raid *Raid) MoveAllPlayers(sim *Simulation, duration time.Duration) {
+	for _, unit := range raid.AllPlayerUnits {
+		if unit.Hardcast.Expires > sim.CurrentTime && !unit.Hardcast.CanMove {
+			player := unit
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt:     unit.Hardcast.Expires,
+				Priority: ActionPriorityPrePull + 1,
+				OnAction: func(sim *Simulation) {
+					player.MoveDuration(duration, sim)
+				},
+			})
+		} else {
+			unit.MoveDuration(duration, sim)
+		}
+	}
+}
+
 func (raid *Raid) getNextPetIndex() int32 {
 	petIndex := raid.nextPetIndex
 	raid.nextPetIndex++
@@ -229,9 +289,11 @@ func (raid *Raid) getNextPetIndex() int32 {
 
 func (raid *Raid) GetRaidBuffs(baseRaidBuffs *proto.RaidBuffs) *proto.RaidBuffs {
 	// Compute the full raid buffs from the raid.
+	// Work on a copy, since some agents add counts and the base buffs may be
+	// shared between sims.
 	raidBuffs := &proto.RaidBuffs{}
 	if baseRaidBuffs != nil {
-		raidBuffs = baseRaidBuffs
+		raidBuffs = googleProto.Clone(baseRaidBuffs).(*proto.RaidBuffs)
 	}
 	for _, party := range raid.Parties {
 		for _, player := range party.Players {
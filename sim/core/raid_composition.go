@@ -0,0 +1,398 @@
+package core
+
+import (
+	"fmt"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Implemented by agents which bring buffs or debuffs that they don't add in
+// AddRaidBuffs, such as shouts, curses and debuffs from talents. These are
+// only used when a raid derives its buffs from the roster.
+type RosterBuffProvider interface {
+	AddRosterBuffs(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs)
+}
+
+// Paladins bring Kings unless another paladin or a druid already covers it,
+// in which case they bring Might.
+func addPaladinBlessing(raidBuffs *proto.RaidBuffs, buffs *proto.RaidBuffs) {
+	if !raidBuffs.BlessingOfKings && !raidBuffs.MarkOfTheWild {
+		buffs.BlessingOfKings = true
+	} else if !raidBuffs.BlessingOfMight {
+		buffs.BlessingOfMight = true
+	} else {
+		buffs.BlessingOfKings = true
+	}
+}
+
+// A player in the active parties, with the buffs and debuffs they provide.
+type rosterMember struct {
+	player  *proto.Player
+	buffs   *proto.RaidBuffs
+	debuffs *proto.Debuffs
+}
+
+func isEmptyRaidSlot(player *proto.Player) bool {
+	return (player == nil) || (player.Class == proto.Class_ClassUnknown)
+}
+
+func numActiveParties(raid *proto.Raid) int {
+	if raid.NumActiveParties == 0 {
+		return len(raid.Parties)
+	}
+	return min(int(raid.NumActiveParties), len(raid.Parties))
+}
+
+func playerRef(partyIdx int, playerIdx int) *proto.UnitReference {
+	return &proto.UnitReference{Type: proto.UnitReference_Player, Index: int32(partyIdx*5 + playerIdx)}
+}
+
+// Works out which buffs and debuffs each player in the active parties
+// provides, along with the combined buffs and debuffs of the whole roster.
+func deriveRosterBuffs(raid *proto.Raid) ([]*rosterMember, *proto.RaidBuffs, *proto.Debuffs) {
+	var roster []*rosterMember
+	for _, party := range raid.Parties[:numActiveParties(raid)] {
+		for _, player := range party.GetPlayers() {
+			if isEmptyRaidSlot(player) {
+				continue
+			}
+			roster = append(roster, &rosterMember{
+				player:  player,
+				buffs:   &proto.RaidBuffs{},
+				debuffs: &proto.Debuffs{},
+			})
+		}
+	}
+
+	// Ask the agents themselves, so that the buffs follow their talents and
+	// options the same way they do in the sim.
+	var agents []Agent
+	for _, party := range NewRaid(&proto.Raid{Parties: raid.Parties[:numActiveParties(raid)]}).Parties {
+		agents = append(agents, party.Players...)
+	}
+
+	raidBuffs := &proto.RaidBuffs{}
+	raidDebuffs := &proto.Debuffs{}
+	for i, member := range roster {
+		agent := agents[i]
+		agent.AddRaidBuffs(member.buffs)
+		agent.GetCharacter().AddRaidBuffs(member.buffs)
+		if provider, ok := agent.(RosterBuffProvider); ok {
+			provider.AddRosterBuffs(member.buffs, member.debuffs)
+		}
+		googleProto.Merge(raidBuffs, member.buffs)
+		googleProto.Merge(raidDebuffs, member.debuffs)
+	}
+
+	// Blessings go last, so that they fill whatever the rest of the raid is missing.
+	for _, member := range roster {
+		if member.player.Class == proto.Class_ClassPaladin {
+			addPaladinBlessing(raidBuffs, member.buffs)
+			googleProto.Merge(raidBuffs, member.buffs)
+		}
+	}
+
+	// Merging adds up the counts, so recount them.
+	raidBuffs.ManaTideTotemCount = 0
+	for _, member := range roster {
+		raidBuffs.ManaTideTotemCount += member.buffs.ManaTideTotemCount
+	}
+
+	return roster, raidBuffs, raidDebuffs
+}
+
+// Returns a copy of the raid with its buffs and debuffs replaced by the ones
+// from the roster.
+func withRosterBuffs(raid *proto.Raid) *proto.Raid {
+	_, buffs, debuffs := deriveRosterBuffs(raid)
+	raid = googleProto.Clone(raid).(*proto.Raid)
+	raid.Buffs = buffs
+	raid.Debuffs = debuffs
+	return raid
+}
+
+// A group of interchangeable buffs or debuffs, of which only one counts.
+type buffCategory struct {
+	name     string
+	isDebuff bool
+	provided func(buffs *proto.RaidBuffs, debuffs *proto.Debuffs) bool
+}
+
+var buffCategories = []buffCategory{
+	{name: "+5% Base Stats", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.MarkOfTheWild || b.BlessingOfKings || b.DrumsOfTheBurningWild
+	}},
+	{name: "+Stamina", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.PowerWordFortitude || b.CommandingShout || b.BloodPact
+	}},
+	{name: "+Strength and Agility", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.BattleShout || b.HornOfWinter || b.StrengthOfEarthTotem
+	}},
+	{name: "+10% Attack Power", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.TrueshotAura || b.UnleashedRage || b.AbominationsMight || b.BlessingOfMight
+	}},
+	{name: "+10% Melee Haste", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.WindfuryTotem || b.IcyTalons || b.HuntingParty
+	}},
+	{name: "+Mana", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.ArcaneBrilliance || b.FelIntelligence
+	}},
+	{name: "+Mana Regen", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.ManaSpringTotem
+	}},
+	{name: "+Spell Power", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.DemonicPact || b.TotemicWrath || b.FlametongueTotem || b.FelIntelligence
+	}},
+	{name: "+5% Spell Haste", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.MoonkinForm || b.ShadowForm || b.WrathOfAirTotem
+	}},
+	{name: "+3% Damage", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.ArcaneTactics || b.FerociousInspiration || b.Communion
+	}},
+	{name: "+5% Crit", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.LeaderOfThePack || b.ElementalOath || b.HonorAmongThieves || b.Rampage || b.TerrifyingRoar || b.FuriousHowl
+	}},
+	{name: "Major Haste", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.Bloodlust || b.Heroism || b.TimeWarp
+	}},
+	{name: "Mana Replenishment", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.ManaTideTotemCount > 0
+	}},
+	{name: "+Armor", provided: func(b *proto.RaidBuffs, _ *proto.Debuffs) bool {
+		return b.DevotionAura || b.StoneskinTotem
+	}},
+
+	{name: "+8% Spell Damage", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.CurseOfElements || d.EbonPlaguebringer || d.EarthAndMoon || d.MasterPoisoner || d.FireBreath || d.LightningBreath
+	}},
+	{name: "+5% Spell Crit", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.CriticalMass || d.ShadowAndFlame
+	}},
+	{name: "+30% Bleed Damage", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.BloodFrenzy || d.Hemorrhage || d.Mangle || d.Stampede
+	}},
+	{name: "Major Armor Reduction", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.ExposeArmor || d.SunderArmor || d.FaerieFire || d.CorrosiveSpit
+	}},
+	{name: "+4% Physical Damage", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.SavageCombat || d.BrittleBones || d.AcidSpit
+	}},
+	{name: "-Damage Dealt", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.CurseOfWeakness || d.DemoralizingRoar || d.DemoralizingShout || d.Vindication || d.ScarletFever || d.DemoralizingScreech
+	}},
+	{name: "-Attack Speed", isDebuff: true, provided: func(_ *proto.RaidBuffs, d *proto.Debuffs) bool {
+		return d.ThunderClap || d.FrostFever || d.InfectedWounds || d.JudgementsOfTheJust || d.DustCloud || d.EarthShock
+	}},
+}
+
+// Reports which buff and debuff categories the active parties of the raid
+// cover, and who covers them.
+func GetRaidBuffCoverage(raid *proto.Raid) []*proto.BuffCoverage {
+	roster, buffs, debuffs := deriveRosterBuffs(raid)
+
+	coverage := make([]*proto.BuffCoverage, 0, len(buffCategories))
+	for _, category := range buffCategories {
+		entry := &proto.BuffCoverage{
+			Category: category.name,
+			IsDebuff: category.isDebuff,
+			Covered:  category.provided(buffs, debuffs),
+		}
+		for _, member := range roster {
+			if category.provided(member.buffs, member.debuffs) {
+				entry.Providers = append(entry.Providers, member.player.Name)
+			}
+		}
+		coverage = append(coverage, entry)
+	}
+	return coverage
+}
+
+type raidCompositionOptimizer struct {
+	baseSettings *proto.RaidSimRequest
+	locked       []*proto.UnitReference
+	simFunc      func(*proto.RaidSimRequest) *proto.RaidSimResult
+}
+
+// OptimizeRaidComposition reports the buff coverage of the raid's active
+// parties, and searches for the bench players (those in parties beyond
+// num_active_parties) to bring in for the most raid DPS.
+//
+// The search is greedy: each step sims every exchange of one bench player with
+// one unlocked active player or empty active slot, and keeps the best, until
+// max_swaps is reached or no exchange helps anymore. Buffs and debuffs of every
+// simmed roster are derived from its players, and all rosters use the same
+// random seed so that they can be compared with few iterations.
+func OptimizeRaidComposition(request *proto.OptimizeRaidCompositionRequest) (result *proto.OptimizeRaidCompositionResult) {
+	defer func() {
+		if err := recover(); err != nil {
+			result = &proto.OptimizeRaidCompositionResult{
+				Error: &proto.ErrorOutcome{
+					Message: fmt.Sprintf("%v\nStack Trace:\n%s", err, string(debug.Stack())),
+				},
+			}
+		}
+	}()
+
+	if request.GetBaseSettings().GetRaid() == nil {
+		return &proto.OptimizeRaidCompositionResult{Error: &proto.ErrorOutcome{Message: "raid composition: request has no raid"}}
+	}
+
+	opt := newRaidCompositionOptimizer(request)
+
+	original := opt.newCandidate(request.BaseSettings.Raid, nil)
+	result = &proto.OptimizeRaidCompositionResult{
+		Original: original,
+	}
+	if request.GetSettings().GetMaxSwaps() <= 0 {
+		return result
+	}
+
+	if err := opt.evaluate(original); err != nil {
+		result.Error = err
+		return result
+	}
+
+	current := original
+	for numSwaps := 0; numSwaps < int(request.Settings.MaxSwaps); numSwaps++ {
+		var best *proto.RaidCompositionCandidate
+		for _, neighbor := range opt.neighbors(current) {
+			if err := opt.evaluate(neighbor); err != nil {
+				result.Error = err
+				return result
+			}
+			result.Candidates = append(result.Candidates, neighbor)
+			if (best == nil) || (neighbor.RaidDps.Avg > best.RaidDps.Avg) {
+				best = neighbor
+			}
+		}
+
+		if (best == nil) || (best.RaidDps.Avg <= current.RaidDps.Avg) {
+			break
+		}
+		current = best
+	}
+
+	sort.SliceStable(result.Candidates, func(i, j int) bool {
+		return result.Candidates[i].RaidDps.Avg > result.Candidates[j].RaidDps.Avg
+	})
+	return result
+}
+
+func newRaidCompositionOptimizer(request *proto.OptimizeRaidCompositionRequest) *raidCompositionOptimizer {
+	baseSettings := googleProto.Clone(request.BaseSettings).(*proto.RaidSimRequest)
+	if baseSettings.SimOptions == nil {
+		baseSettings.SimOptions = &proto.SimOptions{}
+	}
+	if iterations := request.GetSettings().GetIterations(); iterations > 0 {
+		baseSettings.SimOptions.Iterations = iterations
+	}
+
+	// Every roster uses the same RNG, so that differences between them come
+	// from the roster rather than from luck.
+	if baseSettings.SimOptions.RandomSeed == 0 {
+		baseSettings.SimOptions.RandomSeed = time.Now().UnixNano()
+	}
+	baseSettings.SimOptions.UseLabeledRands = true
+
+	opt := &raidCompositionOptimizer{
+		baseSettings: baseSettings,
+		locked:       append(slices.Clone(request.GetSettings().GetLockedPlayers()), baseSettings.Raid.Tanks...),
+		simFunc:      RunRaidSimConcurrent,
+	}
+	if IsRunningInWasm() {
+		opt.simFunc = RunRaidSim
+	}
+	return opt
+}
+
+func (opt *raidCompositionOptimizer) newCandidate(raid *proto.Raid, swaps []*proto.RaidCompositionSwap) *proto.RaidCompositionCandidate {
+	return &proto.RaidCompositionCandidate{
+		Swaps:    swaps,
+		Raid:     raid,
+		Coverage: GetRaidBuffCoverage(raid),
+	}
+}
+
+func (opt *raidCompositionOptimizer) isLocked(ref *proto.UnitReference) bool {
+	return slices.ContainsFunc(opt.locked, func(locked *proto.UnitReference) bool {
+		return (locked.Type == proto.UnitReference_Player) && (locked.Index == ref.Index)
+	})
+}
+
+// Every roster which exchanges one bench player of the candidate with one of
+// its unlocked active players or empty active slots.
+func (opt *raidCompositionOptimizer) neighbors(candidate *proto.RaidCompositionCandidate) []*proto.RaidCompositionCandidate {
+	raid := candidate.Raid
+	numActive := numActiveParties(raid)
+
+	var neighbors []*proto.RaidCompositionCandidate
+	for benchPartyIdx := numActive; benchPartyIdx < len(raid.Parties); benchPartyIdx++ {
+		for benchIdx, benchPlayer := range raid.Parties[benchPartyIdx].GetPlayers() {
+			if isEmptyRaidSlot(benchPlayer) {
+				continue
+			}
+
+			for activePartyIdx := 0; activePartyIdx < numActive; activePartyIdx++ {
+				for activeIdx := 0; activeIdx < 5; activeIdx++ {
+					activeRef := playerRef(activePartyIdx, activeIdx)
+					if opt.isLocked(activeRef) {
+						continue
+					}
+
+					var activePlayer *proto.Player
+					if players := raid.Parties[activePartyIdx].GetPlayers(); activeIdx < len(players) {
+						activePlayer = players[activeIdx]
+					}
+
+					swap := &proto.RaidCompositionSwap{
+						BenchPlayer:     playerRef(benchPartyIdx, benchIdx),
+						ActivePlayer:    activeRef,
+						BenchPlayerName: benchPlayer.Name,
+					}
+					if !isEmptyRaidSlot(activePlayer) {
+						swap.ActivePlayerName = activePlayer.Name
+					}
+
+					newRaid := googleProto.Clone(raid).(*proto.Raid)
+					swapRaidSlots(newRaid, swap.BenchPlayer, swap.ActivePlayer)
+					neighbors = append(neighbors, opt.newCandidate(newRaid, append(slices.Clone(candidate.Swaps), swap)))
+				}
+			}
+		}
+	}
+	return neighbors
+}
+
+func swapRaidSlots(raid *proto.Raid, a *proto.UnitReference, b *proto.UnitReference) {
+	slotA := raidSlot(raid, a)
+	slotB := raidSlot(raid, b)
+	*slotA, *slotB = *slotB, *slotA
+}
+
+// Pointer to a player slot of the raid, growing the party if needed.
+func raidSlot(raid *proto.Raid, ref *proto.UnitReference) **proto.Player {
+	party := raid.Parties[ref.Index/5]
+	playerIdx := int(ref.Index % 5)
+	for len(party.Players) <= playerIdx {
+		party.Players = append(party.Players, &proto.Player{})
+	}
+	return &party.Players[playerIdx]
+}
+
+func (opt *raidCompositionOptimizer) evaluate(candidate *proto.RaidCompositionCandidate) *proto.ErrorOutcome {
+	rsr := googleProto.Clone(opt.baseSettings).(*proto.RaidSimRequest)
+	rsr.Raid = googleProto.Clone(candidate.Raid).(*proto.Raid)
+	rsr.Raid.DeriveBuffsFromRoster = true
+
+	simResult := opt.simFunc(rsr)
+	if simResult.Error != nil {
+		return simResult.Error
+	}
+	candidate.RaidDps = simResult.RaidMetrics.Dps
+	return nil
+}
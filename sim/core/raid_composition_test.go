@@ -0,0 +1,120 @@
+package core_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func TestOptimizeRaidComposition(t *testing.T) {
+	hunter := getTestPlayerMM()
+	hunter.Name = "Hunter"
+	dk := getTestPlayerBloodDk()
+	dk.Name = "Death Knight"
+	feral := getTestPlayerFeralCat()
+	feral.Name = "Feral"
+
+	rsr := makeTestCase(hunter)
+	rsr.Raid.Parties[0].Players = append(rsr.Raid.Parties[0].Players, dk)
+	rsr.Raid.Parties = append(rsr.Raid.Parties, &proto.Party{Players: []*proto.Player{feral}})
+	rsr.Raid.NumActiveParties = 1
+
+	result := core.OptimizeRaidComposition(&proto.OptimizeRaidCompositionRequest{
+		BaseSettings: rsr,
+		Settings: &proto.RaidCompositionSettings{
+			LockedPlayers: []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 1}},
+			MaxSwaps:      1,
+			Iterations:    50,
+		},
+	})
+	if result.Error != nil {
+		t.Fatalf("Optimizer failed: %s", result.Error.Message)
+	}
+
+	getCoverage := func(candidate *proto.RaidCompositionCandidate, category string) *proto.BuffCoverage {
+		idx := slices.IndexFunc(candidate.Coverage, func(coverage *proto.BuffCoverage) bool {
+			return coverage.Category == category
+		})
+		if idx == -1 {
+			t.Fatalf("No coverage reported for %s", category)
+		}
+		return candidate.Coverage[idx]
+	}
+
+	attackPower := getCoverage(result.Original, "+10% Attack Power")
+	if !attackPower.Covered || !slices.Equal(attackPower.Providers, []string{"Hunter", "Death Knight"}) {
+		t.Errorf("Expected +10%% Attack Power from the hunter and death knight, got %v", attackPower.Providers)
+	}
+	if getCoverage(result.Original, "+30% Bleed Damage").Covered {
+		t.Errorf("Expected +30%% Bleed Damage to be missing without the feral")
+	}
+
+	// The feral can take any of the 4 unlocked slots of the first party.
+	if len(result.Candidates) != 4 {
+		t.Fatalf("Expected 4 candidates, got %d", len(result.Candidates))
+	}
+
+	best := result.Candidates[0]
+	if best.RaidDps.GetAvg() <= result.Original.RaidDps.GetAvg() {
+		t.Errorf("Expected more than the original %0.2f DPS, got %0.2f", result.Original.RaidDps.GetAvg(), best.RaidDps.GetAvg())
+	}
+	if swap := best.Swaps[0]; (swap.BenchPlayerName != "Feral") || (swap.ActivePlayerName != "") {
+		t.Errorf("Expected the feral to fill an empty slot, got %s for %s", swap.BenchPlayerName, swap.ActivePlayerName)
+	}
+	if bleed := getCoverage(best, "+30% Bleed Damage"); !bleed.Covered || !slices.Equal(bleed.Providers, []string{"Feral"}) {
+		t.Errorf("Expected +30%% Bleed Damage from the feral, got %v", bleed.Providers)
+	}
+}
+
+func TestGetRaidBuffCoverageFollowsTalents(t *testing.T) {
+	talented := getTestPlayerFeralCat()
+	talented.Name = "Talented"
+	untalented := getTestPlayerFeralCat()
+	untalented.Name = "Untalented"
+	untalented.TalentsString = ""
+
+	rsr := makeTestCase(talented)
+	rsr.Raid.Parties[0].Players = append(rsr.Raid.Parties[0].Players, untalented)
+
+	coverage := core.GetRaidBuffCoverage(rsr.Raid)
+	getProviders := func(category string) []string {
+		idx := slices.IndexFunc(coverage, func(coverage *proto.BuffCoverage) bool {
+			return coverage.Category == category
+		})
+		if idx == -1 {
+			t.Fatalf("No coverage reported for %s", category)
+		}
+		return coverage[idx].Providers
+	}
+
+	// Leader of the Pack needs the talent, while Faerie Fire and Mangle don't.
+	if providers := getProviders("+5% Crit"); !slices.Equal(providers, []string{"Talented"}) {
+		t.Errorf("Expected +5%% Crit only from the talented feral, got %v", providers)
+	}
+	if providers := getProviders("Major Armor Reduction"); !slices.Equal(providers, []string{"Talented", "Untalented"}) {
+		t.Errorf("Expected Major Armor Reduction from both ferals, got %v", providers)
+	}
+	if providers := getProviders("+30% Bleed Damage"); !slices.Equal(providers, []string{"Talented", "Untalented"}) {
+		t.Errorf("Expected +30%% Bleed Damage from both ferals, got %v", providers)
+	}
+}
+
+func TestDeriveBuffsFromRoster(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	fullBuffsStats := core.ComputeStats(&proto.ComputeStatsRequest{Raid: rsr.Raid, Encounter: rsr.Encounter}).
+		RaidStats.Parties[0].Players[0].FinalStats.Stats
+
+	rsr.Raid.DeriveBuffsFromRoster = true
+	rosterStats := core.ComputeStats(&proto.ComputeStatsRequest{Raid: rsr.Raid, Encounter: rsr.Encounter}).
+		RaidStats.Parties[0].Players[0].FinalStats.Stats
+
+	// A lone hunter brings none of the stat buffs.
+	if rosterStats[proto.Stat_StatAgility] >= fullBuffsStats[proto.Stat_StatAgility] {
+		t.Errorf("Expected less agility than the %0.0f with full buffs, got %0.0f", fullBuffsStats[proto.Stat_StatAgility], rosterStats[proto.Stat_StatAgility])
+	}
+	if !rsr.Raid.Buffs.BlessingOfKings {
+		t.Errorf("Expected the request's buffs to be left unchanged")
+	}
+}
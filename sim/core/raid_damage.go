@@ -0,0 +1,120 @@
+package core
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+// Health pools used for target dummies when the raid damage model doesn't
+// specify its own, roughly in line with raid-buffed level 85 characters.
+const (
+	DefaultDummyHealth     = 150000.0
+	DefaultTankDummyHealth = 200000.0
+)
+
+func defaultRaidDamageInterval(profileType proto.RaidDamageProfileType) time.Duration {
+	switch profileType {
+	case proto.RaidDamageProfileType_RaidDamageSpiky:
+		return time.Second * 3
+	case proto.RaidDamageProfileType_RaidDamagePeriodicAoe:
+		return time.Second * 10
+	case proto.RaidDamageProfileType_RaidDamageTankOnly:
+		return time.Millisecond * 1500
+	default:
+		return time.Second
+	}
+}
+
+// Gives the raid's target dummies health bars, and registers the encounter's
+// raid damage profiles against them.
+func (env *Environment) setupRaidDamageModel() {
+	dummies := env.Raid.GetTargetDummies()
+	if len(dummies) == 0 {
+		return
+	}
+
+	model := env.Encounter.RaidDamage
+	dummyHealth := TernaryFloat64(model.DummyHealth > 0, model.DummyHealth, DefaultDummyHealth)
+	tankHealth := TernaryFloat64(model.TankHealth > 0, model.TankHealth, DefaultTankDummyHealth)
+	numTanks := min(int(max(model.NumTanks, 0)), len(dummies))
+
+	var tanks, raiders []*Unit
+	for i, dummy := range dummies {
+		if i < numTanks {
+			dummy.AddStat(stats.Health, tankHealth)
+			tanks = append(tanks, &dummy.Unit)
+		} else {
+			dummy.AddStat(stats.Health, dummyHealth)
+			raiders = append(raiders, &dummy.Unit)
+		}
+		dummy.EnableHealthBar()
+		dummy.trackChanceOfDeath(nil)
+	}
+
+	for i, profile := range model.Profiles {
+		targets := raiders
+		if profile.Type == proto.RaidDamageProfileType_RaidDamageTankOnly {
+			targets = tanks
+		}
+		if len(targets) == 0 || profile.Dps <= 0 {
+			continue
+		}
+		env.registerRaidDamageProfile(int32(i), profile, targets)
+	}
+}
+
+func (env *Environment) registerRaidDamageProfile(index int32, profile *proto.RaidDamageProfile, targets []*Unit) {
+	source := env.Encounter.TargetUnits[0]
+
+	interval := DurationFromSeconds(profile.IntervalSeconds)
+	if interval <= 0 {
+		interval = defaultRaidDamageInterval(profile.Type)
+	}
+	variation := Clamp(profile.DamageVariation, 0, 1)
+
+	// Each affected dummy takes profile.Dps on average, so a spike on a single
+	// dummy carries the damage for all of them.
+	damagePerEvent := profile.Dps * interval.Seconds()
+	isSpiky := profile.Type == proto.RaidDamageProfileType_RaidDamageSpiky
+	if isSpiky {
+		damagePerEvent *= float64(len(targets))
+	}
+
+	spell := source.RegisterSpell(SpellConfig{
+		ActionID:    ActionID{OtherID: proto.OtherAction_OtherActionRaidDamage, Tag: index + 1},
+		SpellSchool: SpellSchoolFromProto(profile.School),
+		ProcMask:    ProcMaskEmpty,
+		Flags:       SpellFlagIgnoreAttackerModifiers | SpellFlagIgnoreResists | SpellFlagNoOnCastComplete,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+			baseDamage := damagePerEvent * (1 + variation*(2*sim.RandomFloat("Raid Damage Variation")-1))
+			spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeAlwaysHit)
+		},
+	})
+
+	source.RegisterResetEffect(func(sim *Simulation) {
+		StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: DurationFromSeconds(max(profile.StartSeconds, 0)),
+			OnAction: func(sim *Simulation) {
+				StartPeriodicAction(sim, PeriodicActionOptions{
+					Period:          interval,
+					TickImmediately: true,
+					OnAction: func(sim *Simulation) {
+						if isSpiky {
+							spell.Cast(sim, targets[int(sim.RandomFloat("Raid Damage Target")*float64(len(targets)))])
+							return
+						}
+						for _, target := range targets {
+							spell.Cast(sim, target)
+						}
+					},
+				})
+			},
+		})
+	})
+}
@@ -0,0 +1,56 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func TestRaidDamageModel(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 20
+	rsr.Raid.TargetDummies = 3
+	rsr.Encounter.RaidDamage = &proto.RaidDamageModel{
+		NumTanks:    1,
+		DummyHealth: 1e6,
+		Profiles: []*proto.RaidDamageProfile{
+			{Type: proto.RaidDamageProfileType_RaidDamageConstant, Dps: 1000},
+			{Type: proto.RaidDamageProfileType_RaidDamageSpiky, Dps: 500, DamageVariation: 0.5},
+			{Type: proto.RaidDamageProfileType_RaidDamageTankOnly, Dps: 2000, IntervalSeconds: 2},
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	players := result.RaidMetrics.Parties[0].Players
+	tank, raiders := players[1], players[2:4]
+
+	if dtps := tank.Dtps.Avg; math.Abs(dtps-2000) > 20 {
+		t.Errorf("Expected the tank dummy to take ~2000 DTPS, got %0.1f", dtps)
+	}
+	// 200000 default tank health against 600000 damage.
+	if tank.ChanceOfDeath != 1 {
+		t.Errorf("Expected the tank dummy to die, got chance of death %0.2f", tank.ChanceOfDeath)
+	}
+
+	raidDtps := 0.0
+	for _, raider := range raiders {
+		raidDtps += raider.Dtps.Avg
+		if raider.ChanceOfDeath != 0 {
+			t.Errorf("Expected %s to survive, got chance of death %0.2f", raider.Name, raider.ChanceOfDeath)
+		}
+	}
+	if avg := raidDtps / float64(len(raiders)); math.Abs(avg-1500) > 100 {
+		t.Errorf("Expected non-tank dummies to take ~1500 DTPS, got %0.1f", avg)
+	}
+
+	// Nothing should hit the player itself.
+	if dtps := players[0].Dtps.Avg; dtps != 0 {
+		t.Errorf("Expected the player to take no damage, got %0.1f DTPS", dtps)
+	}
+}
@@ -0,0 +1,348 @@
+package core
+
+import (
+	"bytes"
+	"cmp"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Number of results kept in memory when only a cache directory is configured.
+const DefaultResultCacheSize = 256
+
+// RaidSimResultCache stores raid sim results by a hash of their canonical request.
+//
+// Recently used results are kept in memory, and if a directory is given every
+// result is also written to disk so it can be reused by later processes.
+type RaidSimResultCache struct {
+	capacity int
+	dir      string
+	version  string
+
+	mut     sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+type resultCacheEntry struct {
+	key    string
+	result *proto.RaidSimResult
+}
+
+// Creates a cache holding up to capacity results in memory. If dir is not empty,
+// results are also stored on disk there. Results are only shared between caches
+// of the same version, so pass the sim's build version to avoid reusing results
+// of an older sim. Development builds don't have a meaningful version, so their
+// disk cache is keyed by a hash of the running executable instead.
+func NewRaidSimResultCache(capacity int, dir string, version string) (*RaidSimResultCache, error) {
+	if dir != "" && (version == "" || version == "development") {
+		buildHash, err := executableHash()
+		if err != nil {
+			log.Printf("[WARN] Not storing results in %s, unable to identify this development build: %s", dir, err.Error())
+			dir = ""
+		} else {
+			version = "development-" + buildHash
+		}
+	}
+
+	if dir != "" {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return nil, err
+		}
+	}
+	return &RaidSimResultCache{
+		capacity: max(capacity, 1),
+		dir:      dir,
+		version:  version,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	}, nil
+}
+
+// Hash of the running executable, which changes with every code change.
+func executableHash() (string, error) {
+	path, err := os.Executable()
+	if err != nil {
+		return "", err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// Returns a copy of the cached result for key, or nil if there is none.
+func (c *RaidSimResultCache) Get(key string) *proto.RaidSimResult {
+	c.mut.Lock()
+	if elem, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(elem)
+		result := elem.Value.(*resultCacheEntry).result
+		c.mut.Unlock()
+		c.hits.Add(1)
+		return googleProto.Clone(result).(*proto.RaidSimResult)
+	}
+	c.mut.Unlock()
+
+	result := c.load(key)
+	if result == nil {
+		c.misses.Add(1)
+		return nil
+	}
+	c.hits.Add(1)
+	c.add(key, result)
+	return googleProto.Clone(result).(*proto.RaidSimResult)
+}
+
+// Stores a copy of result under key.
+func (c *RaidSimResultCache) Put(key string, result *proto.RaidSimResult) {
+	result = googleProto.Clone(result).(*proto.RaidSimResult)
+	c.add(key, result)
+	c.store(key, result)
+}
+
+// Returns the number of lookups which did and did not find a result.
+func (c *RaidSimResultCache) Stats() (hits int64, misses int64) {
+	return c.hits.Load(), c.misses.Load()
+}
+
+// Returns the number of results held in memory.
+func (c *RaidSimResultCache) Len() int {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+	return c.lru.Len()
+}
+
+func (c *RaidSimResultCache) add(key string, result *proto.RaidSimResult) {
+	c.mut.Lock()
+	defer c.mut.Unlock()
+
+	if elem, ok := c.entries[key]; ok {
+		elem.Value.(*resultCacheEntry).result = result
+		c.lru.MoveToFront(elem)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&resultCacheEntry{key: key, result: result})
+	for c.lru.Len() > c.capacity {
+		oldest := c.lru.Back()
+		c.lru.Remove(oldest)
+		delete(c.entries, oldest.Value.(*resultCacheEntry).key)
+	}
+}
+
+func (c *RaidSimResultCache) path(key string) string {
+	return filepath.Join(c.dir, key+".result")
+}
+
+func (c *RaidSimResultCache) load(key string) *proto.RaidSimResult {
+	if c.dir == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(c.path(key))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			log.Printf("[ERROR] Failed to read cached result %s: %s", key, err.Error())
+		}
+		return nil
+	}
+
+	result := &proto.RaidSimResult{}
+	if err := googleProto.Unmarshal(data, result); err != nil {
+		log.Printf("[ERROR] Ignoring unreadable cached result %s: %s", key, err.Error())
+		return nil
+	}
+	return result
+}
+
+func (c *RaidSimResultCache) store(key string, result *proto.RaidSimResult) {
+	if c.dir == "" {
+		return
+	}
+
+	data, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result %s for the cache: %s", key, err.Error())
+		return
+	}
+
+	// Write to a temporary file first so other processes never read a partial result.
+	tmpPath := c.path(key) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		log.Printf("[ERROR] Failed to write cached result %s: %s", key, err.Error())
+		return
+	}
+	if err := os.Rename(tmpPath, c.path(key)); err != nil {
+		log.Printf("[ERROR] Failed to write cached result %s: %s", key, err.Error())
+	}
+}
+
+// Key for the result of running rsr with the named runner. Different runners,
+// e.g. the single threaded and the concurrent sim, produce different results
+// for the same request so they never share entries.
+func (c *RaidSimResultCache) key(runner string, rsr *proto.RaidSimRequest) string {
+	hash := sha256.New()
+	hash.Write([]byte(c.version))
+	hash.Write([]byte{0})
+	hash.Write([]byte(runner))
+	hash.Write([]byte{0})
+	hash.Write(canonicalRaidSimRequestBytes(rsr))
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Serializes everything in a request which affects its result, such that
+// requests differing only in their request ID, the order of their cooldowns or
+// the order of the extra item database sent along with each player serialize
+// the same. The random seed and iterations are part of the result.
+func canonicalRaidSimRequestBytes(rsr *proto.RaidSimRequest) []byte {
+	canonical := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+	canonical.RequestId = ""
+
+	for _, party := range canonical.GetRaid().GetParties() {
+		for _, player := range party.GetPlayers() {
+			if player.GetCooldowns() != nil {
+				slices.SortStableFunc(player.Cooldowns.Cooldowns, func(a, b *proto.Cooldown) int {
+					return bytes.Compare(deterministicMarshal(a.Id), deterministicMarshal(b.Id))
+				})
+			}
+			if player.GetDatabase() != nil {
+				player.Database = canonicalSimDatabase(player.Database)
+			}
+		}
+	}
+
+	return deterministicMarshal(canonical)
+}
+
+// Reduces a player database to the entries addToDatabase would actually add,
+// sorted by ID. Entries already in the global database and repeated IDs are
+// ignored by the sim, so they are dropped here as well.
+func canonicalSimDatabase(db *proto.SimDatabase) *proto.SimDatabase {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	canonical := &proto.SimDatabase{
+		Items: canonicalDatabaseEntries(db.Items, func(item *proto.SimItem) int32 { return item.Id }, func(id int32) bool {
+			_, ok := ItemsByID[id]
+			return ok
+		}),
+		RandomSuffixes: canonicalDatabaseEntries(db.RandomSuffixes, func(suffix *proto.ItemRandomSuffix) int32 { return suffix.Id }, func(id int32) bool {
+			_, ok := RandomSuffixesByID[id]
+			return ok
+		}),
+		Enchants: canonicalDatabaseEntries(db.Enchants, func(enchant *proto.SimEnchant) int32 { return enchant.EffectId }, func(id int32) bool {
+			_, ok := EnchantsByEffectID[id]
+			return ok
+		}),
+		Gems: canonicalDatabaseEntries(db.Gems, func(gem *proto.SimGem) int32 { return gem.Id }, func(id int32) bool {
+			_, ok := GemsByID[id]
+			return ok
+		}),
+		ReforgeStats: canonicalDatabaseEntries(db.ReforgeStats, func(reforge *proto.ReforgeStat) int32 { return reforge.Id }, func(id int32) bool {
+			_, ok := ReforgeStatsByID[id]
+			return ok
+		}),
+	}
+
+	if len(canonical.Items)+len(canonical.RandomSuffixes)+len(canonical.Enchants)+len(canonical.Gems)+len(canonical.ReforgeStats) == 0 {
+		return nil
+	}
+	return canonical
+}
+
+// Keeps the first entry for each ID which is not yet known, sorted by ID.
+func canonicalDatabaseEntries[T any](entries []T, getID func(T) int32, isKnown func(int32) bool) []T {
+	kept := make([]T, 0, len(entries))
+	for _, entry := range entries {
+		if !isKnown(getID(entry)) {
+			kept = append(kept, entry)
+		}
+	}
+
+	slices.SortStableFunc(kept, func(a, b T) int {
+		return cmp.Compare(getID(a), getID(b))
+	})
+	return slices.CompactFunc(kept, func(a, b T) bool {
+		return getID(a) == getID(b)
+	})
+}
+
+func deterministicMarshal(msg googleProto.Message) []byte {
+	data, err := googleProto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// Results of requests without a fixed seed are random, and interactive sims
+// are driven from outside, so neither can be reused.
+func isCacheableRaidSimRequest(rsr *proto.RaidSimRequest) bool {
+	return rsr.GetSimOptions().GetRandomSeed() != 0 && !rsr.GetSimOptions().GetInteractive()
+}
+
+var raidSimResultCache *RaidSimResultCache
+
+// SetRaidSimResultCache makes every raid sim, including the ones run by bulk sims
+// and stat weights, reuse results from the cache. Pass nil to disable caching.
+// Must be called before any sims are started.
+func SetRaidSimResultCache(cache *RaidSimResultCache) {
+	raidSimResultCache = cache
+}
+
+func GetRaidSimResultCache() *RaidSimResultCache {
+	return raidSimResultCache
+}
+
+// Runs a raid sim through the result cache, if there is one. On a hit the
+// cached result is sent as the final progress update and progress is closed,
+// just like run would.
+func runRaidSimWithCache(runner string, rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, run func() *proto.RaidSimResult) *proto.RaidSimResult {
+	cache := raidSimResultCache
+	if cache == nil || !isCacheableRaidSimRequest(rsr) {
+		return run()
+	}
+
+	key := cache.key(runner, rsr)
+	if result := cache.Get(key); result != nil {
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     rsr.SimOptions.Iterations,
+				CompletedIterations: rsr.SimOptions.Iterations,
+				Dps:                 result.GetRaidMetrics().GetDps().GetAvg(),
+				Hps:                 result.GetRaidMetrics().GetHps().GetAvg(),
+				FinalRaidResult:     result,
+			}
+			close(progress)
+		}
+		return result
+	}
+
+	result := run()
+	if result != nil && result.Error == nil {
+		cache.Put(key, result)
+	}
+	return result
+}
@@ -0,0 +1,110 @@
+package core
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestCanonicalRaidSimRequestBytes(t *testing.T) {
+	// Pretend the global database already knows this item.
+	const knownItemID = 1_000_001
+	mutex.Lock()
+	ItemsByID[knownItemID] = Item{ID: knownItemID}
+	mutex.Unlock()
+	defer func() {
+		mutex.Lock()
+		delete(ItemsByID, knownItemID)
+		mutex.Unlock()
+	}()
+
+	rsr := &proto.RaidSimRequest{
+		Raid: SinglePlayerRaidProto(&proto.Player{
+			Cooldowns: &proto.Cooldowns{
+				Cooldowns: []*proto.Cooldown{
+					{Id: ActionID{SpellID: 3045}.ToProto(), Timings: []float64{10}},
+					{Id: ActionID{ItemID: 58145}.ToProto(), Timings: []float64{0}},
+				},
+			},
+			Database: &proto.SimDatabase{
+				Items: []*proto.SimItem{{Id: 2_000_002}, {Id: 2_000_001}},
+				Gems:  []*proto.SimGem{{Id: 2_000_003}},
+			},
+		}, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+		Encounter:  &proto.Encounter{Duration: 180},
+		SimOptions: &proto.SimOptions{Iterations: 100, RandomSeed: 1},
+	}
+	canonical := canonicalRaidSimRequestBytes(rsr)
+
+	for name, modify := range map[string]func(*proto.RaidSimRequest){
+		"request id": func(r *proto.RaidSimRequest) { r.RequestId = "some-request" },
+		"cooldown order": func(r *proto.RaidSimRequest) {
+			cooldowns := r.Raid.Parties[0].Players[0].Cooldowns.Cooldowns
+			cooldowns[0], cooldowns[1] = cooldowns[1], cooldowns[0]
+		},
+		"database order": func(r *proto.RaidSimRequest) {
+			items := r.Raid.Parties[0].Players[0].Database.Items
+			items[0], items[1] = items[1], items[0]
+		},
+		"duplicate database entry": func(r *proto.RaidSimRequest) {
+			db := r.Raid.Parties[0].Players[0].Database
+			db.Gems = append(db.Gems, db.Gems[0])
+		},
+		"known database entry": func(r *proto.RaidSimRequest) {
+			db := r.Raid.Parties[0].Players[0].Database
+			db.Items = append(db.Items, &proto.SimItem{Id: knownItemID})
+		},
+	} {
+		same := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+		modify(same)
+		if !bytes.Equal(canonicalRaidSimRequestBytes(same), canonical) {
+			t.Errorf("Changing the %s changed the canonical request", name)
+		}
+	}
+
+	for name, modify := range map[string]func(*proto.RaidSimRequest){
+		"seed":       func(r *proto.RaidSimRequest) { r.SimOptions.RandomSeed++ },
+		"iterations": func(r *proto.RaidSimRequest) { r.SimOptions.Iterations++ },
+		"duration":   func(r *proto.RaidSimRequest) { r.Encounter.Duration++ },
+		"timings":    func(r *proto.RaidSimRequest) { r.Raid.Parties[0].Players[0].Cooldowns.Cooldowns[0].Timings[0]++ },
+		"database": func(r *proto.RaidSimRequest) {
+			db := r.Raid.Parties[0].Players[0].Database
+			db.Items = append(db.Items, &proto.SimItem{Id: 2_000_004})
+		},
+	} {
+		different := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+		modify(different)
+		if bytes.Equal(canonicalRaidSimRequestBytes(different), canonical) {
+			t.Errorf("Changing the %s did not change the canonical request", name)
+		}
+	}
+}
+
+func TestDevelopmentResultCacheKey(t *testing.T) {
+	rsr := &proto.RaidSimRequest{SimOptions: &proto.SimOptions{Iterations: 1, RandomSeed: 1}}
+
+	development, err := NewRaidSimResultCache(1, t.TempDir(), "development")
+	if err != nil {
+		t.Fatal(err)
+	}
+	unset, err := NewRaidSimResultCache(1, t.TempDir(), "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	release, err := NewRaidSimResultCache(1, t.TempDir(), "v1.0.0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if development.version == "development" {
+		t.Errorf("Development cache is not keyed by the build")
+	}
+	if development.key("sim", rsr) != unset.key("sim", rsr) {
+		t.Errorf("Development and unversioned caches of the same build use different keys")
+	}
+	if development.key("sim", rsr) == release.key("sim", rsr) {
+		t.Errorf("Development and release caches use the same key")
+	}
+}
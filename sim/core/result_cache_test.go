@@ -0,0 +1,77 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestRaidSimResultCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := core.NewRaidSimResultCache(1, dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := &proto.RaidSimResult{AvgIterationDuration: 1}
+	second := &proto.RaidSimResult{AvgIterationDuration: 2}
+	cache.Put("first", first)
+	cache.Put("second", second)
+	if cache.Len() != 1 {
+		t.Fatalf("Expected 1 result in memory, got %d", cache.Len())
+	}
+
+	// The first result was evicted from memory, but is still on disk.
+	if got := cache.Get("first"); !googleProto.Equal(got, first) {
+		t.Errorf("Expected %v, got %v", first, got)
+	}
+	if got := cache.Get("missing"); got != nil {
+		t.Errorf("Expected no result, got %v", got)
+	}
+
+	reopened, err := core.NewRaidSimResultCache(1, dir, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := reopened.Get("second"); !googleProto.Equal(got, second) {
+		t.Errorf("Expected %v after reopening, got %v", second, got)
+	}
+
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d and %d", hits, misses)
+	}
+}
+
+func TestRunRaidSimWithResultCache(t *testing.T) {
+	cache, err := core.NewRaidSimResultCache(core.DefaultResultCacheSize, "", "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	core.SetRaidSimResultCache(cache)
+	defer core.SetRaidSimResultCache(nil)
+
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 10
+
+	uncached := core.RunRaidSim(rsr)
+	progress := make(chan *proto.ProgressMetrics, 10)
+	cached := core.RunSim(rsr, progress, simsignals.CreateSignals())
+	if !googleProto.Equal(uncached, cached) {
+		t.Fatalf("Cached result differs from the original result")
+	}
+
+	final := <-progress
+	if !googleProto.Equal(final.FinalRaidResult, cached) {
+		t.Errorf("Expected the cached result as final progress, got %v", final)
+	}
+	if _, ok := <-progress; ok {
+		t.Errorf("Expected progress to be closed after the cached result")
+	}
+
+	if hits, misses := cache.Stats(); hits != 1 || misses != 1 {
+		t.Errorf("Expected 1 hit and 1 miss, got %d and %d", hits, misses)
+	}
+}
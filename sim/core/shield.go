@@ -5,6 +5,10 @@ import "strconv"
 type ShieldConfig struct {
 	SelfOnly bool // Set to true to only create the self-shield.
 
+	// Set to true to have the shield absorb incoming damage, up to the applied
+	// amount. Absorbs that expire unused are recorded as overhealing.
+	AbsorbsDamage bool
+
 	Spell *Spell
 
 	Aura
@@ -16,6 +20,9 @@ type Shield struct {
 
 	// Embed Aura so we can use IsActive/Refresh/etc directly.
 	*Aura
+
+	absorbsDamage bool
+	remaining     float64
 }
 
 func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
@@ -24,29 +31,87 @@ func (shield *Shield) Apply(sim *Simulation, shieldAmount float64) {
 	//attackTable := caster.AttackTables[target.UnitIndex]
 
 	// Shields are not affected by healing pseudostats the same way heals are.
-	// So we only apply the spell-specific multiplier.
-	shieldAmount *= shield.Spell.DamageMultiplier
+	// So we only apply the spell-specific multipliers.
+	shieldAmount *= shield.Spell.DamageMultiplier * shield.Spell.DamageMultiplierAdditive
+
+	// Re-applying an absorbing shield keeps whatever is left of the old one.
+	carry := 0.0
+	if shield.absorbsDamage && shield.Aura.IsActive() {
+		carry = shield.remaining
+		shield.remaining = 0
+	}
 
 	shield.Aura.Deactivate(sim)
 	shield.Aura.Activate(sim)
 
+	if shield.absorbsDamage {
+		shield.remaining = carry + shieldAmount
+	}
+
 	threat := 0.0 // TODO
 	shield.Spell.SpellMetrics[target.UnitIndex].TotalThreat += threat
 	shield.Spell.SpellMetrics[target.UnitIndex].TotalShielding += shieldAmount
+	if !shield.absorbsDamage {
+		// Without absorb tracking there's no way to tell how much was wasted.
+		shield.Spell.SpellMetrics[target.UnitIndex].TotalEffective += shieldAmount
+	}
 	shield.Spell.SpellMetrics[target.UnitIndex].Hits++
+	if sim.Breakdown != nil {
+		sim.Breakdown.recordHealing(sim, shield.Spell, shieldAmount)
+	}
 
 	if sim.Log != nil {
 		caster.Log(sim, "%s %s Hit for %0.3f shielding. (Threat: %0.3f)", target.LogLabel(), shield.Spell.ActionID, shieldAmount, threat)
 	}
 }
 
+// Returns the amount of damage this shield can still absorb.
+func (shield *Shield) RemainingAbsorb() float64 {
+	return shield.remaining
+}
+
 func newShield(config Shield) *Shield {
 	shield := &Shield{}
 	*shield = config
 
+	if shield.absorbsDamage {
+		shield.registerAbsorb()
+	}
+
 	return shield
 }
 
+func (shield *Shield) registerAbsorb() {
+	target := shield.Aura.Unit
+
+	shield.Aura.ApplyOnExpire(func(_ *Aura, sim *Simulation) {
+		// Absorbs still up when the iteration ends aren't counted either way.
+		if sim.CurrentTime < sim.Duration {
+			shield.Spell.SpellMetrics[target.UnitIndex].TotalOverhealing += shield.remaining
+		}
+		shield.remaining = 0
+	})
+
+	target.AddDynamicDamageTakenModifier(func(sim *Simulation, _ *Spell, result *SpellResult) {
+		if !shield.Aura.IsActive() || result.Damage <= 0 {
+			return
+		}
+
+		absorbed := min(shield.remaining, result.Damage)
+		result.Damage -= absorbed
+		shield.remaining -= absorbed
+		shield.Spell.SpellMetrics[target.UnitIndex].TotalEffective += absorbed
+
+		if sim.Log != nil {
+			target.Log(sim, "%s absorbed %.1f damage, remaining: %.1f", shield.Aura.Label, absorbed, shield.remaining)
+		}
+
+		if shield.remaining <= 0 {
+			shield.Aura.Deactivate(sim)
+		}
+	})
+}
+
 type ShieldArray []*Shield
 
 func (shields ShieldArray) Get(target *Unit) *Shield {
@@ -62,7 +127,8 @@ func (spell *Spell) createShields(config ShieldConfig) {
 		config.Spell = spell
 	}
 	shield := Shield{
-		Spell: config.Spell,
+		Spell:         config.Spell,
+		absorbsDamage: config.AbsorbsDamage,
 	}
 
 	auraConfig := config.Aura
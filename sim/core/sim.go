@@ -43,6 +43,12 @@ type Simulation struct {
 
 	Log func(string, ...interface{})
 
+	// Records typed events when the combat log is enabled, nil otherwise.
+	CombatLog *CombatLogger
+
+	// Records damage over time and by phase when requested, nil otherwise.
+	Breakdown *BreakdownTracker
+
 	executePhase int32 // 20, 25, or 35 for the respective execute range, 100 otherwise
 
 	executePhaseCallbacks []func(*Simulation, int32) // 2nd parameter is 35 for 35%, 25 for 25% and 20 for 20%
@@ -107,10 +113,24 @@ func (sim *Simulation) RemoveTask(task Task) {
 }
 
 func RunSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
-	return runSim(rsr, progress, false, signals)
+	return runRaidSimWithCache("sim", rsr, progress, func() *proto.RaidSimResult {
+		return runSimToPrecision(rsr, progress, func(batch *proto.RaidSimRequest, batchProgress chan *proto.ProgressMetrics, presim *presimState) *proto.RaidSimResult {
+			return runSim(batch, batchProgress, presim, signals)
+		})
+	})
+}
+
+// Shares the presims between sims which split up the same request, so they
+// only run once, e.g. for the batches of runSimToPrecision.
+type presimState struct {
+	done bool
+	// Fight length estimated by the presims, for health fights.
+	duration time.Duration
 }
 
-func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, skipPresim bool, signals simsignals.Signals) (result *proto.RaidSimResult) {
+// Runs the presims unless presim is already done, and uses its duration
+// estimate for health fights. A nil presim runs them without sharing.
+func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, presim *presimState, signals simsignals.Signals) (result *proto.RaidSimResult) {
 	if !rsr.SimOptions.IsTest {
 		defer func() {
 			if err := recover(); err != nil {
@@ -140,7 +160,10 @@ func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, ski
 
 	sim := NewSim(rsr, signals)
 
-	if !skipPresim {
+	if presim == nil {
+		presim = &presimState{}
+	}
+	if !presim.done {
 		if progress != nil {
 			progress <- &proto.ProgressMetrics{
 				TotalIterations: sim.Options.Iterations,
@@ -163,18 +186,24 @@ func runSim(rsr *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, ski
 				TotalIterations: sim.Options.Iterations,
 				PresimRunning:   false,
 			}
-			sim.ProgressReport = func(progMetric *proto.ProgressMetrics) {
-				progress <- progMetric
-			}
 			runtime.Gosched() // allow time for message to make it back out.
 		}
-		// Use pre-sim as estimate for length of fight (when using health fight)
-		if sim.Encounter.EndFightAtHealth > 0 && presimResult != nil {
-			sim.BaseDuration = time.Duration(presimResult.AvgIterationDuration) * time.Second
-			sim.Duration = time.Duration(presimResult.AvgIterationDuration) * time.Second
-			sim.Encounter.DurationIsEstimate = false // we now have a pretty good value for duration
+		presim.done = true
+		if presimResult != nil {
+			presim.duration = time.Duration(presimResult.AvgIterationDuration) * time.Second
+		}
+	}
+	if progress != nil {
+		sim.ProgressReport = func(progMetric *proto.ProgressMetrics) {
+			progress <- progMetric
 		}
 	}
+	// Use pre-sim as estimate for length of fight (when using health fight)
+	if sim.Encounter.EndFightAtHealth > 0 && presim.duration > 0 {
+		sim.BaseDuration = presim.duration
+		sim.Duration = presim.duration
+		sim.Encounter.DurationIsEstimate = false // we now have a pretty good value for duration
+	}
 
 	// using a variable here allows us to mutate it in the deferred recover, sending out error info
 	result = sim.run()
@@ -193,7 +222,7 @@ func newSimWithEnv(env *Environment, simOptions *proto.SimOptions, signals simsi
 		rseed = time.Now().UnixNano()
 	}
 
-	return &Simulation{
+	sim := &Simulation{
 		Environment: env,
 		Options:     simOptions,
 
@@ -205,6 +234,12 @@ func newSimWithEnv(env *Environment, simOptions *proto.SimOptions, signals simsi
 
 		Signals: signals,
 	}
+
+	if breakdown := simOptions.Breakdown; (breakdown != nil) && ((breakdown.BucketSeconds > 0) || breakdown.IncludePhases) {
+		sim.Breakdown = newBreakdownTracker(env, breakdown)
+	}
+
+	return sim
 }
 
 // Returns a random float64 between 0.0 (inclusive) and 1.0 (exclusive).
@@ -296,6 +331,12 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 	// 	fmt.Printf(fmt.Sprintf("[%0.1f] "+message+"\n", append([]interface{}{sim.CurrentTime.Seconds()}, vals...)...))
 	// }
 
+	var combatLog *CombatLogger
+	if sim.Options.CombatLog.GetFormat() != proto.CombatLogFormat_CombatLogFormatNone {
+		combatLog = newCombatLogger()
+	}
+	sim.setCombatLogIteration(combatLog, 0)
+
 	sim.runOnce()
 	firstIterationDuration := sim.Duration
 	if sim.Encounter.EndFightAtHealth != 0 {
@@ -331,6 +372,7 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 
 		// Before each iteration, reset state to seed+iterations
 		sim.reseedRands(int64(i))
+		sim.setCombatLogIteration(combatLog, i)
 
 		sim.runOnce()
 		iterDuration := sim.Duration
@@ -348,6 +390,23 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 		AvgIterationDuration:   totalDuration.Seconds() / float64(sim.Options.Iterations),
 		IterationsDone:         sim.Options.Iterations,
 	}
+	result.RelativeStandardError = raidSimRelativeStandardError(result)
+	if !sim.Options.SaveDigests {
+		ClearQuantileDigests(result)
+	}
+
+	if sim.Breakdown != nil {
+		result.Breakdown = sim.Breakdown.ToProto()
+	}
+
+	if combatLog != nil {
+		switch sim.Options.CombatLog.Format {
+		case proto.CombatLogFormat_CombatLogFormatEvents:
+			result.CombatLog = combatLog.Events()
+		case proto.CombatLogFormat_CombatLogFormatText:
+			result.CombatLogText = CombatLogText(combatLog.Events())
+		}
+	}
 
 	// Final progress report
 	if sim.ProgressReport != nil {
@@ -361,6 +420,15 @@ func (sim *Simulation) run() *proto.RaidSimResult {
 	return result
 }
 
+// Enables the combat log only for the iteration chosen in the sim options.
+func (sim *Simulation) setCombatLogIteration(combatLog *CombatLogger, iteration int32) {
+	if combatLog != nil && sim.Options.CombatLog.Iteration == iteration {
+		sim.CombatLog = combatLog
+	} else {
+		sim.CombatLog = nil
+	}
+}
+
 // RunOnce is the main event loop. It will run the simulation for number of seconds.
 func (sim *Simulation) runOnce() {
 	sim.reset()
@@ -408,6 +476,10 @@ func (sim *Simulation) reset() {
 
 	sim.CurrentTime = 0
 
+	if sim.Breakdown != nil {
+		sim.Breakdown.reset()
+	}
+
 	sim.trackers = sim.trackers[:0]
 	sim.minTrackerTime = NeverExpires
 
@@ -474,6 +546,10 @@ func (sim *Simulation) Cleanup() {
 	for _, target := range sim.Encounter.TargetUnits {
 		target.Metrics.doneIteration(target, sim)
 	}
+
+	if sim.Breakdown != nil {
+		sim.Breakdown.doneIteration(sim)
+	}
 }
 
 func (sim *Simulation) runPendingActions() {
@@ -549,6 +625,10 @@ func (sim *Simulation) advanceTasks() {
 
 // Advance moves time forward counting down auras, CDs, mana regen, etc
 func (sim *Simulation) advance(nextTime time.Duration) {
+	if sim.Breakdown != nil {
+		sim.Breakdown.advance(sim, nextTime)
+	}
+
 	sim.CurrentTime = nextTime
 
 	// this is a loop to handle duplicate ExecuteProportions, e.g. if they're all set to 100%, you reach
@@ -37,6 +37,7 @@ func SplitSimRequestForConcurrency(request *proto.RaidSimRequest, splitCount int
 
 	// Sims increment their seed each iteration. Offset starting seed of each split to emulate that.
 	nextStartSeed := split[0].SimOptions.RandomSeed + int64(split[0].SimOptions.Iterations)
+	splitStartIteration := split[0].SimOptions.Iterations
 
 	for i := 1; i < int(splitCount); i++ {
 		split[i] = googleProto.Clone(request).(*proto.RaidSimRequest)
@@ -44,6 +45,40 @@ func SplitSimRequestForConcurrency(request *proto.RaidSimRequest, splitCount int
 		split[i].SimOptions.DebugFirstIteration = false // No logs
 		split[i].SimOptions.RandomSeed = nextStartSeed
 		nextStartSeed += int64(split[i].SimOptions.Iterations)
+
+		// Only the split containing the requested iteration records the combat log.
+		if combatLog := split[i].SimOptions.CombatLog; combatLog != nil {
+			combatLog.Iteration -= splitStartIteration
+			if combatLog.Iteration < 0 || combatLog.Iteration >= split[i].SimOptions.Iterations {
+				split[i].SimOptions.CombatLog = nil
+			}
+		}
+		splitStartIteration += split[i].SimOptions.Iterations
+	}
+	if combatLog := split[0].SimOptions.CombatLog; combatLog != nil && combatLog.Iteration >= split[0].SimOptions.Iterations {
+		split[0].SimOptions.CombatLog = nil
+	}
+
+	// Splits which each reach the target precision get there together with
+	// about splitCount times fewer iterations each. They may run more iterations
+	// than requested, so their seeds are spaced by the most they can run.
+	if target := request.SimOptions.TargetRelativeStandardError; target > 0 {
+		maxIterations := request.SimOptions.MaxIterations
+		if maxIterations <= 0 {
+			maxIterations = defaultMaxIterations
+		}
+		nextStartSeed := request.SimOptions.RandomSeed
+		for _, splitRequest := range split {
+			splitRequest.SimOptions.TargetRelativeStandardError = target * math.Sqrt(float64(splitCount))
+			splitRequest.SimOptions.MaxIterations = max(maxIterations/splitCount, splitRequest.SimOptions.Iterations)
+			splitRequest.SimOptions.RandomSeed = nextStartSeed
+			nextStartSeed += int64(splitRequest.SimOptions.MaxIterations)
+		}
+	}
+
+	// Split results are merged, which needs their percentile digests.
+	for _, splitRequest := range split {
+		splitRequest.SimOptions.SaveDigests = true
 	}
 
 	res.SplitsDone = splitCount
@@ -63,6 +98,7 @@ func (rsrc *raidSimResultCombiner) newDistMetrics() *proto.DistributionMetrics {
 		Hist:           make(map[int32]int32),
 		AllValues:      make([]float64, 0),
 		AggregatorData: &proto.AggregatorData{},
+		Digest:         &proto.QuantileDigest{},
 	}
 }
 
@@ -75,6 +111,7 @@ func (rsrc *raidSimResultCombiner) newUnitMetrics(baseUnit *proto.UnitMetrics) *
 		Dtps:      rsrc.newDistMetrics(),
 		Tmi:       rsrc.newDistMetrics(),
 		Hps:       rsrc.newDistMetrics(),
+		Ehps:      rsrc.newDistMetrics(),
 		Tto:       rsrc.newDistMetrics(),
 		Actions:   make([]*proto.ActionMetrics, 0, len(baseUnit.Actions)),
 		Auras:     make([]*proto.AuraMetrics, len(baseUnit.Auras)),
@@ -93,9 +130,47 @@ func (rsrc *raidSimResultCombiner) newUnitMetrics(baseUnit *proto.UnitMetrics) *
 		newUm.Pets[i] = rsrc.newUnitMetrics(pet)
 	}
 
+	if baseUnit.RotationStats != nil {
+		newUm.RotationStats = newAPLStats(baseUnit.RotationStats)
+	}
+
 	return newUm
 }
 
+// Keeps the validations, since these are the same for all runs.
+func newAPLStats(baseStats *proto.APLStats) *proto.APLStats {
+	newItemStats := func(itemStats []*proto.APLActionStats) []*proto.APLActionStats {
+		return MapSlice(itemStats, func(stats *proto.APLActionStats) *proto.APLActionStats {
+			return &proto.APLActionStats{Validations: stats.Validations}
+		})
+	}
+
+	return &proto.APLStats{
+		PriorityList: newItemStats(baseStats.PriorityList),
+		Variables:    newItemStats(baseStats.Variables),
+		ActionLists: MapSlice(baseStats.ActionLists, func(list *proto.APLActionListStats) *proto.APLActionListStats {
+			return &proto.APLActionListStats{PriorityList: newItemStats(list.PriorityList)}
+		}),
+	}
+}
+
+func combineAPLStats(base *proto.APLStats, add *proto.APLStats, weight float64) {
+	combineItemStats := func(base []*proto.APLActionStats, add []*proto.APLActionStats) {
+		for i, addStats := range add {
+			base[i].EvaluationsAvg += addStats.EvaluationsAvg * weight
+			base[i].ConditionTrueAvg += addStats.ConditionTrueAvg * weight
+			base[i].ExecutionsAvg += addStats.ExecutionsAvg * weight
+			base[i].WaitSecondsAvg += addStats.WaitSecondsAvg * weight
+		}
+	}
+
+	combineItemStats(base.PriorityList, add.PriorityList)
+	for i, addList := range add.ActionLists {
+		combineItemStats(base.ActionLists[i].PriorityList, addList.PriorityList)
+	}
+	base.IdleSecondsAvg += add.IdleSecondsAvg * weight
+}
+
 func (rsrc *raidSimResultCombiner) newPartyMetrics(baseParty *proto.PartyMetrics) *proto.PartyMetrics {
 	newPm := &proto.PartyMetrics{
 		Dps:     rsrc.newDistMetrics(),
@@ -133,8 +208,14 @@ func (rsrc *raidSimResultCombiner) combineDistMetrics(base *proto.DistributionMe
 
 	base.AggregatorData.N += add.AggregatorData.N
 	base.AggregatorData.SumSq += add.AggregatorData.SumSq
+
+	digest := quantileDigestFromProto(base.Digest)
+	digest.merge(quantileDigestFromProto(add.Digest))
+	base.Digest = digest.ToProto()
+
 	if isLast {
 		base.Stdev = math.Sqrt(base.AggregatorData.SumSq/float64(base.AggregatorData.N) - base.Avg*base.Avg)
+		digest.fillPercentiles(base)
 	}
 }
 
@@ -192,6 +273,7 @@ func (rsrc *raidSimResultCombiner) addActionMetrics(unit *proto.UnitMetrics, add
 		baseTgt.Healing += addTgt.Healing
 		baseTgt.CritHealing += addTgt.CritHealing
 		baseTgt.Shielding += addTgt.Shielding
+		baseTgt.Overhealing += addTgt.Overhealing
 		baseTgt.CastTimeMs += addTgt.CastTimeMs
 	}
 }
@@ -235,11 +317,17 @@ func (rsrc *raidSimResultCombiner) addResourceMetrics(unit *proto.UnitMetrics, a
 }
 
 func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, add *proto.UnitMetrics, isLast bool, weight float64) {
+	// Empty party slots have placeholder metrics with nothing to combine.
+	if add.Dps == nil {
+		return
+	}
+
 	rsrc.combineDistMetrics(base.Dps, add.Dps, isLast, weight)
 	rsrc.combineDistMetrics(base.Threat, add.Threat, isLast, weight)
 	rsrc.combineDistMetrics(base.Dtps, add.Dtps, isLast, weight)
 	rsrc.combineDistMetrics(base.Tmi, add.Tmi, isLast, weight)
 	rsrc.combineDistMetrics(base.Hps, add.Hps, isLast, weight)
+	rsrc.combineDistMetrics(base.Ehps, add.Ehps, isLast, weight)
 	rsrc.combineDistMetrics(base.Tto, add.Tto, isLast, weight)
 
 	base.SecondsOomAvg += add.SecondsOomAvg * weight
@@ -260,6 +348,23 @@ func (rsrc *raidSimResultCombiner) combineUnitMetrics(base *proto.UnitMetrics, a
 	for i, addPet := range add.Pets {
 		rsrc.combineUnitMetrics(base.Pets[i], addPet, isLast, weight)
 	}
+
+	if base.RotationStats != nil && add.RotationStats != nil {
+		combineAPLStats(base.RotationStats, add.RotationStats, weight)
+	}
+}
+
+// Spawn rate is weighted by iterations, but kill rate and lifetime are
+// averaged over spawned waves only, so need to be weighted by spawns.
+func (rsrc *raidSimResultCombiner) combineWaveMetrics(base *proto.TargetWaveMetrics, add *proto.TargetWaveMetrics, isLast bool, weight float64) {
+	rsrc.combineDistMetrics(base.Dps, add.Dps, isLast, weight)
+
+	totalSpawnWeight := base.SpawnRate + add.SpawnRate*weight
+	if totalSpawnWeight > 0 {
+		base.KillRate = (base.KillRate*base.SpawnRate + add.KillRate*add.SpawnRate*weight) / totalSpawnWeight
+		base.AvgLifetimeSeconds = (base.AvgLifetimeSeconds*base.SpawnRate + add.AvgLifetimeSeconds*add.SpawnRate*weight) / totalSpawnWeight
+	}
+	base.SpawnRate = totalSpawnWeight
 }
 
 func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast bool, weight float64) {
@@ -279,12 +384,27 @@ func (rsrc *raidSimResultCombiner) AddResult(result *proto.RaidSimResult, isLast
 		rsrc.combineUnitMetrics(rsrc.Combined.EncounterMetrics.Targets[i], tar, isLast, weight)
 	}
 
+	for i, wave := range result.EncounterMetrics.Waves {
+		rsrc.combineWaveMetrics(rsrc.Combined.EncounterMetrics.Waves[i], wave, isLast, weight)
+	}
+
+	if result.Breakdown != nil {
+		combineBreakdownMetrics(rsrc.Combined.Breakdown, result.Breakdown)
+	}
+
 	rsrc.Combined.AvgIterationDuration += result.AvgIterationDuration * weight
 	rsrc.Combined.IterationsDone += result.IterationsDone
 
 	if rsrc.Debug {
 		rsrc.Combined.Logs += "-SIMSTART-\n" + result.Logs
 	}
+
+	if len(result.CombatLog) > 0 {
+		rsrc.Combined.CombatLog = result.CombatLog
+	}
+	if result.CombatLogText != "" {
+		rsrc.Combined.CombatLogText = result.CombatLogText
+	}
 }
 
 func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
@@ -296,6 +416,7 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 		},
 		EncounterMetrics: &proto.EncounterMetrics{
 			Targets: make([]*proto.UnitMetrics, len(baseRsr.EncounterMetrics.Targets)),
+			Waves:   make([]*proto.TargetWaveMetrics, len(baseRsr.EncounterMetrics.Waves)),
 		},
 		FirstIterationDuration: baseRsr.FirstIterationDuration,
 	}
@@ -312,6 +433,17 @@ func (rsrc *raidSimResultCombiner) SetBaseResult(baseRsr *proto.RaidSimResult) {
 		newRsr.EncounterMetrics.Targets[i] = rsrc.newUnitMetrics(tar)
 	}
 
+	for i, wave := range baseRsr.EncounterMetrics.Waves {
+		newRsr.EncounterMetrics.Waves[i] = &proto.TargetWaveMetrics{
+			Name: wave.Name,
+			Dps:  rsrc.newDistMetrics(),
+		}
+	}
+
+	if baseRsr.Breakdown != nil {
+		newRsr.Breakdown = &proto.BreakdownMetrics{BucketSeconds: baseRsr.Breakdown.BucketSeconds}
+	}
+
 	rsrc.Combined = newRsr
 }
 
@@ -337,6 +469,7 @@ func CombineConcurrentSimResults(results []*proto.RaidSimResult, isDebug bool) *
 		resultWeight := float64(results[i].IterationsDone) / float64(totalIterations)
 		rsrc.AddResult(result, i == numResults-1, resultWeight)
 	}
+	rsrc.Combined.RelativeStandardError = raidSimRelativeStandardError(rsrc.Combined)
 
 	return rsrc.Combined
 }
@@ -399,7 +532,17 @@ func (csd *concurrentSimData) MakeProgressMetrics() *proto.ProgressMetrics {
 }
 
 // Run sim on multiple threads concurrently by splitting interations over multiple sims, transparently combining results into the progress channel.
-func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) (result *proto.RaidSimResult) {
+func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+	// Results depend on how the iterations are split, so the number of splits is part of the cache key.
+	concurrency := TernaryInt32(request.SimOptions.IsTest, 3, int32(runtime.NumCPU()))
+	return runRaidSimWithCache(fmt.Sprintf("concurrent/%d", concurrency), request, progress, func() *proto.RaidSimResult {
+		return runSimToPrecision(request, progress, func(batch *proto.RaidSimRequest, batchProgress chan *proto.ProgressMetrics, presim *presimState) *proto.RaidSimResult {
+			return runSimConcurrentWithSplits(batch, batchProgress, presim, signals, concurrency)
+		})
+	})
+}
+
+func runSimConcurrentWithSplits(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, presim *presimState, signals simsignals.Signals, concurrency int32) (result *proto.RaidSimResult) {
 	defer func() {
 		if !request.SimOptions.IsTest {
 			if err := recover(); err != nil {
@@ -427,7 +570,7 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 		}
 	}()
 
-	splitRes := SplitSimRequestForConcurrency(request, TernaryInt32(request.SimOptions.IsTest, 3, int32(runtime.NumCPU())))
+	splitRes := SplitSimRequestForConcurrency(request, concurrency)
 
 	if splitRes.ErrorResult != "" {
 		panic(splitRes.ErrorResult)
@@ -456,8 +599,15 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 		log.Printf("Running %d iterations on %d concurrent sims.", csd.IterationsTotal, csd.Concurrency)
 	}
 
+	// Every split runs the same presims, so only the first one needs to share
+	// its results, unless they're already done.
+	presimDone := (presim != nil) && presim.done
 	for i, req := range splitRes.Requests {
-		go RunSim(req, substituteChannels[i], signals)
+		splitPresim := presim
+		if (i > 0) && !presimDone {
+			splitPresim = nil
+		}
+		go runSim(req, substituteChannels[i], splitPresim, signals)
 	}
 
 	progressCounter := 0
@@ -513,6 +663,9 @@ func runSimConcurrent(request *proto.RaidSimRequest, progress chan *proto.Progre
 	}
 
 	result = CombineConcurrentSimResults(csd.FinalResults, request.SimOptions.Debug)
+	if !request.SimOptions.SaveDigests {
+		ClearQuantileDigests(result)
+	}
 
 	if progress != nil {
 		pm := csd.MakeProgressMetrics()
@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"strconv"
+	"sync"
 	"testing"
 
 	"github.com/wowsims/cata/sim/core"
@@ -9,8 +10,14 @@ import (
 	"github.com/wowsims/cata/sim/death_knight/blood"
 	"github.com/wowsims/cata/sim/druid/feral"
 	"github.com/wowsims/cata/sim/hunter/marksmanship"
+	"github.com/wowsims/cata/sim/paladin/protection"
 )
 
+var registerMMOnce sync.Once
+var registerBloodDkOnce sync.Once
+var registerFeralOnce sync.Once
+var registerProtPaladinOnce sync.Once
+
 func getTestPlayerMM() *proto.Player {
 	var FullConsumes = &proto.Consumes{
 		Flask:         proto.Flask_FlaskOfTheWinds,
@@ -48,7 +55,7 @@ func getTestPlayerMM() *proto.Player {
 		},
 	}
 
-	marksmanship.RegisterMarksmanshipHunter()
+	registerMMOnce.Do(marksmanship.RegisterMarksmanshipHunter)
 
 	return &proto.Player{
 		Race:           proto.Race_RaceOrc,
@@ -90,7 +97,7 @@ func getTestPlayerBloodDk() *proto.Player {
 		Food:          proto.Food_FoodBeerBasedCrocolisk,
 	}
 
-	blood.RegisterBloodDeathKnight()
+	registerBloodDkOnce.Do(blood.RegisterBloodDeathKnight)
 
 	return &proto.Player{
 		Race:           proto.Race_RaceWorgen,
@@ -132,7 +139,7 @@ func getTestPlayerFeralCat() *proto.Player {
 		PrepopPotion:  proto.Potions_PotionOfTheTolvir,
 	}
 
-	feral.RegisterFeralDruid()
+	registerFeralOnce.Do(feral.RegisterFeralDruid)
 
 	return &proto.Player{
 		Race:           proto.Race_RaceTauren,
@@ -148,6 +155,50 @@ func getTestPlayerFeralCat() *proto.Player {
 	}
 }
 
+func getTestPlayerProtPaladin() *proto.Player {
+	var StandardTalents = "-32023013122121101231-032032"
+	var StandardGlyphs = &proto.Glyphs{
+		Prime1: int32(proto.PaladinPrimeGlyph_GlyphOfHammerOfTheRighteous),
+		Prime2: int32(proto.PaladinPrimeGlyph_GlyphOfCrusaderStrike),
+		Prime3: int32(proto.PaladinPrimeGlyph_GlyphOfSealOfTruth),
+		Major1: int32(proto.PaladinMajorGlyph_GlyphOfTheAsceticCrusader),
+		Major2: int32(proto.PaladinMajorGlyph_GlyphOfLayOnHands),
+		Major3: int32(proto.PaladinMajorGlyph_GlyphOfFocusedShield),
+	}
+
+	var PlayerOptionsBasic = &proto.Player_ProtectionPaladin{
+		ProtectionPaladin: &proto.ProtectionPaladin{
+			Options: &proto.ProtectionPaladin_Options{
+				ClassOptions: &proto.PaladinOptions{
+					Seal: proto.PaladinSeal_Truth,
+					Aura: proto.PaladinAura_Retribution,
+				},
+			},
+		},
+	}
+
+	var FullConsumes = &proto.Consumes{
+		Flask:         proto.Flask_FlaskOfSteelskin,
+		Food:          proto.Food_FoodLavascaleMinestrone,
+		DefaultPotion: proto.Potions_GolembloodPotion,
+	}
+
+	registerProtPaladinOnce.Do(protection.RegisterProtectionPaladin)
+
+	return &proto.Player{
+		Race:           proto.Race_RaceBloodElf,
+		Class:          proto.Class_ClassPaladin,
+		Equipment:      core.GetGearSet("../../ui/paladin/protection/gear_sets", "T12").GearSet,
+		Rotation:       core.GetAplRotation("../../ui/paladin/protection/apls", "default").Rotation,
+		Consumes:       FullConsumes,
+		Spec:           PlayerOptionsBasic,
+		Glyphs:         StandardGlyphs,
+		TalentsString:  StandardTalents,
+		Buffs:          core.FullIndividualBuffs,
+		ReactionTimeMs: 100,
+	}
+}
+
 func makeTestCase(player *proto.Player) *proto.RaidSimRequest {
 	return &proto.RaidSimRequest{
 		Raid: core.SinglePlayerRaidProto(
@@ -180,8 +231,33 @@ func TestConcurrentRaidSim(t *testing.T) {
 
 	for i, player := range players {
 		rsr := makeTestCase(player)
+		// The single threaded digests bound the multi threaded percentiles.
+		rsr.SimOptions.SaveDigests = true
 		stRes := core.RunRaidSim(rsr)
 		mtRes := core.RunRaidSimConcurrent(rsr)
 		core.CompareConcurrentSimResultsTest(t, strconv.Itoa(i), stRes, mtRes, 0.00001)
 	}
 }
+
+func TestConcurrentRaidSimDigests(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+
+	splitRes := core.SplitSimRequestForConcurrency(rsr, 2)
+	split := core.RunRaidSim(splitRes.Requests[0])
+	if len(split.RaidMetrics.Dps.Digest.GetMeans()) == 0 {
+		t.Errorf("Expected a digest in the result of a split sim")
+	}
+
+	for name, result := range map[string]*proto.RaidSimResult{
+		"single threaded": core.RunRaidSim(rsr),
+		"multi threaded":  core.RunRaidSimConcurrent(rsr),
+	} {
+		dps := result.RaidMetrics.Dps
+		if dps.Digest != nil || result.RaidMetrics.Parties[0].Players[0].Dps.Digest != nil {
+			t.Errorf("Expected no digests in the final %s result", name)
+		}
+		if dps.Median <= 0 {
+			t.Errorf("Expected percentiles in the final %s result, got median %f", name, dps.Median)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"math"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const defaultMaxIterations = 100000
+
+// Relative standard error of the mean of a distribution.
+func RelativeStandardError(dist *proto.DistributionMetrics) float64 {
+	n := dist.GetAggregatorData().GetN()
+	if n <= 1 || dist.GetAvg() == 0 {
+		return 0
+	}
+	return dist.Stdev / math.Sqrt(float64(n)) / math.Abs(dist.Avg)
+}
+
+// Precision of a raid sim result, see RaidSimResult.relative_standard_error.
+func raidSimRelativeStandardError(result *proto.RaidSimResult) float64 {
+	metrics := result.GetRaidMetrics()
+	if metrics.GetDps().GetAvg() == 0 {
+		return RelativeStandardError(metrics.GetHps())
+	}
+	return RelativeStandardError(metrics.GetDps())
+}
+
+// Runs a raid sim in batches until it reaches SimOptions.target_relative_standard_error.
+//
+// runBatch runs a request with a fixed number of iterations, sending progress
+// and closing the progress channel like runSim. Each batch continues the seeds
+// of the previous one, so the combined result matches one sim with all of
+// their iterations split over several threads. The presims only run for the
+// first batch, and later ones reuse its fight duration.
+func runSimToPrecision(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, runBatch func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, *presimState) *proto.RaidSimResult) *proto.RaidSimResult {
+	options := request.SimOptions
+	target := options.GetTargetRelativeStandardError()
+	if target <= 0 || options.Interactive {
+		return runBatch(request, progress, nil)
+	}
+
+	maxIterations := options.MaxIterations
+	if maxIterations <= 0 {
+		maxIterations = defaultMaxIterations
+	}
+	maxIterations = max(maxIterations, options.Iterations)
+
+	var results []*proto.RaidSimResult
+	var result *proto.RaidSimResult
+	var done int32
+	batch := options.Iterations
+	total := batch
+	presim := &presimState{}
+	for {
+		batchRequest := googleProto.Clone(request).(*proto.RaidSimRequest)
+		batchRequest.SimOptions.Iterations = batch
+		batchRequest.SimOptions.RandomSeed = options.RandomSeed + int64(done)
+		batchRequest.SimOptions.TargetRelativeStandardError = 0
+		batchRequest.SimOptions.MaxIterations = 0
+		batchRequest.SimOptions.SaveDigests = true
+		if done > 0 {
+			batchRequest.SimOptions.DebugFirstIteration = false
+			if combatLog := batchRequest.SimOptions.CombatLog; combatLog != nil {
+				combatLog.Iteration -= done
+				if combatLog.Iteration < 0 || combatLog.Iteration >= batch {
+					batchRequest.SimOptions.CombatLog = nil
+				}
+			}
+		}
+
+		batchResult := runPrecisionBatch(batchRequest, progress, done, total, presim, runBatch)
+		if batchResult.Error != nil {
+			if progress != nil {
+				progress <- &proto.ProgressMetrics{FinalRaidResult: batchResult}
+				close(progress)
+			}
+			return batchResult
+		}
+		results = append(results, batchResult)
+		done += batchResult.IterationsDone
+
+		result = CombineConcurrentSimResults(results, options.Debug)
+		rse := result.RelativeStandardError
+		if rse <= target || done >= maxIterations {
+			break
+		}
+
+		// The standard error shrinks with the square root of the iterations.
+		needed := int32(math.Ceil(float64(done) * (rse / target) * (rse / target)))
+		batch = min(max(needed-done, done/10, 1), maxIterations-done)
+		// Later estimates may need fewer iterations, but progress shouldn't
+		// go backwards.
+		total = max(total, done+batch)
+	}
+	if !options.SaveDigests {
+		ClearQuantileDigests(result)
+	}
+
+	if progress != nil {
+		progress <- &proto.ProgressMetrics{
+			TotalIterations:     done,
+			CompletedIterations: done,
+			Dps:                 result.RaidMetrics.Dps.Avg,
+			Hps:                 result.RaidMetrics.Hps.Avg,
+			FinalRaidResult:     result,
+		}
+		close(progress)
+	}
+	return result
+}
+
+// Runs one batch of runSimToPrecision, forwarding its progress as part of the
+// whole sim, out of the total iterations currently expected. The final result
+// is left for runSimToPrecision to report.
+func runPrecisionBatch(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, done int32, total int32, presim *presimState, runBatch func(*proto.RaidSimRequest, chan *proto.ProgressMetrics, *presimState) *proto.RaidSimResult) *proto.RaidSimResult {
+	if progress == nil {
+		return runBatch(request, nil, presim)
+	}
+
+	batchProgress := make(chan *proto.ProgressMetrics, 20)
+	forwarded := make(chan struct{})
+	go func() {
+		defer close(forwarded)
+		for msg := range batchProgress {
+			if msg.FinalRaidResult != nil {
+				return
+			}
+			msg.CompletedIterations += done
+			msg.TotalIterations = total
+			progress <- msg
+		}
+	}()
+
+	result := runBatch(request, batchProgress, presim)
+	<-forwarded
+	return result
+}
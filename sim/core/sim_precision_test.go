@@ -0,0 +1,85 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestRunRaidSimToPrecision(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 50
+	rsr.SimOptions.TargetRelativeStandardError = 0.001
+	rsr.SimOptions.MaxIterations = 5000
+
+	progress := make(chan *proto.ProgressMetrics, 1000)
+	result := core.RunSim(rsr, progress, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	if result.IterationsDone <= 50 || result.IterationsDone >= 5000 {
+		t.Fatalf("Expected more iterations than the first batch and less than the maximum, got %d with %f", result.IterationsDone, result.RelativeStandardError)
+	}
+	if result.RelativeStandardError > 0.001 {
+		t.Errorf("Expected a relative standard error of at most 0.001, got %f", result.RelativeStandardError)
+	}
+
+	var final *proto.ProgressMetrics
+	var total int32
+	presims := 0
+	for msg := range progress {
+		if msg.CompletedIterations > msg.TotalIterations {
+			t.Errorf("Completed %d of %d iterations", msg.CompletedIterations, msg.TotalIterations)
+		}
+		if msg.FinalRaidResult == nil {
+			if msg.TotalIterations < total {
+				t.Errorf("Expected the total iterations to never go down, got %d after %d", msg.TotalIterations, total)
+			}
+			total = msg.TotalIterations
+		}
+		if msg.PresimRunning {
+			presims++
+		}
+		final = msg
+	}
+	if final.GetFinalRaidResult() != result {
+		t.Errorf("Expected the result as final progress")
+	}
+	if presims != 1 {
+		t.Errorf("Expected the presims to only run for the first batch, ran %d times", presims)
+	}
+
+	// Batches continue each other's seeds, so they add up to a single sim.
+	single := googleProto.Clone(rsr).(*proto.RaidSimRequest)
+	single.SimOptions.Iterations = result.IterationsDone
+	single.SimOptions.TargetRelativeStandardError = 0
+	expected := core.RunRaidSim(single)
+	if diff := math.Abs(expected.RaidMetrics.Dps.Avg - result.RaidMetrics.Dps.Avg); diff > 1e-6*expected.RaidMetrics.Dps.Avg {
+		t.Errorf("Expected %0.3f DPS like a single sim, got %0.3f", expected.RaidMetrics.Dps.Avg, result.RaidMetrics.Dps.Avg)
+	}
+	if diff := math.Abs(expected.RelativeStandardError - result.RelativeStandardError); diff > 1e-6 {
+		t.Errorf("Expected a relative standard error of %f like a single sim, got %f", expected.RelativeStandardError, result.RelativeStandardError)
+	}
+}
+
+func TestRunRaidSimConcurrentToPrecision(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 60
+	rsr.SimOptions.TargetRelativeStandardError = 0.0001
+	rsr.SimOptions.MaxIterations = 600
+
+	result := core.RunRaidSimConcurrent(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+	if result.IterationsDone != 600 {
+		t.Errorf("Expected to stop at the maximum of 600 iterations, got %d", result.IterationsDone)
+	}
+	if result.RelativeStandardError <= 0.0001 {
+		t.Errorf("Expected the target precision to not be reached, got %f", result.RelativeStandardError)
+	}
+}
@@ -12,6 +12,11 @@ func (s *triggerSignal) Trigger() {
 	}
 }
 
+// Done returns a channel which is closed once the signal is triggered.
+func (s *triggerSignal) Done() <-chan struct{} {
+	return s.channel
+}
+
 func (s *triggerSignal) IsTriggered() bool {
 	select {
 	case <-s.channel:
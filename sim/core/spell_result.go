@@ -171,6 +171,10 @@ func (spell *Spell) ApplyPostOutcomeDamageModifiers(sim *Simulation, result *Spe
 func (spell *Spell) CalcOutcome(sim *Simulation, target *Unit, outcomeApplier OutcomeApplier) *SpellResult {
 	attackTable := spell.Unit.AttackTables[target.UnitIndex]
 	result := spell.NewResult(target)
+	if target.isDespawnedEnemy() {
+		result.Outcome = OutcomeMiss
+		return result
+	}
 
 	outcomeApplier(sim, result, attackTable)
 	result.Threat = spell.ThreatFromDamage(result.Outcome, result.Damage)
@@ -181,6 +185,10 @@ func (spell *Spell) calcDamageInternal(sim *Simulation, target *Unit, baseDamage
 	attackTable := spell.Unit.AttackTables[target.UnitIndex]
 
 	result := spell.NewResult(target)
+	if target.isDespawnedEnemy() {
+		result.Outcome = OutcomeMiss
+		return result
+	}
 	result.Damage = baseDamage
 
 	if sim.Log == nil {
@@ -273,8 +281,18 @@ func (spell *Spell) CalcAndDealOutcome(sim *Simulation, target *Unit, outcomeApp
 
 // Applies the fully computed spell result to the sim.
 func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result *SpellResult) {
+	// Hits on targets which aren't currently spawned, e.g. from AoE spells
+	// which loop over every target, don't happen at all.
+	if result.Target.isDespawnedEnemy() {
+		spell.DisposeResult(result)
+		return
+	}
+
 	if sim.CurrentTime >= 0 {
 		spell.SpellMetrics[result.Target.UnitIndex].TotalDamage += result.Damage
+		if sim.Breakdown != nil {
+			sim.Breakdown.recordDamage(sim, spell, result.Target, result.Damage)
+		}
 		if isPeriodic {
 			spell.SpellMetrics[result.Target.UnitIndex].TotalTickDamage += result.Damage
 		}
@@ -297,8 +315,9 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 	}
 
 	// Mark total damage done in raid so far for health based fights.
-	// Don't include damage done by EnemyUnits to Players
-	if result.Target.Type == EnemyUnit {
+	// Don't include damage done by EnemyUnits to Players, or damage done to
+	// adds which the fight doesn't depend on.
+	if (result.Target.Type == EnemyUnit) && !sim.Encounter.Targets[result.Target.Index].spawnsMidFight {
 		sim.Encounter.DamageTaken += result.Damage
 	}
 
@@ -309,6 +328,9 @@ func (spell *Spell) dealDamageInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s (SpellSchool: %d). (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.DamageString(), spell.SpellSchool, result.Threat)
 		}
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		sim.CombatLog.LogDamage(sim, spell, result, isPeriodic)
+	}
 
 	if !spell.Flags.Matches(SpellFlagNoOnDamageDealt) {
 		if isPeriodic {
@@ -371,12 +393,12 @@ func (spell *Spell) calcHealingInternal(sim *Simulation, target *Unit, baseHeali
 
 	if sim.Log == nil {
 		result.Damage *= casterMultiplier
-		result.Damage = spell.applyTargetHealingModifiers(result.Damage, attackTable)
+		result.Damage = spell.applyTargetHealingModifiers(sim, result.Damage, attackTable)
 		outcomeApplier(sim, result, attackTable)
 	} else {
 		result.Damage *= casterMultiplier
 		afterCasterMods := result.Damage
-		result.Damage = spell.applyTargetHealingModifiers(result.Damage, attackTable)
+		result.Damage = spell.applyTargetHealingModifiers(sim, result.Damage, attackTable)
 		afterTargetMods := result.Damage
 		outcomeApplier(sim, result, attackTable)
 		afterOutcome := result.Damage
@@ -417,8 +439,18 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 	}
 	spell.SpellMetrics[result.Target.UnitIndex].TotalHealing += result.Damage
 	spell.SpellMetrics[result.Target.UnitIndex].TotalThreat += result.Threat
+	if sim.Breakdown != nil {
+		sim.Breakdown.recordHealing(sim, spell, result.Damage)
+	}
+	overheal := 0.0
 	if result.Target.HasHealthBar() {
+		effective := max(0, min(result.Damage, result.Target.MaxHealth()-result.Target.CurrentHealth()))
+		overheal = result.Damage - effective
+		spell.SpellMetrics[result.Target.UnitIndex].TotalOverhealing += overheal
+		spell.SpellMetrics[result.Target.UnitIndex].TotalEffective += effective
 		result.Target.GainHealth(sim, result.Damage, spell.HealthMetrics(result.Target))
+	} else {
+		spell.SpellMetrics[result.Target.UnitIndex].TotalEffective += result.Damage
 	}
 
 	if sim.Log != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
@@ -428,6 +460,9 @@ func (spell *Spell) dealHealingInternal(sim *Simulation, isPeriodic bool, result
 			spell.Unit.Log(sim, "%s %s %s. (Threat: %0.3f)", result.Target.LogLabel(), spell.ActionID, result.HealingString(), result.Threat)
 		}
 	}
+	if sim.CombatLog != nil && !spell.Flags.Matches(SpellFlagNoLogs) {
+		sim.CombatLog.LogHealing(sim, spell, result, isPeriodic, overheal)
+	}
 
 	if isPeriodic {
 		spell.Unit.OnPeriodicHealDealt(sim, spell, result)
@@ -500,6 +535,10 @@ func (result *SpellResult) applyTargetModifiers(sim *Simulation, spell *Spell, a
 	result.Damage *= spell.TargetDamageMultiplier(sim, attackTable, isPeriodic)
 }
 func (spell *Spell) TargetDamageMultiplier(sim *Simulation, attackTable *AttackTable, isPeriodic bool) float64 {
+	if attackTable.Defender.PseudoStats.Invulnerable > 0 {
+		return 0
+	}
+
 	if spell.Flags.Matches(SpellFlagIgnoreTargetModifiers) {
 		return 1
 	}
@@ -542,12 +581,15 @@ func (spell *Spell) CasterHealingMultiplier() float64 {
 
 	return spell.DamageMultiplier * spell.DamageMultiplierAdditive * spell.Unit.PseudoStats.HealingDealtMultiplier
 }
-func (spell *Spell) applyTargetHealingModifiers(damage float64, attackTable *AttackTable) float64 {
+func (spell *Spell) applyTargetHealingModifiers(sim *Simulation, damage float64, attackTable *AttackTable) float64 {
 	if spell.Flags.Matches(SpellFlagIgnoreTargetModifiers) {
 		return damage
 	}
 
-	return damage *
-		attackTable.Defender.PseudoStats.HealingTakenMultiplier *
-		attackTable.HealingDealtMultiplier
+	multiplier := attackTable.Defender.PseudoStats.HealingTakenMultiplier * attackTable.HealingDealtMultiplier
+	for _, healingDoneByCaster := range attackTable.HealingDoneByCasterMultiplier {
+		multiplier *= healingDoneByCaster(sim, spell, attackTable)
+	}
+
+	return damage * multiplier
 }
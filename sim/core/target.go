@@ -4,6 +4,8 @@ import (
 	"strconv"
 	"time"
 
+	googleProto "google.golang.org/protobuf/proto"
+
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/stats"
 )
@@ -15,6 +17,10 @@ type Encounter struct {
 	ActiveTargets     []*Target
 	TargetUnits       []*Unit
 
+	// Units of the active targets, for AoE spells which hit everything
+	// that's currently up.
+	ActiveTargetUnits []*Unit
+
 	ExecuteProportion_20 float64
 	ExecuteProportion_25 float64
 	ExecuteProportion_35 float64
@@ -27,6 +33,13 @@ type Encounter struct {
 	// In health fight: set to true until we get something to base on
 	DurationIsEstimate bool
 
+	// Incoming damage on the raid's target dummies. Target AIs may add their
+	// own profiles during initialization.
+	RaidDamage *proto.RaidDamageModel
+
+	// Groups of targets which spawn and despawn during the fight.
+	Waves []*TargetWave
+
 	// Value to multiply by, for damage spells which are subject to the aoe cap.
 	aoeCapMultiplier float64
 }
@@ -44,6 +57,10 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		ExecuteProportion_90: max(options.ExecuteProportion_90, 0),
 		Targets:              []*Target{},
 		ActiveTargets:        []*Target{},
+		RaidDamage:           &proto.RaidDamageModel{},
+	}
+	if options.RaidDamage != nil {
+		encounter.RaidDamage = googleProto.Clone(options.RaidDamage).(*proto.RaidDamageModel)
 	}
 	for targetIndex, targetOptions := range options.Targets {
 		target := NewTarget(targetOptions, int32(targetIndex))
@@ -60,10 +77,23 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		encounter.TargetUnits = append(encounter.TargetUnits, &target.Unit)
 	}
 
+	if options.Script != nil {
+		encounter.Targets[0].AI = NewScriptedTargetAI(options.Script)
+		encounter.markScriptedAdds(options.Script)
+	}
+
+	for _, waveConfig := range options.Waves {
+		encounter.Waves = append(encounter.Waves, encounter.newTargetWave(waveConfig))
+	}
+
 	// If UseHealth is set, we use the sum of targets health. After creating the targets to make sure stat modifications are done
+	// Targets which spawn mid-fight don't count, as the fight can't depend on
+	// them being killed.
 	if options.UseHealth {
-		for _, t := range options.Targets {
-			encounter.EndFightAtHealth += t.Stats[stats.Health]
+		for i, t := range options.Targets {
+			if !encounter.Targets[i].spawnsMidFight {
+				encounter.EndFightAtHealth += t.Stats[stats.Health]
+			}
 		}
 		if encounter.EndFightAtHealth == 0 {
 			encounter.EndFightAtHealth = 1 // default to something so we don't instantly end without anything.
@@ -76,16 +106,31 @@ func NewEncounter(options *proto.Encounter) Encounter {
 		encounter.DurationIsEstimate = true
 	}
 
-	encounter.updateAOECapMultiplier()
+	encounter.updateActiveTargetUnits()
 
 	return encounter
 }
 
+// Adds a source of incoming damage on the raid's target dummies.
+func (encounter *Encounter) AddRaidDamageProfile(profile *proto.RaidDamageProfile) {
+	encounter.RaidDamage.Profiles = append(encounter.RaidDamage.Profiles, profile)
+}
+
 func (encounter *Encounter) AOECapMultiplier() float64 {
 	return encounter.aoeCapMultiplier
 }
+
+// Should be called whenever the list of active targets changes.
+func (encounter *Encounter) updateActiveTargetUnits() {
+	// AoE spells may be looping over the old list, so don't reuse it.
+	encounter.ActiveTargetUnits = make([]*Unit, len(encounter.ActiveTargets))
+	for i, target := range encounter.ActiveTargets {
+		encounter.ActiveTargetUnits[i] = &target.Unit
+	}
+	encounter.updateAOECapMultiplier()
+}
 func (encounter *Encounter) updateAOECapMultiplier() {
-	encounter.aoeCapMultiplier = min(10/float64(len(encounter.Targets)), 1)
+	encounter.aoeCapMultiplier = min(10/float64(len(encounter.ActiveTargets)), 1)
 }
 
 func (encounter *Encounter) doneIteration(sim *Simulation) {
@@ -93,6 +138,12 @@ func (encounter *Encounter) doneIteration(sim *Simulation) {
 		target := encounter.Targets[i]
 		target.doneIteration(sim)
 	}
+
+	// Needs to run before the targets' own metrics are finalized, while they
+	// still hold this iteration's damage taken.
+	for _, wave := range encounter.Waves {
+		wave.doneIteration(sim)
+	}
 }
 
 func (encounter *Encounter) GetMetricsProto() *proto.EncounterMetrics {
@@ -106,6 +157,10 @@ func (encounter *Encounter) GetMetricsProto() *proto.EncounterMetrics {
 		i++
 	}
 
+	for _, wave := range encounter.Waves {
+		metrics.Waves = append(metrics.Waves, wave.GetMetricsProto())
+	}
+
 	return metrics
 }
 
@@ -116,6 +171,11 @@ type Target struct {
 	IsActive bool
 
 	AI TargetAI
+
+	// Set for targets which aren't present on the pull, and are instead
+	// spawned by a wave or encounter script.
+	spawnsMidFight bool
+	wave           *TargetWave
 }
 
 func NewTarget(options *proto.Target, targetIndex int32) *Target {
@@ -174,18 +234,29 @@ func (target *Target) Reset(sim *Simulation) {
 	target.Unit.reset(sim, nil)
 	target.CurrentTarget = target.defaultTarget
 
-	target.SetGCDTimer(sim, 0)
+	target.IsActive = !target.spawnsMidFight
+	target.enabled = target.IsActive
+	if target.IsActive {
+		target.SetGCDTimer(sim, 0)
+	}
 	if target.AI != nil {
 		target.AI.Reset(sim)
 	}
 }
 
+// Cycles through the targets which are currently spawned, in index order.
+// Works from despawned targets too, by continuing from their position.
 func (target *Target) NextTarget() *Target {
-	nextIndex := target.Index + 1
-	if nextIndex >= target.Env.GetNumTargets() {
-		nextIndex = 0
+	activeTargets := target.Env.Encounter.ActiveTargets
+	for _, activeTarget := range activeTargets {
+		if activeTarget.Index > target.Index {
+			return activeTarget
+		}
+	}
+	if len(activeTargets) == 0 {
+		return target
 	}
-	return target.Env.GetTarget(nextIndex)
+	return activeTargets[0]
 }
 
 func (target *Target) GetMetricsProto() *proto.UnitMetrics {
@@ -230,6 +301,10 @@ type AttackTable struct {
 	// When you need more then 1 active, default to using the above one
 	// Used with EnableDamageDoneByCaster/DisableDamageDoneByCaster
 	DamageDoneByCasterExtraMultiplier []DynamicDamageDoneByCaster
+
+	// Healing done to the defender is multiplied by the result of each callback.
+	// Used for effects that depend on the defender's current health.
+	HealingDoneByCasterMultiplier []DynamicDamageDoneByCaster
 }
 
 func NewAttackTable(attacker *Unit, defender *Unit) *AttackTable {
@@ -2,6 +2,7 @@ package core
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/stats"
@@ -23,6 +24,8 @@ func NewTargetDummy(dummyIndex int, party *Party, partyIndex int) *TargetDummy {
 				auraTracker: newAuraTracker(),
 				Metrics:     NewUnitMetrics(),
 
+				ReactionTime: time.Millisecond * 10,
+
 				StatDependencyManager: stats.NewStatDependencyManager(),
 			},
 			Name:       name,
@@ -36,6 +39,7 @@ func NewTargetDummy(dummyIndex int, party *Party, partyIndex int) *TargetDummy {
 
 	td.Label = fmt.Sprintf("%s (#%d)", td.Name, td.Index+1)
 	td.GCD = td.NewTimer()
+	td.RotationTimer = td.NewTimer()
 
 	return td
 }
@@ -0,0 +1,289 @@
+package core
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Target AI which runs a user-provided EncounterScript, for modelling custom
+// fights without a dedicated Go implementation.
+type ScriptedTargetAI struct {
+	Target *Target
+
+	script   *proto.EncounterScript
+	raidSize int
+
+	phases           []*scriptedPhase
+	invulnerableAura *Aura
+
+	// State tracking
+	currentPhase int
+	phaseActions []*PendingAction
+}
+
+type scriptedPhase struct {
+	name           string
+	startAt        time.Duration
+	startAtPercent float64
+	events         []*scriptedEvent
+}
+
+type scriptedEvent struct {
+	config   *proto.EncounterScriptEvent
+	start    time.Duration
+	repeat   time.Duration
+	duration time.Duration
+
+	spell  *Spell
+	target *Target
+}
+
+func NewScriptedTargetAI(script *proto.EncounterScript) *ScriptedTargetAI {
+	return &ScriptedTargetAI{
+		script:   script,
+		raidSize: int(TernaryInt32(script.RaidSize > 0, script.RaidSize, 25)),
+	}
+}
+
+func (ai *ScriptedTargetAI) Initialize(target *Target, _ *proto.Target) {
+	ai.Target = target
+	ai.registerInvulnerability()
+
+	numCasts := 0
+	for phaseIdx, phaseConfig := range ai.script.Phases {
+		phase := &scriptedPhase{
+			name:           phaseConfig.Name,
+			startAt:        DurationFromSeconds(phaseConfig.StartAtSeconds),
+			startAtPercent: phaseConfig.StartAtPercent / 100,
+		}
+		if phase.name == "" {
+			phase.name = fmt.Sprintf("Phase %d", phaseIdx+1)
+		}
+
+		for eventIdx, eventConfig := range phaseConfig.Events {
+			event := &scriptedEvent{
+				config:   eventConfig,
+				start:    DurationFromSeconds(max(eventConfig.StartSeconds, 0)),
+				repeat:   DurationFromSeconds(max(eventConfig.RepeatSeconds, 0)),
+				duration: DurationFromSeconds(max(eventConfig.DurationSeconds, 0)),
+			}
+
+			switch eventConfig.Type {
+			case proto.EncounterScriptEventType_ScriptEventCast:
+				numCasts++
+				event.spell = ai.registerCast(eventConfig, numCasts)
+			case proto.EncounterScriptEventType_ScriptEventTargetSwitch, proto.EncounterScriptEventType_ScriptEventAddSpawn:
+				if (eventConfig.TargetIndex <= 0) || (eventConfig.TargetIndex >= int32(len(target.Env.Encounter.Targets))) {
+					panic(fmt.Sprintf("Encounter script event %d of %s refers to invalid target index %d", eventIdx+1, phase.name, eventConfig.TargetIndex))
+				}
+				event.target = target.Env.Encounter.Targets[eventConfig.TargetIndex]
+			}
+
+			phase.events = append(phase.events, event)
+		}
+
+		ai.phases = append(ai.phases, phase)
+	}
+}
+
+// Uses PseudoStats.Invulnerable rather than zeroing DamageTakenMultiplier, so
+// damage taken debuffs changing during the window are kept afterwards.
+func (ai *ScriptedTargetAI) registerInvulnerability() {
+	ai.invulnerableAura = ai.Target.RegisterAura(Aura{
+		Label: "Invulnerable",
+
+		OnGain: func(aura *Aura, sim *Simulation) {
+			aura.Unit.PseudoStats.Invulnerable++
+		},
+		OnExpire: func(aura *Aura, sim *Simulation) {
+			aura.Unit.PseudoStats.Invulnerable--
+		},
+	})
+}
+
+func (ai *ScriptedTargetAI) registerCast(config *proto.EncounterScriptEvent, castIndex int) *Spell {
+	actionID := ActionID{SpellID: config.SpellId}
+	if config.SpellId == 0 {
+		actionID = ActionID{OtherID: proto.OtherAction_OtherActionScriptedCast, Tag: int32(castIndex)}
+	}
+
+	variation := Clamp(config.DamageVariation, 0, 1)
+	numTargets := max(int(config.NumTargets), 1)
+
+	return ai.Target.RegisterSpell(SpellConfig{
+		ActionID:         actionID,
+		SpellSchool:      SpellSchoolFromProto(config.School),
+		ProcMask:         ProcMaskSpellDamage,
+		Flags:            SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *Simulation, target *Unit, spell *Spell) {
+			var hitTargets []*Unit
+			switch config.CastTarget {
+			case proto.EncounterScriptCastTarget_ScriptCastRandomPlayers:
+				hitTargets = sim.Raid.GetRandomPlayerUnits(sim, "Scripted Cast Target", numTargets, ai.raidSize)
+			case proto.EncounterScriptCastTarget_ScriptCastRaid:
+				hitTargets = sim.Raid.AllPlayerUnits
+			default:
+				hitTargets = []*Unit{target}
+			}
+
+			for _, hitTarget := range hitTargets {
+				baseDamage := config.Damage * (1 + variation*(2*sim.RandomFloat("Scripted Cast Damage")-1))
+				spell.CalcAndDealDamage(sim, hitTarget, baseDamage, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *ScriptedTargetAI) Reset(sim *Simulation) {
+	randomAutoOffset := DurationFromSeconds(sim.RandomFloat("Melee Timing") * ai.Target.AutoAttacks.MainhandSwingSpeed().Seconds())
+	ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime-randomAutoOffset, false)
+
+	ai.currentPhase = -1
+	ai.phaseActions = ai.phaseActions[:0]
+	if len(ai.phases) > 0 {
+		ai.startPhase(sim, 0)
+	}
+}
+
+// Name of the phase the script is currently in.
+func (ai *ScriptedTargetAI) CurrentPhaseName() string {
+	if ai.currentPhase < 0 {
+		return ""
+	}
+	return ai.phases[ai.currentPhase].name
+}
+
+func (ai *ScriptedTargetAI) startPhase(sim *Simulation, phaseIdx int) {
+	for _, pa := range ai.phaseActions {
+		pa.Cancel(sim)
+	}
+	ai.phaseActions = ai.phaseActions[:0]
+
+	ai.currentPhase = phaseIdx
+	phase := ai.phases[phaseIdx]
+	if sim.Log != nil {
+		ai.Target.Log(sim, "Starting %s", phase.name)
+	}
+
+	for _, event := range phase.events {
+		ai.scheduleEvent(sim, event)
+	}
+
+	// Time triggers are exact, while health triggers are polled from the
+	// rotation.
+	if nextIdx := phaseIdx + 1; (nextIdx < len(ai.phases)) && (ai.phases[nextIdx].startAt > sim.CurrentTime) {
+		ai.phaseActions = append(ai.phaseActions, StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: ai.phases[nextIdx].startAt,
+			OnAction: func(sim *Simulation) {
+				ai.checkPhaseTransition(sim)
+			},
+		}))
+	}
+}
+
+func (ai *ScriptedTargetAI) checkPhaseTransition(sim *Simulation) {
+	for nextIdx := ai.currentPhase + 1; nextIdx < len(ai.phases); nextIdx = ai.currentPhase + 1 {
+		next := ai.phases[nextIdx]
+		timeTriggered := (next.startAt > 0) && (sim.CurrentTime >= next.startAt)
+		percentTriggered := (next.startAtPercent > 0) && (sim.GetRemainingDurationPercent() <= next.startAtPercent)
+		if !timeTriggered && !percentTriggered {
+			return
+		}
+		ai.startPhase(sim, nextIdx)
+	}
+}
+
+func (ai *ScriptedTargetAI) scheduleEvent(sim *Simulation, event *scriptedEvent) {
+	ai.phaseActions = append(ai.phaseActions, StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: sim.CurrentTime + event.start,
+		OnAction: func(sim *Simulation) {
+			ai.executeEvent(sim, event)
+
+			if event.repeat > 0 {
+				ai.phaseActions = append(ai.phaseActions, StartPeriodicAction(sim, PeriodicActionOptions{
+					Period: event.repeat,
+					OnAction: func(sim *Simulation) {
+						ai.executeEvent(sim, event)
+					},
+				}))
+			}
+		},
+	}))
+}
+
+func (ai *ScriptedTargetAI) executeEvent(sim *Simulation, event *scriptedEvent) {
+	switch event.config.Type {
+	case proto.EncounterScriptEventType_ScriptEventCast:
+		target := ai.Target.CurrentTarget
+		if target == nil {
+			// For individual non tank sims we still want abilities to work
+			target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+		}
+		event.spell.Cast(sim, target)
+	case proto.EncounterScriptEventType_ScriptEventMovement:
+		sim.Raid.MoveAllPlayers(sim, event.duration)
+	case proto.EncounterScriptEventType_ScriptEventInvulnerable:
+		ai.makeInvulnerable(sim, event.duration)
+	case proto.EncounterScriptEventType_ScriptEventTargetSwitch:
+		ai.switchPlayerTargets(sim, event.target, event.duration)
+	case proto.EncounterScriptEventType_ScriptEventAddSpawn:
+		ai.spawnAdd(sim, event.target, event.duration)
+	}
+}
+
+func (ai *ScriptedTargetAI) makeInvulnerable(sim *Simulation, duration time.Duration) {
+	if !ai.invulnerableAura.IsActive() {
+		ai.invulnerableAura.Duration = duration
+		ai.invulnerableAura.Activate(sim)
+	} else if sim.CurrentTime+duration > ai.invulnerableAura.ExpiresAt() {
+		ai.invulnerableAura.UpdateExpires(sim.CurrentTime + duration)
+	}
+}
+
+// Points every non-tank player and pet at newTarget for the given duration, after
+// which players who are still attacking it go back to their previous target.
+func (ai *ScriptedTargetAI) switchPlayerTargets(sim *Simulation, newTarget *Target, duration time.Duration) {
+	var switchedUnits, previousTargets []*Unit
+
+	for _, unit := range sim.Raid.AllUnits {
+		if (unit == ai.Target.CurrentTarget) || (unit == newTarget.CurrentTarget) || (unit.CurrentTarget == &newTarget.Unit) {
+			continue
+		}
+
+		switchedUnits = append(switchedUnits, unit)
+		previousTargets = append(previousTargets, unit.CurrentTarget)
+		unit.CurrentTarget = &newTarget.Unit
+	}
+
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: sim.CurrentTime + duration,
+		OnAction: func(sim *Simulation) {
+			for i, unit := range switchedUnits {
+				if unit.CurrentTarget == &newTarget.Unit {
+					unit.CurrentTarget = previousTargets[i]
+				}
+			}
+		},
+	})
+}
+
+func (ai *ScriptedTargetAI) spawnAdd(sim *Simulation, add *Target, duration time.Duration) {
+	add.Spawn(sim)
+	ai.switchPlayerTargets(sim, add, duration)
+
+	StartDelayedAction(sim, DelayedActionOptions{
+		DoAt: sim.CurrentTime + duration,
+		OnAction: func(sim *Simulation) {
+			add.Despawn(sim)
+		},
+	})
+}
+
+func (ai *ScriptedTargetAI) ExecuteCustomRotation(sim *Simulation) {
+	ai.checkPhaseTransition(sim)
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+BossGCD)
+}
@@ -0,0 +1,56 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func TestScriptedInvulnerabilityKeepsDamageTakenDebuffs(t *testing.T) {
+	sim := &Simulation{}
+	target := &Target{
+		Unit: Unit{
+			Type:        EnemyUnit,
+			Level:       88,
+			auraTracker: newAuraTracker(),
+			PseudoStats: stats.NewPseudoStats(),
+		},
+	}
+	ai := &ScriptedTargetAI{Target: target}
+	ai.registerInvulnerability()
+	ai.invulnerableAura.Duration = NeverExpires
+
+	debuff := target.RegisterAura(Aura{
+		Label:    "Damage Taken Debuff",
+		Duration: NeverExpires,
+		OnGain: func(aura *Aura, sim *Simulation) {
+			aura.Unit.PseudoStats.DamageTakenMultiplier *= 1.3
+		},
+		OnExpire: func(aura *Aura, sim *Simulation) {
+			aura.Unit.PseudoStats.DamageTakenMultiplier /= 1.3
+		},
+	})
+
+	spell := &Spell{}
+	attackTable := &AttackTable{Defender: &target.Unit, DamageTakenMultiplier: 1, HauntSEDamageTakenMultiplier: 1}
+
+	ai.invulnerableAura.Activate(sim)
+	debuff.Activate(sim)
+	if multiplier := spell.TargetDamageMultiplier(sim, attackTable, false); multiplier != 0 {
+		t.Fatalf("Expected no damage while invulnerable, got multiplier %f", multiplier)
+	}
+
+	// A debuff applied during the window stays after it ends.
+	ai.invulnerableAura.Deactivate(sim)
+	if multiplier := spell.TargetDamageMultiplier(sim, attackTable, false); !WithinToleranceFloat64(multiplier, 1.3, 1e-9) {
+		t.Fatalf("Expected multiplier 1.3 after invulnerability ended, got %f", multiplier)
+	}
+
+	// A debuff removed during the window isn't restored when it ends.
+	ai.invulnerableAura.Activate(sim)
+	debuff.Deactivate(sim)
+	ai.invulnerableAura.Deactivate(sim)
+	if multiplier := spell.TargetDamageMultiplier(sim, attackTable, false); !WithinToleranceFloat64(multiplier, 1, 1e-9) {
+		t.Fatalf("Expected multiplier 1 after invulnerability ended, got %f", multiplier)
+	}
+}
@@ -0,0 +1,61 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func TestEncounterScript(t *testing.T) {
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 20
+	rsr.Encounter.Targets = append(rsr.Encounter.Targets, core.NewDefaultTarget())
+	rsr.Encounter.Script = &proto.EncounterScript{
+		Phases: []*proto.EncounterScriptPhase{
+			{
+				Name: "Pull",
+				Events: []*proto.EncounterScriptEvent{
+					{
+						Type:          proto.EncounterScriptEventType_ScriptEventCast,
+						RepeatSeconds: 10,
+						Damage:        1000,
+						School:        proto.SpellSchool_SpellSchoolFire,
+						CastTarget:    proto.EncounterScriptCastTarget_ScriptCastRaid,
+					},
+				},
+			},
+			{
+				Name:           "Adds",
+				StartAtSeconds: 150,
+				Events: []*proto.EncounterScriptEvent{
+					{
+						Type:            proto.EncounterScriptEventType_ScriptEventInvulnerable,
+						DurationSeconds: 150,
+					},
+					{
+						Type:            proto.EncounterScriptEventType_ScriptEventAddSpawn,
+						DurationSeconds: 150,
+						TargetIndex:     1,
+					},
+				},
+			},
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	// 15 raid-wide casts before the second phase cancels them.
+	if dtps := result.RaidMetrics.Parties[0].Players[0].Dtps.Avg; math.Abs(dtps-50) > 1 {
+		t.Errorf("Expected the player to take ~50 DTPS, got %0.1f", dtps)
+	}
+
+	boss, add := result.EncounterMetrics.Targets[0], result.EncounterMetrics.Targets[1]
+	if add.Dtps.Avg < 0.5*boss.Dtps.Avg {
+		t.Errorf("Expected the add to take roughly as much damage as the boss, got %0.1f vs %0.1f DTPS", add.Dtps.Avg, boss.Dtps.Avg)
+	}
+}
@@ -0,0 +1,375 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+// A group of targets which spawn together during the fight, and despawn once
+// they are all killed or their duration runs out.
+type TargetWave struct {
+	Name    string
+	Targets []*Target
+
+	spawnAt        time.Duration
+	spawnAtPercent float64
+	duration       time.Duration
+
+	// State tracking
+	spawnedAt     time.Duration
+	despawnedAt   time.Duration
+	numAlive      int
+	numKilled     int
+	triggerAction *PendingAction
+	despawnAction *PendingAction
+
+	// Metrics, aggregated over all iterations.
+	dps           DistributionMetrics
+	numIterations int
+	numSpawns     int
+	numKills      int
+	totalLifetime time.Duration
+}
+
+func (encounter *Encounter) newTargetWave(config *proto.TargetWave) *TargetWave {
+	wave := &TargetWave{
+		Name:           config.Name,
+		spawnAt:        DurationFromSeconds(max(config.SpawnAtSeconds, 0)),
+		spawnAtPercent: Clamp(config.SpawnAtPercent, 0, 100) / 100,
+		duration:       DurationFromSeconds(max(config.DurationSeconds, 0)),
+		dps:            NewDistributionMetrics(),
+	}
+	if wave.Name == "" {
+		wave.Name = fmt.Sprintf("Wave %d", len(encounter.Waves)+1)
+	}
+
+	for _, targetIndex := range config.TargetIndices {
+		if (targetIndex <= 0) || (targetIndex >= int32(len(encounter.Targets))) {
+			panic(fmt.Sprintf("%s refers to invalid target index %d", wave.Name, targetIndex))
+		}
+
+		target := encounter.Targets[targetIndex]
+		if target.wave != nil {
+			panic(fmt.Sprintf("Target %d is part of both %s and %s", targetIndex, target.wave.Name, wave.Name))
+		}
+
+		target.wave = wave
+		target.spawnsMidFight = wave.spawnsMidFight()
+		wave.Targets = append(wave.Targets, target)
+	}
+
+	if len(wave.Targets) == 0 {
+		panic(fmt.Sprintf("%s has no targets", wave.Name))
+	}
+
+	return wave
+}
+
+// Waves without a trigger are up from the pull.
+func (wave *TargetWave) spawnsMidFight() bool {
+	return (wave.spawnAt > 0) || (wave.spawnAtPercent > 0)
+}
+
+// Adds which are spawned by their own AI or a script, rather than by a wave.
+func (encounter *Encounter) markScriptedAdds(script *proto.EncounterScript) {
+	for _, phase := range script.Phases {
+		for _, event := range phase.Events {
+			if (event.Type == proto.EncounterScriptEventType_ScriptEventAddSpawn) && (event.TargetIndex > 0) && (event.TargetIndex < int32(len(encounter.Targets))) {
+				encounter.Targets[event.TargetIndex].spawnsMidFight = true
+			}
+		}
+	}
+}
+
+// Rebuilds the list of active targets at the start of an iteration, after
+// every target has been reset.
+func (encounter *Encounter) resetActiveTargets() {
+	encounter.ActiveTargets = encounter.ActiveTargets[:0]
+	for _, target := range encounter.Targets {
+		if target.IsActive {
+			encounter.ActiveTargets = append(encounter.ActiveTargets, target)
+		}
+	}
+	encounter.updateActiveTargetUnits()
+}
+
+func (env *Environment) setupTargetWaves() {
+	for _, target := range env.Encounter.Targets {
+		if target.spawnsMidFight || (target.wave != nil) {
+			target.registerDeathTracking()
+		}
+	}
+}
+
+// Gives wave targets with health a health bar, so that they despawn once
+// they've taken that much damage.
+func (target *Target) registerDeathTracking() {
+	if target.GetStat(stats.Health) <= 0 {
+		return
+	}
+
+	target.EnableHealthBar()
+
+	onDamageTaken := func(aura *Aura, sim *Simulation, _ *Spell, result *SpellResult) {
+		if (result.Damage <= 0) || !target.IsActive || (target.CurrentHealth() <= 0) {
+			return
+		}
+
+		aura.Unit.RemoveHealth(sim, result.Damage)
+		if target.CurrentHealth() <= 0 {
+			// Despawning expires the target's auras, so wait until whatever
+			// dealt the killing blow has finished.
+			StartDelayedAction(sim, DelayedActionOptions{
+				DoAt: sim.CurrentTime,
+				OnAction: func(sim *Simulation) {
+					target.despawn(sim, true)
+				},
+			})
+		}
+	}
+
+	target.RegisterAura(Aura{
+		Label:    "Death Tracking",
+		Duration: NeverExpires,
+		OnReset: func(aura *Aura, sim *Simulation) {
+			aura.Activate(sim)
+		},
+		OnSpellHitTaken:       onDamageTaken,
+		OnPeriodicDamageTaken: onDamageTaken,
+	})
+}
+
+func (encounter *Encounter) resetWaves(sim *Simulation) {
+	// Players assigned to an add which isn't up yet start on the boss, and
+	// switch over once it spawns.
+	for _, unit := range sim.Raid.AllUnits {
+		if (unit.CurrentTarget != nil) && unit.CurrentTarget.isDespawnedEnemy() {
+			unit.CurrentTarget = &encounter.ActiveTargets[0].Unit
+		}
+	}
+
+	for _, wave := range encounter.Waves {
+		wave.reset(sim)
+	}
+}
+
+func (wave *TargetWave) reset(sim *Simulation) {
+	wave.dps.reset()
+	wave.spawnedAt = -1
+	wave.despawnedAt = -1
+	wave.numAlive = 0
+	wave.numKilled = 0
+	wave.triggerAction = nil
+	wave.despawnAction = nil
+
+	if !wave.spawnsMidFight() {
+		wave.onSpawn(sim)
+		return
+	}
+
+	// Time triggers are exact, while health triggers are polled.
+	if wave.spawnAt > 0 {
+		wave.triggerAction = StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: wave.spawnAt,
+			OnAction: func(sim *Simulation) {
+				wave.Spawn(sim)
+			},
+		})
+	}
+	if wave.spawnAtPercent > 0 {
+		StartPeriodicAction(sim, PeriodicActionOptions{
+			Period: time.Second,
+			OnAction: func(sim *Simulation) {
+				if (wave.spawnedAt < 0) && (sim.GetRemainingDurationPercent() <= wave.spawnAtPercent) {
+					wave.Spawn(sim)
+				}
+			},
+		})
+	}
+}
+
+// Spawns every target in the wave. Does nothing if the wave has already
+// spawned this iteration.
+func (wave *TargetWave) Spawn(sim *Simulation) {
+	if wave.spawnedAt >= 0 {
+		return
+	}
+
+	if sim.Log != nil {
+		wave.Targets[0].Log(sim, "Spawning %s", wave.Name)
+	}
+
+	for _, target := range wave.Targets {
+		target.Spawn(sim)
+	}
+	wave.onSpawn(sim)
+}
+
+func (wave *TargetWave) onSpawn(sim *Simulation) {
+	wave.spawnedAt = max(sim.CurrentTime, 0)
+	wave.numAlive = len(wave.Targets)
+
+	if wave.triggerAction != nil {
+		wave.triggerAction.Cancel(sim)
+		wave.triggerAction = nil
+	}
+
+	if wave.duration > 0 {
+		wave.despawnAction = StartDelayedAction(sim, DelayedActionOptions{
+			DoAt: wave.spawnedAt + wave.duration,
+			OnAction: func(sim *Simulation) {
+				wave.despawnAction = nil
+				wave.Despawn(sim)
+			},
+		})
+	}
+}
+
+// Despawns any targets in the wave which are still alive.
+func (wave *TargetWave) Despawn(sim *Simulation) {
+	for _, target := range wave.Targets {
+		target.Despawn(sim)
+	}
+}
+
+func (wave *TargetWave) onTargetDespawned(sim *Simulation, killed bool) {
+	wave.numAlive--
+	if killed {
+		wave.numKilled++
+	}
+
+	if wave.numAlive > 0 {
+		return
+	}
+
+	wave.despawnedAt = sim.CurrentTime
+	if wave.despawnAction != nil {
+		wave.despawnAction.Cancel(sim)
+		wave.despawnAction = nil
+	}
+
+	if sim.Log != nil {
+		wave.Targets[0].Log(sim, "%s despawned after %s, %d of %d killed", wave.Name, wave.despawnedAt-wave.spawnedAt, wave.numKilled, len(wave.Targets))
+	}
+}
+
+func (wave *TargetWave) doneIteration(sim *Simulation) {
+	wave.numIterations++
+
+	for _, target := range wave.Targets {
+		wave.dps.Total += target.Metrics.dtps.Total
+	}
+	wave.dps.doneIteration(sim)
+
+	if wave.spawnedAt < 0 {
+		return
+	}
+
+	wave.numSpawns++
+	if wave.numKilled == len(wave.Targets) {
+		wave.numKills++
+	}
+
+	despawnedAt := TernaryDuration(wave.despawnedAt >= 0, wave.despawnedAt, sim.CurrentTime)
+	wave.totalLifetime += despawnedAt - wave.spawnedAt
+}
+
+func (wave *TargetWave) GetMetricsProto() *proto.TargetWaveMetrics {
+	metrics := &proto.TargetWaveMetrics{
+		Name: wave.Name,
+		Dps:  wave.dps.ToProto(),
+	}
+
+	if wave.numIterations > 0 {
+		metrics.SpawnRate = float64(wave.numSpawns) / float64(wave.numIterations)
+	}
+	if wave.numSpawns > 0 {
+		metrics.KillRate = float64(wave.numKills) / float64(wave.numSpawns)
+		metrics.AvgLifetimeSeconds = wave.totalLifetime.Seconds() / float64(wave.numSpawns)
+	}
+
+	return metrics
+}
+
+// Brings a target into the fight, e.g. when an add spawns. Players who were
+// assigned to the target go back to attacking it.
+func (target *Target) Spawn(sim *Simulation) {
+	if target.IsActive {
+		return
+	}
+
+	target.IsActive = true
+	target.enabled = true
+	if target.HasHealthBar() {
+		target.currentHealth = target.MaxHealth()
+	}
+
+	activeTargets := &target.Env.Encounter.ActiveTargets
+	insertAt, _ := slices.BinarySearchFunc(*activeTargets, target, func(a, b *Target) int {
+		return int(a.Index - b.Index)
+	})
+	*activeTargets = slices.Insert(*activeTargets, insertAt, target)
+	target.Env.Encounter.updateActiveTargetUnits()
+
+	for _, unit := range sim.Raid.AllUnits {
+		if unit.defaultTarget == &target.Unit {
+			unit.CurrentTarget = &target.Unit
+		}
+	}
+
+	target.AutoAttacks.EnableAutoSwing(sim)
+	target.SetGCDTimer(sim, sim.CurrentTime)
+
+	if sim.Log != nil {
+		target.Log(sim, "Spawned")
+	}
+}
+
+// Removes a target from the fight, e.g. when an add is killed. Its DoTs and
+// other temporary debuffs drop, and anyone attacking it moves on to the
+// first active target. Permanent auras, like its death tracking and raid
+// debuffs, stay up so they still apply if the target spawns again.
+func (target *Target) Despawn(sim *Simulation) {
+	target.despawn(sim, false)
+}
+
+func (target *Target) despawn(sim *Simulation, killed bool) {
+	if !target.IsActive {
+		return
+	}
+
+	target.IsActive = false
+	target.enabled = false
+
+	encounter := &target.Env.Encounter
+	if idx := slices.Index(encounter.ActiveTargets, target); idx != -1 {
+		encounter.ActiveTargets = slices.Delete(encounter.ActiveTargets, idx, idx+1)
+	}
+	encounter.updateActiveTargetUnits()
+
+	target.AutoAttacks.CancelAutoSwing(sim)
+	if target.rotationAction != nil {
+		target.CancelGCDTimer(sim)
+	}
+	target.auraTracker.expireAll(sim)
+
+	if len(encounter.ActiveTargets) > 0 {
+		for _, unit := range sim.Raid.AllUnits {
+			if unit.CurrentTarget == &target.Unit {
+				unit.CurrentTarget = &encounter.ActiveTargets[0].Unit
+			}
+		}
+	}
+
+	if sim.Log != nil {
+		target.Log(sim, Ternary(killed, "Killed", "Despawned"))
+	}
+
+	if target.wave != nil {
+		target.wave.onTargetDespawned(sim, killed)
+	}
+}
@@ -0,0 +1,228 @@
+package core_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func TestTargetWaves(t *testing.T) {
+	const addHealth = 1_000_000
+
+	boss := googleProto.Clone(core.NewDefaultTarget()).(*proto.Target)
+	boss.TankIndex = -1
+	boss.SecondTankIndex = -1
+
+	// The player tanks the first add, so attacks it as soon as it spawns.
+	tankedAdd := googleProto.Clone(core.NewDefaultTarget()).(*proto.Target)
+	tankedAdd.TankIndex = 0
+	tankedAdd.Stats[stats.Health] = addHealth
+
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 20
+	rsr.Raid.Tanks = []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}}
+	rsr.Encounter.Targets = []*proto.Target{boss, tankedAdd, core.NewDefaultTarget()}
+	rsr.Encounter.Waves = []*proto.TargetWave{
+		{
+			Name:           "Tanked Add",
+			TargetIndices:  []int32{1},
+			SpawnAtSeconds: 60,
+		},
+		{
+			Name:            "Ignored Add",
+			TargetIndices:   []int32{2},
+			SpawnAtPercent:  20,
+			DurationSeconds: 30,
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	tanked, ignored := result.EncounterMetrics.Waves[0], result.EncounterMetrics.Waves[1]
+	if tanked.SpawnRate != 1 || tanked.KillRate != 1 {
+		t.Errorf("Expected the tanked add to always spawn and die, got spawn rate %0.2f and kill rate %0.2f", tanked.SpawnRate, tanked.KillRate)
+	}
+	if tanked.AvgLifetimeSeconds <= 0 || tanked.AvgLifetimeSeconds >= 240 {
+		t.Errorf("Expected the tanked add to die before the end of the fight, got lifetime %0.1fs", tanked.AvgLifetimeSeconds)
+	}
+
+	// Damage stops once the add dies, so it takes its health worth plus a
+	// single overkilling hit.
+	if damageTaken := result.EncounterMetrics.Targets[1].Dtps.Avg * 300; damageTaken < addHealth || damageTaken > addHealth*1.1 {
+		t.Errorf("Expected the tanked add to take ~%d damage, got %0.0f", addHealth, damageTaken)
+	}
+	if math.Abs(tanked.Dps.Avg-result.EncounterMetrics.Targets[1].Dtps.Avg) > 1e-6 {
+		t.Errorf("Expected wave DPS to match damage taken by its target, got %0.1f vs %0.1f", tanked.Dps.Avg, result.EncounterMetrics.Targets[1].Dtps.Avg)
+	}
+	if result.EncounterMetrics.Targets[0].Dtps.Avg <= 0 {
+		t.Errorf("Expected the player to go back to the boss after killing the add")
+	}
+
+	if ignored.SpawnRate != 1 || ignored.KillRate != 0 || math.Abs(ignored.AvgLifetimeSeconds-30) > 1e-6 {
+		t.Errorf("Expected the ignored add to despawn after 30s, got spawn rate %0.2f, kill rate %0.2f and lifetime %0.1fs", ignored.SpawnRate, ignored.KillRate, ignored.AvgLifetimeSeconds)
+	}
+}
+
+func TestTargetWavesAOE(t *testing.T) {
+	multiShotID := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 2643}}
+
+	// The add at index 1 despawns early on, while the one after it stays up
+	// for the whole fight, so AoE spells need to skip over the gap.
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 20
+	rsr.Encounter.Targets = []*proto.Target{core.NewDefaultTarget(), core.NewDefaultTarget(), core.NewDefaultTarget()}
+	rsr.Encounter.Waves = []*proto.TargetWave{
+		{
+			Name:            "Short-lived Add",
+			TargetIndices:   []int32{1},
+			DurationSeconds: 20,
+		},
+	}
+	player := rsr.Raid.Parties[0].Players[0]
+	player.DistanceFromTarget = 20
+	player.Rotation.PriorityList = append([]*proto.APLListItem{{Action: &proto.APLAction{
+		Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: multiShotID}},
+	}}}, player.Rotation.PriorityList...)
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	damage := make([]float64, len(rsr.Encounter.Targets))
+	for _, action := range result.RaidMetrics.Parties[0].Players[0].Actions {
+		if action.Id.GetSpellId() != 2643 {
+			continue
+		}
+		for _, targetMetrics := range action.Targets {
+			// Targets come first in the unit indices.
+			if int(targetMetrics.UnitIndex) < len(damage) {
+				damage[targetMetrics.UnitIndex] += targetMetrics.Damage
+			}
+		}
+	}
+
+	if damage[0] <= 0 {
+		t.Fatalf("Expected Multi-Shot to be cast, got damage %v", damage)
+	}
+	if damage[2] < damage[0]*0.9 || damage[2] > damage[0]*1.1 {
+		t.Errorf("Expected Multi-Shot to keep hitting the add which stays up as often as the boss, got damage %v", damage)
+	}
+	if damage[1] <= 0 || damage[1] > damage[0]*0.2 {
+		t.Errorf("Expected Multi-Shot to stop hitting the add once it despawns, got damage %v", damage)
+	}
+}
+
+func TestTargetWavesConsecration(t *testing.T) {
+	consecrationID := &proto.ActionID{RawId: &proto.ActionID_SpellId{SpellId: 26573}}
+
+	// Consecration ticks loop over every target, so both the add which
+	// despawns early and the one which only spawns late must be skipped
+	// while they're not up.
+	rsr := makeTestCase(getTestPlayerProtPaladin())
+	rsr.SimOptions.Iterations = 20
+	rsr.Raid.Tanks = []*proto.UnitReference{{Type: proto.UnitReference_Player, Index: 0}}
+	rsr.Encounter.Targets = []*proto.Target{core.NewDefaultTarget(), core.NewDefaultTarget(), core.NewDefaultTarget()}
+	rsr.Encounter.Waves = []*proto.TargetWave{
+		{
+			Name:            "Short-lived Add",
+			TargetIndices:   []int32{1},
+			DurationSeconds: 20,
+		},
+		{
+			Name:           "Late Add",
+			TargetIndices:  []int32{2},
+			SpawnAtSeconds: 240,
+		},
+	}
+	player := rsr.Raid.Parties[0].Players[0]
+	player.Rotation.PriorityList = append([]*proto.APLListItem{{Action: &proto.APLAction{
+		Action: &proto.APLAction_CastSpell{CastSpell: &proto.APLActionCastSpell{SpellId: consecrationID}},
+	}}}, player.Rotation.PriorityList...)
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	damage := make([]float64, len(rsr.Encounter.Targets))
+	hits := make([]int32, len(rsr.Encounter.Targets))
+	for _, action := range result.RaidMetrics.Parties[0].Players[0].Actions {
+		if action.Id.GetSpellId() != 26573 {
+			continue
+		}
+		for _, targetMetrics := range action.Targets {
+			if int(targetMetrics.UnitIndex) < len(damage) {
+				damage[targetMetrics.UnitIndex] += targetMetrics.Damage
+				hits[targetMetrics.UnitIndex] += targetMetrics.Hits + targetMetrics.Crits + targetMetrics.Ticks + targetMetrics.CritTicks + targetMetrics.Misses
+			}
+		}
+	}
+
+	if damage[0] <= 0 {
+		t.Fatalf("Expected Consecration to be cast, got damage %v", damage)
+	}
+	// Consecration ticks once a second, so the add which is up for the
+	// first 20s of a 300s fight should take at most 1/10th of the ticks.
+	if damage[1] <= 0 || damage[1] > damage[0]*0.2 || hits[1]*10 > hits[0] {
+		t.Errorf("Expected Consecration to stop hitting the add once it despawns, got damage %v and hits %v", damage, hits)
+	}
+	if damage[2] <= 0 || damage[2] > damage[0]*0.3 || hits[2]*5 > hits[0] {
+		t.Errorf("Expected Consecration to only hit the late add once it spawns, got damage %v and hits %v", damage, hits)
+	}
+	for i := 1; i < len(damage); i++ {
+		if dtps := result.EncounterMetrics.Targets[i].Dtps.Avg * 300; dtps > damage[0]*0.5 {
+			t.Errorf("Expected target %d to only take damage while it's up, got %0.0f", i, dtps)
+		}
+	}
+}
+
+func TestTargetRespawn(t *testing.T) {
+	const addHealth = 300_000
+
+	add := googleProto.Clone(core.NewDefaultTarget()).(*proto.Target)
+	add.Stats[stats.Health] = addHealth
+
+	// The add is only up briefly the first time, and killed once it's back.
+	rsr := makeTestCase(getTestPlayerMM())
+	rsr.SimOptions.Iterations = 20
+	rsr.Encounter.Targets = []*proto.Target{core.NewDefaultTarget(), add}
+	rsr.Encounter.Script = &proto.EncounterScript{
+		Phases: []*proto.EncounterScriptPhase{
+			{
+				Name: "Pull",
+				Events: []*proto.EncounterScriptEvent{
+					{
+						Type:            proto.EncounterScriptEventType_ScriptEventAddSpawn,
+						StartSeconds:    10,
+						DurationSeconds: 2,
+						TargetIndex:     1,
+					},
+					{
+						Type:            proto.EncounterScriptEventType_ScriptEventAddSpawn,
+						StartSeconds:    30,
+						DurationSeconds: 120,
+						TargetIndex:     1,
+					},
+				},
+			},
+		},
+	}
+
+	result := core.RunRaidSim(rsr)
+	if result.Error != nil {
+		t.Fatalf("Sim failed: %s", result.Error.Message)
+	}
+
+	// A surviving add would keep taking damage for the full 2 minutes.
+	if damageTaken := result.EncounterMetrics.Targets[1].Dtps.Avg * 300; damageTaken < addHealth || damageTaken > addHealth*1.5 {
+		t.Errorf("Expected the respawned add to die after taking ~%d damage, got %0.0f", addHealth, damageTaken)
+	}
+}
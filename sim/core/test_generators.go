@@ -213,6 +213,7 @@ func (combos *SettingsCombos) GetTest(testIdx int) (string, *proto.ComputeStatsR
 	}
 	if combos.IsHealer {
 		rsr.Raid.TargetDummies = 1
+		rsr.Encounter = withHealerRaidDamage(rsr.Encounter)
 	}
 
 	return strings.Join(testNameParts, "-"), nil, nil, rsr
@@ -411,6 +412,7 @@ func (generator *ItemsTestGenerator) GetTest(testIdx int) (string, *proto.Comput
 	}
 	if generator.IsHealer {
 		rsr.Raid.TargetDummies = 1
+		rsr.Encounter = withHealerRaidDamage(rsr.Encounter)
 	}
 
 	return label, nil, nil, rsr
@@ -520,8 +522,14 @@ func FullCharacterTestSuiteGenerator(config CharacterSuiteConfig) TestGenerator
 	if config.IsTank {
 		defaultRaid.Tanks = append(defaultRaid.Tanks, &proto.UnitReference{Type: proto.UnitReference_Player, Index: 0})
 	}
+	defaultEncounter := func(variation float64) *proto.Encounter {
+		return MakeSingleTargetEncounter(variation)
+	}
 	if config.IsHealer {
 		defaultRaid.TargetDummies = 1
+		defaultEncounter = func(variation float64) *proto.Encounter {
+			return withHealerRaidDamage(MakeSingleTargetEncounter(variation))
+		}
 	}
 
 	generator := &CombinedTestGenerator{
@@ -590,7 +598,7 @@ func FullCharacterTestSuiteGenerator(config CharacterSuiteConfig) TestGenerator
 			Name: "Default",
 			Request: &proto.RaidSimRequest{
 				Raid:       newRaid,
-				Encounter:  MakeSingleTargetEncounter(0),
+				Encounter:  defaultEncounter(0),
 				SimOptions: DefaultSimTestOptions,
 			},
 		},
@@ -626,7 +634,7 @@ func FullCharacterTestSuiteGenerator(config CharacterSuiteConfig) TestGenerator
 			Name: "Default",
 			Request: &proto.RaidSimRequest{
 				Raid:       defaultRaid,
-				Encounter:  MakeSingleTargetEncounter(5),
+				Encounter:  defaultEncounter(5),
 				SimOptions: AverageDefaultSimTestOptions,
 			},
 		},
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
 	"testing"
@@ -169,7 +170,43 @@ func checkResourceMetrics(t *testing.T, loc string, st []*proto.ResourceMetrics,
 	}
 }
 
+// Percentiles are estimated from digests, which are compressed differently
+// depending on how iterations were split across threads. Rather than exact
+// values, check that each is within the error bounds of the other's.
+func checkPercentiles(t *testing.T, loc string, st *proto.DistributionMetrics, mt *proto.DistributionMetrics, baseFloatTolerance float64) {
+	digest := quantileDigestFromProto(st.Digest)
+	percentiles := []struct {
+		name  string
+		q     float64
+		value float64
+	}{
+		{"P5", 0.05, mt.P5},
+		{"P25", 0.25, mt.P25},
+		{"Median", 0.5, mt.Median},
+		{"P75", 0.75, mt.P75},
+		{"P95", 0.95, mt.P95},
+	}
+
+	for _, p := range percentiles {
+		rankTolerance := 2 * math.Pi * math.Sqrt(p.q*(1-p.q)) / digestCompression
+		low := digest.quantile(max(p.q-rankTolerance, 0)) - baseFloatTolerance
+		high := digest.quantile(min(p.q+rankTolerance, 1)) + baseFloatTolerance
+		if p.value < low || p.value > high {
+			t.Logf("%s.%s: Expected between %f and %f but is %f for multi threaded result!", loc, p.name, low, high, p.value)
+			t.Fail()
+		}
+	}
+}
+
 func compareStruct(t *testing.T, loc string, vst reflect.Value, vmt reflect.Value, baseFloatTolerance float64) {
+	isDistribution := false
+	if vst.CanAddr() && vmt.CanAddr() {
+		if stDist, ok := vst.Addr().Interface().(*proto.DistributionMetrics); ok {
+			isDistribution = true
+			checkPercentiles(t, loc, stDist, vmt.Addr().Interface().(*proto.DistributionMetrics), baseFloatTolerance)
+		}
+	}
+
 	for i := 0; i < vst.NumField(); i++ {
 		fieldName := vst.Type().Field(i).Name
 		fieldType := vst.Type().Field(i).Type.Name()
@@ -178,6 +215,10 @@ func compareStruct(t *testing.T, loc string, vst reflect.Value, vmt reflect.Valu
 			continue
 		}
 
+		if isDistribution && slices.Contains([]string{"P5", "P25", "Median", "P75", "P95", "Digest"}, fieldName) {
+			continue
+		}
+
 		stField := vst.Field(i)
 		mtField := vmt.Field(i)
 
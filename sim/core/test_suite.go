@@ -236,6 +236,8 @@ func RunTestSuite(t *testing.T, suiteName string, generator TestGenerator) {
 					t.Fail()
 				}
 			} else if rsr != nil && !strings.Contains(testName, "Casts") {
+				// The digests bound the percentiles of the multi threaded result below.
+				rsr.SimOptions.SaveDigests = true
 				simResult := testSuite.TestDPS(fullTestName, rsr)
 				if actualDpsResult, ok := testSuite.testResults.DpsResults[fullTestName]; ok {
 					if expectedDpsResult, ok := expectedResults.DpsResults[fullTestName]; ok {
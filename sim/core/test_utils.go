@@ -5,6 +5,8 @@ import (
 	"os"
 	"testing"
 
+	googleProto "google.golang.org/protobuf/proto"
+
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/stats"
 )
@@ -141,6 +143,21 @@ func MakeDefaultEncounterCombos() []EncounterCombo {
 	}
 }
 
+// Incoming damage used by healer test suites, so that heals and absorbs have
+// missing health to fill.
+var HealerTestRaidDamage = &proto.RaidDamageModel{
+	Profiles: []*proto.RaidDamageProfile{
+		{Type: proto.RaidDamageProfileType_RaidDamageConstant, Dps: 3000, DamageVariation: 0.2},
+		{Type: proto.RaidDamageProfileType_RaidDamageSpiky, Dps: 2000, DamageVariation: 0.3},
+	},
+}
+
+func withHealerRaidDamage(encounter *proto.Encounter) *proto.Encounter {
+	encounter = googleProto.Clone(encounter).(*proto.Encounter)
+	encounter.RaidDamage = HealerTestRaidDamage
+	return encounter
+}
+
 func MakeSingleTargetEncounter(variation float64) *proto.Encounter {
 	return &proto.Encounter{
 		Duration:             LongDuration,
@@ -197,12 +197,16 @@ func (unit *Unit) getSpellpowerValueImpl(spell *Spell) float64 {
 
 // Units can be disabled for several reasons:
 //  1. Downtime for temporary pets (e.g. Water Elemental)
-//  2. Enemy units in various phases (not yet implemented)
-//  3. Dead units (not yet implemented)
+//  2. Enemy units which haven't spawned yet, or have been killed
+//  3. Dead players (not yet implemented)
 func (unit *Unit) IsEnabled() bool {
 	return unit.enabled
 }
 
+func (unit *Unit) isDespawnedEnemy() bool {
+	return (unit.Type == EnemyUnit) && !unit.enabled
+}
+
 func (unit *Unit) IsActive() bool {
 	return unit.IsEnabled() && unit.CurrentHealthPercent() > 0
 }
@@ -620,6 +624,10 @@ func (unit *Unit) doneIteration(sim *Simulation) {
 	for _, spell := range unit.Spellbook {
 		spell.doneIteration()
 	}
+
+	if unit.Rotation != nil {
+		unit.Rotation.doneIteration(sim)
+	}
 }
 
 func (unit *Unit) GetSpellsMatchingSchool(school SpellSchool) []*Spell {
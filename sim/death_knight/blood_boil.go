@@ -8,7 +8,7 @@ var BloodBoilActionID = core.ActionID{SpellID: 48721}
 
 func (dk *DeathKnight) registerBloodBoilSpell() {
 	rpMetric := dk.NewRunicPowerMetrics(BloodBoilActionID)
-	results := make([]*core.SpellResult, dk.Env.GetNumTargets())
+	results := make([]*core.SpellResult, len(dk.Env.Encounter.TargetUnits))
 	dk.RegisterSpell(core.SpellConfig{
 		ActionID:       BloodBoilActionID,
 		Flags:          core.SpellFlagAPL,
@@ -31,7 +31,7 @@ func (dk *DeathKnight) registerBloodBoilSpell() {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			anyHit := false
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
+			for idx, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 				baseDamage := dk.ClassSpellScaling*0.31700000167 + 0.08*spell.MeleeAttackPower()
 				baseDamage *= core.TernaryFloat64(dk.DiseasesAreActive(aoeTarget), 1.5, 1.0)
 				baseDamage *= sim.Encounter.AOECapMultiplier()
@@ -52,14 +52,14 @@ func (dk *DeathKnight) registerBloodBoilSpell() {
 }
 
 func (dk *DeathKnight) registerDrwBloodBoilSpell() *core.Spell {
-	results := make([]*core.SpellResult, dk.Env.GetNumTargets())
+	results := make([]*core.SpellResult, len(dk.Env.Encounter.TargetUnits))
 	return dk.RuneWeapon.RegisterSpell(core.SpellConfig{
 		ActionID:    BloodBoilActionID,
 		SpellSchool: core.SpellSchoolShadow,
 		ProcMask:    core.ProcMaskSpellDamage,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
+			for idx, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 				baseDamage := dk.ClassSpellScaling*0.31700000167 + 0.08*spell.MeleeAttackPower()
 				baseDamage *= core.TernaryFloat64(dk.RuneWeapon.DiseasesAreActive(aoeTarget), 1.5, 1.0)
 				baseDamage *= sim.Encounter.AOECapMultiplier()
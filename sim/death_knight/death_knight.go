@@ -110,6 +110,13 @@ func (dk *DeathKnight) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	raidBuffs.HornOfWinter = true
 }
 
+func (dk *DeathKnight) AddRosterBuffs(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	debuffs.FrostFever = true
+	debuffs.ScarletFever = dk.Talents.ScarletFever > 0
+	debuffs.BrittleBones = dk.Talents.BrittleBones > 0
+	debuffs.EbonPlaguebringer = dk.Talents.EbonPlaguebringer > 0
+}
+
 func (dk *DeathKnight) ApplyTalents() {
 	dk.ApplyBloodTalents()
 	dk.ApplyFrostTalents()
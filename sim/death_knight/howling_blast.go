@@ -11,7 +11,7 @@ func (dk *DeathKnight) registerHowlingBlastSpell() {
 		return
 	}
 
-	results := make([]*core.SpellResult, dk.Env.GetNumTargets())
+	results := make([]*core.SpellResult, len(dk.Env.Encounter.TargetUnits))
 
 	dk.RegisterSpell(core.SpellConfig{
 		ActionID:       HowlingBlastActionID,
@@ -37,7 +37,7 @@ func (dk *DeathKnight) registerHowlingBlastSpell() {
 		CritMultiplier: dk.DefaultMeleeCritMultiplier(),
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			for idx, aoeTarget := range sim.Encounter.TargetUnits {
+			for idx, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 				baseDamage := dk.ClassSpellScaling*1.17499995232 + 0.44*spell.MeleeAttackPower()
 
 				if aoeTarget != target {
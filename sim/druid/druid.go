@@ -40,6 +40,7 @@ type Druid struct {
 	Berserk               *DruidSpell
 	CatCharge             *DruidSpell
 	DemoralizingRoar      *DruidSpell
+	Efflorescence         *DruidSpell
 	Enrage                *DruidSpell
 	FaerieFire            *DruidSpell
 	FerociousBite         *DruidSpell
@@ -51,15 +52,19 @@ type Druid struct {
 	GiftOfTheWild         *DruidSpell
 	Lacerate              *DruidSpell
 	Languish              *DruidSpell
+	Lifebloom             *DruidSpell
 	MangleBear            *DruidSpell
 	MangleCat             *DruidSpell
 	Maul                  *DruidSpell
 	MaulQueueSpell        *DruidSpell
 	Moonfire              *DruidSpell
+	Nourish               *DruidSpell
 	Pulverize             *DruidSpell
 	Rebirth               *DruidSpell
 	Rake                  *DruidSpell
 	Ravage                *DruidSpell
+	Regrowth              *DruidSpell
+	Rejuvenation          *DruidSpell
 	Rip                   *DruidSpell
 	SavageRoar            *DruidSpell
 	Shred                 *DruidSpell
@@ -68,11 +73,14 @@ type Druid struct {
 	Starsurge             *DruidSpell
 	Sunfire               *DruidSpell
 	SurvivalInstincts     *DruidSpell
+	Swiftmend             *DruidSpell
 	SwipeBear             *DruidSpell
 	SwipeCat              *DruidSpell
 	TigersFury            *DruidSpell
 	Thrash                *DruidSpell
+	TreeOfLife            *DruidSpell
 	Typhoon               *DruidSpell
+	WildGrowth            *DruidSpell
 	Wrath                 *DruidSpell
 	WildMushrooms         *DruidSpell
 	WildMushroomsDetonate *DruidSpell
@@ -107,6 +115,7 @@ type Druid struct {
 	StrengthOfThePantherAura *core.Aura
 	SurvivalInstinctsAura    *core.Aura
 	TigersFuryAura           *core.Aura
+	TreeOfLifeAura           *core.Aura
 
 	BleedCategories core.ExclusiveCategoryArray
 
@@ -182,17 +191,22 @@ const (
 	DruidSpellMarkOfTheWild
 	DruidSpellSwiftmend
 	DruidSpellWildGrowth
+	DruidSpellRegrowthHoT
+	DruidSpellLifebloomBloom
+	DruidSpellGiftOfTheEarthmother
+	DruidSpellEfflorescence
 
 	DruidSpellLast
-	DruidSpellsAll      = DruidSpellLast<<1 - 1
-	DruidSpellDoT       = DruidSpellInsectSwarm | DruidSpellMoonfireDoT | DruidSpellSunfireDoT
-	DruidSpellHoT       = DruidSpellRejuvenation | DruidSpellLifebloom | DruidSpellRegrowth | DruidSpellWildGrowth
-	DruidSpellInstant   = DruidSpellBarkskin | DruidSpellInsectSwarm | DruidSpellMoonfire | DruidSpellStarfall | DruidSpellSunfire | DruidSpellFearieFire | DruidSpellBarkskin
-	DruidSpellMangle    = DruidSpellMangleBear | DruidSpellMangleCat
-	DruidArcaneSpells   = DruidSpellMoonfire | DruidSpellMoonfireDoT | DruidSpellStarfire | DruidSpellStarsurge | DruidSpellStarfall
-	DruidNatureSpells   = DruidSpellInsectSwarm | DruidSpellStarsurge | DruidSpellSunfire | DruidSpellSunfireDoT | DruidSpellTyphoon | DruidSpellHurricane
-	DruidHealingSpells  = DruidSpellHealingTouch | DruidSpellRegrowth | DruidSpellRejuvenation | DruidSpellLifebloom | DruidSpellNourish | DruidSpellSwiftmend
-	DruidDamagingSpells = DruidArcaneSpells | DruidNatureSpells
+	DruidSpellsAll       = DruidSpellLast<<1 - 1
+	DruidSpellDoT        = DruidSpellInsectSwarm | DruidSpellMoonfireDoT | DruidSpellSunfireDoT
+	DruidSpellHoT        = DruidSpellRejuvenation | DruidSpellLifebloom | DruidSpellRegrowthHoT | DruidSpellWildGrowth
+	DruidSpellDirectHeal = DruidSpellHealingTouch | DruidSpellRegrowth | DruidSpellNourish | DruidSpellSwiftmend | DruidSpellLifebloomBloom | DruidSpellGiftOfTheEarthmother
+	DruidSpellInstant    = DruidSpellBarkskin | DruidSpellInsectSwarm | DruidSpellMoonfire | DruidSpellStarfall | DruidSpellSunfire | DruidSpellFearieFire | DruidSpellBarkskin
+	DruidSpellMangle     = DruidSpellMangleBear | DruidSpellMangleCat
+	DruidArcaneSpells    = DruidSpellMoonfire | DruidSpellMoonfireDoT | DruidSpellStarfire | DruidSpellStarsurge | DruidSpellStarfall
+	DruidNatureSpells    = DruidSpellInsectSwarm | DruidSpellStarsurge | DruidSpellSunfire | DruidSpellSunfireDoT | DruidSpellTyphoon | DruidSpellHurricane
+	DruidHealingSpells   = DruidSpellHoT | DruidSpellDirectHeal | DruidSpellEfflorescence
+	DruidDamagingSpells  = DruidArcaneSpells | DruidNatureSpells
 )
 
 type SelfBuffs struct {
@@ -215,6 +229,14 @@ func (druid *Druid) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	raidBuffs.MarkOfTheWild = true
 }
 
+func (druid *Druid) AddRosterBuffs(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	debuffs.FaerieFire = true
+	debuffs.Mangle = druid.InForm(Cat | Bear)
+	debuffs.DemoralizingRoar = druid.InForm(Bear)
+	debuffs.InfectedWounds = druid.Talents.InfectedWounds > 0
+	debuffs.EarthAndMoon = druid.Talents.EarthAndMoon
+}
+
 func (druid *Druid) BalanceCritMultiplier() float64 {
 	return druid.SpellCritMultiplier(1, 0)
 }
@@ -340,6 +362,17 @@ func (druid *Druid) RegisterFeralTankSpells() {
 	druid.registerThrashBearSpell()
 }
 
+func (druid *Druid) RegisterRestorationSpells() {
+	druid.registerEfflorescence()
+	druid.registerLifebloomSpell()
+	druid.registerNourishSpell()
+	druid.registerRegrowthSpell()
+	druid.registerRejuvenationSpell()
+	druid.registerSwiftmendSpell()
+	druid.registerTreeOfLifeCD()
+	druid.registerWildGrowthSpell()
+}
+
 func (druid *Druid) Reset(_ *core.Simulation) {
 	druid.eclipseEnergyBar.reset()
 	druid.BleedsActive = 0
@@ -283,6 +283,9 @@ func (eb *eclipseEnergyBar) addLunarEnergy(amount float64, sim *core.Simulation,
 	if sim.Log != nil {
 		eb.druid.Log(sim, "Gained %0.0f lunar energy from %s (%0.0f --> %0.0f) of %0.0f total.", gain, metrics.ActionID, old, eb.lunarEnergy, 100.0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, &eb.druid.Unit, metrics, gain, eb.lunarEnergy)
+	}
 
 	if eb.lunarEnergy == 100 {
 		eb.SetEclipse(LunarEclipse, sim, spell)
@@ -379,6 +382,9 @@ func (eb *eclipseEnergyBar) addSolarEnergy(amount float64, sim *core.Simulation,
 	if sim.Log != nil {
 		eb.druid.Log(sim, "Gained %0.0f solar energy from %s (%0.0f --> %0.0f) of %0.0f total.", gain, metrics.ActionID, old, eb.solarEnergy, 100.0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, &eb.druid.Unit, metrics, gain, eb.solarEnergy)
+	}
 
 	if eb.solarEnergy == 100 {
 		eb.SetEclipse(SolarEclipse, sim, spell)
@@ -33,6 +33,22 @@ func (druid *Druid) ApplyGlyphs() {
 		})
 	}
 
+	if druid.HasPrimeGlyph(proto.DruidPrimeGlyph_GlyphOfRejuvenation) {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask:  DruidSpellRejuvenation,
+			FloatValue: 0.1,
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
+	if druid.HasPrimeGlyph(proto.DruidPrimeGlyph_GlyphOfLifebloom) {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask:  DruidSpellLifebloom,
+			FloatValue: 10,
+			Kind:       core.SpellMod_BonusCrit_Percent,
+		})
+	}
+
 	if druid.HasMajorGlyph(proto.DruidMajorGlyph_GlyphOfStarfall) {
 		druid.AddStaticMod(core.SpellModConfig{
 			ClassMask: DruidSpellStarfall,
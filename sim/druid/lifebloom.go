@@ -0,0 +1,119 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (druid *Druid) registerLifebloomSpell() {
+	bloomSpell := druid.Unit.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33778},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete,
+		ClassSpellMask: DruidSpellLifebloomBloom,
+
+		DamageMultiplier: 1 + 0.05*float64(druid.Talents.GiftOfTheEarthmother),
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+	})
+
+	malfurionsGiftChance := 0.02 * float64(druid.Talents.MalfurionsGift)
+
+	// Lifebloom can only be active on a single target outside of Tree of Life.
+	var lifebloomTargets []*core.Unit
+	removingFromTarget := false
+
+	druid.Lifebloom = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33763},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL | SpellFlagOmenTrigger,
+		ClassSpellMask: DruidSpellLifebloom,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.07,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label:     "Lifebloom",
+				MaxStacks: 3,
+				OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+					// Stacks are only cleared when the aura fades. Lifebloom only blooms when it
+					// runs out, not when it's moved to another target.
+					if newStacks != 0 || aura.IsActive() || removingFromTarget || sim.CurrentTime >= sim.Duration {
+						return
+					}
+					// The bloom scales with the number of stacks, including its spell power bonus.
+					baseHealing := (druid.ClassSpellScaling*1.938 + 0.284*bloomSpell.HealingPower(aura.Unit)) * float64(oldStacks)
+					bloomSpell.CalcAndDealHealing(sim, aura.Unit, baseHealing, bloomSpell.OutcomeHealingCrit)
+				},
+			},
+			NumberOfTicks:       10,
+			TickLength:          time.Second,
+			AffectedByCastSpeed: true,
+			BonusCoefficient:    0.0234,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				dot.SnapshotHeal(target, druid.ClassSpellScaling*0.207)
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+
+				if malfurionsGiftChance > 0 && sim.Proc(malfurionsGiftChance, "Malfurion's Gift") {
+					druid.ProcOoc(sim)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if !druid.TreeOfLifeAura.IsActive() {
+				removingFromTarget = true
+				for _, other := range lifebloomTargets {
+					if other != target {
+						spell.Hot(other).Deactivate(sim)
+					}
+				}
+				removingFromTarget = false
+			}
+			lifebloomTargets = core.FilterSlice(lifebloomTargets, func(unit *core.Unit) bool {
+				return spell.Hot(unit).IsActive() && unit != target
+			})
+			lifebloomTargets = append(lifebloomTargets, target)
+
+			hot := spell.Hot(target)
+			stacks := core.TernaryInt32(hot.IsActive(), hot.GetStacks()+1, 1)
+			hot.Apply(sim)
+			hot.SetStacks(sim, stacks)
+			hot.SnapshotBaseDamage *= float64(hot.GetStacks())
+		},
+	})
+}
+
+// Refreshes the duration of Lifebloom without adding a stack, e.g. from Empowered Touch.
+func (druid *Druid) RefreshLifebloom(sim *core.Simulation, target *core.Unit) {
+	if druid.Lifebloom == nil {
+		return
+	}
+
+	hot := druid.Lifebloom.Hot(target)
+	if !hot.IsActive() {
+		return
+	}
+
+	stacks := hot.GetStacks()
+	hot.Apply(sim)
+	hot.SnapshotBaseDamage *= float64(stacks)
+}
@@ -10,7 +10,6 @@ import (
 func (druid *Druid) registerMangleBearSpell() {
 	mangleAuras := druid.NewEnemyAuraArray(core.MangleAura)
 	glyphBonus := core.TernaryFloat64(druid.HasPrimeGlyph(proto.DruidPrimeGlyph_GlyphOfMangle), 1.1, 1.0)
-	maxHits := min(druid.Env.GetNumTargets(), 3)
 
 	druid.MangleBear = druid.RegisterSpell(Bear, core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 33878},
@@ -41,7 +40,7 @@ func (druid *Druid) registerMangleBearSpell() {
 		MaxRange:         core.MaxMeleeRange,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
-			numHits := core.TernaryInt32(druid.BerserkAura.IsActive(), maxHits, 1)
+			numHits := core.TernaryInt32(druid.BerserkAura.IsActive(), min(sim.GetNumTargets(), 3), 1)
 			curTarget := target
 
 			for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
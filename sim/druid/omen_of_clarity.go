@@ -36,6 +36,14 @@ func (druid *Druid) applyOmenOfClarity() {
 				druid.SwipeBear,
 				druid.SwipeCat,
 				druid.Thrash,
+
+				// Restoration
+				druid.Lifebloom,
+				druid.Nourish,
+				druid.Regrowth,
+				druid.Rejuvenation,
+				druid.Swiftmend,
+				druid.WildGrowth,
 			}, func(spell *DruidSpell) bool { return spell != nil })
 		},
 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
@@ -118,5 +126,20 @@ func (druid *Druid) applyOmenOfClarity() {
 				}
 			}
 		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			// Heals don't trigger OnSpellHitDealt, so they roll for a proc when the cast
+			// completes instead, at the same rate as damaging spells.
+			if !spell.ProcMask.Matches(core.ProcMaskSpellHealing) || !spell.Flags.Matches(SpellFlagOmenTrigger) {
+				return
+			}
+
+			castTime := spell.CurCast.CastTime.Seconds()
+			if castTime == 0 {
+				castTime = 1.5
+			}
+			if sim.Proc((castTime/60)*3.5*0.666, "Clearcasting") {
+				druid.ProcOoc(sim)
+			}
+		},
 	})
 }
@@ -0,0 +1,128 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (druid *Druid) registerRegrowthSpell() {
+	regrowthHot := druid.Unit.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 8936}.WithTag(1),
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagPassiveSpell,
+		ClassSpellMask: DruidSpellRegrowthHoT,
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Regrowth",
+			},
+			NumberOfTicks:    3,
+			TickLength:       time.Second * 2,
+			BonusCoefficient: 0.0296,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				dot.SnapshotHeal(target, druid.ClassSpellScaling*0.316)
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.Hot(target).Apply(sim)
+		},
+	})
+
+	druid.Regrowth = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 8936},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL | SpellFlagOmenTrigger,
+		ClassSpellMask: DruidSpellRegrowth,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.35,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+
+		BonusCritPercent: 20 * float64(druid.Talents.NaturesBounty),
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.2936,
+
+		RelatedDotSpell: regrowthHot,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := druid.CalcAndRollDamageRange(sim, 3.081, 0.112)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			spell.RelatedDotSpell.Cast(sim, target)
+			druid.procEmpoweredTouch(sim, target)
+		},
+	})
+}
+
+func (druid *Druid) registerNourishSpell() {
+	druid.Nourish = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 50464},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL | SpellFlagOmenTrigger,
+		ClassSpellMask: DruidSpellNourish,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.1,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Second * 3,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.266,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := druid.CalcAndRollDamageRange(sim, 2.316, 0.15)
+			// Nourish heals for 20% more on targets with one of the druid's HoTs.
+			if druid.hasHotActive(target) {
+				baseHealing *= 1.2
+			}
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			druid.procEmpoweredTouch(sim, target)
+		},
+	})
+}
+
+func (druid *Druid) hasHotActive(target *core.Unit) bool {
+	return druid.Rejuvenation.Hot(target).IsActive() ||
+		druid.Regrowth.RelatedDotSpell.Hot(target).IsActive() ||
+		druid.Lifebloom.Hot(target).IsActive() ||
+		(druid.WildGrowth != nil && druid.WildGrowth.Hot(target).IsActive())
+}
+
+// Empowered Touch lets Regrowth and Nourish refresh Lifebloom on their target.
+func (druid *Druid) procEmpoweredTouch(sim *core.Simulation, target *core.Unit) {
+	if druid.Talents.EmpoweredTouch == 0 {
+		return
+	}
+	if sim.Proc(0.5*float64(druid.Talents.EmpoweredTouch), "Empowered Touch") {
+		druid.RefreshLifebloom(sim, target)
+	}
+}
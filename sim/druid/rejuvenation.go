@@ -0,0 +1,75 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (druid *Druid) registerRejuvenationSpell() {
+	var giftOfTheEarthmother *core.Spell
+	if druid.Talents.GiftOfTheEarthmother > 0 {
+		giftOfTheEarthmother = druid.Unit.RegisterSpell(core.SpellConfig{
+			ActionID:       core.ActionID{SpellID: 64801},
+			SpellSchool:    core.SpellSchoolNature,
+			ProcMask:       core.ProcMaskSpellHealing,
+			Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete,
+			ClassSpellMask: DruidSpellGiftOfTheEarthmother,
+
+			DamageMultiplier: 1,
+			CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+			ThreatMultiplier: 1,
+		})
+	}
+
+	druid.Rejuvenation = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 774},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL | SpellFlagOmenTrigger,
+		ClassSpellMask: DruidSpellRejuvenation,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.2,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Rejuvenation",
+			},
+			NumberOfTicks:    4,
+			TickLength:       time.Second * 3,
+			BonusCoefficient: 0.134,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				if !isRollover {
+					dot.SnapshotHeal(target, druid.ClassSpellScaling*1.307)
+				}
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			hot := spell.Hot(target)
+			hot.Apply(sim)
+
+			if giftOfTheEarthmother != nil {
+				// Gift of the Earthmother instantly heals for a portion of Rejuvenation's total healing.
+				baseHealing := hot.SnapshotBaseDamage * float64(hot.BaseTickCount) * 0.05 * float64(druid.Talents.GiftOfTheEarthmother)
+				giftOfTheEarthmother.CalcAndDealHealing(sim, target, baseHealing, giftOfTheEarthmother.OutcomeHealingCrit)
+			}
+		},
+	})
+}
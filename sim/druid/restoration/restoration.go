@@ -1,6 +1,8 @@
 package restoration
 
 import (
+	"time"
+
 	"github.com/wowsims/cata/sim/core"
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/druid"
@@ -36,6 +38,9 @@ func NewRestorationDruid(character *core.Character, options *proto.Player) *Rest
 		resto.SelfBuffs.InnervateTarget = restoOptions.Options.ClassOptions.InnervateTarget
 	}
 
+	// Meditation
+	resto.PseudoStats.SpiritRegenRateCombat = 0.5
+
 	return resto
 }
 
@@ -48,7 +53,70 @@ func (resto *RestorationDruid) GetDruid() *druid.Druid {
 }
 
 func (resto *RestorationDruid) Initialize() {
+	resto.CurrentTarget = resto.GetMainTarget()
 	resto.Druid.Initialize()
+	resto.RegisterRestorationSpells()
+}
+
+func getMasteryBonus(masteryPoints float64) float64 {
+	return (10 + masteryPoints*1.25) / 100
+}
+
+func (resto *RestorationDruid) ApplyTalents() {
+	resto.Druid.ApplyTalents()
+
+	// Gift of Nature
+	resto.AddStaticMod(core.SpellModConfig{
+		ProcMask:   core.ProcMaskSpellHealing,
+		FloatValue: 0.25,
+		Kind:       core.SpellMod_DamageDone_Pct,
+	})
+
+	resto.applyHarmony()
+}
+
+// Mastery: Harmony, direct heals are stronger and empower periodic healing for 10 sec.
+func (resto *RestorationDruid) applyHarmony() {
+	directMod := resto.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  druid.DruidSpellDirectHeal,
+		FloatValue: getMasteryBonus(resto.GetMasteryPoints()),
+		Kind:       core.SpellMod_DamageDone_Pct,
+	})
+	directMod.Activate()
+
+	periodicMod := resto.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  druid.DruidSpellHoT,
+		FloatValue: getMasteryBonus(resto.GetMasteryPoints()),
+		Kind:       core.SpellMod_DamageDone_Pct,
+	})
+
+	resto.AddOnMasteryStatChanged(func(sim *core.Simulation, oldMastery, newMastery float64) {
+		masteryBonus := getMasteryBonus(core.MasteryRatingToMasteryPoints(newMastery))
+		directMod.UpdateFloatValue(masteryBonus)
+		periodicMod.UpdateFloatValue(masteryBonus)
+	})
+
+	harmonyAura := resto.RegisterAura(core.Aura{
+		Label:    "Harmony",
+		ActionID: core.ActionID{SpellID: 100977},
+		Duration: time.Second * 10,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			periodicMod.Activate()
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			periodicMod.Deactivate()
+		},
+	})
+
+	core.MakeProcTriggerAura(&resto.Unit, core.ProcTrigger{
+		Name:           "Harmony Trigger",
+		ActionID:       core.ActionID{SpellID: 77495},
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: druid.DruidSpellHealingTouch | druid.DruidSpellRegrowth | druid.DruidSpellNourish | druid.DruidSpellSwiftmend,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			harmonyAura.Activate(sim)
+		},
+	})
 }
 
 func (resto *RestorationDruid) Reset(sim *core.Simulation) {
@@ -1,67 +1,82 @@
 package restoration
 
 import (
-	_ "github.com/wowsims/cata/sim/common" // imported to get caster sets included. (we use spellfire here)
+	"testing"
+
+	_ "github.com/wowsims/cata/sim/common" // imported to get caster sets included.
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
 )
 
 func init() {
 	RegisterRestorationDruid()
 }
 
-// func TestRestoration(t *testing.T) {
-// 	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
-// 		Class: proto.Class_ClassDruid,
-// 		Race:  proto.Race_RaceTauren,
+func TestRestoration(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
+		Class:      proto.Class_ClassDruid,
+		Race:       proto.Race_RaceTauren,
+		OtherRaces: []proto.Race{proto.Race_RaceNightElf},
+		IsHealer:   true,
+
+		GearSet:  core.GetGearSet("../../../ui/druid/restoration/gear_sets", "p1"),
+		Talents:  StandardTalents,
+		Glyphs:   StandardGlyphs,
+		Consumes: FullConsumes,
 
-// 		GearSet:     core.GetGearSet("../../../ui/restoration_druid/gear_sets", "p1"),
-// 		Talents:     StandardTalents,
-// 		Glyphs:      StandardGlyphs,
-// 		Consumes:    FullConsumes,
-// 		SpecOptions: core.SpecOptionsCombo{Label: "Standard", SpecOptions: PlayerOptionsStandard},
-// 		Rotation:    core.RotationCombo{Label: "Default", Rotation: DefaultRotation},
+		SpecOptions: core.SpecOptionsCombo{Label: "Standard", SpecOptions: PlayerOptionsStandard},
 
-// 		ItemFilter: core.ItemFilter{
-// 			WeaponTypes: []proto.WeaponType{
-// 				proto.WeaponType_WeaponTypeDagger,
-// 				proto.WeaponType_WeaponTypeMace,
-// 				proto.WeaponType_WeaponTypeOffHand,
-// 				proto.WeaponType_WeaponTypeStaff,
-// 				proto.WeaponType_WeaponTypePolearm,
-// 			},
-// 			ArmorType: proto.ArmorType_ArmorTypeLeather,
-// 			RangedWeaponTypes: []proto.RangedWeaponType{
-// 				proto.RangedWeaponType_RangedWeaponTypeRelic,
-// 			},
-// 		},
-// 	}))
-// }
+		Rotation: core.GetAplRotation("../../../ui/druid/restoration/apls", "default"),
 
-// var StandardTalents = "05320031103--230023312131502331050313051"
-// var StandardGlyphs = &proto.Glyphs{
-// 	Major1: int32(proto.DruidMajorGlyph_GlyphOfWildGrowth),
-// 	Major2: int32(proto.DruidMajorGlyph_GlyphOfSwiftmend),
-// 	Major3: int32(proto.DruidMajorGlyph_GlyphOfNourish),
-// }
+		ItemFilter: core.ItemFilter{
+			WeaponTypes: []proto.WeaponType{
+				proto.WeaponType_WeaponTypeDagger,
+				proto.WeaponType_WeaponTypeMace,
+				proto.WeaponType_WeaponTypeOffHand,
+				proto.WeaponType_WeaponTypeStaff,
+				proto.WeaponType_WeaponTypePolearm,
+			},
+			ArmorType: proto.ArmorType_ArmorTypeLeather,
+			RangedWeaponTypes: []proto.RangedWeaponType{
+				proto.RangedWeaponType_RangedWeaponTypeRelic,
+			},
+		},
 
-// var FullConsumes = &proto.Consumes{
-// 	Flask:           proto.Flask_FlaskOfBlindingLight,
-// 	Food:            proto.Food_FoodBlackenedBasilisk,
-// 	DefaultPotion:   proto.Potions_SuperManaPotion,
-// 	PrepopPotion:    proto.Potions_DestructionPotion,
-// 	DefaultConjured: proto.Conjured_ConjuredDarkRune,
-// }
+		EPReferenceStat: proto.Stat_StatSpellPower,
+		StatsToWeigh: []proto.Stat{
+			proto.Stat_StatIntellect,
+			proto.Stat_StatSpirit,
+			proto.Stat_StatSpellPower,
+			proto.Stat_StatCritRating,
+			proto.Stat_StatHasteRating,
+			proto.Stat_StatMasteryRating,
+		},
+	}))
+}
 
-// var PlayerOptionsStandard = &proto.Player_RestorationDruid{
-// 	RestorationDruid: &proto.RestorationDruid{
-// 		Options: &proto.RestorationDruid_Options{
-// 			InnervateTarget: &proto.UnitReference{Type: proto.UnitReference_Player, Index: 0}, // self innervate
-// 		},
-// 	},
-// }
+var StandardTalents = "3023--202301332103223100311"
+var StandardGlyphs = &proto.Glyphs{
+	Prime1: int32(proto.DruidPrimeGlyph_GlyphOfRejuvenation),
+	Prime2: int32(proto.DruidPrimeGlyph_GlyphOfLifebloom),
+	Prime3: int32(proto.DruidPrimeGlyph_GlyphOfSwiftmend),
+	Major1: int32(proto.DruidMajorGlyph_GlyphOfWildGrowth),
+	Major2: int32(proto.DruidMajorGlyph_GlyphOfInnervate),
+	Major3: int32(proto.DruidMajorGlyph_GlyphOfRebirth),
+}
 
-// var DefaultRotation = core.APLRotationFromJsonString(`{
-// 	"type": "TypeAPL",
-// 	"priorityList": [
-// 		{"action":{"autocastOtherCooldowns":{}}}
-// 	]
-// }`)
+var FullConsumes = &proto.Consumes{
+	Flask:         proto.Flask_FlaskOfTheDraconicMind,
+	Food:          proto.Food_FoodSeafoodFeast,
+	DefaultPotion: proto.Potions_MythicalManaPotion,
+	PrepopPotion:  proto.Potions_VolcanicPotion,
+}
+
+var PlayerOptionsStandard = &proto.Player_RestorationDruid{
+	RestorationDruid: &proto.RestorationDruid{
+		Options: &proto.RestorationDruid_Options{
+			ClassOptions: &proto.DruidOptions{
+				InnervateTarget: &proto.UnitReference{Type: proto.UnitReference_Player, Index: 0}, // self innervate
+			},
+		},
+	},
+}
@@ -12,7 +12,6 @@ func (druid *Druid) registerStarfallSpell() {
 		return
 	}
 
-	numberOfTicks := core.TernaryInt32(druid.Env.GetNumTargets() > 1, 20, 10)
 	tickLength := time.Second
 
 	starfallTickSpell := druid.RegisterSpell(Humanoid|Moonkin, core.SpellConfig{
@@ -56,7 +55,7 @@ func (druid *Druid) registerStarfallSpell() {
 			Aura: core.Aura{
 				Label: "Starfall",
 			},
-			NumberOfTicks: numberOfTicks,
+			NumberOfTicks: 10,
 			TickLength:    tickLength,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
 				starfallTickSpell.Cast(sim, target)
@@ -66,7 +65,9 @@ func (druid *Druid) registerStarfallSpell() {
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			result := spell.CalcAndDealOutcome(sim, target, spell.OutcomeMagicHit)
 			if result.Landed() {
-				spell.Dot(target).Apply(sim)
+				dot := spell.Dot(target)
+				dot.BaseTickCount = core.TernaryInt32(sim.GetNumTargets() > 1, 20, 10)
+				dot.Apply(sim)
 			}
 		},
 	})
@@ -0,0 +1,113 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (druid *Druid) registerSwiftmendSpell() {
+	consumesHot := !druid.HasPrimeGlyph(proto.DruidPrimeGlyph_GlyphOfSwiftmend)
+
+	getConsumableHot := func(target *core.Unit) *core.Dot {
+		if rejuv := druid.Rejuvenation.Hot(target); rejuv.IsActive() {
+			return rejuv
+		}
+		if regrowth := druid.Regrowth.RelatedDotSpell.Hot(target); regrowth.IsActive() {
+			return regrowth
+		}
+		return nil
+	}
+
+	druid.Swiftmend = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 18562},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL | SpellFlagOmenTrigger,
+		ClassSpellMask: DruidSpellSwiftmend,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.1,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    druid.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.537,
+
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return getConsumableHot(target) != nil
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := druid.ClassSpellScaling * 4.647
+			result := spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+
+			if consumesHot {
+				getConsumableHot(target).Deactivate(sim)
+			}
+
+			if druid.Efflorescence != nil {
+				druid.Efflorescence.SelfHot().SnapshotBaseDamage = result.Damage * 0.04 * float64(druid.Talents.Efflorescence)
+				druid.Efflorescence.Cast(sim, target)
+			}
+		},
+	})
+}
+
+// Swiftmend leaves a patch of Efflorescence under its target, which heals the
+// most injured allies standing in it every second.
+func (druid *Druid) registerEfflorescence() {
+	if druid.Talents.Efflorescence == 0 {
+		return
+	}
+
+	const numTargets = 3
+	var efflorescenceTarget *core.Unit
+
+	druid.Efflorescence = druid.RegisterSpell(Any, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 81262},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagIgnoreModifiers,
+		ClassSpellMask: DruidSpellEfflorescence,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			SelfOnly: true,
+			Aura: core.Aura{
+				Label: "Efflorescence",
+			},
+			NumberOfTicks: 7,
+			TickLength:    time.Second,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, healTarget := range druid.GetSmartHealTargets(efflorescenceTarget, numTargets) {
+					dot.Spell.CalcAndDealPeriodicHealing(sim, healTarget, dot.SnapshotBaseDamage, dot.Spell.OutcomeHealing)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			efflorescenceTarget = target
+			// Apply would take a new snapshot, so keep the amount set by Swiftmend.
+			hot := spell.SelfHot()
+			baseHealing := hot.SnapshotBaseDamage
+			hot.Apply(sim)
+			hot.SnapshotBaseDamage = baseHealing
+		},
+	})
+}
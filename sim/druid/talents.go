@@ -56,6 +56,9 @@ func (druid *Druid) ApplyTalents() {
 	druid.applyEarthAndMoon()
 	druid.applyMoonkinForm()
 	druid.applyLunarShower()
+
+	// Restoration
+	druid.applyRestorationTalents()
 	// if druid.Talents.PrimalPrecision > 0 {
 	// 	druid.AddStat(stats.Expertise, 5.0*float64(druid.Talents.PrimalPrecision)*core.ExpertisePerQuarterPercentReduction)
 	// }
@@ -185,6 +188,48 @@ func (druid *Druid) applyGenesis() {
 	}
 }
 
+func (druid *Druid) applyRestorationTalents() {
+	if druid.Talents.BlessingOfTheGrove > 0 {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask:  DruidSpellRejuvenation,
+			FloatValue: 0.02 * float64(druid.Talents.BlessingOfTheGrove),
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
+	if druid.Talents.Naturalist > 0 {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask: DruidSpellHealingTouch | DruidSpellNourish,
+			TimeValue: time.Millisecond * time.Duration(-250*druid.Talents.Naturalist),
+			Kind:      core.SpellMod_CastTime_Flat,
+		})
+	}
+
+	if druid.Talents.ImprovedRejuvenation > 0 {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask:  DruidSpellRejuvenation | DruidSpellSwiftmend,
+			FloatValue: 0.05 * float64(druid.Talents.ImprovedRejuvenation),
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
+	if druid.Talents.EmpoweredTouch > 0 {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask:  DruidSpellHealingTouch | DruidSpellRegrowth | DruidSpellNourish,
+			FloatValue: 0.05 * float64(druid.Talents.EmpoweredTouch),
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
+	if druid.Talents.SwiftRejuvenation {
+		druid.AddStaticMod(core.SpellModConfig{
+			ClassMask: DruidSpellRejuvenation,
+			TimeValue: time.Millisecond * -500,
+			Kind:      core.SpellMod_GlobalCooldown_Flat,
+		})
+	}
+}
+
 func (druid *Druid) applyEuphoria() {
 	if druid.Talents.Euphoria == 0 {
 		return
@@ -0,0 +1,64 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (druid *Druid) registerTreeOfLifeCD() {
+	if !druid.Talents.TreeOfLife {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 33891}
+	healingBonus := 1.15 * core.TernaryFloat64(druid.Talents.MasterShapeshifter, 1.04, 1)
+
+	regrowthMod := druid.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  DruidSpellRegrowth,
+		FloatValue: -1,
+		Kind:       core.SpellMod_CastTime_Pct,
+	})
+
+	druid.TreeOfLifeAura = druid.RegisterAura(core.Aura{
+		Label:    "Tree of Life",
+		ActionID: actionID,
+		Duration: time.Second*25 + time.Second*3*time.Duration(druid.Talents.NaturalShapeshifter),
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			druid.PseudoStats.HealingDealtMultiplier *= healingBonus
+			regrowthMod.Activate()
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			druid.PseudoStats.HealingDealtMultiplier /= healingBonus
+			regrowthMod.Deactivate()
+		},
+	})
+
+	druid.TreeOfLife = druid.RegisterSpell(Any, core.SpellConfig{
+		ActionID: actionID,
+		Flags:    core.SpellFlagAPL,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.06,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    druid.NewTimer(),
+				Duration: time.Minute * 3,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			druid.TreeOfLifeAura.Activate(sim)
+		},
+	})
+
+	druid.AddMajorCooldown(core.MajorCooldown{
+		Spell: druid.TreeOfLife.Spell,
+		Type:  core.CooldownTypeDPS,
+	})
+}
@@ -0,0 +1,67 @@
+package druid
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (druid *Druid) registerWildGrowthSpell() {
+	if !druid.Talents.WildGrowth {
+		return
+	}
+
+	hasGlyph := druid.HasMajorGlyph(proto.DruidMajorGlyph_GlyphOfWildGrowth)
+	numTargets := core.TernaryInt(hasGlyph, 6, 5)
+
+	druid.WildGrowth = druid.RegisterSpell(Humanoid|Tree, core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 48438},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL | SpellFlagOmenTrigger,
+		ClassSpellMask: DruidSpellWildGrowth,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.27,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    druid.NewTimer(),
+				Duration: core.TernaryDuration(hasGlyph, time.Second*10, time.Second*8),
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   druid.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Wild Growth",
+			},
+			NumberOfTicks:    7,
+			TickLength:       time.Second,
+			BonusCoefficient: 0.0920,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				dot.SnapshotHeal(target, druid.ClassSpellScaling*0.397)
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			// Tree of Life lets Wild Growth hit 2 additional targets.
+			count := numTargets + core.TernaryInt(druid.TreeOfLifeAura.IsActive(), 2, 0)
+			for _, healTarget := range druid.GetSmartHealTargets(target, count) {
+				spell.Hot(healTarget).Apply(sim)
+			}
+		},
+	})
+}
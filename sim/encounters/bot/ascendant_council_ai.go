@@ -0,0 +1,362 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addAscendantCouncil(raidPrefix string) {
+	createAscendantCouncilPreset(raidPrefix, 25, true, 43735, 79_500_000, 120_000)
+}
+
+func createAscendantCouncilPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64, bossMinBaseDamage float64) {
+	targetName := fmt.Sprintf("Ascendant Council %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		// The council is modeled as a single target representing whichever
+		// member the raid is currently attacking. Its health pool covers the
+		// damage needed across all three phases.
+		Config: &proto.Target{
+			Id:        bossNpcId,
+			Name:      targetName,
+			Level:     88,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0, // actual value doesn't matter in Cata, as long as damage parameters are fit consistently
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  ascendantCouncilTargetInputs(),
+		},
+
+		AI: makeAscendantCouncilAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func ascendantCouncilTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Phase 2 start (% remaining)",
+			Tooltip:     "Encounter progress, as a percentage of total health or duration remaining, at which Arion and Terrastra take over from Feludius and Ignacious.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 70,
+		},
+		{
+			Label:       "Phase 3 start (% remaining)",
+			Tooltip:     "Encounter progress at which the Elementium Monstrosity forms.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 40,
+		},
+	}
+}
+
+func makeAscendantCouncilAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &AscendantCouncilAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type AscendantCouncilAI struct {
+	Target *core.Target
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	phase2Start float64
+	phase3Start float64
+
+	// Phase tracking
+	phase             int32
+	phase3StartedAt   time.Duration
+	nextOverloadIsSky bool
+
+	// Spell + aura references
+	glaciate            *core.Spell
+	flameTorrent        *core.Spell
+	lightningBlast      *core.Spell
+	overload            *core.Spell
+	lavaSeed            *core.Spell
+	electricInstability *core.Spell
+}
+
+func (ai *AscendantCouncilAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.phase2Start = config.TargetInputs[0].NumberValue / 100
+	ai.phase3Start = config.TargetInputs[1].NumberValue / 100
+
+	ai.registerPhase1Spells()
+	ai.registerPhase2Spells()
+	ai.registerPhase3Spells()
+}
+
+func (ai *AscendantCouncilAI) Reset(sim *core.Simulation) {
+	ai.phase = 1
+	ai.phase3StartedAt = 0
+	ai.nextOverloadIsSky = true
+
+	randomAutoOffset := core.DurationFromSeconds(sim.RandomFloat("Melee Timing") * ai.Target.AutoAttacks.MainhandSwingSpeed().Seconds())
+	ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime-randomAutoOffset, false)
+
+	ai.glaciate.CD.Set(time.Second * 30)
+	ai.flameTorrent.CD.Set(core.DurationFromSeconds(sim.RandomFloat("Flame Torrent Timing") * ai.flameTorrent.CD.Duration.Seconds()))
+}
+
+func (ai *AscendantCouncilAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+func (ai *AscendantCouncilAI) registerPhase1Spells() {
+	// Glaciate falls off with distance from Feludius, so the raid-wide
+	// average is much lower than the point blank damage.
+	glaciateBase := []float64{14000, 17500, 19500, 24500}[ai.scalingIndex()]
+	glaciateVariance := glaciateBase * 0.2
+
+	ai.glaciate = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 82746},
+		SpellSchool:      core.SpellSchoolFrost,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 33,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// Players run out of the point blank area before the cast lands.
+			sim.Raid.MoveAllPlayers(sim, time.Second*2)
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := glaciateBase + glaciateVariance*sim.RandomFloat("Glaciate Damage")
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	flameTorrentBase := []float64{32000, 38000, 42000, 50000}[ai.scalingIndex()]
+
+	ai.flameTorrent = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 82777},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 22,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			damageRoll := flameTorrentBase * (0.95 + 0.1*sim.RandomFloat("Flame Torrent Damage"))
+			spell.CalcAndDealDamage(sim, target, damageRoll, spell.OutcomeAlwaysHit)
+		},
+	})
+}
+
+func (ai *AscendantCouncilAI) registerPhase2Spells() {
+	lightningBlastBase := []float64{45000, 55000, 60000, 72000}[ai.scalingIndex()]
+
+	ai.lightningBlast = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 83070},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 20,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			damageRoll := lightningBlastBase * (0.95 + 0.1*sim.RandomFloat("Lightning Blast Damage"))
+			spell.CalcAndDealDamage(sim, target, damageRoll, spell.OutcomeAlwaysHit)
+		},
+	})
+
+	// Quake and Thundershock alternate. The raid picks up Swirling Winds to
+	// float over Quake entirely, and Grounded to take reduced Thundershock
+	// damage, at the cost of moving to the debuff sources before each one.
+	thundershockBase := []float64{10500, 13500, 14500, 18500}[ai.scalingIndex()]
+	quakeActionID := core.ActionID{SpellID: 83565}
+	thundershockActionID := core.ActionID{SpellID: 83067}
+
+	thundershock := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         thundershockActionID,
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := thundershockBase * (0.9 + 0.2*sim.RandomFloat("Thundershock Damage"))
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	quake := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: quakeActionID,
+		ProcMask: core.ProcMaskEmpty,
+	})
+
+	ai.overload = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 84915},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 33,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, time.Second*3)
+
+			if ai.nextOverloadIsSky {
+				thundershock.Cast(sim, target)
+			} else {
+				quake.Cast(sim, target)
+			}
+			ai.nextOverloadIsSky = !ai.nextOverloadIsSky
+		},
+	})
+}
+
+func (ai *AscendantCouncilAI) registerPhase3Spells() {
+	lavaSeedBase := []float64{16000, 19000, 21000, 26000}[ai.scalingIndex()]
+
+	ai.lavaSeed = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 84913},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 23,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, time.Second*2)
+
+			// Most of the raid dodges the seeds. Stragglers take the hit.
+			numHit := core.TernaryInt(ai.raidSize == 10, 1, 3)
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Lava Seed Target", numHit, int(ai.raidSize)) {
+				damageRoll := lavaSeedBase * (0.9 + 0.2*sim.RandomFloat("Lava Seed Damage"))
+				spell.CalcAndDealDamage(sim, hitTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Electric Instability zaps random players every second, ramping up the
+	// longer the Monstrosity is alive.
+	instabilityBase := []float64{3000, 3000, 4000, 4000}[ai.scalingIndex()]
+	numInstabilityTargets := core.TernaryInt(ai.raidSize == 10, 1, 3)
+
+	ai.electricInstability = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 84529},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			rampMultiplier := 1.0 + 0.1*(sim.CurrentTime-ai.phase3StartedAt).Seconds()/20
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Electric Instability Target", numInstabilityTargets, int(ai.raidSize)) {
+				spell.CalcAndDealDamage(sim, hitTarget, instabilityBase*rampMultiplier, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *AscendantCouncilAI) updatePhase(sim *core.Simulation, target *core.Unit) {
+	remaining := sim.GetRemainingDurationPercent()
+
+	if (ai.phase == 1) && (remaining <= ai.phase2Start) {
+		ai.phase = 2
+		ai.overload.CD.Set(sim.CurrentTime + time.Second*15)
+		ai.lightningBlast.CD.Set(sim.CurrentTime + time.Second*10)
+	}
+
+	if (ai.phase == 2) && (remaining <= ai.phase3Start) {
+		ai.phase = 3
+		ai.phase3StartedAt = sim.CurrentTime
+		ai.lavaSeed.CD.Set(sim.CurrentTime + time.Second*15)
+
+		core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+			Period:   time.Second,
+			Priority: core.ActionPriorityDOT,
+
+			OnAction: func(sim *core.Simulation) {
+				ai.electricInstability.Cast(sim, target)
+			},
+		})
+	}
+}
+
+func (ai *AscendantCouncilAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	tankTarget := target
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	ai.updatePhase(sim, target)
+
+	switch ai.phase {
+	case 1:
+		if ai.glaciate.IsReady(sim) {
+			ai.glaciate.Cast(sim, target)
+		} else if (tankTarget != nil) && ai.flameTorrent.IsReady(sim) {
+			ai.flameTorrent.Cast(sim, tankTarget)
+		}
+	case 2:
+		if ai.overload.IsReady(sim) {
+			ai.overload.Cast(sim, target)
+		} else if (tankTarget != nil) && ai.lightningBlast.IsReady(sim) {
+			ai.lightningBlast.Cast(sim, tankTarget)
+		}
+	case 3:
+		if ai.lavaSeed.IsReady(sim) {
+			ai.lavaSeed.Cast(sim, target)
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,6 @@
+package bot
+
+func Register() {
+	addHalfus("The Bastion of Twilight")
+	addAscendantCouncil("The Bastion of Twilight")
+}
@@ -0,0 +1,290 @@
+package bot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addHalfus(raidPrefix string) {
+	createHalfusPreset(raidPrefix, 25, true, 46209, 61_843_000, 140_000)
+}
+
+func createHalfusPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64, bossMinBaseDamage float64) {
+	targetName := fmt.Sprintf("Halfus Wyrmbreaker %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        bossNpcId,
+			Name:      targetName,
+			Level:     88,
+			MobType:   proto.MobType_MobTypeHumanoid,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0, // actual value doesn't matter in Cata, as long as damage parameters are fit consistently
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  halfusTargetInputs(),
+		},
+
+		AI: makeHalfusAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func halfusTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:     "Storm Rider active",
+			Tooltip:   "If checked, Halfus will cast Shadow Nova on the raid. Assumes the raid interrupts roughly half of the casts.",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+		{
+			Label:     "Slate Dragon active",
+			Tooltip:   "If checked, Halfus' melee attacks apply Malevolent Strikes, reducing healing taken by the tank.",
+			InputType: proto.InputType_Bool,
+			BoolValue: true,
+		},
+		{
+			Label:     "Nether Scion active",
+			Tooltip:   "If checked, Halfus gains Frenzied Assault, doubling his attack speed.",
+			InputType: proto.InputType_Bool,
+			BoolValue: false,
+		},
+	}
+}
+
+func makeHalfusAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &HalfusAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type HalfusAI struct {
+	Target *core.Target
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	stormRider  bool
+	slateDragon bool
+	netherScion bool
+
+	// Spell + aura references
+	furiousRoar *core.Spell
+	shadowNova  *core.Spell
+}
+
+func (ai *HalfusAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.stormRider = config.TargetInputs[0].BoolValue
+	ai.slateDragon = config.TargetInputs[1].BoolValue
+	ai.netherScion = config.TargetInputs[2].BoolValue
+
+	ai.registerFuriousRoar()
+	ai.registerShadowNova()
+	ai.registerMalevolentStrikes()
+	ai.registerFrenziedAssault()
+}
+
+func (ai *HalfusAI) Reset(sim *core.Simulation) {
+	// Add random auto delay to avoid artificial Haste breakpoints coming from APL evaluations after tank autos synchronizing with
+	// damage taken events.
+	randomAutoOffset := core.DurationFromSeconds(sim.RandomFloat("Melee Timing") * ai.Target.AutoAttacks.MainhandSwingSpeed().Seconds())
+	ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime-randomAutoOffset, false)
+
+	ai.furiousRoar.CD.Set(time.Second * 30)
+	if ai.shadowNova != nil {
+		ai.shadowNova.CD.Set(core.DurationFromSeconds(sim.RandomFloat("Shadow Nova Timing") * ai.shadowNova.CD.Duration.Seconds()))
+	}
+}
+
+func (ai *HalfusAI) registerFuriousRoar() {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	scalingIndex := core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+	furiousRoarBase := []float64{23125, 32375, 32375, 41625}[scalingIndex]
+	furiousRoarVariance := furiousRoarBase * 0.15
+
+	// Each Furious Roar cast is a set of three roars in quick succession.
+	const roarsPerCast = 3
+	const roarInterval = time.Millisecond * 1500
+
+	roarTick := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 86169},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := furiousRoarBase + furiousRoarVariance*sim.RandomFloat("Furious Roar Damage")
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.furiousRoar = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 83710},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, _ *core.Spell) {
+			ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime+roarInterval*roarsPerCast, false)
+
+			core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:          roarInterval,
+				NumTicks:        roarsPerCast,
+				TickImmediately: true,
+				Priority:        core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					roarTick.Cast(sim, target)
+				},
+			})
+		},
+	})
+}
+
+func (ai *HalfusAI) registerShadowNova() {
+	if !ai.stormRider {
+		return
+	}
+
+	scalingIndex := core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+	shadowNovaBase := []float64{28275, 33930, 33930, 42412}[scalingIndex]
+	shadowNovaVariance := shadowNovaBase * 0.1
+
+	ai.shadowNova = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 86168},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 12,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			// The raid is expected to kick most casts, so only roughly half
+			// of them go off.
+			if !sim.Proc(0.5, "Shadow Nova Interrupt") {
+				return
+			}
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := shadowNovaBase + shadowNovaVariance*sim.RandomFloat("Shadow Nova Damage")
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *HalfusAI) registerMalevolentStrikes() {
+	if !ai.slateDragon {
+		return
+	}
+
+	malevolentStrikesConfig := core.Aura{
+		Label:     "Malevolent Strikes",
+		ActionID:  core.ActionID{SpellID: 39171},
+		MaxStacks: 15,
+		Duration:  time.Second * 30,
+
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			aura.Unit.PseudoStats.HealingTakenMultiplier *= (1.0 - 0.06*float64(newStacks)) / (1.0 - 0.06*float64(oldStacks))
+		},
+	}
+
+	for _, playerUnit := range ai.Target.Env.Raid.AllPlayerUnits {
+		playerUnit.GetOrRegisterAura(malevolentStrikesConfig)
+	}
+
+	core.MakeProcTriggerAura(&ai.Target.Unit, core.ProcTrigger{
+		Name:     "Malevolent Strikes Trigger",
+		Callback: core.CallbackOnSpellHitDealt,
+		ProcMask: core.ProcMaskMeleeMH,
+		Outcome:  core.OutcomeLanded,
+		Harmful:  true,
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			aura := result.Target.GetAuraByID(malevolentStrikesConfig.ActionID)
+			if aura != nil {
+				aura.Activate(sim)
+				aura.AddStack(sim)
+			}
+		},
+	})
+}
+
+func (ai *HalfusAI) registerFrenziedAssault() {
+	if !ai.netherScion {
+		return
+	}
+
+	ai.Target.RegisterAura(core.Aura{
+		Label:    "Frenzied Assault",
+		ActionID: core.ActionID{SpellID: 83693},
+		Duration: core.NeverExpires,
+
+		OnReset: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Activate(sim)
+		},
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyAttackSpeed(sim, 2)
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyAttackSpeed(sim, 0.5)
+		},
+	})
+}
+
+func (ai *HalfusAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	if ai.furiousRoar.IsReady(sim) {
+		ai.furiousRoar.Cast(sim, target)
+	} else if (ai.shadowNova != nil) && ai.shadowNova.IsReady(sim) {
+		ai.shadowNova.Cast(sim, target)
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
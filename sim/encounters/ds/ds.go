@@ -0,0 +1,7 @@
+package ds
+
+func Register() {
+	addMorchok("Dragon Soul")
+	addSpineOfDeathwing("Dragon Soul")
+	addMadnessOfDeathwing("Dragon Soul")
+}
@@ -0,0 +1,267 @@
+package ds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addMadnessOfDeathwing(raidPrefix string) {
+	createMadnessOfDeathwingPreset(raidPrefix, 25, false, 56173, 170_000_000)
+}
+
+func createMadnessOfDeathwingPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64) {
+	targetName := fmt.Sprintf("Madness of Deathwing %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:        bossNpcId,
+			Name:      targetName,
+			Level:     88,
+			MobType:   proto.MobType_MobTypeDragonkin,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0, // actual value doesn't matter in Cata, as long as damage parameters are fit consistently
+			}.ToProtoArray(),
+
+			SpellSchool:  proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:   0, // Deathwing himself never melees
+			TargetInputs: madnessOfDeathwingTargetInputs(),
+		},
+
+		AI: makeMadnessOfDeathwingAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func madnessOfDeathwingTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Phase 2 start",
+			Tooltip:     "Percentage of the fight remaining when Deathwing falls onto the platform and Corrupted Blood begins.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 20,
+		},
+		{
+			Label:       "Platform movement",
+			Tooltip:     "How long in seconds the raid spends jumping to the next platform after each Elementium Bolt.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 5,
+		},
+	}
+}
+
+func makeMadnessOfDeathwingAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &MadnessOfDeathwingAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type MadnessOfDeathwingAI struct {
+	// Unit references
+	Target *core.Target
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	phase2Start      float64
+	platformMovement time.Duration
+
+	// Spell + aura references
+	elementiumBolt *core.Spell
+	crush          *core.Spell
+	impale         *core.Spell
+	corruptedBlood *core.Spell
+
+	// State tracking
+	inPhase2 bool
+}
+
+func (ai *MadnessOfDeathwingAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.phase2Start = config.TargetInputs[0].NumberValue / 100
+	ai.platformMovement = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+
+	ai.registerElementiumBolt()
+	ai.registerCrush()
+	ai.registerImpale()
+	ai.registerCorruptedBlood()
+}
+
+func (ai *MadnessOfDeathwingAI) Reset(sim *core.Simulation) {
+	ai.inPhase2 = false
+
+	ai.elementiumBolt.CD.Set(time.Second * 40)
+	ai.crush.CD.Set(time.Second * 8)
+	ai.impale.CD.Set(time.Second * 22)
+}
+
+func (ai *MadnessOfDeathwingAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+func (ai *MadnessOfDeathwingAI) registerElementiumBolt() {
+	// Nozdormu slows the bolt so that it only deals its reduced damage once
+	// the raid has moved out, after which everyone jumps to the next platform.
+	elementiumBoltBase := []float64{60_000, 75_000, 90_000, 110_000}[ai.scalingIndex()]
+
+	ai.elementiumBolt = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 105651},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 90,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, elementiumBoltBase*sim.Roll(0.95, 1.05), spell.OutcomeAlwaysHit)
+			}
+
+			sim.Raid.MoveAllPlayers(sim, ai.platformMovement)
+		},
+	})
+}
+
+func (ai *MadnessOfDeathwingAI) registerCrush() {
+	// Mutated Corruption tentacles Crush everyone standing in a line, which
+	// in practice means a handful of unlucky ranged players.
+	crushBase := []float64{70_000, 80_000, 95_000, 110_000}[ai.scalingIndex()]
+	numCrushed := core.TernaryInt(ai.raidSize == 10, 2, 5)
+
+	ai.crush = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 106385},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Crush Target", numCrushed, int(ai.raidSize)) {
+				spell.CalcAndDealDamage(sim, hitTarget, crushBase*sim.Roll(0.9, 1.1), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *MadnessOfDeathwingAI) registerImpale() {
+	impaleBase := []float64{450_000, 550_000, 650_000, 800_000}[ai.scalingIndex()]
+
+	ai.impale = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 106400},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 35,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealDamage(sim, target, impaleBase*sim.Roll(0.95, 1.05), spell.OutcomeAlwaysHit)
+		},
+	})
+}
+
+func (ai *MadnessOfDeathwingAI) registerCorruptedBlood() {
+	// Corrupted Blood pulses on the whole raid every 2s, getting stronger
+	// the lower Deathwing's health drops.
+	corruptedBloodBase := []float64{8_000, 10_000, 12_000, 15_000}[ai.scalingIndex()]
+
+	ai.corruptedBlood = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 106834},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			phase2Progress := 1.0
+			if ai.phase2Start > 0 {
+				phase2Progress = 1.0 - sim.GetRemainingDurationPercent()/ai.phase2Start
+			}
+
+			damage := corruptedBloodBase * (1.0 + 4.0*max(phase2Progress, 0))
+
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, damage, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *MadnessOfDeathwingAI) enterPhase2(sim *core.Simulation) {
+	ai.inPhase2 = true
+
+	// The platform adds die when Deathwing falls, so only Impale's tank hits
+	// and Corrupted Blood remain.
+	ai.elementiumBolt.CD.Set(core.NeverExpires)
+	ai.crush.CD.Set(core.NeverExpires)
+
+	core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+		Period:          time.Second * 2,
+		TickImmediately: true,
+
+		OnAction: func(sim *core.Simulation) {
+			ai.corruptedBlood.Cast(sim, &ai.Target.Unit)
+		},
+	})
+}
+
+func (ai *MadnessOfDeathwingAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	if !ai.inPhase2 && (sim.GetRemainingDurationPercent() <= ai.phase2Start) {
+		ai.enterPhase2(sim)
+	}
+
+	if ai.elementiumBolt.IsReady(sim) {
+		ai.elementiumBolt.Cast(sim, target)
+	} else if ai.impale.IsReady(sim) {
+		ai.impale.Cast(sim, target)
+	} else if ai.crush.IsReady(sim) {
+		ai.crush.Cast(sim, target)
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
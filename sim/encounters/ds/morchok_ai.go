@@ -0,0 +1,286 @@
+package ds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addMorchok(raidPrefix string) {
+	createMorchokPreset(raidPrefix, 25, false, 55265, 87_570_000, 165_000)
+}
+
+func createMorchokPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64, bossMinBaseDamage float64) {
+	targetName := fmt.Sprintf("Morchok %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              bossNpcId,
+			Name:            targetName,
+			Level:           88,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0, // actual value doesn't matter in Cata, as long as damage parameters are fit consistently
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  morchokTargetInputs(),
+		},
+
+		AI: makeMorchokAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func morchokTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Black Blood movement",
+			Tooltip:     "How long in seconds the raid spends running behind the pillars for each Black Blood of the Earth.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 5,
+		},
+	}
+}
+
+func makeMorchokAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &MorchokAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type MorchokAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	blackBloodMovement time.Duration
+
+	// Spell + aura references
+	stomp              *core.Spell
+	resonatingCrystal  *core.Spell
+	earthenVortex      *core.Spell
+	furiousAura        *core.Aura
+	blackBloodDuration time.Duration
+}
+
+func (ai *MorchokAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+	ai.blackBloodMovement = core.DurationFromSeconds(config.TargetInputs[0].NumberValue)
+	ai.blackBloodDuration = time.Second * 17
+
+	ai.registerStomp()
+	ai.registerResonatingCrystal()
+	ai.registerEarthenVortex()
+	ai.registerCrushArmor()
+	ai.registerFurious()
+}
+
+func (ai *MorchokAI) Reset(sim *core.Simulation) {
+	randomAutoOffset := core.DurationFromSeconds(sim.RandomFloat("Melee Timing") * ai.Target.AutoAttacks.MainhandSwingSpeed().Seconds())
+	ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime-randomAutoOffset, false)
+
+	ai.stomp.CD.Set(time.Second * 12)
+	ai.resonatingCrystal.CD.Set(time.Second * 19)
+	ai.earthenVortex.CD.Set(time.Second * 56)
+}
+
+func (ai *MorchokAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+func (ai *MorchokAI) registerStomp() {
+	// Stomp damage is split between everyone within 25 yards, with the two
+	// closest players (the tanks) taking a double share.
+	stompTotal := []float64{1_000_000, 2_500_000, 1_500_000, 3_750_000}[ai.scalingIndex()]
+	stompShare := stompTotal / float64(ai.raidSize+2)
+
+	ai.stomp = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 103414},
+		SpellSchool:      core.SpellSchoolPhysical,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 12,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				isTank := (aoeTarget == ai.MainTank) || (aoeTarget == ai.OffTank)
+				damage := core.TernaryFloat64(isTank, 2*stompShare, stompShare)
+				spell.CalcAndDealDamage(sim, aoeTarget, damage, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *MorchokAI) registerResonatingCrystal() {
+	// The crystal explosion is split between the closest few players, who
+	// stand close to soak it.
+	crystalTotal := []float64{600_000, 1_400_000, 900_000, 2_100_000}[ai.scalingIndex()]
+	numSoakers := core.TernaryInt(ai.raidSize == 10, 3, 7)
+
+	ai.resonatingCrystal = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 103545},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Resonating Crystal Target", numSoakers, int(ai.raidSize)) {
+				spell.CalcAndDealDamage(sim, hitTarget, crystalTotal/float64(numSoakers), spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *MorchokAI) registerEarthenVortex() {
+	// Earthen Vortex pulls the raid in, then Black Blood of the Earth floods
+	// the room and everyone has to run behind a pillar. Morchok doesn't melee
+	// during this time.
+	ai.earthenVortex = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 103821},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 75,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, ai.blackBloodMovement)
+
+			blackBloodEnd := sim.CurrentTime + ai.blackBloodDuration
+			ai.Target.AutoAttacks.StopMeleeUntil(sim, blackBloodEnd, false)
+			ai.Target.ExtendGCDUntil(sim, blackBloodEnd)
+
+			// Morchok resets his timers when he comes out of Black Blood.
+			ai.stomp.CD.Set(blackBloodEnd + time.Second*5)
+			ai.resonatingCrystal.CD.Set(blackBloodEnd + time.Second*12)
+		},
+	})
+}
+
+func (ai *MorchokAI) registerCrushArmor() {
+	crushArmorConfig := core.Aura{
+		Label:     "Crush Armor",
+		ActionID:  core.ActionID{SpellID: 103687},
+		MaxStacks: 3,
+		Duration:  time.Second * 20,
+
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			aura.Unit.PseudoStats.ArmorMultiplier *= (1.0 - 0.1*float64(newStacks)) / (1.0 - 0.1*float64(oldStacks))
+		},
+	}
+
+	for _, tankUnit := range []*core.Unit{ai.MainTank, ai.OffTank} {
+		if tankUnit != nil {
+			tankUnit.GetOrRegisterAura(crushArmorConfig)
+		}
+	}
+
+	core.MakeProcTriggerAura(&ai.Target.Unit, core.ProcTrigger{
+		Name:       "Crush Armor Trigger",
+		Callback:   core.CallbackOnSpellHitDealt,
+		ProcMask:   core.ProcMaskMeleeMH,
+		Outcome:    core.OutcomeLanded,
+		ProcChance: 0.5,
+		Harmful:    true,
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			aura := result.Target.GetAuraByID(crushArmorConfig.ActionID)
+			if aura != nil {
+				aura.Activate(sim)
+				aura.AddStack(sim)
+			}
+		},
+	})
+}
+
+func (ai *MorchokAI) registerFurious() {
+	ai.furiousAura = ai.Target.RegisterAura(core.Aura{
+		Label:    "Furious",
+		ActionID: core.ActionID{SpellID: 103846},
+		Duration: core.NeverExpires,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyAttackSpeed(sim, 1.3)
+			aura.Unit.PseudoStats.DamageDealtMultiplier *= 1.2
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyAttackSpeed(sim, 1/1.3)
+			aura.Unit.PseudoStats.DamageDealtMultiplier /= 1.2
+		},
+	})
+}
+
+func (ai *MorchokAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	if !ai.furiousAura.IsActive() && (sim.GetRemainingDurationPercent() <= 0.2) {
+		ai.furiousAura.Activate(sim)
+	}
+
+	if ai.earthenVortex.IsReady(sim) {
+		ai.earthenVortex.Cast(sim, target)
+		return
+	}
+
+	if ai.stomp.IsReady(sim) {
+		ai.stomp.Cast(sim, target)
+	} else if ai.resonatingCrystal.IsReady(sim) {
+		ai.resonatingCrystal.Cast(sim, target)
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,304 @@
+package ds
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addSpineOfDeathwing(raidPrefix string) {
+	createSpineOfDeathwingPreset(raidPrefix, 25, false, 53890, 78_000_000, 95_000)
+}
+
+func createSpineOfDeathwingPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64, bossMinBaseDamage float64) {
+	targetName := fmt.Sprintf("Spine of Deathwing %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              bossNpcId,
+			Name:            targetName,
+			Level:           88,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0, // actual value doesn't matter in Cata, as long as damage parameters are fit consistently
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    1.5,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  spineOfDeathwingTargetInputs(),
+		},
+
+		AI: makeSpineOfDeathwingAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func spineOfDeathwingTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Amalgamation lifetime",
+			Tooltip:     "How long in seconds each Hideous Amalgamation lives before it is killed and releases a Nuclear Blast.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+		{
+			Label:       "Barrel Roll movement",
+			Tooltip:     "How long in seconds the raid spends moving to balance Deathwing during each Barrel Roll.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+	}
+}
+
+func makeSpineOfDeathwingAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &SpineOfDeathwingAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type SpineOfDeathwingAI struct {
+	// Unit references
+	Target *core.Target
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	amalgamationLifetime time.Duration
+	barrelRollMovement   time.Duration
+
+	// Spell + aura references
+	absorbedBloodAura *core.Aura
+	nuclearBlast      *core.Spell
+	bloodCorruption   *core.Spell
+	fieryGrip         *core.Spell
+	barrelRoll        *core.Spell
+
+	// State tracking
+	nextNuclearBlast time.Duration
+}
+
+func (ai *SpineOfDeathwingAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.amalgamationLifetime = core.DurationFromSeconds(config.TargetInputs[0].NumberValue)
+	ai.barrelRollMovement = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+
+	if ai.amalgamationLifetime <= 0 {
+		ai.amalgamationLifetime = core.NeverExpires
+	}
+
+	ai.registerAbsorbedBlood()
+	ai.registerNuclearBlast()
+	ai.registerBloodCorruption()
+	ai.registerFieryGrip()
+	ai.registerBarrelRoll()
+}
+
+func (ai *SpineOfDeathwingAI) Reset(sim *core.Simulation) {
+	randomAutoOffset := core.DurationFromSeconds(sim.RandomFloat("Melee Timing") * ai.Target.AutoAttacks.MainhandSwingSpeed().Seconds())
+	ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime-randomAutoOffset, false)
+
+	ai.nextNuclearBlast = ai.amalgamationLifetime
+	ai.bloodCorruption.CD.Set(time.Second * 10)
+	ai.fieryGrip.CD.Set(time.Second * 20)
+	ai.barrelRoll.CD.Set(time.Second * 60)
+}
+
+func (ai *SpineOfDeathwingAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+func (ai *SpineOfDeathwingAI) registerAbsorbedBlood() {
+	// The Amalgamation absorbs the Corrupted Blood killed near it, gaining
+	// 10% damage per stack. Stacks are reset whenever a new Amalgamation
+	// takes its place.
+	ai.absorbedBloodAura = ai.Target.RegisterAura(core.Aura{
+		Label:     "Absorbed Blood",
+		ActionID:  core.ActionID{SpellID: 105248},
+		MaxStacks: 9,
+		Duration:  core.NeverExpires,
+
+		OnReset: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Activate(sim)
+		},
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period: time.Second * 12,
+
+				OnAction: func(sim *core.Simulation) {
+					if aura.IsActive() {
+						aura.AddStack(sim)
+					}
+				},
+			})
+		},
+
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			aura.Unit.PseudoStats.DamageDealtMultiplier *= (1.0 + 0.1*float64(newStacks)) / (1.0 + 0.1*float64(oldStacks))
+		},
+	})
+}
+
+func (ai *SpineOfDeathwingAI) registerNuclearBlast() {
+	nuclearBlastBase := []float64{50_000, 60_000, 70_000, 85_000}[ai.scalingIndex()]
+
+	ai.nuclearBlast = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 105845},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, nuclearBlastBase*sim.Roll(0.95, 1.05), spell.OutcomeAlwaysHit)
+			}
+
+			// A fresh Amalgamation replaces the one that just exploded.
+			ai.absorbedBloodAura.SetStacks(sim, 0)
+			ai.nextNuclearBlast = sim.CurrentTime + ai.amalgamationLifetime
+		},
+	})
+}
+
+func (ai *SpineOfDeathwingAI) registerBloodCorruption() {
+	bloodCorruptionTick := []float64{20_000, 24_000, 28_000, 34_000}[ai.scalingIndex()]
+
+	ai.bloodCorruption = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 106199},
+		SpellSchool:      core.SpellSchoolShadow,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Blood Corruption: Death",
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 7,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, bloodCorruptionTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Blood Corruption Target", 1, int(ai.raidSize)) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+}
+
+func (ai *SpineOfDeathwingAI) registerFieryGrip() {
+	// Burning Tendons grip a few players until they are freed by breaking
+	// the tendons' armor plates.
+	fieryGripTick := []float64{25_000, 30_000, 35_000, 42_000}[ai.scalingIndex()]
+	numGripped := core.TernaryInt(ai.raidSize == 10, 1, 3)
+
+	ai.fieryGrip = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 105490},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Fiery Grip",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: 8,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, fieryGripTick, dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, dotTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Fiery Grip Target", numGripped, int(ai.raidSize)) {
+				spell.Dot(dotTarget).Apply(sim)
+			}
+		},
+	})
+}
+
+func (ai *SpineOfDeathwingAI) registerBarrelRoll() {
+	ai.barrelRoll = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 105773},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 90,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, ai.barrelRollMovement)
+		},
+	})
+}
+
+func (ai *SpineOfDeathwingAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	if sim.CurrentTime >= ai.nextNuclearBlast {
+		ai.nuclearBlast.Cast(sim, target)
+	} else if ai.barrelRoll.IsReady(sim) {
+		ai.barrelRoll.Cast(sim, target)
+	} else if ai.bloodCorruption.IsReady(sim) {
+		ai.bloodCorruption.Cast(sim, target)
+	} else if ai.fieryGrip.IsReady(sim) {
+		ai.fieryGrip.Cast(sim, target)
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,73 @@
+package encounters_test
+
+import (
+	"testing"
+
+	"github.com/wowsims/cata/sim"
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+func init() {
+	sim.RegisterAll()
+}
+
+// Smoke test running every preset encounter, so boss AIs are at least checked
+// for panics. Each raid's bosses are covered by its own subtests.
+func TestPresetEncounters(t *testing.T) {
+	player := &proto.Player{
+		Race:      proto.Race_RaceTroll,
+		Class:     proto.Class_ClassShaman,
+		Equipment: core.GetGearSet("../../ui/shaman/elemental/gear_sets", "p3.default").GearSet,
+		Rotation:  core.GetAplRotation("../../ui/shaman/elemental/apls", "default").Rotation,
+		Spec: &proto.Player_ElementalShaman{
+			ElementalShaman: &proto.ElementalShaman{
+				Options: &proto.ElementalShaman_Options{
+					ClassOptions: &proto.ShamanOptions{
+						Shield: proto.ShamanShield_LightningShield,
+					},
+				},
+			},
+		},
+	}
+
+	for _, preset := range core.PresetEncounters {
+		t.Run(preset.Path, func(t *testing.T) {
+			for _, useHealth := range []bool{false, true} {
+				encounter := &proto.Encounter{
+					Duration:          300,
+					DurationVariation: 30,
+					UseHealth:         useHealth,
+				}
+				for _, presetTarget := range preset.Targets {
+					target := googleProto.Clone(presetTarget.Target).(*proto.Target)
+					if useHealth {
+						// Raid boss health would take a single player hours
+						// to chew through, so scale it down to a few minutes.
+						target.Stats[stats.Health] /= 1000
+					}
+					encounter.Targets = append(encounter.Targets, target)
+				}
+
+				rsr := &proto.RaidSimRequest{
+					Raid:      core.SinglePlayerRaidProto(player, &proto.PartyBuffs{}, &proto.RaidBuffs{}, &proto.Debuffs{}),
+					Encounter: encounter,
+					SimOptions: &proto.SimOptions{
+						Iterations: 10,
+						RandomSeed: 101,
+					},
+				}
+
+				result := core.RunRaidSim(rsr)
+				if result.Error != nil {
+					t.Fatalf("Sim with UseHealth=%t failed: %s", useHealth, result.Error.Message)
+				}
+				if result.RaidMetrics.Dps.Avg <= 0 {
+					t.Fatalf("Sim with UseHealth=%t did no damage", useHealth)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,347 @@
+package firelands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addAlysrazor(raidPrefix string) {
+	createAlysrazorPreset(raidPrefix, 25, true, 52530, 105_000_000)
+}
+
+func createAlysrazorPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64) {
+	targetName := fmt.Sprintf("Alysrazor %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		// Alysrazor is never tanked, so no melee parameters are configured.
+		Config: &proto.Target{
+			Id:        bossNpcId,
+			Name:      targetName,
+			Level:     88,
+			MobType:   proto.MobType_MobTypeBeast,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:  proto.SpellSchool_SpellSchoolFire,
+			TargetInputs: alysrazorTargetInputs(),
+		},
+
+		AI: makeAlysrazorAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func alysrazorTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:     "Fly during Stage 1",
+			Tooltip:   "If checked, the simulated player flies through Alysrazor's fiery rings during Stage 1, stacking Blazing Power and eventually gaining Alysra's Razor.",
+			InputType: proto.InputType_Bool,
+			BoolValue: false,
+		},
+		{
+			Label:       "Stage 1 duration",
+			Tooltip:     "How long in seconds each flight stage lasts before the Fiery Tornadoes.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 90,
+		},
+	}
+}
+
+func makeAlysrazorAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &AlysrazorAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+const (
+	alysrazorTornadoDuration = time.Second * 30
+	alysrazorBurnoutDuration = time.Second * 30
+)
+
+type AlysrazorAI struct {
+	Target *core.Target
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	flyDuringStage1 bool
+	flightDuration  time.Duration
+
+	// Spell + aura references
+	firestorm          *core.Spell
+	fieryTornado       *core.Spell
+	blazingBuffet      *core.Spell
+	burnoutAura        *core.Aura
+	blazingPowerAuras  core.AuraArray
+	alysrasRazorAuras  core.AuraArray
+	blazingPowerAction *core.PendingAction
+}
+
+func (ai *AlysrazorAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.flyDuringStage1 = config.TargetInputs[0].BoolValue
+	ai.flightDuration = core.DurationFromSeconds(config.TargetInputs[1].NumberValue)
+
+	ai.registerFlightSpells()
+	ai.registerTornadoSpells()
+	ai.registerBurnoutSpells()
+}
+
+func (ai *AlysrazorAI) Reset(sim *core.Simulation) {
+	ai.blazingPowerAction = nil
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+}
+
+func (ai *AlysrazorAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+// The encounter loops through three stages: Alysrazor circles the arena while
+// the raid deals with her adds, lands to spin up Fiery Tornadoes, and then
+// burns out and re-ignites.
+func (ai *AlysrazorAI) cycleDuration() time.Duration {
+	return ai.flightDuration + alysrazorTornadoDuration + alysrazorBurnoutDuration
+}
+
+func (ai *AlysrazorAI) isFlying(sim *core.Simulation) bool {
+	return sim.CurrentTime%ai.cycleDuration() < ai.flightDuration
+}
+
+func (ai *AlysrazorAI) isSpinningTornadoes(sim *core.Simulation) bool {
+	return !ai.isFlying(sim) && (sim.CurrentTime%ai.cycleDuration() < ai.flightDuration+alysrazorTornadoDuration)
+}
+
+func (ai *AlysrazorAI) registerFlightSpells() {
+	// Heroic only: the raid hides behind eggs from Firestorm, so only the
+	// lingering damage gets through.
+	if ai.isHeroic {
+		firestormBase := []float64{0, 0, 9000, 12000}[ai.scalingIndex()]
+
+		ai.firestorm = ai.Target.RegisterSpell(core.SpellConfig{
+			ActionID:         core.ActionID{SpellID: 100744},
+			SpellSchool:      core.SpellSchoolFire,
+			ProcMask:         core.ProcMaskSpellDamage,
+			DamageMultiplier: 1,
+
+			Cast: core.CastConfig{
+				CD: core.Cooldown{
+					Timer:    ai.Target.NewTimer(),
+					Duration: time.Second * 85,
+				},
+			},
+
+			ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+				sim.Raid.MoveAllPlayers(sim, time.Second*3)
+
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					damageRoll := firestormBase * (0.9 + 0.2*sim.RandomFloat("Firestorm Damage"))
+					spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+				}
+			},
+		})
+	}
+
+	if !ai.flyDuringStage1 {
+		return
+	}
+
+	// Flyers pick up a Blazing Power stack from each ring they pass through,
+	// and Alysra's Razor once they reach 25 stacks.
+	player := &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+
+	ai.alysrasRazorAuras = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit != player {
+			return nil
+		}
+		return unit.RegisterAura(core.Aura{
+			Label:    "Alysra's Razor",
+			ActionID: core.ActionID{SpellID: 100029},
+			Duration: time.Second * 30,
+
+			OnGain: func(aura *core.Aura, sim *core.Simulation) {
+				aura.Unit.AddStatsDynamic(sim, stats.Stats{
+					stats.PhysicalCritPercent: 75,
+					stats.SpellCritPercent:    75,
+				})
+			},
+			OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+				aura.Unit.AddStatsDynamic(sim, stats.Stats{
+					stats.PhysicalCritPercent: -75,
+					stats.SpellCritPercent:    -75,
+				})
+			},
+		})
+	})
+
+	ai.blazingPowerAuras = ai.Target.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		if unit != player {
+			return nil
+		}
+		return unit.RegisterAura(core.Aura{
+			Label:     "Blazing Power",
+			ActionID:  core.ActionID{SpellID: 99461},
+			Duration:  time.Second * 40,
+			MaxStacks: 25,
+
+			OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+				// Each stack grants 8% haste.
+				change := (1 + 0.08*float64(newStacks)) / (1 + 0.08*float64(oldStacks))
+				aura.Unit.MultiplyCastSpeed(change)
+				aura.Unit.MultiplyAttackSpeed(sim, change)
+
+				if newStacks == aura.MaxStacks {
+					ai.alysrasRazorAuras.Get(aura.Unit).Activate(sim)
+				}
+			},
+		})
+	})
+}
+
+func (ai *AlysrazorAI) registerTornadoSpells() {
+	// Players constantly sidestep the tornadoes, occasionally getting
+	// clipped by one.
+	tornadoBase := []float64{22000, 26000, 29000, 34000}[ai.scalingIndex()]
+
+	ai.fieryTornado = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 99816},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 5,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, time.Second)
+
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Fiery Tornado Target", 1, int(ai.raidSize)) {
+				damageRoll := tornadoBase * (0.9 + 0.2*sim.RandomFloat("Fiery Tornado Damage"))
+				spell.CalcAndDealDamage(sim, hitTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+}
+
+func (ai *AlysrazorAI) registerBurnoutSpells() {
+	ai.burnoutAura = ai.Target.RegisterAura(core.Aura{
+		Label:    "Burnout",
+		ActionID: core.ActionID{SpellID: 99432},
+		Duration: alysrazorBurnoutDuration,
+	})
+
+	// Blazing Buffet pulses on the raid while Alysrazor is burnt out.
+	blazingBuffetTick := []float64{2000, 2500, 3000, 3750}[ai.scalingIndex()]
+
+	ai.blazingBuffet = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 99757},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			IsAOE: true,
+			Aura: core.Aura{
+				Label: "Blazing Buffet",
+			},
+
+			TickLength:    time.Second,
+			NumberOfTicks: int32(alysrazorBurnoutDuration / time.Second),
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+					dot.Spell.CalcAndDealDamage(sim, aoeTarget, blazingBuffetTick, dot.Spell.OutcomeAlwaysHit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			ai.burnoutAura.Activate(sim)
+			spell.AOEDot().Apply(sim)
+		},
+	})
+}
+
+func (ai *AlysrazorAI) startBlazingPower(sim *core.Simulation) {
+	if !ai.flyDuringStage1 || (ai.blazingPowerAction != nil) {
+		return
+	}
+
+	player := &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	blazingPowerAura := ai.blazingPowerAuras.Get(player)
+	flightEnd := sim.CurrentTime - sim.CurrentTime%ai.cycleDuration() + ai.flightDuration
+
+	// One ring every few seconds while flying.
+	const ringInterval = time.Second * 3
+	numRings := int((flightEnd - sim.CurrentTime) / ringInterval)
+	if numRings <= 0 {
+		return
+	}
+
+	ai.blazingPowerAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+		Period:   ringInterval,
+		NumTicks: numRings,
+		Priority: core.ActionPriorityDOT,
+
+		OnAction: func(sim *core.Simulation) {
+			blazingPowerAura.Activate(sim)
+			blazingPowerAura.AddStack(sim)
+		},
+
+		CleanUp: func(sim *core.Simulation) {
+			ai.blazingPowerAction = nil
+		},
+	})
+}
+
+func (ai *AlysrazorAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	if ai.isFlying(sim) {
+		ai.startBlazingPower(sim)
+
+		if (ai.firestorm != nil) && ai.firestorm.IsReady(sim) {
+			ai.firestorm.Cast(sim, target)
+		}
+	} else if ai.isSpinningTornadoes(sim) {
+		if ai.fieryTornado.IsReady(sim) {
+			ai.fieryTornado.Cast(sim, target)
+		}
+	} else if !ai.burnoutAura.IsActive() && !ai.blazingBuffet.AOEDot().IsActive() {
+		ai.blazingBuffet.Cast(sim, target)
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -2,5 +2,7 @@ package firelands
 
 func Register() {
 	addBethtilac("Firelands")
+	addAlysrazor("Firelands")
 	addBaleroc("Firelands")
+	addRagnaros("Firelands")
 }
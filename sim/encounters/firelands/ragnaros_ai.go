@@ -0,0 +1,450 @@
+package firelands
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addRagnaros(raidPrefix string) {
+	createRagnarosPreset(raidPrefix, 25, true, 52409, 115_000_000, 190_000)
+}
+
+func createRagnarosPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64, bossMinBaseDamage float64) {
+	targetName := fmt.Sprintf("Ragnaros %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		Config: &proto.Target{
+			Id:              bossNpcId,
+			Name:            targetName,
+			Level:           88,
+			MobType:         proto.MobType_MobTypeElemental,
+			TankIndex:       0,
+			SecondTankIndex: 1,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0, // actual value doesn't matter in Cata, as long as damage parameters are fit consistently
+			}.ToProtoArray(),
+
+			SpellSchool:   proto.SpellSchool_SpellSchoolPhysical,
+			SwingSpeed:    2.0,
+			MinBaseDamage: bossMinBaseDamage,
+			DamageSpread:  0.4,
+			TargetInputs:  ragnarosTargetInputs(),
+		},
+
+		AI: makeRagnarosAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func ragnarosTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Intermission duration",
+			Tooltip:     "How long in seconds Ragnaros stays submerged at 70% and 40% while the raid deals with the Sons of Flame. He cannot be damaged during this time. Set to 0 to skip the intermissions.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 45,
+		},
+		{
+			Label:       "Burning Wound tank swap stacks",
+			Tooltip:     "Tank 2 taunts once the current tank reaches this many Burning Wound stacks. Ignored if there is no second tank.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 6,
+		},
+	}
+}
+
+func makeRagnarosAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &RagnarosAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type RagnarosAI struct {
+	// Unit references
+	Target   *core.Target
+	MainTank *core.Unit
+	OffTank  *core.Unit
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	intermissionDuration time.Duration
+	tankSwapStacks       int32
+
+	// Phase tracking. Intermissions happen between phases and are tracked
+	// by the Submerged aura instead.
+	phase int32
+
+	// Spell + aura references
+	submergedAura   *core.Aura
+	wrathOfRagnaros *core.Spell
+	handOfRagnaros  *core.Spell
+	magmaTrap       *core.Spell
+	sulfurasSmash   *core.Spell
+	engulfingFlames *core.Spell
+	moltenSeed      *core.Spell
+	livingMeteor    *core.Spell
+}
+
+func (ai *RagnarosAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.MainTank = target.CurrentTarget
+	ai.OffTank = target.SecondaryTarget
+	ai.intermissionDuration = core.DurationFromSeconds(config.TargetInputs[0].NumberValue)
+	ai.tankSwapStacks = int32(config.TargetInputs[1].NumberValue)
+
+	ai.registerSubmerge()
+	ai.registerBurningWound()
+	ai.registerPhase1Spells()
+	ai.registerLaterPhaseSpells()
+}
+
+func (ai *RagnarosAI) Reset(sim *core.Simulation) {
+	ai.phase = 1
+
+	ai.wrathOfRagnaros.CD.Set(time.Second * 6)
+	ai.handOfRagnaros.CD.Set(time.Second * 25)
+	ai.magmaTrap.CD.Set(time.Second * 16)
+	ai.sulfurasSmash.CD.Set(time.Second * 30)
+
+	// Randomize melee and cast timings to prevent fake APL-Haste couplings.
+	randomAutoOffset := core.DurationFromSeconds(sim.RandomFloat("Melee Timing") * ai.Target.AutoAttacks.MainhandSwingSpeed().Seconds())
+	ai.Target.AutoAttacks.StopMeleeUntil(sim, sim.CurrentTime-randomAutoOffset, false)
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+}
+
+func (ai *RagnarosAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+func (ai *RagnarosAI) swapTargets(sim *core.Simulation, newTankTarget *core.Unit) {
+	ai.Target.AutoAttacks.CancelAutoSwing(sim)
+	ai.Target.CurrentTarget = newTankTarget
+	if (newTankTarget != nil) && !ai.submergedAura.IsActive() {
+		ai.Target.AutoAttacks.EnableAutoSwing(sim)
+	}
+}
+
+func (ai *RagnarosAI) registerSubmerge() {
+	ai.submergedAura = ai.Target.RegisterAura(core.Aura{
+		Label:    "Submerged",
+		ActionID: core.ActionID{SpellID: 98982},
+		Duration: ai.intermissionDuration,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.AutoAttacks.CancelAutoSwing(sim)
+			aura.Unit.PseudoStats.Invulnerable++
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.PseudoStats.Invulnerable--
+			if (sim.CurrentTime < sim.Duration) && (ai.Target.CurrentTarget != nil) {
+				aura.Unit.AutoAttacks.EnableAutoSwing(sim)
+			}
+		},
+	})
+}
+
+func (ai *RagnarosAI) registerBurningWound() {
+	burningWoundTick := []float64{2000, 2400, 2800, 3400}[ai.scalingIndex()]
+	burningWoundActionID := core.ActionID{SpellID: 99399}
+
+	burningWoundDot := ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         burningWoundActionID,
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		Flags:            core.SpellFlagIgnoreResists,
+		DamageMultiplier: 1,
+
+		Dot: core.DotConfig{
+			Aura: core.Aura{
+				Label:     "Burning Wound",
+				MaxStacks: 99,
+			},
+
+			TickLength:    time.Second * 2,
+			NumberOfTicks: 10,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.Spell.CalcAndDealPeriodicDamage(sim, target, burningWoundTick*float64(dot.GetStacks()), dot.Spell.OutcomeAlwaysHit)
+			},
+		},
+	})
+
+	core.MakeProcTriggerAura(&ai.Target.Unit, core.ProcTrigger{
+		Name:     "Burning Wound Trigger",
+		Callback: core.CallbackOnSpellHitDealt,
+		ProcMask: core.ProcMaskMeleeMH,
+		Outcome:  core.OutcomeLanded,
+		Harmful:  true,
+
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			dot := burningWoundDot.Dot(result.Target)
+			dot.Apply(sim)
+			dot.AddStack(sim)
+
+			if (ai.tankSwapStacks <= 0) || (dot.GetStacks() < ai.tankSwapStacks) {
+				return
+			}
+
+			if ai.OffTank != nil {
+				ai.swapTargets(sim, core.Ternary(result.Target == ai.MainTank, ai.OffTank, ai.MainTank))
+				return
+			}
+
+			// Without a second tank, emulate the swap by leaving the tank
+			// alone until their stacks fall off.
+			ai.swapTargets(sim, nil)
+			core.StartDelayedAction(sim, core.DelayedActionOptions{
+				DoAt: dot.ExpiresAt(),
+				OnAction: func(sim *core.Simulation) {
+					ai.swapTargets(sim, ai.MainTank)
+				},
+			})
+		},
+	})
+}
+
+func (ai *RagnarosAI) registerPhase1Spells() {
+	wrathBase := []float64{41000, 52000, 52000, 66000}[ai.scalingIndex()]
+	wrathTargets := core.TernaryInt(ai.raidSize == 10, 1, 3)
+
+	ai.wrathOfRagnaros = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 98263},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 25,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Wrath of Ragnaros Target", wrathTargets, int(ai.raidSize)) {
+				damageRoll := wrathBase * (0.95 + 0.1*sim.RandomFloat("Wrath of Ragnaros Damage"))
+				spell.CalcAndDealDamage(sim, hitTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// Hand of Ragnaros only reaches players in melee range.
+	handBase := []float64{22000, 28000, 28000, 35000}[ai.scalingIndex()]
+	handTargets := core.TernaryInt(ai.raidSize == 10, 5, 12)
+
+	ai.handOfRagnaros = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 98237},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 25,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Hand of Ragnaros Target", handTargets, int(ai.raidSize)) {
+				damageRoll := handBase * (0.95 + 0.1*sim.RandomFloat("Hand of Ragnaros Damage"))
+				spell.CalcAndDealDamage(sim, hitTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	// A player soaks each Magma Trap, which erupts on the whole raid.
+	magmaTrapBase := []float64{24000, 30000, 30000, 38000}[ai.scalingIndex()]
+
+	ai.magmaTrap = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 98175},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 25,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := magmaTrapBase * (0.95 + 0.1*sim.RandomFloat("Magma Trap Damage"))
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.sulfurasSmash = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 98710},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, time.Second*2)
+		},
+	})
+}
+
+func (ai *RagnarosAI) registerLaterPhaseSpells() {
+	ai.engulfingFlames = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 99172},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 40,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, time.Second*3)
+		},
+	})
+
+	// Molten Seed explosions fall off with distance, and the raid spreads
+	// to keep each player at roughly one nearby explosion.
+	moltenSeedBase := []float64{28000, 34000, 36000, 44000}[ai.scalingIndex()]
+
+	ai.moltenSeed = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 98498},
+		SpellSchool:      core.SpellSchoolFire,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 60,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := moltenSeedBase * (0.9 + 0.2*sim.RandomFloat("Molten Seed Damage"))
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.livingMeteor = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 99268},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 45,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			// Kiting the meteors costs the raid several seconds of uptime.
+			sim.Raid.MoveAllPlayers(sim, time.Second*4)
+		},
+	})
+}
+
+func (ai *RagnarosAI) updatePhase(sim *core.Simulation) {
+	if ai.submergedAura.IsActive() {
+		return
+	}
+
+	remaining := sim.GetRemainingDurationPercent()
+	nextPhaseAt := core.TernaryFloat64(ai.phase == 1, 0.7, 0.4)
+	if (ai.phase >= 3) || (remaining > nextPhaseAt) {
+		return
+	}
+
+	ai.phase++
+	if ai.intermissionDuration > 0 {
+		ai.submergedAura.Activate(sim)
+	}
+
+	phaseStart := sim.CurrentTime + ai.intermissionDuration
+	ai.sulfurasSmash.CD.Set(phaseStart + time.Second*15)
+	ai.engulfingFlames.CD.Set(phaseStart + time.Second*30)
+	if ai.phase == 2 {
+		ai.moltenSeed.CD.Set(phaseStart + time.Second*15)
+	} else {
+		ai.livingMeteor.CD.Set(phaseStart + time.Second*45)
+	}
+}
+
+func (ai *RagnarosAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	ai.updatePhase(sim)
+
+	if ai.submergedAura.IsActive() {
+		ai.Target.WaitUntil(sim, ai.submergedAura.ExpiresAt())
+		return
+	}
+
+	switch ai.phase {
+	case 1:
+		if ai.sulfurasSmash.IsReady(sim) {
+			ai.sulfurasSmash.Cast(sim, target)
+		} else if ai.wrathOfRagnaros.IsReady(sim) {
+			ai.wrathOfRagnaros.Cast(sim, target)
+		} else if ai.handOfRagnaros.IsReady(sim) {
+			ai.handOfRagnaros.Cast(sim, target)
+		} else if ai.magmaTrap.IsReady(sim) {
+			ai.magmaTrap.Cast(sim, target)
+		}
+	case 2:
+		if ai.sulfurasSmash.IsReady(sim) {
+			ai.sulfurasSmash.Cast(sim, target)
+		} else if ai.moltenSeed.IsReady(sim) {
+			ai.moltenSeed.Cast(sim, target)
+		} else if ai.engulfingFlames.IsReady(sim) {
+			ai.engulfingFlames.Cast(sim, target)
+		}
+	case 3:
+		if ai.sulfurasSmash.IsReady(sim) {
+			ai.sulfurasSmash.Cast(sim, target)
+		} else if ai.livingMeteor.IsReady(sim) {
+			ai.livingMeteor.Cast(sim, target)
+		} else if ai.engulfingFlames.IsReady(sim) {
+			ai.engulfingFlames.Cast(sim, target)
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -4,15 +4,21 @@ import (
 	"github.com/wowsims/cata/sim/core"
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/stats"
+	"github.com/wowsims/cata/sim/encounters/bot"
 	"github.com/wowsims/cata/sim/encounters/bwd"
+	"github.com/wowsims/cata/sim/encounters/ds"
 	"github.com/wowsims/cata/sim/encounters/firelands"
+	"github.com/wowsims/cata/sim/encounters/tfw"
 )
 
 func init() {
 	AddDefaultPresetEncounter()
 	addMovementAI()
 	bwd.Register()
+	bot.Register()
+	tfw.Register()
 	firelands.Register()
+	ds.Register()
 }
 
 func AddSingleTargetBossEncounter(presetTarget *core.PresetTarget) {
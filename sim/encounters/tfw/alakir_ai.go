@@ -0,0 +1,337 @@
+package tfw
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func addAlakir(raidPrefix string) {
+	createAlakirPreset(raidPrefix, 25, true, 46753, 84_500_000)
+}
+
+func createAlakirPreset(raidPrefix string, raidSize int32, isHeroic bool, bossNpcId int32, bossHealth float64) {
+	targetName := fmt.Sprintf("Al'Akir %d", raidSize)
+
+	if isHeroic {
+		targetName += " H"
+	}
+
+	core.AddPresetTarget(&core.PresetTarget{
+		PathPrefix: raidPrefix,
+
+		// Al'Akir is not tanked, so no melee parameters are configured.
+		Config: &proto.Target{
+			Id:        bossNpcId,
+			Name:      targetName,
+			Level:     88,
+			MobType:   proto.MobType_MobTypeElemental,
+			TankIndex: 0,
+
+			Stats: stats.Stats{
+				stats.Health:      bossHealth,
+				stats.Armor:       11977,
+				stats.AttackPower: 0,
+			}.ToProtoArray(),
+
+			SpellSchool:  proto.SpellSchool_SpellSchoolNature,
+			TargetInputs: alakirTargetInputs(),
+		},
+
+		AI: makeAlakirAI(raidSize, isHeroic),
+	})
+
+	core.AddPresetEncounter(targetName, []string{
+		raidPrefix + "/" + targetName,
+	})
+}
+
+func alakirTargetInputs() []*proto.TargetInput {
+	return []*proto.TargetInput{
+		{
+			Label:       "Squall Line movement",
+			Tooltip:     "How long in seconds the raid spends repositioning for each Squall Line in Phases 1 and 2, and for each Lightning Clouds in Phase 3.",
+			InputType:   proto.InputType_Number,
+			NumberValue: 3,
+		},
+	}
+}
+
+func makeAlakirAI(raidSize int32, isHeroic bool) core.AIFactory {
+	return func() core.TargetAI {
+		return &AlakirAI{
+			raidSize: raidSize,
+			isHeroic: isHeroic,
+		}
+	}
+}
+
+type AlakirAI struct {
+	Target *core.Target
+
+	// Static parameters associated with a given preset
+	raidSize int32
+	isHeroic bool
+
+	// Dynamic parameters taken from user inputs
+	movementDuration time.Duration
+
+	// Phase tracking
+	phase int32
+
+	// Spell + aura references
+	windBurst       *core.Spell
+	lightningStrike *core.Spell
+	squallLine      *core.Spell
+	acidRainAura    *core.Aura
+	acidRainDamage  *core.Spell
+	lightning       *core.Spell
+	lightningClouds *core.Spell
+}
+
+func (ai *AlakirAI) Initialize(target *core.Target, config *proto.Target) {
+	ai.Target = target
+	ai.movementDuration = core.DurationFromSeconds(config.TargetInputs[0].NumberValue)
+
+	ai.registerPhase1Spells()
+	ai.registerAcidRain()
+	ai.registerPhase3Spells()
+}
+
+func (ai *AlakirAI) Reset(sim *core.Simulation) {
+	ai.phase = 1
+
+	ai.windBurst.CD.Set(time.Second * 22)
+	ai.squallLine.CD.Set(time.Second * 15)
+	ai.lightningStrike.CD.Set(core.DurationFromSeconds(sim.RandomFloat("Lightning Strike Timing") * ai.lightningStrike.CD.Duration.Seconds()))
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.DurationFromSeconds(sim.RandomFloat("Specials Timing")*core.BossGCD.Seconds()))
+}
+
+func (ai *AlakirAI) scalingIndex() int {
+	// 0 - 10N, 1 - 25N, 2 - 10H, 3 - 25H
+	return core.TernaryInt(ai.raidSize == 10, core.TernaryInt(ai.isHeroic, 2, 0), core.TernaryInt(ai.isHeroic, 3, 1))
+}
+
+func (ai *AlakirAI) registerPhase1Spells() {
+	windBurstBase := []float64{21375, 25650, 29925, 35625}[ai.scalingIndex()]
+
+	ai.windBurst = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 87770},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 25,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				damageRoll := windBurstBase * (0.95 + 0.1*sim.RandomFloat("Wind Burst Damage"))
+				spell.CalcAndDealDamage(sim, aoeTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+
+			// Everyone gets knocked back and has to run back in.
+			sim.Raid.MoveAllPlayers(sim, time.Second*2)
+		},
+	})
+
+	// Lightning Strike is a cone aimed at a random player, so it only hits
+	// part of the raid.
+	lightningStrikeBase := []float64{19000, 23750, 26125, 30875}[ai.scalingIndex()]
+	lightningStrikeTargets := core.TernaryInt(ai.raidSize == 10, 3, 8)
+
+	ai.lightningStrike = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 88214},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 12,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Lightning Strike Target", lightningStrikeTargets, int(ai.raidSize)) {
+				damageRoll := lightningStrikeBase * (0.95 + 0.1*sim.RandomFloat("Lightning Strike Damage"))
+				spell.CalcAndDealDamage(sim, hitTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.squallLine = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 91129},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, ai.movementDuration)
+		},
+	})
+}
+
+func (ai *AlakirAI) registerAcidRain() {
+	// Acid Rain pulses on the whole raid for the rest of Phase 2, gaining a
+	// stack every 15 seconds.
+	acidRainTick := []float64{1500, 1850, 2250, 2750}[ai.scalingIndex()]
+	acidRainActionID := core.ActionID{SpellID: 88290}
+	var stackAction, tickAction *core.PendingAction
+
+	ai.acidRainDamage = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         acidRainActionID,
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			stacks := float64(ai.acidRainAura.GetStacks())
+			for _, aoeTarget := range sim.Raid.AllPlayerUnits {
+				spell.CalcAndDealDamage(sim, aoeTarget, acidRainTick*stacks, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.acidRainAura = ai.Target.RegisterAura(core.Aura{
+		Label:     "Acid Rain",
+		ActionID:  acidRainActionID,
+		MaxStacks: math.MaxInt32,
+		Duration:  core.NeverExpires,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.SetStacks(sim, 1)
+
+			stackAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second * 15,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					aura.AddStack(sim)
+				},
+			})
+
+			tickAction = core.StartPeriodicAction(sim, core.PeriodicActionOptions{
+				Period:   time.Second * 2,
+				Priority: core.ActionPriorityDOT,
+
+				OnAction: func(sim *core.Simulation) {
+					ai.acidRainDamage.Cast(sim, &ai.Target.Unit)
+				},
+			})
+		},
+
+		OnExpire: func(_ *core.Aura, sim *core.Simulation) {
+			stackAction.Cancel(sim)
+			tickAction.Cancel(sim)
+		},
+	})
+}
+
+func (ai *AlakirAI) registerPhase3Spells() {
+	// In Phase 3 the raid is flying and Al'Akir zaps random players with
+	// Lightning while Lightning Clouds force the raid to change altitude.
+	lightningBase := []float64{6000, 7200, 8400, 10000}[ai.scalingIndex()]
+	lightningTargets := core.TernaryInt(ai.raidSize == 10, 1, 3)
+
+	ai.lightning = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID:         core.ActionID{SpellID: 89668},
+		SpellSchool:      core.SpellSchoolNature,
+		ProcMask:         core.ProcMaskSpellDamage,
+		DamageMultiplier: 1,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 2,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			for _, hitTarget := range sim.Raid.GetRandomPlayerUnits(sim, "Lightning Target", lightningTargets, int(ai.raidSize)) {
+				damageRoll := lightningBase * (0.9 + 0.2*sim.RandomFloat("Lightning Damage"))
+				spell.CalcAndDealDamage(sim, hitTarget, damageRoll, spell.OutcomeAlwaysHit)
+			}
+		},
+	})
+
+	ai.lightningClouds = ai.Target.RegisterSpell(core.SpellConfig{
+		ActionID: core.ActionID{SpellID: 89588},
+		ProcMask: core.ProcMaskEmpty,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    ai.Target.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			sim.Raid.MoveAllPlayers(sim, ai.movementDuration)
+		},
+	})
+}
+
+func (ai *AlakirAI) updatePhase(sim *core.Simulation) {
+	remaining := sim.GetRemainingDurationPercent()
+
+	if (ai.phase == 1) && (remaining <= 0.8) {
+		ai.phase = 2
+		ai.acidRainAura.Activate(sim)
+	}
+
+	if (ai.phase == 2) && (remaining <= 0.25) {
+		ai.phase = 3
+		ai.acidRainAura.Deactivate(sim)
+		ai.lightningClouds.CD.Set(sim.CurrentTime + time.Second*15)
+	}
+}
+
+func (ai *AlakirAI) ExecuteCustomRotation(sim *core.Simulation) {
+	target := ai.Target.CurrentTarget
+	if target == nil {
+		// For individual non tank sims we still want abilities to work
+		target = &ai.Target.Env.Raid.Parties[0].Players[0].GetCharacter().Unit
+	}
+
+	ai.updatePhase(sim)
+
+	switch ai.phase {
+	case 1:
+		if ai.windBurst.IsReady(sim) {
+			ai.windBurst.Cast(sim, target)
+		} else if ai.squallLine.IsReady(sim) {
+			ai.squallLine.Cast(sim, target)
+		} else if ai.lightningStrike.IsReady(sim) {
+			ai.lightningStrike.Cast(sim, target)
+		}
+	case 2:
+		if ai.squallLine.IsReady(sim) {
+			ai.squallLine.Cast(sim, target)
+		} else if ai.lightningStrike.IsReady(sim) {
+			ai.lightningStrike.Cast(sim, target)
+		}
+	case 3:
+		if ai.lightningClouds.IsReady(sim) {
+			ai.lightningClouds.Cast(sim, target)
+		} else if ai.lightning.IsReady(sim) {
+			ai.lightning.Cast(sim, target)
+		}
+	}
+
+	ai.Target.ExtendGCDUntil(sim, sim.CurrentTime+core.BossGCD)
+}
@@ -0,0 +1,5 @@
+package tfw
+
+func Register() {
+	addAlakir("Throne of the Four Winds")
+}
@@ -200,6 +200,10 @@ func (hunter *Hunter) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	}
 }
 
+func (hunter *Hunter) AddRosterBuffs(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+	raidBuffs.HuntingParty = hunter.Talents.HuntingParty
+}
+
 func (hunter *Hunter) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
@@ -41,15 +41,15 @@ func (hunter *Hunter) registerMultiShotSpell() {
 
 			baseDamageArray := make([]*core.SpellResult, numHits)
 			for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
-				currentTarget := hunter.Env.GetTargetUnit(hitIndex)
+				currentTarget := hunter.Env.GetActiveTargetUnit(hitIndex)
 				baseDamage := sharedDmg + 0.2*spell.RangedAttackPower(currentTarget)
 				baseDamageArray[hitIndex] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeRangedHitAndCrit)
 
 			}
 			spell.WaitTravelTime(sim, func(sim *core.Simulation) {
-				curTarget := target
 				for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
 					spell.DealDamage(sim, baseDamageArray[hitIndex])
+					curTarget := baseDamageArray[hitIndex].Target
 					if hunter.Talents.SerpentSpread > 0 {
 						duration := time.Duration(3+(hunter.Talents.SerpentSpread*3)) * time.Second
 
@@ -60,7 +60,6 @@ func (hunter *Hunter) registerMultiShotSpell() {
 						ss.BaseTickCount = (3 + (hunter.Talents.SerpentSpread * 3)) / 2
 						ss.Apply(sim)
 					}
-					curTarget = sim.Environment.NextTargetUnit(curTarget)
 				}
 			})
 
@@ -81,6 +81,12 @@ func (mage *Mage) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	raidBuffs.ArcaneBrilliance = true
 }
 
+func (mage *Mage) AddRosterBuffs(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	raidBuffs.TimeWarp = true
+	raidBuffs.ArcaneTactics = mage.Talents.ArcaneTactics
+	debuffs.CriticalMass = mage.Talents.CriticalMass > 0
+}
+
 func (mage *Mage) AddPartyBuffs(partyBuffs *proto.PartyBuffs) {
 }
 
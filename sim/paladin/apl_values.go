@@ -9,6 +9,8 @@ func (paladin *Paladin) NewAPLValue(rot *core.APLRotation, config *proto.APLValu
 	switch config.Value.(type) {
 	case *proto.APLValue_CurrentHolyPower:
 		return paladin.newValueCurrentHolyPower(config.GetCurrentHolyPower(), config.Uuid)
+	case *proto.APLValue_PaladinIsBeaconTarget:
+		return paladin.newValueIsBeaconTarget(rot, config.GetPaladinIsBeaconTarget(), config.Uuid)
 	default:
 		return nil
 	}
@@ -37,3 +39,35 @@ func (value *APLValueCurrentHolyPower) GetInt(sim *core.Simulation) int32 {
 func (value *APLValueCurrentHolyPower) String() string {
 	return "Current Holy Power"
 }
+
+type APLValueIsBeaconTarget struct {
+	core.DefaultAPLValueImpl
+	paladin *Paladin
+	unit    core.UnitReference
+}
+
+func (paladin *Paladin) newValueIsBeaconTarget(rot *core.APLRotation, config *proto.APLValuePaladinIsBeaconTarget, uuid *proto.UUID) core.APLValue {
+	if !paladin.Talents.BeaconOfLight {
+		rot.ValidationMessageByUUID(uuid, proto.LogLevel_Warning, "Beacon of Light is not talented.")
+		return nil
+	}
+
+	unit := rot.GetTargetUnit(config.TargetUnit)
+	if unit.Get() == nil {
+		return nil
+	}
+
+	return &APLValueIsBeaconTarget{
+		paladin: paladin,
+		unit:    unit,
+	}
+}
+func (value *APLValueIsBeaconTarget) Type() proto.APLValueType {
+	return proto.APLValueType_ValueTypeBool
+}
+func (value *APLValueIsBeaconTarget) GetBool(sim *core.Simulation) bool {
+	return value.paladin.IsBeaconTarget(value.unit.Get())
+}
+func (value *APLValueIsBeaconTarget) String() string {
+	return "Is Beacon Target"
+}
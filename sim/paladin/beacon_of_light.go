@@ -0,0 +1,90 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+// IsBeaconTarget returns true if the unit currently has this paladin's Beacon of Light.
+func (paladin *Paladin) IsBeaconTarget(unit *core.Unit) bool {
+	return paladin.beaconTarget != nil && paladin.beaconTarget == unit
+}
+
+func (paladin *Paladin) registerBeaconOfLight() {
+	if !paladin.Talents.BeaconOfLight {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 53563}
+
+	// Heals copied to the Beacon target have already been modified once, so
+	// only the target's modifiers apply.
+	beaconHeal := paladin.RegisterSpell(core.SpellConfig{
+		ActionID:    core.ActionID{SpellID: 53652},
+		SpellSchool: core.SpellSchoolHoly,
+		ProcMask:    core.ProcMaskEmpty,
+		Flags:       core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagIgnoreAttackerModifiers,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+	})
+
+	paladin.BeaconOfLightAuras = paladin.NewAllyAuraArray(func(unit *core.Unit) *core.Aura {
+		return unit.RegisterAura(core.Aura{
+			Label:    "Beacon of Light-" + paladin.Label,
+			ActionID: actionID,
+			Duration: time.Minute * 5,
+			OnGain: func(aura *core.Aura, sim *core.Simulation) {
+				paladin.beaconTarget = aura.Unit
+			},
+			OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+				if paladin.beaconTarget == aura.Unit {
+					paladin.beaconTarget = nil
+				}
+			},
+		})
+	})
+
+	paladin.BeaconOfLight = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskEmpty,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskBeaconOfLight,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.06,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if paladin.beaconTarget != nil && paladin.beaconTarget != target {
+				paladin.BeaconOfLightAuras.Get(paladin.beaconTarget).Deactivate(sim)
+			}
+			paladin.BeaconOfLightAuras.Get(target).Activate(sim)
+		},
+	})
+
+	// Direct heals on other targets are copied to the Beacon target, Holy Light
+	// in full and everything else at half strength.
+	core.MakeProcTriggerAura(&paladin.Unit, core.ProcTrigger{
+		Name:           "Beacon of Light Trigger",
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: SpellMaskDirectHeal,
+		Harmful:        true,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if paladin.beaconTarget == nil || result.Target == paladin.beaconTarget {
+				return
+			}
+
+			transferPct := core.TernaryFloat64(spell.Matches(SpellMaskHolyLight), 1, 0.5)
+			beaconHeal.CalcAndDealHealing(sim, paladin.beaconTarget, result.Damage*transferPct, beaconHeal.OutcomeHealing)
+		},
+	})
+}
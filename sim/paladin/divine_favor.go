@@ -0,0 +1,63 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/stats"
+)
+
+func (paladin *Paladin) registerDivineFavor() {
+	if !paladin.Talents.DivineFavor {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 31842}
+	duration := time.Second * 20
+	if paladin.HasPrimeGlyph(proto.PaladinPrimeGlyph_GlyphOfDivineFavor) {
+		duration += time.Second * 10
+	}
+
+	paladin.DivineFavorAura = paladin.RegisterAura(core.Aura{
+		Label:    "Divine Favor" + paladin.Label,
+		ActionID: actionID,
+		Duration: duration,
+
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			paladin.MultiplyCastSpeed(1.2)
+			paladin.AddStatDynamic(sim, stats.SpellCritPercent, 20)
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			paladin.MultiplyCastSpeed(1 / 1.2)
+			paladin.AddStatDynamic(sim, stats.SpellCritPercent, -20)
+		},
+	})
+
+	paladin.DivineFavor = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		Flags:          core.SpellFlagNoOnCastComplete | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskDivineFavor,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost: 0.03,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				NonEmpty: true,
+			},
+			CD: core.Cooldown{
+				Timer:    paladin.NewTimer(),
+				Duration: time.Minute * 3,
+			},
+		},
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			paladin.DivineFavorAura.Activate(sim)
+		},
+	})
+
+	paladin.AddMajorCooldown(core.MajorCooldown{
+		Spell: paladin.DivineFavor,
+		Type:  core.CooldownTypeDPS,
+	})
+}
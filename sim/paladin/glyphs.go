@@ -53,6 +53,20 @@ func (paladin *Paladin) applyGlyphs() {
 			FloatValue: 0.1,
 		})
 	}
+	if paladin.HasPrimeGlyph(proto.PaladinPrimeGlyph_GlyphOfHolyShock) {
+		paladin.AddStaticMod(core.SpellModConfig{
+			Kind:       core.SpellMod_BonusCrit_Percent,
+			ClassMask:  SpellMaskHolyShock | SpellMaskHolyShockHeal,
+			FloatValue: 5,
+		})
+	}
+	if paladin.HasPrimeGlyph(proto.PaladinPrimeGlyph_GlyphOfWordOfGlory) {
+		paladin.AddStaticMod(core.SpellModConfig{
+			Kind:       core.SpellMod_DamageDone_Pct,
+			ClassMask:  SpellMaskWordOfGlory,
+			FloatValue: 0.1,
+		})
+	}
 
 	// Major Glyphs
 	if paladin.HasMajorGlyph(proto.PaladinMajorGlyph_GlyphOfHammerOfWrath) {
@@ -74,6 +88,13 @@ func (paladin *Paladin) applyGlyphs() {
 			FloatValue: 2,
 		})
 	}
+	if paladin.HasMajorGlyph(proto.PaladinMajorGlyph_GlyphOfBeaconOfLight) {
+		paladin.AddStaticMod(core.SpellModConfig{
+			Kind:       core.SpellMod_PowerCost_Pct,
+			ClassMask:  SpellMaskBeaconOfLight,
+			FloatValue: -1,
+		})
+	}
 	if paladin.HasMajorGlyph(proto.PaladinMajorGlyph_GlyphOfTheAsceticCrusader) {
 		paladin.AddStaticMod(core.SpellModConfig{
 			Kind:       core.SpellMod_PowerCost_Pct,
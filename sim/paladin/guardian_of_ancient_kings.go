@@ -139,8 +139,7 @@ func (paladin *Paladin) registerRetributionGuardian(duration time.Duration) *cor
 
 	ancientFuryMinDamage, ancientFuryMaxDamage :=
 		core.CalcScalingSpellEffectVarianceMinMax(proto.Class_ClassPaladin, 0.23659999669, 0.30000001192)
-	numTargets := paladin.Env.GetNumTargets()
-	results := make([]*core.SpellResult, numTargets)
+	results := make([]*core.SpellResult, len(paladin.Env.Encounter.TargetUnits))
 
 	ancientFury := paladin.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 86704},
@@ -167,11 +166,12 @@ func (paladin *Paladin) registerRetributionGuardian(duration time.Duration) *cor
 
 			// Deals X Holy damage per application of Ancient Power,
 			// divided evenly among all targets within 10 yards.
+			numTargets := sim.GetNumTargets()
 			baseDamage *= float64(paladin.AncientPowerAura.GetStacks())
 			baseDamage /= float64(numTargets)
 
 			for idx := int32(0); idx < numTargets; idx++ {
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+				currentTarget := sim.Environment.GetActiveTargetUnit(idx)
 				results[idx] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
 
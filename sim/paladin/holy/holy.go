@@ -1,6 +1,8 @@
 package holy
 
 import (
+	"time"
+
 	"github.com/wowsims/cata/sim/core"
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/core/stats"
@@ -32,6 +34,9 @@ func NewHolyPaladin(character *core.Character, options *proto.Player) *HolyPalad
 		Options: holyOptions.Options,
 	}
 
+	// Meditation
+	holy.PseudoStats.SpiritRegenRateCombat = 0.5
+
 	return holy
 }
 
@@ -48,10 +53,71 @@ func (holy *HolyPaladin) GetPaladin() *paladin.Paladin {
 func (holy *HolyPaladin) ApplyTalents() {
 	holy.Paladin.ApplyTalents()
 	holy.ApplyArmorSpecializationEffect(stats.Intellect, proto.ArmorType_ArmorTypePlate, 86525)
+
+	// Walk in the Light
+	holy.AddStaticMod(core.SpellModConfig{
+		ClassMask:  paladin.SpellMaskHealing,
+		FloatValue: 0.1,
+		Kind:       core.SpellMod_DamageDone_Pct,
+	})
+
+	holy.applyIlluminatedHealing()
 }
 
 func (holy *HolyPaladin) Initialize() {
+	holy.CurrentTarget = holy.GetMainTarget()
 	holy.Paladin.Initialize()
+	holy.RegisterHealingSpells()
+}
+
+func getMasteryBonus(masteryPoints float64) float64 {
+	return (12 + masteryPoints*1.5) / 100
+}
+
+// Mastery: Illuminated Healing, direct heals also place an absorb on their
+// target. The absorb stacks with itself, up to a third of the paladin's health.
+func (holy *HolyPaladin) applyIlluminatedHealing() {
+	illuminatedHealing := holy.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 86273},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagNoOnCastComplete | core.SpellFlagHelpful,
+		ClassSpellMask: paladin.SpellMaskIlluminatedHealing,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		ThreatMultiplier:         1,
+
+		Shield: core.ShieldConfig{
+			AbsorbsDamage: true,
+			Aura: core.Aura{
+				Label:    "Illuminated Healing",
+				Duration: time.Second * 15,
+			},
+		},
+	})
+
+	masteryBonus := getMasteryBonus(holy.GetMasteryPoints())
+	holy.AddOnMasteryStatChanged(func(sim *core.Simulation, oldMastery, newMastery float64) {
+		masteryBonus = getMasteryBonus(core.MasteryRatingToMasteryPoints(newMastery))
+	})
+
+	core.MakeProcTriggerAura(&holy.Unit, core.ProcTrigger{
+		Name:           "Illuminated Healing Trigger",
+		ActionID:       core.ActionID{SpellID: 76669},
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: paladin.SpellMaskDirectHeal,
+		Harmful:        true,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			shield := illuminatedHealing.Shield(result.Target)
+			added := min(result.Damage*masteryBonus, max(0, holy.MaxHealth()/3-shield.RemainingAbsorb()))
+			if added <= 0 {
+				return
+			}
+
+			shield.Apply(sim, added)
+		},
+	})
 }
 
 func (holy *HolyPaladin) Reset(sim *core.Simulation) {
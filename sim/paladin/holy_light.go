@@ -0,0 +1,50 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (paladin *Paladin) newHolyLightSpellConfig(actionID core.ActionID, classMask int64, baseCost float64, castTime time.Duration, coeff float64, bonusCoeff float64) core.SpellConfig {
+	return core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: classMask,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   baseCost,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: castTime,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   paladin.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: bonusCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := paladin.CalcAndRollDamageRange(sim, coeff, 0.11)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	}
+}
+
+func (paladin *Paladin) registerHolyLight() {
+	paladin.HolyLight = paladin.RegisterSpell(paladin.newHolyLightSpellConfig(core.ActionID{SpellID: 635}, SpellMaskHolyLight, 0.12, time.Second*3, 2.869, 0.432))
+}
+
+func (paladin *Paladin) registerDivineLight() {
+	paladin.DivineLight = paladin.RegisterSpell(paladin.newHolyLightSpellConfig(core.ActionID{SpellID: 82326}, SpellMaskDivineLight, 0.35, time.Second*3, 7.776, 1.1))
+}
+
+func (paladin *Paladin) registerFlashOfLight() {
+	paladin.FlashOfLight = paladin.RegisterSpell(paladin.newHolyLightSpellConfig(core.ActionID{SpellID: 19750}, SpellMaskFlashOfLight, 0.31, time.Millisecond*1500, 7.092, 0.863))
+}
@@ -55,6 +55,9 @@ func (pb *HolyPowerBar) GainHolyPower(sim *core.Simulation, amountToAdd int32, m
 	if sim.Log != nil {
 		pb.paladin.Log(sim, "Gained %d holy power from %s (%d --> %d) of %0.0f total.", amountToAdd, metrics.ActionID, pb.holyPower, newHolyPower, 3.0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, &pb.paladin.Unit, metrics, float64(amountToAdd), float64(newHolyPower))
+	}
 
 	pb.holyPower = newHolyPower
 }
@@ -72,6 +75,9 @@ func (pb *HolyPowerBar) SpendHolyPower(sim *core.Simulation, metrics *core.Resou
 	if sim.Log != nil {
 		pb.paladin.Log(sim, "Spent %d holy power from %s (%d --> %d) of %0.0f total.", pb.holyPower, metrics.ActionID, pb.holyPower, 0, 3.0)
 	}
+	if sim.CombatLog != nil {
+		sim.CombatLog.LogResource(sim, &pb.paladin.Unit, metrics, float64(-pb.holyPower), 0)
+	}
 
 	metrics.AddEvent(float64(-pb.holyPower), float64(-pb.holyPower))
 	pb.holyPower = 0
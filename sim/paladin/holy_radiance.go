@@ -0,0 +1,58 @@
+package paladin
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+// Holy Radiance heals the most injured allies near the paladin every second.
+func (paladin *Paladin) registerHolyRadiance() {
+	const numTargets = 6
+
+	paladin.HolyRadiance = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 82327},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskHolyRadiance,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.4,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    paladin.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   paladin.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.0675,
+
+		Hot: core.DotConfig{
+			SelfOnly: true,
+			Aura: core.Aura{
+				Label: "Holy Radiance",
+			},
+			NumberOfTicks: 10,
+			TickLength:    time.Second,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, healTarget := range paladin.GetSmartHealTargets(&paladin.Unit, numTargets) {
+					dot.Spell.CalcAndDealPeriodicHealing(sim, healTarget, paladin.CalcScalingSpellDmg(0.628), dot.Spell.OutcomeHealingCrit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			spell.SelfHot().Apply(sim)
+		},
+	})
+}
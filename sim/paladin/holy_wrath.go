@@ -9,7 +9,6 @@ import (
 
 func (paladin *Paladin) registerHolyWrath() {
 	hwAvgDamage := core.CalcScalingSpellAverageEffect(proto.Class_ClassPaladin, 2.33299994469)
-	numTargets := paladin.Env.GetNumTargets()
 
 	paladin.HolyWrath = paladin.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 2812},
@@ -39,6 +38,7 @@ func (paladin *Paladin) registerHolyWrath() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			numTargets := sim.GetNumTargets()
 			results := make([]*core.SpellResult, numTargets)
 			baseDamage := hwAvgDamage + .61*spell.SpellPower()
 
@@ -46,7 +46,7 @@ func (paladin *Paladin) registerHolyWrath() {
 			baseDamage /= float64(numTargets)
 
 			for idx := int32(0); idx < numTargets; idx++ {
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+				currentTarget := sim.Environment.GetActiveTargetUnit(idx)
 				results[idx] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMagicHitAndCrit)
 			}
 
@@ -0,0 +1,49 @@
+package paladin
+
+import (
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+// Light of Dawn heals the most injured allies in a cone in front of the
+// paladin, which we treat as the paladin and the lowest health allies.
+func (paladin *Paladin) registerLightOfDawn() {
+	if !paladin.Talents.LightOfDawn {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 85222}
+	hpMetrics := paladin.NewHolyPowerMetrics(actionID)
+	numTargets := 5 + core.TernaryInt(paladin.HasMajorGlyph(proto.PaladinMajorGlyph_GlyphOfLightOfDawn), 1, 0)
+
+	paladin.LightOfDawn = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskLightOfDawn,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return paladin.GetHolyPowerValue() > 0
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   paladin.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, spell *core.Spell) {
+			holyPower := float64(paladin.GetHolyPowerValue())
+
+			for _, healTarget := range paladin.GetSmartHealTargets(&paladin.Unit, numTargets) {
+				baseHealing := holyPower * (paladin.CalcAndRollDamageRange(sim, 0.622, 0.108) + 0.132*spell.HealingPower(healTarget))
+				spell.CalcAndDealHealing(sim, healTarget, baseHealing, spell.OutcomeHealingCrit)
+			}
+			paladin.SpendHolyPower(sim, hpMetrics)
+		},
+	})
+}
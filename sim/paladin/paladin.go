@@ -47,6 +47,15 @@ const (
 
 	SpellMaskHolyShock
 	SpellMaskWordOfGlory
+	SpellMaskHolyShockHeal
+	SpellMaskHolyLight
+	SpellMaskDivineLight
+	SpellMaskFlashOfLight
+	SpellMaskLightOfDawn
+	SpellMaskHolyRadiance
+	SpellMaskBeaconOfLight
+	SpellMaskIlluminatedHealing
+	SpellMaskDivineFavor
 
 	SpellMaskSealOfTruth
 	SpellMaskSealOfInsight
@@ -58,6 +67,16 @@ const SpellMaskBuilder = SpellMaskCrusaderStrike |
 	SpellMaskDivineStorm |
 	SpellMaskHammerOfTheRighteousMelee
 
+const SpellMaskDirectHeal = SpellMaskHolyShockHeal |
+	SpellMaskWordOfGlory |
+	SpellMaskHolyLight |
+	SpellMaskDivineLight |
+	SpellMaskFlashOfLight |
+	SpellMaskLightOfDawn
+
+const SpellMaskHealing = SpellMaskDirectHeal |
+	SpellMaskHolyRadiance
+
 const SpellMaskHammerOfTheRighteous = SpellMaskHammerOfTheRighteousMelee | SpellMaskHammerOfTheRighteousAoe
 
 const SpellMaskJudgement = SpellMaskJudgementOfTruth |
@@ -167,6 +186,15 @@ type Paladin struct {
 	JudgementOfRighteousness *core.Spell
 	JudgementOfJustice       *core.Spell
 	ShieldOfTheRighteous     *core.Spell
+	HolyShockHeal            *core.Spell
+	WordOfGlory              *core.Spell
+	HolyLight                *core.Spell
+	DivineLight              *core.Spell
+	FlashOfLight             *core.Spell
+	LightOfDawn              *core.Spell
+	HolyRadiance             *core.Spell
+	BeaconOfLight            *core.Spell
+	DivineFavor              *core.Spell
 
 	HolyShieldAura          *core.Aura
 	RighteousFuryAura       *core.Aura
@@ -185,6 +213,13 @@ type Paladin struct {
 	SacredDutyAura          *core.Aura
 	GoakAura                *core.Aura
 	AncientPowerAura        *core.Aura
+	InfusionOfLightAura     *core.Aura
+	DaybreakAura            *core.Aura
+	DivineFavorAura         *core.Aura
+	ConvictionAura          *core.Aura
+
+	BeaconOfLightAuras core.AuraArray
+	beaconTarget       *core.Unit
 
 	// Cached Gurthalak tentacles
 	gurthalakTentacles []*cata.TentacleOfTheOldOnesPet
@@ -242,6 +277,11 @@ func (paladin *Paladin) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	}
 }
 
+func (paladin *Paladin) AddRosterBuffs(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	debuffs.Vindication = paladin.Talents.Vindication
+	debuffs.JudgementsOfTheJust = paladin.Talents.JudgementsOfTheJust > 0
+}
+
 func (paladin *Paladin) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
@@ -269,6 +309,20 @@ func (paladin *Paladin) registerSpells() {
 	paladin.registerDivineProtectionSpell()
 }
 
+// Registers the heals used by Holy. Other specs don't cast them, so they're
+// left out of registerSpells.
+func (paladin *Paladin) RegisterHealingSpells() {
+	paladin.registerBeaconOfLight()
+	paladin.registerHolyShockHeal()
+	paladin.registerWordOfGlory()
+	paladin.registerHolyLight()
+	paladin.registerDivineLight()
+	paladin.registerFlashOfLight()
+	paladin.registerLightOfDawn()
+	paladin.registerHolyRadiance()
+	paladin.registerDivineFavor()
+}
+
 func (paladin *Paladin) Reset(sim *core.Simulation) {
 	switch paladin.Seal {
 	case proto.PaladinSeal_Truth:
@@ -14,8 +14,8 @@ func (prot *ProtectionPaladin) registerAvengersShieldSpell() {
 	glyphedSingleTargetAS := prot.HasMajorGlyph(proto.PaladinMajorGlyph_GlyphOfFocusedShield)
 
 	// Glyph to single target, OR apply to up to 3 targets
-	numTargets := core.TernaryInt32(glyphedSingleTargetAS, 1, min(3, prot.Env.GetNumTargets()))
-	results := make([]*core.SpellResult, numTargets)
+	maxTargets := core.TernaryInt32(glyphedSingleTargetAS, 1, 3)
+	results := make([]*core.SpellResult, maxTargets)
 
 	prot.AvengersShield = prot.RegisterSpell(core.SpellConfig{
 		ActionID:    actionId,
@@ -45,12 +45,13 @@ func (prot *ProtectionPaladin) registerAvengersShieldSpell() {
 		ThreatMultiplier: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			numTargets := min(maxTargets, sim.GetNumTargets())
 			constBaseDamage := 0.20999999344*spell.SpellPower() + 0.41899999976*spell.MeleeAttackPower()
 
 			for idx := int32(0); idx < numTargets; idx++ {
 				baseDamage := constBaseDamage + sim.RollWithLabel(asMinDamage, asMaxDamage, "Avengers Shield"+prot.Label)
 
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+				currentTarget := sim.Environment.GetActiveTargetUnit(idx)
 				results[idx] = spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 			}
 
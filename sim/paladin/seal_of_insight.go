@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
 )
 
 func (paladin *Paladin) registerSealOfInsight() {
@@ -79,6 +80,20 @@ func (paladin *Paladin) registerSealOfInsight() {
 		},
 	})
 
+	if paladin.HasPrimeGlyph(proto.PaladinPrimeGlyph_GlyphOfSealOfInsight) {
+		healingMod := paladin.AddDynamicMod(core.SpellModConfig{
+			ClassMask:  SpellMaskHealing,
+			Kind:       core.SpellMod_DamageDone_Pct,
+			FloatValue: 0.05,
+		})
+		paladin.SealOfInsightAura.ApplyOnGain(func(_ *core.Aura, _ *core.Simulation) {
+			healingMod.Activate()
+		})
+		paladin.SealOfInsightAura.ApplyOnExpire(func(_ *core.Aura, _ *core.Simulation) {
+			healingMod.Deactivate()
+		})
+	}
+
 	// Seal of Insight self-buff.
 	aura := paladin.SealOfInsightAura
 	paladin.RegisterSpell(core.SpellConfig{
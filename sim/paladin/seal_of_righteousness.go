@@ -7,13 +7,7 @@ import (
 )
 
 func (paladin *Paladin) registerSealOfRighteousness() {
-	var numTargets int32
-	if paladin.Talents.SealsOfCommand {
-		numTargets = paladin.Env.GetNumTargets()
-	} else {
-		numTargets = 1
-	}
-	results := make([]*core.SpellResult, numTargets)
+	results := make([]*core.SpellResult, len(paladin.Env.Encounter.TargetUnits))
 
 	// Judgement of Righteousness cast on Judgement
 	paladin.JudgementOfRighteousness = paladin.RegisterSpell(core.SpellConfig{
@@ -74,9 +68,10 @@ func (paladin *Paladin) registerSealOfRighteousness() {
 		baseDamage := paladin.GetMHWeapon().SwingSpeed *
 			(0.022*spell.SpellPower() + 0.011*spell.MeleeAttackPower())
 
+		numTargets := core.TernaryInt32(paladin.Talents.SealsOfCommand, sim.GetNumTargets(), 1)
 		for idx := int32(0); idx < numTargets; idx++ {
 			// can't miss if melee swing landed, but can crit
-			results[idx] = spell.CalcDamage(sim, sim.Environment.GetTargetUnit(idx), baseDamage, spell.OutcomeMeleeSpecialCritOnly)
+			results[idx] = spell.CalcDamage(sim, sim.Environment.GetActiveTargetUnit(idx), baseDamage, spell.OutcomeMeleeSpecialCritOnly)
 		}
 
 		for idx := int32(0); idx < numTargets; idx++ {
@@ -11,6 +11,12 @@ func (paladin *Paladin) applyHolyTalents() {
 	paladin.applyJudgementsOfThePure()
 	paladin.applyBlazingLight()
 	paladin.applyDenounce()
+	paladin.applyClarityOfPurpose()
+	paladin.applyInfusionOfLight()
+	paladin.applyDaybreak()
+	paladin.applySpeedOfLight()
+	paladin.applyConviction()
+	paladin.applyTowerOfRadiance()
 }
 
 func (paladin *Paladin) applyArbiterOfTheLight() {
@@ -98,3 +104,142 @@ func (paladin *Paladin) applyDenounce() {
 		FloatValue: -([]float64{0, 0.38, 0.75}[paladin.Talents.Denounce]),
 	})
 }
+
+func (paladin *Paladin) applyClarityOfPurpose() {
+	if paladin.Talents.ClarityOfPurpose == 0 {
+		return
+	}
+
+	paladin.AddStaticMod(core.SpellModConfig{
+		ClassMask: SpellMaskHolyLight | SpellMaskDivineLight,
+		Kind:      core.SpellMod_CastTime_Flat,
+		TimeValue: -time.Millisecond * time.Duration(150*paladin.Talents.ClarityOfPurpose),
+	})
+}
+
+func (paladin *Paladin) applyInfusionOfLight() {
+	if paladin.Talents.InfusionOfLight == 0 {
+		return
+	}
+
+	castTimeMod := paladin.AddDynamicMod(core.SpellModConfig{
+		ClassMask: SpellMaskHolyLight | SpellMaskDivineLight,
+		Kind:      core.SpellMod_CastTime_Flat,
+		TimeValue: -time.Millisecond * time.Duration(750*paladin.Talents.InfusionOfLight),
+	})
+
+	paladin.InfusionOfLightAura = paladin.RegisterAura(core.Aura{
+		Label:    "Infusion of Light" + paladin.Label,
+		ActionID: core.ActionID{SpellID: 54149},
+		Duration: time.Second * 15,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Activate()
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Deactivate()
+		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.Matches(SpellMaskHolyLight | SpellMaskDivineLight) {
+				aura.Deactivate(sim)
+			}
+		},
+	})
+
+	core.MakeProcTriggerAura(&paladin.Unit, core.ProcTrigger{
+		Name:           "Infusion of Light Trigger" + paladin.Label,
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: SpellMaskHolyShockHeal,
+		Outcome:        core.OutcomeCrit,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			paladin.InfusionOfLightAura.Activate(sim)
+		},
+	})
+}
+
+func (paladin *Paladin) applyDaybreak() {
+	if paladin.Talents.Daybreak == 0 {
+		return
+	}
+
+	paladin.DaybreakAura = paladin.RegisterAura(core.Aura{
+		Label:    "Daybreak" + paladin.Label,
+		ActionID: core.ActionID{SpellID: 88819},
+		Duration: time.Second * 12,
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			// The next Holy Shock has no cooldown.
+			if spell.Matches(SpellMaskHolyShockHeal) {
+				spell.CD.Reset()
+				aura.Deactivate(sim)
+			}
+		},
+	})
+
+	core.MakeProcTriggerAura(&paladin.Unit, core.ProcTrigger{
+		Name:           "Daybreak Trigger" + paladin.Label,
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: SpellMaskFlashOfLight | SpellMaskHolyLight | SpellMaskDivineLight,
+		ProcChance:     0.1 * float64(paladin.Talents.Daybreak),
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			paladin.DaybreakAura.Activate(sim)
+		},
+	})
+}
+
+func (paladin *Paladin) applySpeedOfLight() {
+	if paladin.Talents.SpeedOfLight == 0 {
+		return
+	}
+
+	paladin.MultiplyCastSpeed(1 + 0.01*float64(paladin.Talents.SpeedOfLight))
+}
+
+func (paladin *Paladin) applyConviction() {
+	if paladin.Talents.Conviction == 0 {
+		return
+	}
+
+	bonusPerStack := 0.01 * float64(paladin.Talents.Conviction)
+
+	paladin.ConvictionAura = paladin.RegisterAura(core.Aura{
+		Label:     "Conviction" + paladin.Label,
+		ActionID:  core.ActionID{SpellID: 20050},
+		Duration:  time.Second * 15,
+		MaxStacks: 3,
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
+			oldMultiplier := 1 + bonusPerStack*float64(oldStacks)
+			newMultiplier := 1 + bonusPerStack*float64(newStacks)
+			paladin.PseudoStats.DamageDealtMultiplier *= newMultiplier / oldMultiplier
+			paladin.PseudoStats.HealingDealtMultiplier *= newMultiplier / oldMultiplier
+		},
+	})
+
+	core.MakeProcTriggerAura(&paladin.Unit, core.ProcTrigger{
+		Name:     "Conviction Trigger" + paladin.Label,
+		Callback: core.CallbackOnSpellHitDealt | core.CallbackOnHealDealt,
+		Outcome:  core.OutcomeCrit,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			paladin.ConvictionAura.Activate(sim)
+			paladin.ConvictionAura.AddStack(sim)
+		},
+	})
+}
+
+func (paladin *Paladin) applyTowerOfRadiance() {
+	if paladin.Talents.TowerOfRadiance == 0 {
+		return
+	}
+
+	hpMetrics := paladin.NewHolyPowerMetrics(core.ActionID{SpellID: 88852})
+	procChance := []float64{0, 0.33, 0.66, 1}[paladin.Talents.TowerOfRadiance]
+
+	core.MakeProcTriggerAura(&paladin.Unit, core.ProcTrigger{
+		Name:           "Tower of Radiance" + paladin.Label,
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: SpellMaskFlashOfLight | SpellMaskHolyLight | SpellMaskDivineLight,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if paladin.IsBeaconTarget(result.Target) && sim.Proc(procChance, "Tower of Radiance"+paladin.Label) {
+				paladin.GainHolyPower(sim, 1, hpMetrics)
+			}
+		},
+	})
+}
@@ -32,7 +32,11 @@ func (paladin *Paladin) applyCrusade() {
 		FloatValue: 0.1 * float64(paladin.Talents.Crusade),
 	})
 
-	// TODO: Add Healing Mod for Holy Shock if healing sim gets implemented
+	paladin.AddStaticMod(core.SpellModConfig{
+		ClassMask:  SpellMaskHolyShockHeal,
+		Kind:       core.SpellMod_DamageDone_Flat,
+		FloatValue: 0.1 * float64(paladin.Talents.Crusade),
+	})
 }
 
 func (paladin *Paladin) applyRuleOfLaw() {
@@ -222,7 +226,6 @@ func (paladin *Paladin) applyDivineStorm() {
 		return
 	}
 
-	numTargets := paladin.Env.GetNumTargets()
 	actionId := core.ActionID{SpellID: 53385}
 	hpMetrics := paladin.NewHolyPowerMetrics(actionId)
 
@@ -254,11 +257,12 @@ func (paladin *Paladin) applyDivineStorm() {
 		CritMultiplier:   paladin.DefaultMeleeCritMultiplier(),
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			numTargets := sim.GetNumTargets()
 			numHits := 0
 			results := make([]*core.SpellResult, numTargets)
 
 			for idx := int32(0); idx < numTargets; idx++ {
-				currentTarget := sim.Environment.GetTargetUnit(idx)
+				currentTarget := sim.Environment.GetActiveTargetUnit(idx)
 				baseDamage := spell.Unit.MHWeaponDamage(sim, spell.MeleeAttackPower())
 				result := spell.CalcDamage(sim, currentTarget, baseDamage, spell.OutcomeMeleeSpecialHitAndCrit)
 				if result.Landed() {
@@ -0,0 +1,39 @@
+package paladin
+
+import (
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (paladin *Paladin) registerWordOfGlory() {
+	actionID := core.ActionID{SpellID: 85673}
+	hpMetrics := paladin.NewHolyPowerMetrics(actionID)
+
+	paladin.WordOfGlory = paladin.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskWordOfGlory,
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return paladin.GetHolyPowerValue() > 0
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   paladin.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			holyPower := float64(paladin.GetHolyPowerValue())
+
+			baseHealing := holyPower * (paladin.CalcAndRollDamageRange(sim, 2.236, 0.108) + 0.198*spell.HealingPower(target))
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+			paladin.SpendHolyPower(sim, hpMetrics)
+		},
+	})
+}
@@ -0,0 +1,43 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (priest *Priest) registerBindingHealSpell() {
+	priest.BindingHeal = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 32546},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellBindingHeal,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.28,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 1500,
+			},
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.644,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := priest.CalcAndRollDamageRange(sim, 6.547, 0.25)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+
+			// Binding Heal always heals the caster as well, even when self-cast.
+			baseHealing = priest.CalcAndRollDamageRange(sim, 6.547, 0.25)
+			spell.CalcAndDealHealing(sim, &priest.Unit, baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
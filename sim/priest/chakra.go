@@ -0,0 +1,150 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (priest *Priest) registerChakraSpell() {
+	if !priest.Talents.Chakra {
+		return
+	}
+
+	makeChakraState := func(label string, spellID int32) *core.Aura {
+		return priest.RegisterAura(core.Aura{
+			Label:    label,
+			ActionID: core.ActionID{SpellID: spellID},
+			Duration: time.Second * 30,
+		})
+	}
+
+	priest.ChakraSerenityAura = priest.RegisterAura(core.Aura{
+		Label:    "Chakra: Serenity",
+		ActionID: core.ActionID{SpellID: 81208},
+		Duration: time.Second * 30,
+		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			// Direct heals refresh the duration of Renew on the target.
+			if spell.ClassSpellMask&PriestSpellDirectHeal == 0 {
+				return
+			}
+			if renew := priest.Renew.Hot(result.Target); renew.IsActive() {
+				renew.ApplyRollover(sim)
+			}
+		},
+	})
+	priest.ChakraSerenityAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_BonusCrit_Percent,
+		ClassMask:  PriestSpellDirectHeal,
+		FloatValue: 10,
+	})
+
+	priest.ChakraSanctuaryAura = makeChakraState("Chakra: Sanctuary", 81206)
+	priest.ChakraSanctuaryAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		ClassMask:  PriestSpellAoEHeal,
+		FloatValue: 0.15,
+	})
+	priest.ChakraSanctuaryAura.AttachSpellMod(core.SpellModConfig{
+		Kind:      core.SpellMod_Cooldown_Flat,
+		ClassMask: PriestSpellCircleOfHealing,
+		TimeValue: -time.Second * 2,
+	})
+
+	priest.ChakraChastiseAura = makeChakraState("Chakra: Chastise", 81209)
+	priest.ChakraChastiseAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_DamageDone_Pct,
+		School:     core.SpellSchoolHoly | core.SpellSchoolShadow,
+		ProcMask:   core.ProcMaskSpellDamage,
+		FloatValue: 0.15,
+	})
+
+	chakraStates := []*core.Aura{priest.ChakraSerenityAura, priest.ChakraSanctuaryAura, priest.ChakraChastiseAura}
+	enterState := func(sim *core.Simulation, state *core.Aura) {
+		for _, other := range chakraStates {
+			if other != state {
+				other.Deactivate(sim)
+			}
+		}
+		state.Activate(sim)
+	}
+
+	actionID := core.ActionID{SpellID: 14751}
+	priest.ChakraAura = priest.RegisterAura(core.Aura{
+		Label:    "Chakra",
+		ActionID: actionID,
+		Duration: core.NeverExpires,
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			switch {
+			case spell.ClassSpellMask&(PriestSpellFlashHeal|PriestSpellBindingHeal|PriestSpellGreaterHeal|PriestSpellHeal) != 0:
+				enterState(sim, priest.ChakraSerenityAura)
+			case spell.ClassSpellMask&PriestSpellPrayerOfHealing != 0:
+				enterState(sim, priest.ChakraSanctuaryAura)
+			case spell.ClassSpellMask&(PriestSpellSmite|PriestSpellMindSpike) != 0:
+				enterState(sim, priest.ChakraChastiseAura)
+			default:
+				return
+			}
+			aura.Deactivate(sim)
+		},
+	})
+
+	priest.Chakra = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		Flags:          core.SpellFlagNoOnCastComplete | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellChakra,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			priest.ChakraAura.Activate(sim)
+		},
+	})
+
+	if priest.Talents.Revelations {
+		priest.registerHolyWordSerenitySpell()
+	}
+}
+
+func (priest *Priest) registerHolyWordSerenitySpell() {
+	priest.HolyWordSerenity = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 88684},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellHolyWordSerenity,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.12,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 15,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return priest.ChakraSerenityAura.IsActive()
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.486,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := priest.CalcAndRollDamageRange(sim, 5.941, 0.16)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+}
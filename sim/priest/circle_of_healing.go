@@ -0,0 +1,54 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (priest *Priest) registerCircleOfHealingSpell() {
+	if !priest.Talents.CircleOfHealing {
+		return
+	}
+
+	numTargets := 5
+	if priest.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfCircleOfHealing) {
+		numTargets++
+	}
+
+	priest.CircleOfHealing = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 34861},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellCircleOfHealing,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.21,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.259,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			for _, healTarget := range priest.GetSmartHealTargets(target, numTargets) {
+				baseHealing := priest.CalcAndRollDamageRange(sim, 2.571, 0.1)
+				spell.CalcAndDealHealing(sim, healTarget, baseHealing, spell.OutcomeHealingCrit)
+			}
+		},
+	})
+}
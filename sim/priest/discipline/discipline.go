@@ -43,6 +43,9 @@ func newDisciplinePriest(character *core.Character, options *proto.Player) *Disc
 		Options: discOptions.Options,
 	}
 
+	// Meditation
+	discPriest.PseudoStats.SpiritRegenRateCombat = 0.5
+
 	discPriest.SelfBuffs.PowerInfusionTarget = &proto.UnitReference{}
 	if discPriest.Talents.PowerInfusion && discPriest.Options.PowerInfusionTarget != nil {
 		discPriest.SelfBuffs.PowerInfusionTarget = discPriest.Options.PowerInfusionTarget
@@ -55,24 +58,37 @@ func (discPriest *DisciplinePriest) GetPriest() *priest.Priest {
 	return discPriest.Priest
 }
 
-func (discPriest *DisciplinePriest) GetMainTarget() *core.Unit {
-	target := discPriest.Env.Raid.GetFirstTargetDummy()
-	if target == nil {
-		return &discPriest.Unit
-	} else {
-		return &target.Unit
-	}
-}
-
 func (discPriest *DisciplinePriest) Initialize() {
 	discPriest.CurrentTarget = discPriest.GetMainTarget()
 	discPriest.Priest.Initialize()
-	// discPriest.Priest.RegisterHealingSpells()
+	discPriest.RegisterHealingSpells()
 
-	// // discPriest.ApplyRapture(discPriest.Options.RapturesPerMinute)
-	// discPriest.RegisterHymnOfHopeCD()
+	discPriest.RegisterHolyFireSpell()
+	discPriest.RegisterSmiteSpell()
+	discPriest.RegisterPenanceSpell()
+	discPriest.RegisterHymnOfHopeCD()
 }
 
 func (discPriest *DisciplinePriest) Reset(sim *core.Simulation) {
-	//discPriest.Priest.Reset(sim)
+	discPriest.Priest.Reset(sim)
+}
+
+func getMasteryBonus(masteryPoints float64) float64 {
+	return (20 + masteryPoints*2.5) / 100
+}
+
+func (discPriest *DisciplinePriest) ApplyTalents() {
+	discPriest.Priest.ApplyTalents()
+
+	// Mastery: Shield Discipline
+	shieldMod := discPriest.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  priest.PriestSpellPowerWordShield | priest.PriestSpellDivineAegis,
+		FloatValue: getMasteryBonus(discPriest.GetMasteryPoints()),
+		Kind:       core.SpellMod_DamageDone_Pct,
+	})
+	shieldMod.Activate()
+
+	discPriest.AddOnMasteryStatChanged(func(sim *core.Simulation, oldMastery, newMastery float64) {
+		shieldMod.UpdateFloatValue(getMasteryBonus(core.MasteryRatingToMasteryPoints(newMastery)))
+	})
 }
@@ -33,6 +33,30 @@ func (priest *Priest) ApplyGlyphs() {
 		})
 	}
 
+	if priest.HasPrimeGlyph(proto.PriestPrimeGlyph_GlyphOfFlashHeal) {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask:  PriestSpellFlashHeal,
+			FloatValue: 10,
+			Kind:       core.SpellMod_BonusCrit_Percent,
+		})
+	}
+
+	if priest.HasPrimeGlyph(proto.PriestPrimeGlyph_GlyphOfPenance) {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask: PriestSpellPenance | PriestSpellPenanceHeal,
+			TimeValue: time.Second * -2,
+			Kind:      core.SpellMod_Cooldown_Flat,
+		})
+	}
+
+	if priest.HasPrimeGlyph(proto.PriestPrimeGlyph_GlyphOfRenew) {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask:  PriestSpellRenew,
+			FloatValue: 0.1,
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
 	if priest.HasPrimeGlyph(proto.PriestPrimeGlyph_GlyphOfShadowWordDeath) {
 		priest.RegisterAura(core.Aura{
 			Label:    "Glyph of Shadow Word: Death",
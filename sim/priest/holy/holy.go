@@ -1,6 +1,8 @@
 package holy
 
 import (
+	"time"
+
 	"github.com/wowsims/cata/sim/core"
 	"github.com/wowsims/cata/sim/core/proto"
 	"github.com/wowsims/cata/sim/priest"
@@ -36,6 +38,9 @@ func NewHolyPriest(character *core.Character, options *proto.Player) *HolyPriest
 		Priest: basePriest,
 	}
 
+	// Meditation
+	holyPriest.PseudoStats.SpiritRegenRateCombat = 0.5
+
 	return holyPriest
 }
 
@@ -48,14 +53,80 @@ func (holyPriest *HolyPriest) GetPriest() *priest.Priest {
 }
 
 func (holyPriest *HolyPriest) Initialize() {
+	holyPriest.CurrentTarget = holyPriest.GetMainTarget()
 	holyPriest.Priest.Initialize()
+	holyPriest.RegisterHealingSpells()
 
-	// holyPriest.RegisterHolyFireSpell()
-	// holyPriest.RegisterSmiteSpell()
-	// holyPriest.RegisterPenanceSpell()
-	// holyPriest.RegisterHymnOfHopeCD()
+	holyPriest.RegisterHolyFireSpell()
+	holyPriest.RegisterSmiteSpell()
+	holyPriest.RegisterHymnOfHopeCD()
 }
 
 func (holyPriest *HolyPriest) Reset(sim *core.Simulation) {
-	//holyPriest.Priest.Reset(sim)
+	holyPriest.Priest.Reset(sim)
+}
+
+func getMasteryBonus(masteryPoints float64) float64 {
+	return (10 + masteryPoints*1.25) / 100
+}
+
+func (holyPriest *HolyPriest) ApplyTalents() {
+	holyPriest.Priest.ApplyTalents()
+
+	// Spiritual Healing
+	holyPriest.AddStaticMod(core.SpellModConfig{
+		ProcMask:   core.ProcMaskSpellHealing,
+		FloatValue: 0.15,
+		Kind:       core.SpellMod_DamageDone_Pct,
+	})
+
+	holyPriest.applyEchoOfLight()
+}
+
+// Mastery: Echo of Light, direct heals also heal the target over 6 sec.
+func (holyPriest *HolyPriest) applyEchoOfLight() {
+	echoOfLight := holyPriest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 77489},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagIgnoreModifiers,
+		ClassSpellMask: priest.PriestSpellEchoOfLight,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Echo of Light",
+			},
+			NumberOfTicks: 6,
+			TickLength:    time.Second,
+
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.Hot(target).Apply(sim)
+		},
+	})
+
+	core.MakeProcTriggerAura(&holyPriest.Unit, core.ProcTrigger{
+		Name:           "Echo of Light Trigger",
+		ActionID:       core.ActionID{SpellID: 77485},
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: priest.PriestHealingSpells,
+		Harmful:        true,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			hot := echoOfLight.Hot(result.Target)
+			newHealing := result.Damage * getMasteryBonus(holyPriest.GetMasteryPoints())
+
+			// Echo of Light rolls any remaining healing into the new application.
+			ticks := float64(hot.BaseTickCount + core.TernaryInt32(hot.IsActive(), 1, 0))
+			hot.SnapshotBaseDamage = (hot.OutstandingDmg() + newHealing) / ticks
+			hot.SnapshotAttackerMultiplier = 1
+			echoOfLight.Cast(sim, result.Target)
+		},
+	})
 }
@@ -0,0 +1,54 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (priest *Priest) registerInnerFocusSpell() {
+	if !priest.Talents.InnerFocus {
+		return
+	}
+
+	actionID := core.ActionID{SpellID: 89485}
+	affectedSpells := PriestSpellFlashHeal | PriestSpellBindingHeal | PriestSpellGreaterHeal | PriestSpellPrayerOfHealing
+
+	priest.InnerFocusAura = priest.RegisterAura(core.Aura{
+		Label:    "Inner Focus",
+		ActionID: actionID,
+		Duration: core.NeverExpires,
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.ClassSpellMask&affectedSpells != 0 {
+				aura.Deactivate(sim)
+			}
+		},
+	})
+	priest.InnerFocusAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_PowerCost_Pct,
+		ClassMask:  affectedSpells,
+		FloatValue: -1,
+	})
+	priest.InnerFocusAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_BonusCrit_Percent,
+		ClassMask:  affectedSpells,
+		FloatValue: 25,
+	})
+
+	priest.InnerFocus = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		Flags:          core.SpellFlagNoOnCastComplete | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellInnerFocus,
+
+		Cast: core.CastConfig{
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 45,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
+			priest.InnerFocusAura.Activate(sim)
+		},
+	})
+}
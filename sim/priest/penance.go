@@ -0,0 +1,112 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (priest *Priest) registerPenanceHealSpell() {
+	priest.PenanceHeal = priest.makePenanceSpell(true)
+}
+
+func (priest *Priest) RegisterPenanceSpell() {
+	priest.Penance = priest.makePenanceSpell(false)
+}
+
+// The damage and healing versions of Penance share a cooldown.
+func (priest *Priest) penanceCooldownTimer() *core.Timer {
+	if priest.penanceTimer == nil {
+		priest.penanceTimer = priest.NewTimer()
+	}
+	return priest.penanceTimer
+}
+
+func (priest *Priest) makePenanceSpell(isHeal bool) *core.Spell {
+	// Penance is a Discipline specialization spell.
+	if priest.Spec != proto.Spec_SpecDisciplinePriest {
+		return nil
+	}
+
+	actionID := core.ActionID{SpellID: 47540, Tag: core.TernaryInt32(isHeal, 1, 0)}
+	flags := core.SpellFlagChanneled | core.SpellFlagAPL
+	procMask := core.ProcMaskSpellDamage
+	classMask := PriestSpellPenance
+	critMultiplier := priest.DefaultSpellCritMultiplier()
+	if isHeal {
+		flags |= core.SpellFlagHelpful
+		procMask = core.ProcMaskSpellHealing
+		classMask = PriestSpellPenanceHeal
+		critMultiplier = priest.DefaultHealingCritMultiplier()
+	}
+
+	// Penance fires one bolt right away and two more over the channel.
+	dotConfig := core.DotConfig{
+		Aura: core.Aura{
+			Label: "Penance" + core.Ternary(isHeal, "Heal", "") + "-" + priest.Label,
+		},
+		NumberOfTicks:       2,
+		TickLength:          time.Second,
+		AffectedByCastSpeed: true,
+	}
+	if isHeal {
+		dotConfig.OnTick = func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+			baseHealing := priest.CalcAndRollDamageRange(sim, 3.176, 0.122)
+			dot.Spell.CalcAndDealHealing(sim, target, baseHealing, dot.Spell.OutcomeHealingCrit)
+		}
+	} else {
+		dotConfig.OnTick = func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+			baseDamage := priest.CalcAndRollDamageRange(sim, 0.753, 0.122)
+			dot.Spell.CalcAndDealDamage(sim, target, baseDamage, dot.Spell.OutcomeMagicHitAndCrit)
+		}
+	}
+
+	return priest.RegisterSpell(core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       procMask,
+		Flags:          flags,
+		ClassSpellMask: classMask,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           critMultiplier,
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.14,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.penanceCooldownTimer(),
+				Duration: time.Second * 12,
+			},
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: core.TernaryFloat64(isHeal, 0.321, 0.229),
+
+		Dot: core.Ternary(!isHeal, dotConfig, core.DotConfig{}),
+		Hot: core.Ternary(isHeal, dotConfig, core.DotConfig{}),
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			if isHeal {
+				hot := spell.Hot(target)
+				hot.Apply(sim)
+				hot.TickOnce(sim)
+				return
+			}
+
+			result := spell.CalcOutcome(sim, target, spell.OutcomeMagicHitNoHitCounter)
+			if result.Landed() {
+				dot := spell.Dot(target)
+				dot.Apply(sim)
+				dot.TickOnce(sim)
+			}
+			spell.DealOutcome(sim, result)
+		},
+	})
+}
@@ -0,0 +1,81 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (priest *Priest) registerPowerWordShieldSpell() {
+	priest.WeakenedSouls = priest.NewAllyAuraArray(func(target *core.Unit) *core.Aura {
+		return target.GetOrRegisterAura(core.Aura{
+			Label:    "Weakened Soul",
+			ActionID: core.ActionID{SpellID: 6788},
+			Duration: time.Second * 15,
+		})
+	})
+
+	var glyphHeal *core.Spell
+	if priest.HasPrimeGlyph(proto.PriestPrimeGlyph_GlyphOfPowerWordShield) {
+		glyphHeal = priest.RegisterSpell(core.SpellConfig{
+			ActionID:       core.ActionID{SpellID: 56160},
+			SpellSchool:    core.SpellSchoolHoly,
+			ProcMask:       core.ProcMaskSpellHealing,
+			Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagNoSpellMods,
+			ClassSpellMask: PriestSpellPowerWordShield,
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+		})
+	}
+
+	priest.PowerWordShield = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 17},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPowerWordShield,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.34,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 4,
+			},
+		},
+		ExtraCastCondition: func(sim *core.Simulation, target *core.Unit) bool {
+			return !priest.WeakenedSouls.Get(target).IsActive()
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.87,
+
+		Shield: core.ShieldConfig{
+			AbsorbsDamage: true,
+			Aura: core.Aura{
+				Label:    "Power Word Shield",
+				Duration: time.Second * 30,
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			shieldAmount := priest.CalcScalingSpellDmg(8.609) + spell.HealingPower(target)*spell.BonusCoefficient
+			spell.Shield(target).Apply(sim, shieldAmount)
+			if glyphHeal != nil {
+				// Heals for 20% of the absorb, which has already had the shield's multipliers applied.
+				glyphAmount := shieldAmount * spell.DamageMultiplier * spell.DamageMultiplierAdditive * 0.2
+				glyphHeal.CalcAndDealHealing(sim, target, glyphAmount, glyphHeal.OutcomeHealing)
+			}
+			priest.WeakenedSouls.Get(target).Activate(sim)
+		},
+	})
+}
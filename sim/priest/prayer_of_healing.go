@@ -0,0 +1,89 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (priest *Priest) registerPrayerOfHealingSpell() {
+	var glyphHot *core.Spell
+	if priest.HasPrimeGlyph(proto.PriestPrimeGlyph_GlyphOfPrayerOfHealing) {
+		glyphHot = priest.RegisterSpell(core.SpellConfig{
+			ActionID:       core.ActionID{SpellID: 56161},
+			SpellSchool:    core.SpellSchoolHoly,
+			ProcMask:       core.ProcMaskSpellHealing,
+			Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagNoSpellMods,
+			ClassSpellMask: PriestSpellPrayerOfHealing,
+
+			DamageMultiplier: 1,
+			ThreatMultiplier: 1,
+
+			Hot: core.DotConfig{
+				Aura: core.Aura{
+					Label: "Glyph of Prayer of Healing",
+				},
+				NumberOfTicks: 2,
+				TickLength:    time.Second * 3,
+				OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+					dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeTick)
+				},
+			},
+		})
+	}
+
+	priest.PrayerOfHealing = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 596},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPrayerOfHealing,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.26,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.34,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			for _, partyTarget := range priest.getPartyTargets(target) {
+				baseHealing := priest.CalcAndRollDamageRange(sim, 3.37, 0.055)
+				result := spell.CalcAndDealHealing(sim, partyTarget, baseHealing, spell.OutcomeHealingCrit)
+
+				if glyphHot != nil {
+					// Heals for an additional 20% of the amount healed over 6 sec.
+					hot := glyphHot.Hot(partyTarget)
+					hot.SnapshotBaseDamage = result.Damage * 0.2 / float64(hot.BaseTickCount)
+					hot.SnapshotAttackerMultiplier = 1
+					hot.Apply(sim)
+				}
+			}
+		},
+	})
+}
+
+// Returns the members of the target's party, or just the target if it isn't in one.
+func (priest *Priest) getPartyTargets(target *core.Unit) []*core.Unit {
+	agent := priest.Env.Raid.GetPlayerFromUnit(target)
+	if agent == nil || agent.GetCharacter().Party == nil {
+		return []*core.Unit{target}
+	}
+
+	var targets []*core.Unit
+	for _, member := range agent.GetCharacter().Party.PlayersAndPets {
+		targets = append(targets, &member.GetCharacter().Unit)
+	}
+	return targets
+}
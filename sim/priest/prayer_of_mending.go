@@ -0,0 +1,113 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (priest *Priest) registerPrayerOfMendingSpell() {
+	const maxCharges = 5
+
+	firstHealMultiplier := 1.0
+	if priest.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfPrayerOfMending) {
+		firstHealMultiplier = 1.6
+	}
+
+	var pomAuras core.AuraArray
+
+	healSpell := priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33110},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete,
+		ClassSpellMask: PriestSpellPrayerOfMending,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ThreatMultiplier:         1,
+		BonusCoefficient:         0.318,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := priest.CalcScalingSpellDmg(3.15)
+			if pomAuras.Get(target).GetStacks() == maxCharges {
+				baseHealing *= firstHealMultiplier
+			}
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	})
+
+	// Jumps to the most injured ally other than the one which was just healed.
+	nextTarget := func(current *core.Unit) *core.Unit {
+		targets := priest.GetSmartHealTargets(current, 2)
+		if len(targets) < 2 {
+			return current
+		}
+		return targets[1]
+	}
+
+	pomAuras = priest.NewAllyAuraArray(func(target *core.Unit) *core.Aura {
+		return target.RegisterAura(core.Aura{
+			Label:     "PrayerOfMending-" + priest.Label,
+			ActionID:  core.ActionID{SpellID: 41635},
+			Duration:  time.Second * 30,
+			MaxStacks: maxCharges,
+			OnSpellHitTaken: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+				if !result.Landed() || result.Damage <= 0 || spell.Flags.Matches(core.SpellFlagHelpful) {
+					return
+				}
+
+				healSpell.Cast(sim, aura.Unit)
+				charges := aura.GetStacks() - 1
+				aura.Deactivate(sim)
+				if charges <= 0 {
+					return
+				}
+
+				next := pomAuras.Get(nextTarget(aura.Unit))
+				next.Activate(sim)
+				next.SetStacks(sim, charges)
+			},
+		})
+	})
+
+	priest.PrayerOfMending = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 33076},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellPrayerOfMending,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.18,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+			CD: core.Cooldown{
+				Timer:    priest.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			// Only one Prayer of Mending can be active at a time.
+			for _, aura := range pomAuras {
+				if aura != nil {
+					aura.Deactivate(sim)
+				}
+			}
+
+			aura := pomAuras.Get(target)
+			aura.Activate(sim)
+			aura.SetStacks(sim, maxCharges)
+		},
+		RelatedAuraArrays: pomAuras.ToMap(),
+	})
+}
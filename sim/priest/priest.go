@@ -30,28 +30,41 @@ type Priest struct {
 
 	SurgeOfLightProcAura *core.Aura
 
+	InnerFocusAura  *core.Aura
+	SerendipityAura *core.Aura
+	ChakraAura      *core.Aura
+
+	ChakraSerenityAura  *core.Aura
+	ChakraSanctuaryAura *core.Aura
+	ChakraChastiseAura  *core.Aura
+
 	// might want to move these spell / talents into spec specific initialization
-	BindingHeal     *core.Spell
-	CircleOfHealing *core.Spell
-	FlashHeal       *core.Spell
-	GreaterHeal     *core.Spell
-	Penance         *core.Spell
-	PenanceHeal     *core.Spell
-	PowerWordShield *core.Spell
-	PrayerOfHealing *core.Spell
-	PrayerOfMending *core.Spell
-	Renew           *core.Spell
-	EmpoweredRenew  *core.Spell
-	InnerFocus      *core.Spell
-	HolyFire        *core.Spell
-	Smite           *core.Spell
-	DevouringPlague *core.Spell
-	ShadowWordPain  *core.Spell
-	Shadowfiend     *core.Spell
-	VampiricTouch   *core.Spell
+	BindingHeal      *core.Spell
+	CircleOfHealing  *core.Spell
+	FlashHeal        *core.Spell
+	GreaterHeal      *core.Spell
+	Heal             *core.Spell
+	HolyWordSerenity *core.Spell
+	Penance          *core.Spell
+	PenanceHeal      *core.Spell
+	PowerWordShield  *core.Spell
+	PrayerOfHealing  *core.Spell
+	PrayerOfMending  *core.Spell
+	Renew            *core.Spell
+	EmpoweredRenew   *core.Spell
+	InnerFocus       *core.Spell
+	Chakra           *core.Spell
+	HolyFire         *core.Spell
+	Smite            *core.Spell
+	DevouringPlague  *core.Spell
+	ShadowWordPain   *core.Spell
+	Shadowfiend      *core.Spell
+	VampiricTouch    *core.Spell
 
 	WeakenedSouls core.AuraArray
 
+	penanceTimer *core.Timer
+
 	ProcPrayerOfMending core.ApplySpellResults
 
 	ClassSpellScaling float64
@@ -87,6 +100,12 @@ func (priest *Priest) GetCharacter() *core.Character {
 func (priest *Priest) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
+func (priest *Priest) AddRosterBuffs(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+	raidBuffs.PowerWordFortitude = true
+	raidBuffs.ShadowProtection = true
+	raidBuffs.ShadowForm = priest.Talents.Shadowform
+}
+
 func (priest *Priest) Initialize() {
 	if priest.SelfBuffs.UseInnerFire {
 		priest.AddStat(stats.SpellPower, 531)
@@ -113,17 +132,20 @@ func (priest *Priest) Initialize() {
 	priest.newMindSearSpell()
 }
 
-// func (priest *Priest) RegisterHealingSpells() {
-// 	priest.registerPenanceHealSpell()
-// 	priest.registerBindingHealSpell()
-// 	priest.registerCircleOfHealingSpell()
-// 	priest.registerFlashHealSpell()
-// 	priest.registerGreaterHealSpell()
-// 	priest.registerPowerWordShieldSpell()
-// 	priest.registerPrayerOfHealingSpell()
-// 	priest.registerPrayerOfMendingSpell()
-// 	priest.registerRenewSpell()
-// }
+func (priest *Priest) RegisterHealingSpells() {
+	priest.registerPenanceHealSpell()
+	priest.registerBindingHealSpell()
+	priest.registerCircleOfHealingSpell()
+	priest.registerFlashHealSpell()
+	priest.registerGreaterHealSpell()
+	priest.registerHealSpell()
+	priest.registerPowerWordShieldSpell()
+	priest.registerPrayerOfHealingSpell()
+	priest.registerPrayerOfMendingSpell()
+	priest.registerRenewSpell()
+	priest.registerInnerFocusSpell()
+	priest.registerChakraSpell()
+}
 
 func (priest *Priest) AddHolyEvanglismStack(sim *core.Simulation) {
 	if priest.HolyEvangelismProcAura != nil {
@@ -175,18 +197,22 @@ const (
 	PriestSpellFlagNone  int64 = 0
 	PriestSpellArchangel int64 = 1 << iota
 	PriestSpellDarkArchangel
+	PriestSpellAtonement
 	PriestSpellBindingHeal
+	PriestSpellChakra
 	PriestSpellCircleOfHealing
 	PriestSpellDevouringPlague
 	PriestSpellDesperatePrayer
 	PriestSpellDispersion
 	PriestSpellDivineAegis
 	PriestSpellDivineHymn
+	PriestSpellEchoOfLight
 	PriestSpellEmpoweredRenew
 	PriestSpellFade
 	PriestSpellFlashHeal
 	PriestSpellGreaterHeal
 	PriestSpellGuardianSpirit
+	PriestSpellHeal
 	PriestSpellHolyFire
 	PriestSpellHolyNova
 	PriestSpellHolyWordChastise
@@ -205,6 +231,7 @@ const (
 	PriestSpellMindTrauma
 	PriestSpellPainSuppresion
 	PriestSpellPenance
+	PriestSpellPenanceHeal
 	PriestSpellPowerInfusion
 	PriestSpellPowerWordBarrier
 	PriestSpellPowerWordShield
@@ -252,6 +279,20 @@ const (
 		PriestSpellMindSear |
 		PriestSpellMindSpike |
 		PriestSpellVampiricTouch
+	PriestSpellDirectHeal = PriestSpellBindingHeal |
+		PriestSpellFlashHeal |
+		PriestSpellGreaterHeal |
+		PriestSpellHeal |
+		PriestSpellHolyWordSerenity
+	PriestSpellAoEHeal = PriestSpellCircleOfHealing |
+		PriestSpellDivineHymn |
+		PriestSpellPrayerOfHealing |
+		PriestSpellPrayerOfMending
+	PriestHealingSpells = PriestSpellDirectHeal |
+		PriestSpellAoEHeal |
+		PriestSpellPenanceHeal |
+		PriestSpellPowerWordShield |
+		PriestSpellRenew
 )
 
 func (priest *Priest) calcBaseDamage(sim *core.Simulation, coefficient float64, variance float64) float64 {
@@ -0,0 +1,78 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (priest *Priest) registerRenewSpell() {
+	var divineTouch *core.Spell
+	if priest.Talents.DivineTouch > 0 {
+		divineTouch = priest.RegisterSpell(core.SpellConfig{
+			ActionID:       core.ActionID{SpellID: 63544},
+			SpellSchool:    core.SpellSchoolHoly,
+			ProcMask:       core.ProcMaskSpellHealing,
+			Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete,
+			ClassSpellMask: PriestSpellEmpoweredRenew,
+
+			DamageMultiplier:         1,
+			DamageMultiplierAdditive: 1,
+			CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+			ThreatMultiplier:         1,
+		})
+		priest.EmpoweredRenew = divineTouch
+	}
+
+	priest.Renew = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 139},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellRenew,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.17,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD: core.GCDDefault,
+			},
+		},
+		ThreatMultiplier: 1,
+
+		Hot: core.DotConfig{
+			Aura: core.Aura{
+				Label: "Renew",
+			},
+			NumberOfTicks:    4,
+			TickLength:       time.Second * 3,
+			BonusCoefficient: 0.131,
+
+			OnSnapshot: func(sim *core.Simulation, target *core.Unit, dot *core.Dot, isRollover bool) {
+				if !isRollover {
+					dot.SnapshotHeal(target, priest.CalcScalingSpellDmg(1.307))
+				}
+			},
+			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
+				dot.CalcAndDealPeriodicSnapshotHealing(sim, target, dot.OutcomeSnapshotCrit)
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			hot := spell.Hot(target)
+			hot.Apply(sim)
+
+			if divineTouch != nil {
+				// Divine Touch instantly heals for a portion of Renew's total healing.
+				baseHealing := hot.SnapshotBaseDamage * float64(hot.BaseTickCount) * 0.05 * float64(priest.Talents.DivineTouch)
+				divineTouch.CalcAndDealHealing(sim, target, baseHealing, divineTouch.OutcomeHealingCrit)
+			}
+		},
+	})
+}
@@ -0,0 +1,54 @@
+package priest
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (priest *Priest) RegisterSmiteSpell() {
+	hasGlyph := priest.HasMajorGlyph(proto.PriestMajorGlyph_GlyphOfSmite)
+
+	priest.Smite = priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 585},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellDamage,
+		Flags:          core.SpellFlagAPL,
+		ClassSpellMask: PriestSpellSmite,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultSpellCritMultiplier(),
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.15,
+			Multiplier: 1,
+		},
+
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.856,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			// Glyph of Smite increases damage done while Holy Fire is on the target.
+			glyphBonus := 0.0
+			if hasGlyph && priest.HolyFire != nil && priest.HolyFire.Dot(target).IsActive() {
+				glyphBonus = 0.2
+			}
+
+			spell.DamageMultiplierAdditive += glyphBonus
+			baseDamage := priest.CalcAndRollDamageRange(sim, 0.793, 0.114)
+			spell.CalcAndDealDamage(sim, target, baseDamage, spell.OutcomeMagicHitAndCrit)
+			spell.DamageMultiplierAdditive -= glyphBonus
+		},
+		ExpectedInitialDamage: func(sim *core.Simulation, target *core.Unit, spell *core.Spell, _ bool) *core.SpellResult {
+			baseDamage := priest.CalcScalingSpellDmg(0.793)
+			return spell.CalcDamage(sim, target, baseDamage, spell.OutcomeExpectedMagicHitAndCrit)
+		},
+	})
+}
@@ -13,22 +13,16 @@ func (priest *Priest) ApplyTalents() {
 	priest.ApplyArmorSpecializationEffect(stats.Intellect, proto.ArmorType_ArmorTypeCloth, 89745)
 	// TODO:
 	// Reflective Shield
-	// Improved Flash Heal
 	// Renewed Hope
 	// Rapture
 	// Pain Suppression
+	// Power Word: Barrier
+	// Inspiration
+	// Lightwell
+	// Body and Soul
 	// Test of Faith
 	// Guardian Spirit
 
-	// priest.applyDivineAegis()
-	// priest.applyGrace()
-	// priest.applyBorrowedTime()
-	// priest.applyInspiration()
-	// priest.applyHolyConcentration()
-	// priest.applySerendipity()
-	// priest.applySurgeOfLight()
-	// priest.registerInnerFocus()
-
 	// priest.AddStat(stats.SpellCrit, 1*float64(priest.Talents.FocusedWill)*core.CritRatingPerCritChance)
 	// priest.PseudoStats.SpiritRegenRateCasting = []float64{0.0, 0.17, 0.33, 0.5}[priest.Talents.Meditation]
 	// priest.PseudoStats.SchoolDamageTakenMultiplier[stats.SchoolIndexArcane] *= 1 - .02*float64(priest.Talents.SpellWarding)
@@ -64,7 +58,15 @@ func (priest *Priest) ApplyTalents() {
 	// }
 
 	// Disciplin Talents
-	// Improved Power Word: Shield - TBD
+	// Improved Power Word: Shield
+	if priest.Talents.ImprovedPowerWordShield > 0 {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask:  PriestSpellPowerWordShield,
+			FloatValue: 0.1 * float64(priest.Talents.ImprovedPowerWordShield),
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
 	// Twin Disciplines
 	if priest.Talents.TwinDisciplines > 0 {
 		priest.AddStaticMod(core.SpellModConfig{
@@ -90,6 +92,93 @@ func (priest *Priest) ApplyTalents() {
 	// Archangel
 	priest.applyArchangel()
 
+	// Soul Warding
+	if priest.Talents.SoulWarding > 0 {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask: PriestSpellPowerWordShield,
+			TimeValue: time.Second * -1 * time.Duration(priest.Talents.SoulWarding),
+			Kind:      core.SpellMod_Cooldown_Flat,
+		})
+	}
+
+	// Atonement
+	priest.applyAtonement()
+
+	// Inner Focus - inner_focus.go
+	// Borrowed Time
+	priest.applyBorrowedTime()
+
+	// Strength of Soul
+	priest.applyStrengthOfSoul()
+
+	// Divine Aegis
+	priest.applyDivineAegis()
+
+	// Train of Thought
+	priest.applyTrainOfThought()
+
+	// Grace
+	priest.applyGrace()
+
+	// Holy Talents
+	// Improved Renew
+	if priest.Talents.ImprovedRenew > 0 {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask:  PriestSpellRenew,
+			FloatValue: 0.05 * float64(priest.Talents.ImprovedRenew),
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
+	// Empowered Healing
+	if priest.Talents.EmpoweredHealing > 0 {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask:  PriestSpellFlashHeal | PriestSpellGreaterHeal | PriestSpellBindingHeal | PriestSpellHeal,
+			FloatValue: 0.05 * float64(priest.Talents.EmpoweredHealing),
+			Kind:       core.SpellMod_DamageDone_Flat,
+		})
+	}
+
+	// Divine Fury
+	if priest.Talents.DivineFury > 0 {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask: PriestSpellSmite | PriestSpellHolyFire | PriestSpellHeal | PriestSpellGreaterHeal,
+			TimeValue: []time.Duration{0, -150, -350, -500}[priest.Talents.DivineFury] * time.Millisecond,
+			Kind:      core.SpellMod_CastTime_Flat,
+		})
+	}
+
+	// Surge of Light
+	priest.applySurgeOfLight()
+
+	// Divine Touch - renew.go
+	// Holy Concentration
+	priest.PseudoStats.SpiritRegenRateCombat += 0.15 * float64(priest.Talents.HolyConcentration)
+
+	// Tome of Light
+	if priest.Talents.TomeOfLight > 0 {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask:  PriestSpellHolyWordChastise | PriestSpellHolyWordSanctuary | PriestSpellHolyWordSerenity,
+			FloatValue: -0.15 * float64(priest.Talents.TomeOfLight),
+			Kind:       core.SpellMod_Cooldown_Multiplier,
+		})
+	}
+
+	// Rapid Renewal
+	if priest.Talents.RapidRenewal {
+		priest.AddStaticMod(core.SpellModConfig{
+			ClassMask: PriestSpellRenew,
+			TimeValue: time.Millisecond * -500,
+			Kind:      core.SpellMod_GlobalCooldown_Flat,
+		})
+	}
+
+	// Serendipity
+	priest.applySerendipity()
+
+	// Chakra, Revelations - chakra.go
+	// Circle of Healing - circle_of_healing.go
+
 	// Shadow Talents
 	// Darkness
 	if priest.Talents.Darkness > 0 {
@@ -272,6 +361,11 @@ func (priest *Priest) applyArchangel() {
 	archAngelMana := priest.NewManaMetrics(core.ActionID{SpellID: 87152})
 	darkArchAngelMana := priest.NewManaMetrics(core.ActionID{SpellID: 87153})
 
+	archAngelHealingMod := priest.AddDynamicMod(core.SpellModConfig{
+		ProcMask: core.ProcMaskSpellHealing,
+		Kind:     core.SpellMod_DamageDone_Flat,
+	})
+
 	archAngelAura := priest.Unit.RegisterAura(core.Aura{
 		ActionID:  core.ActionID{SpellID: 81700},
 		Label:     "Archangel Aura",
@@ -282,7 +376,12 @@ func (priest *Priest) applyArchangel() {
 				priest.AddMana(sim, 0.01*priest.MaxMana()*float64((newStacks-oldStacks)), archAngelMana)
 			}
 
-			// place holder mod healing done not present yet
+			archAngelHealingMod.UpdateFloatValue(0.03 * float64(newStacks))
+			archAngelHealingMod.Activate()
+		},
+
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			archAngelHealingMod.Deactivate()
 		},
 	})
 
@@ -688,436 +787,289 @@ func (priest *Priest) applyShadowyApparition() {
 	})
 }
 
-// func (priest *Priest) applyDivineAegis() {
-// 	if priest.Talents.DivineAegis == 0 {
-// 		return
-// 	}
-
-// 	divineAegis := priest.RegisterSpell(core.SpellConfig{
-// 		ActionID:    core.ActionID{SpellID: 47515},
-// 		SpellSchool: core.SpellSchoolHoly,
-// 		ProcMask:    core.ProcMaskSpellHealing,
-// 		Flags:       core.SpellFlagNoOnCastComplete | core.SpellFlagHelpful,
-
-// 		DamageMultiplier: 1 *
-// 			(0.1 * float64(priest.Talents.DivineAegis)) *
-// 			core.TernaryFloat64(priest.CouldHaveSetBonus(ItemSetZabrasRaiment, 4), 1.1, 1),
-// 		ThreatMultiplier: 1,
-
-// 		Shield: core.ShieldConfig{
-// 			Aura: core.Aura{
-// 				Label:    "Divine Aegis",
-// 				Duration: time.Second * 12,
-// 			},
-// 		},
-// 	})
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Divine Aegis Talent",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 			if result.Outcome.Matches(core.OutcomeCrit) {
-// 				divineAegis.Shield(result.Target).Apply(sim, result.Damage)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applyGrace() {
-// 	if priest.Talents.Grace == 0 {
-// 		return
-// 	}
-
-// 	procChance := .5 * float64(priest.Talents.Grace)
-
-// 	auras := make([]*core.Aura, len(priest.Env.AllUnits))
-// 	for _, unit := range priest.Env.AllUnits {
-// 		if !priest.IsOpponent(unit) {
-// 			aura := unit.RegisterAura(core.Aura{
-// 				Label:     "Grace" + strconv.Itoa(int(priest.Index)),
-// 				ActionID:  core.ActionID{SpellID: 47517},
-// 				Duration:  time.Second * 15,
-// 				MaxStacks: 3,
-// 				OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks, newStacks int32) {
-// 					priest.AttackTables[aura.Unit.UnitIndex].HealingDealtMultiplier /= 1 + .03*float64(oldStacks)
-// 					priest.AttackTables[aura.Unit.UnitIndex].HealingDealtMultiplier *= 1 + .03*float64(newStacks)
-// 				},
-// 			})
-// 			auras[unit.UnitIndex] = aura
-// 		}
-// 	}
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Grace Talent",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 			if spell == priest.FlashHeal || spell == priest.GreaterHeal || spell == priest.PenanceHeal {
-// 				if sim.Proc(procChance, "Grace") {
-// 					aura := auras[result.Target.UnitIndex]
-// 					aura.Activate(sim)
-// 					aura.AddStack(sim)
-// 				}
-// 			}
-// 		},
-// 	})
-// }
-
-// // This one is called from healing priest sim initialization because it needs an input.
-// func (priest *Priest) ApplyRapture(ppm float64) {
-// 	if priest.Talents.Rapture == 0 {
-// 		return
-// 	}
-
-// 	if ppm <= 0 {
-// 		return
-// 	}
-
-// 	raptureManaCoeff := []float64{0, .015, .020, .025}[priest.Talents.Rapture]
-// 	raptureMetrics := priest.NewManaMetrics(core.ActionID{SpellID: 47537})
-
-// 	priest.RegisterResetEffect(func(sim *core.Simulation) {
-// 		core.StartPeriodicAction(sim, core.PeriodicActionOptions{
-// 			Period: time.Minute / time.Duration(ppm),
-// 			OnAction: func(sim *core.Simulation) {
-// 				priest.AddMana(sim, raptureManaCoeff*priest.MaxMana(), raptureMetrics)
-// 			},
-// 		})
-// 	})
-// }
-
-// func (priest *Priest) applyBorrowedTime() {
-// 	if priest.Talents.BorrowedTime == 0 {
-// 		return
-// 	}
-
-// 	multiplier := 1 + .05*float64(priest.Talents.BorrowedTime)
-
-// 	procAura := priest.RegisterAura(core.Aura{
-// 		Label:    "Borrowed Time",
-// 		ActionID: core.ActionID{SpellID: 52800},
-// 		Duration: time.Second * 6,
-// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-// 			priest.MultiplyCastSpeed(multiplier)
-// 		},
-// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-// 			priest.MultiplyCastSpeed(1 / multiplier)
-// 		},
-// 	})
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Borrwed Time Talent",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
-// 			if spell == priest.PowerWordShield {
-// 				procAura.Activate(sim)
-// 			} else if spell.CurCast.CastTime > 0 {
-// 				procAura.Deactivate(sim)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applyInspiration() {
-// 	if priest.Talents.Inspiration == 0 {
-// 		return
-// 	}
-
-// 	auras := make([]*core.Aura, len(priest.Env.AllUnits))
-// 	for _, unit := range priest.Env.AllUnits {
-// 		if !priest.IsOpponent(unit) {
-// 			aura := core.InspirationAura(unit, priest.Talents.Inspiration)
-// 			auras[unit.UnitIndex] = aura
-// 		}
-// 	}
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Inspiration Talent",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 			if spell == priest.FlashHeal ||
-// 				spell == priest.GreaterHeal ||
-// 				spell == priest.BindingHeal ||
-// 				spell == priest.PrayerOfMending ||
-// 				spell == priest.PrayerOfHealing ||
-// 				spell == priest.CircleOfHealing ||
-// 				spell == priest.PenanceHeal {
-// 				auras[result.Target.UnitIndex].Activate(sim)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applyHolyConcentration() {
-// 	if priest.Talents.HolyConcentration == 0 {
-// 		return
-// 	}
-
-// 	multiplier := 1 + []float64{0, .16, .32, .50}[priest.Talents.HolyConcentration]
-
-// 	procAura := priest.RegisterAura(core.Aura{
-// 		Label:    "Holy Concentration",
-// 		ActionID: core.ActionID{SpellID: 34860},
-// 		Duration: time.Second * 8,
-// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-// 			priest.PseudoStats.SpiritRegenMultiplier *= multiplier
-// 		},
-// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-// 			priest.PseudoStats.SpiritRegenMultiplier /= multiplier
-// 		},
-// 	})
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Holy Concentration Talent",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 			if result.DidCrit() &&
-// 				(spell == priest.FlashHeal || spell == priest.GreaterHeal || spell == priest.EmpoweredRenew) {
-// 				procAura.Activate(sim)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applySerendipity() {
-// 	if priest.Talents.Serendipity == 0 {
-// 		return
-// 	}
-
-// 	reductionPerStack := .04 * float64(priest.Talents.Serendipity)
-
-// 	procAura := priest.RegisterAura(core.Aura{
-// 		Label:     "Serendipity",
-// 		ActionID:  core.ActionID{SpellID: 63737},
-// 		Duration:  time.Second * 20,
-// 		MaxStacks: 3,
-// 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks, newStacks int32) {
-// 			priest.PrayerOfHealing.CastTimeMultiplier += reductionPerStack * float64(oldStacks)
-// 			priest.PrayerOfHealing.CastTimeMultiplier -= reductionPerStack * float64(newStacks)
-// 			priest.GreaterHeal.CastTimeMultiplier += reductionPerStack * float64(oldStacks)
-// 			priest.GreaterHeal.CastTimeMultiplier -= reductionPerStack * float64(newStacks)
-// 		},
-// 	})
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Serendipity Talent",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
-// 			if spell == priest.FlashHeal || spell == priest.BindingHeal {
-// 				procAura.Activate(sim)
-// 				procAura.AddStack(sim)
-// 			} else if spell == priest.GreaterHeal || spell == priest.PrayerOfHealing {
-// 				procAura.Deactivate(sim)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applySurgeOfLight() {
-// 	if priest.Talents.SurgeOfLight == 0 {
-// 		return
-// 	}
-
-// 	procHandler := func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 		if spell == priest.Smite || spell == priest.FlashHeal {
-// 			aura.Deactivate(sim)
-// 		}
-// 	}
-
-// 	priest.SurgeOfLightProcAura = priest.RegisterAura(core.Aura{
-// 		Label:    "Surge of Light Proc",
-// 		ActionID: core.ActionID{SpellID: 33154},
-// 		Duration: time.Second * 10,
-// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-// 			if priest.Smite != nil {
-// 				priest.Smite.CastTimeMultiplier -= 1
-// 				priest.Smite.CostMultiplier -= 1
-// 				priest.Smite.BonusCritRating -= 100 * core.CritRatingPerCritChance
-// 			}
-// 			if priest.FlashHeal != nil {
-// 				priest.FlashHeal.CastTimeMultiplier -= 1
-// 				priest.FlashHeal.CostMultiplier -= 1
-// 				priest.FlashHeal.BonusCritRating -= 100 * core.CritRatingPerCritChance
-// 			}
-// 		},
-// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-// 			if priest.Smite != nil {
-// 				priest.Smite.CastTimeMultiplier += 1
-// 				priest.Smite.CostMultiplier += 1
-// 				priest.Smite.BonusCritRating += 100 * core.CritRatingPerCritChance
-// 			}
-// 			if priest.FlashHeal != nil {
-// 				priest.FlashHeal.CastTimeMultiplier += 1
-// 				priest.FlashHeal.CostMultiplier += 1
-// 				priest.FlashHeal.BonusCritRating += 100 * core.CritRatingPerCritChance
-// 			}
-// 		},
-// 		OnSpellHitDealt: procHandler,
-// 		OnHealDealt:     procHandler,
-// 	})
-
-// 	procChance := 0.25 * float64(priest.Talents.SurgeOfLight)
-// 	icd := core.Cooldown{
-// 		Timer:    priest.NewTimer(),
-// 		Duration: time.Second * 6,
-// 	}
-// 	priest.SurgeOfLightProcAura.Icd = &icd
-
-// 	handler := func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 		if icd.IsReady(sim) && result.Outcome.Matches(core.OutcomeCrit) && sim.RandomFloat("SurgeOfLight") < procChance {
-// 			icd.Use(sim)
-// 			priest.SurgeOfLightProcAura.Activate(sim)
-// 		}
-// 	}
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Surge of Light",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnSpellHitDealt: handler,
-// 		OnHealDealt:     handler,
-// 	})
-// }
-
-// func (priest *Priest) applyMisery() {
-// 	if priest.Talents.Misery == 0 {
-// 		return
-// 	}
-
-// 	miseryAuras := priest.NewEnemyAuraArray(func(target *core.Unit) *core.Aura {
-// 		return core.MiseryAura(target, priest.Talents.Misery)
-// 	})
-// 	priest.Env.RegisterPreFinalizeEffect(func() {
-// 		priest.ShadowWordPain.RelatedAuras = append(priest.ShadowWordPain.RelatedAuras, miseryAuras)
-// 		if priest.VampiricTouch != nil {
-// 			priest.VampiricTouch.RelatedAuras = append(priest.VampiricTouch.RelatedAuras, miseryAuras)
-// 		}
-// 		if priest.MindFlay[1] != nil {
-// 			priest.MindFlayAPL.RelatedAuras = append(priest.MindFlayAPL.RelatedAuras, miseryAuras)
-// 			priest.MindFlay[1].RelatedAuras = append(priest.MindFlay[1].RelatedAuras, miseryAuras)
-// 			priest.MindFlay[2].RelatedAuras = append(priest.MindFlay[2].RelatedAuras, miseryAuras)
-// 			priest.MindFlay[3].RelatedAuras = append(priest.MindFlay[3].RelatedAuras, miseryAuras)
-// 		}
-// 	})
-
-// 	priest.RegisterAura(core.Aura{
-// 		Label:    "Priest Shadow Effects",
-// 		Duration: core.NeverExpires,
-// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Activate(sim)
-// 		},
-// 		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 			if !result.Landed() {
-// 				return
-// 			}
-
-// 			if spell == priest.ShadowWordPain || spell == priest.VampiricTouch || spell.ActionID.SpellID == priest.MindFlay[1].ActionID.SpellID {
-// 				miseryAuras.Get(result.Target).Activate(sim)
-// 			}
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applyShadowWeaving() {
-// 	if priest.Talents.ShadowWeaving == 0 {
-// 		return
-// 	}
-
-// 	priest.ShadowWeavingAura = priest.GetOrRegisterAura(core.Aura{
-// 		Label:     "Shadow Weaving",
-// 		ActionID:  core.ActionID{SpellID: 15258},
-// 		Duration:  time.Second * 15,
-// 		MaxStacks: 5,
-// 		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks int32, newStacks int32) {
-// 			aura.Unit.PseudoStats.SchoolDamageDealtMultiplier[stats.SchoolIndexShadow] /= 1.0 + 0.02*float64(oldStacks)
-// 			aura.Unit.PseudoStats.SchoolDamageDealtMultiplier[stats.SchoolIndexShadow] *= 1.0 + 0.02*float64(newStacks)
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) applyImprovedSpiritTap() {
-// 	if priest.Talents.ImprovedSpiritTap == 0 {
-// 		return
-// 	}
-
-// 	increase := 1 + 0.05*float64(priest.Talents.ImprovedSpiritTap)
-// 	statDep := priest.NewDynamicMultiplyStat(stats.Spirit, increase)
-// 	regen := []float64{0, 0.17, 0.33}[priest.Talents.ImprovedSpiritTap]
-
-// 	priest.ImprovedSpiritTap = priest.GetOrRegisterAura(core.Aura{
-// 		Label:    "Improved Spirit Tap",
-// 		ActionID: core.ActionID{SpellID: 59000},
-// 		Duration: time.Second * 8,
-// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-// 			priest.EnableDynamicStatDep(sim, statDep)
-// 			priest.PseudoStats.SpiritRegenRateCasting += regen
-// 		},
-// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-// 			priest.DisableDynamicStatDep(sim, statDep)
-// 			priest.PseudoStats.SpiritRegenRateCasting -= regen
-// 		},
-// 	})
-// }
-
-// func (priest *Priest) registerInnerFocus() {
-// 	if !priest.Talents.InnerFocus {
-// 		return
-// 	}
-
-// 	actionID := core.ActionID{SpellID: 14751}
-
-// 	priest.InnerFocusAura = priest.RegisterAura(core.Aura{
-// 		Label:    "Inner Focus",
-// 		ActionID: actionID,
-// 		Duration: core.NeverExpires,
-// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Unit.AddStatDynamic(sim, stats.SpellCrit, 25*core.CritRatingPerCritChance)
-// 			aura.Unit.PseudoStats.CostMultiplier -= 1
-// 		},
-// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-// 			aura.Unit.AddStatDynamic(sim, stats.SpellCrit, -25*core.CritRatingPerCritChance)
-// 			aura.Unit.PseudoStats.CostMultiplier += 1
-// 		},
-// 		OnSpellHitDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-// 			// Remove the buff and put skill on CD
-// 			aura.Deactivate(sim)
-// 			priest.InnerFocus.CD.Use(sim)
-// 			priest.UpdateMajorCooldowns()
-// 		},
-// 	})
-
-// 	priest.InnerFocus = priest.RegisterSpell(core.SpellConfig{
-// 		ActionID: actionID,
-// 		Flags:    core.SpellFlagNoOnCastComplete | core.SpellFlagAPL,
-
-// 		Cast: core.CastConfig{
-// 			CD: core.Cooldown{
-// 				Timer:    priest.NewTimer(),
-// 				Duration: time.Duration(float64(time.Minute*3) * (1 - .1*float64(priest.Talents.Aspiration))),
-// 			},
-// 		},
-
-// 		ApplyEffects: func(sim *core.Simulation, _ *core.Unit, _ *core.Spell) {
-// 			priest.InnerFocusAura.Activate(sim)
-// 		},
-// 	})
-// }
+func (priest *Priest) applyAtonement() {
+	if priest.Talents.Atonement == 0 {
+		return
+	}
+
+	atonementPct := 0.5 * float64(priest.Talents.Atonement)
+	atonementSpell := priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 81751},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete,
+		ClassSpellMask: PriestSpellAtonement,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		CritMultiplier:           priest.DefaultHealingCritMultiplier(),
+		ThreatMultiplier:         1,
+	})
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Atonement",
+		Callback:       core.CallbackOnSpellHitDealt,
+		ClassSpellMask: PriestSpellSmite | PriestSpellHolyFire | PriestSpellPenance,
+		Outcome:        core.OutcomeLanded,
+		Harmful:        true,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			target := priest.GetLowestHealthAlly()
+			healing := result.Damage * atonementPct
+			// Atonement is only half as effective when it heals the priest.
+			if target == &priest.Unit {
+				healing *= 0.5
+			}
+			atonementSpell.CalcAndDealHealing(sim, target, healing, atonementSpell.OutcomeHealingCrit)
+		},
+	})
+}
+
+func (priest *Priest) applyBorrowedTime() {
+	if priest.Talents.BorrowedTime == 0 {
+		return
+	}
+
+	hasteMulti := 1 + 0.07*float64(priest.Talents.BorrowedTime)
+	borrowedTimeAura := priest.RegisterAura(core.Aura{
+		Label:    "Borrowed Time",
+		ActionID: core.ActionID{SpellID: 59889},
+		Duration: time.Second * 6,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyCastSpeed(hasteMulti)
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			aura.Unit.MultiplyCastSpeed(1 / hasteMulti)
+		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.ClassSpellMask != PriestSpellPowerWordShield && !spell.Flags.Matches(core.SpellFlagNoOnCastComplete) {
+				aura.Deactivate(sim)
+			}
+		},
+	})
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Borrowed Time Trigger",
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: PriestSpellPowerWordShield,
+		Handler: func(sim *core.Simulation, _ *core.Spell, _ *core.SpellResult) {
+			borrowedTimeAura.Activate(sim)
+		},
+	})
+}
+
+func (priest *Priest) applyStrengthOfSoul() {
+	if priest.Talents.StrengthOfSoul == 0 {
+		return
+	}
+
+	reduction := time.Second * 2 * time.Duration(priest.Talents.StrengthOfSoul)
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Strength of Soul",
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: PriestSpellHeal | PriestSpellFlashHeal | PriestSpellGreaterHeal,
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			weakenedSoul := priest.WeakenedSouls.Get(result.Target)
+			if !weakenedSoul.IsActive() {
+				return
+			}
+
+			if weakenedSoul.RemainingDuration(sim) <= reduction {
+				weakenedSoul.Deactivate(sim)
+			} else {
+				weakenedSoul.UpdateExpires(weakenedSoul.ExpiresAt() - reduction)
+			}
+		},
+	})
+}
+
+func (priest *Priest) applyDivineAegis() {
+	if priest.Talents.DivineAegis == 0 {
+		return
+	}
+
+	shieldPct := 0.1 * float64(priest.Talents.DivineAegis)
+
+	divineAegis := priest.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 47753},
+		SpellSchool:    core.SpellSchoolHoly,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagNoOnCastComplete | core.SpellFlagHelpful,
+		ClassSpellMask: PriestSpellDivineAegis,
+
+		DamageMultiplier:         1,
+		DamageMultiplierAdditive: 1,
+		ThreatMultiplier:         1,
+
+		Shield: core.ShieldConfig{
+			AbsorbsDamage: true,
+			Aura: core.Aura{
+				Label:    "Divine Aegis",
+				Duration: time.Second * 15,
+			},
+		},
+	})
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Divine Aegis Trigger",
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: PriestHealingSpells,
+		Harmful:        true,
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			if !result.DidCrit() && spell.ClassSpellMask != PriestSpellPrayerOfHealing {
+				return
+			}
+
+			// Divine Aegis stacks with itself, up to 40% of the target's max health.
+			shield := divineAegis.Shield(result.Target)
+			added := result.Damage * shieldPct
+			if result.Target.HasHealthBar() {
+				added = min(added, max(0, result.Target.MaxHealth()*0.4-shield.RemainingAbsorb()))
+			}
+			if added <= 0 {
+				return
+			}
+
+			shield.Apply(sim, added)
+		},
+	})
+}
+
+func (priest *Priest) applyTrainOfThought() {
+	if priest.Talents.TrainOfThought == 0 {
+		return
+	}
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Train of Thought",
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: PriestSpellGreaterHeal | PriestSpellSmite,
+		ProcChance:     0.5 * float64(priest.Talents.TrainOfThought),
+		Handler: func(sim *core.Simulation, spell *core.Spell, _ *core.SpellResult) {
+			if spell.ClassSpellMask == PriestSpellGreaterHeal && priest.InnerFocus != nil {
+				priest.InnerFocus.CD.Reduce(time.Second * 5)
+			} else if spell.ClassSpellMask == PriestSpellSmite && priest.Penance != nil {
+				priest.Penance.CD.Reduce(time.Millisecond * 500)
+			}
+		},
+	})
+}
+
+func (priest *Priest) applyGrace() {
+	if priest.Talents.Grace == 0 {
+		return
+	}
+
+	bonusPerStack := 0.04 * float64(priest.Talents.Grace)
+	graceAuras := priest.NewAllyAuraArray(func(target *core.Unit) *core.Aura {
+		return target.RegisterAura(core.Aura{
+			Label:     "Grace-" + priest.Label,
+			ActionID:  core.ActionID{SpellID: 47930},
+			Duration:  time.Second * 15,
+			MaxStacks: 3,
+			OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks, newStacks int32) {
+				attackTable := priest.AttackTables[aura.Unit.UnitIndex]
+				attackTable.HealingDealtMultiplier /= 1 + bonusPerStack*float64(oldStacks)
+				attackTable.HealingDealtMultiplier *= 1 + bonusPerStack*float64(newStacks)
+			},
+		})
+	})
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Grace Trigger",
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: PriestSpellFlashHeal | PriestSpellGreaterHeal | PriestSpellHeal | PriestSpellPenanceHeal,
+		Handler: func(sim *core.Simulation, _ *core.Spell, result *core.SpellResult) {
+			aura := graceAuras.Get(result.Target)
+			aura.Activate(sim)
+			aura.AddStack(sim)
+		},
+	})
+}
+
+func (priest *Priest) applySurgeOfLight() {
+	if priest.Talents.SurgeOfLight == 0 {
+		return
+	}
+
+	priest.SurgeOfLightProcAura = priest.RegisterAura(core.Aura{
+		Label:    "Surge of Light Proc",
+		ActionID: core.ActionID{SpellID: 88688},
+		Duration: time.Second * 10,
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.ClassSpellMask == PriestSpellFlashHeal {
+				aura.Deactivate(sim)
+			}
+		},
+	})
+	priest.SurgeOfLightProcAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_CastTime_Pct,
+		ClassMask:  PriestSpellFlashHeal,
+		FloatValue: -1,
+	})
+	priest.SurgeOfLightProcAura.AttachSpellMod(core.SpellModConfig{
+		Kind:       core.SpellMod_PowerCost_Pct,
+		ClassMask:  PriestSpellFlashHeal,
+		FloatValue: -1,
+	})
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Surge of Light",
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: PriestSpellSmite | PriestSpellHeal | PriestSpellFlashHeal | PriestSpellBindingHeal | PriestSpellGreaterHeal,
+		ProcChance:     0.03 * float64(priest.Talents.SurgeOfLight),
+		Handler: func(sim *core.Simulation, spell *core.Spell, _ *core.SpellResult) {
+			// The free Flash Heal can't refresh its own proc.
+			if spell.ClassSpellMask == PriestSpellFlashHeal && priest.SurgeOfLightProcAura.IsActive() {
+				return
+			}
+			priest.SurgeOfLightProcAura.Activate(sim)
+		},
+	})
+}
+
+func (priest *Priest) applySerendipity() {
+	if priest.Talents.Serendipity == 0 {
+		return
+	}
+
+	castTimeMod := priest.AddDynamicMod(core.SpellModConfig{
+		Kind:      core.SpellMod_CastTime_Pct,
+		ClassMask: PriestSpellGreaterHeal | PriestSpellPrayerOfHealing,
+	})
+	manaMod := priest.AddDynamicMod(core.SpellModConfig{
+		Kind:      core.SpellMod_PowerCost_Pct,
+		ClassMask: PriestSpellGreaterHeal | PriestSpellPrayerOfHealing,
+	})
+
+	priest.SerendipityAura = priest.RegisterAura(core.Aura{
+		Label:     "Serendipity",
+		ActionID:  core.ActionID{SpellID: 63735},
+		Duration:  time.Second * 20,
+		MaxStacks: 2,
+		OnStacksChange: func(aura *core.Aura, sim *core.Simulation, oldStacks, newStacks int32) {
+			castTimeMod.UpdateFloatValue(-0.1 * float64(priest.Talents.Serendipity) * float64(newStacks))
+			castTimeMod.Activate()
+			manaMod.UpdateFloatValue(-0.05 * float64(priest.Talents.Serendipity) * float64(newStacks))
+			manaMod.Activate()
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Deactivate()
+			manaMod.Deactivate()
+		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.ClassSpellMask&(PriestSpellGreaterHeal|PriestSpellPrayerOfHealing) != 0 {
+				aura.Deactivate(sim)
+			}
+		},
+	})
+
+	core.MakeProcTriggerAura(&priest.Unit, core.ProcTrigger{
+		Name:           "Serendipity Trigger",
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: PriestSpellFlashHeal | PriestSpellBindingHeal,
+		Handler: func(sim *core.Simulation, _ *core.Spell, _ *core.SpellResult) {
+			priest.SerendipityAura.Activate(sim)
+			priest.SerendipityAura.AddStack(sim)
+		},
+	})
+}
@@ -120,6 +120,13 @@ func (rogue *Rogue) GetRogue() *Rogue {
 func (rogue *Rogue) AddRaidBuffs(_ *proto.RaidBuffs)   {}
 func (rogue *Rogue) AddPartyBuffs(_ *proto.PartyBuffs) {}
 
+func (rogue *Rogue) AddRosterBuffs(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	raidBuffs.HonorAmongThieves = rogue.Talents.HonorAmongThieves > 0
+	debuffs.MasterPoisoner = rogue.Talents.MasterPoisoner
+	debuffs.SavageCombat = rogue.Talents.SavageCombat > 0
+	debuffs.Hemorrhage = rogue.Talents.Hemorrhage
+}
+
 // Apply the effect of successfully casting a finisher to combo points
 func (rogue *Rogue) ApplyFinisher(sim *core.Simulation, spell *core.Spell) {
 	numPoints := rogue.ComboPoints()
@@ -0,0 +1,68 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
+)
+
+func (shaman *Shaman) registerChainHealSpell() {
+	const numHits = 4
+	const bounceMultiplier = 0.7
+
+	hasGlyph := shaman.HasMajorGlyph(proto.ShamanMajorGlyph_GlyphOfChainHeal)
+	primaryMultiplier := core.TernaryFloat64(hasGlyph, 1.15, 1)
+	jumpMultiplier := core.TernaryFloat64(hasGlyph, 0.9, 1)
+
+	shaman.ChainHeal = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 1064},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskChainHeal,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.17,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Millisecond * 2500,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.286,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			targets := shaman.GetSmartHealTargets(target, numHits)
+
+			// Healing calculation and DealHealing are in separate loops so that the jumps
+			// are not affected by procs from the first target.
+			results := make([]*core.SpellResult, len(targets))
+			bounceCoeff := primaryMultiplier
+			for hitIndex, healTarget := range targets {
+				// Riptide is no longer consumed, but still empowers Chain Heal on its target.
+				hitMultiplier := bounceCoeff * core.TernaryFloat64(shaman.Riptide != nil && shaman.Riptide.Hot(healTarget).IsActive(), 1.25, 1)
+
+				spell.DamageMultiplier *= hitMultiplier
+				baseHealing := shaman.CalcAndRollDamageRange(sim, 2.857, 0.15)
+				results[hitIndex] = spell.CalcHealing(sim, healTarget, baseHealing, spell.OutcomeHealingCrit)
+				spell.DamageMultiplier /= hitMultiplier
+
+				if hitIndex == 0 {
+					bounceCoeff = jumpMultiplier
+				}
+				bounceCoeff *= bounceMultiplier
+			}
+
+			for _, result := range results {
+				spell.DealHealing(sim, result)
+			}
+		},
+	})
+}
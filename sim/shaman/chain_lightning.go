@@ -8,7 +8,7 @@ import (
 )
 
 func (shaman *Shaman) registerChainLightningSpell() {
-	numHits := min(core.TernaryInt32(shaman.HasMajorGlyph(proto.ShamanMajorGlyph_GlyphOfChainLightning), 5, 3), shaman.Env.GetNumTargets())
+	numHits := core.TernaryInt32(shaman.HasMajorGlyph(proto.ShamanMajorGlyph_GlyphOfChainLightning), 5, 3)
 	shaman.ChainLightning = shaman.newChainLightningSpell(false)
 	shaman.ChainLightningOverloads = []*core.Spell{}
 	for i := int32(0); i < numHits; i++ {
@@ -27,14 +27,14 @@ func (shaman *Shaman) newChainLightningSpell(isElementalOverload bool) *core.Spe
 		}
 	}
 
-	numHits := int32(3)
+	maxHits := int32(3)
 	if shaman.HasMajorGlyph(proto.ShamanMajorGlyph_GlyphOfChainLightning) {
 		spellConfig.DamageMultiplier *= 0.90
-		numHits += 2
+		maxHits += 2
 	}
-	numHits = min(numHits, shaman.Env.GetNumTargets())
 
 	spellConfig.ApplyEffects = func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+		numHits := min(maxHits, sim.GetNumTargets())
 		bounceReduction := core.TernaryFloat64(shaman.DungeonSet3.IsActive() && !isElementalOverload, 0.83, 0.7)
 		baseDamage := shaman.CalcAndRollDamageRange(sim, 1.08800005913, 0.13300000131)
 		curTarget := target
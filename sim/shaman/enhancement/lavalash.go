@@ -62,7 +62,7 @@ func (enh *EnhancementShaman) registerLavaLashSpell() {
 				if searingFlames.GetStacks() > 0 {
 					numberSpread := 0
 					maxTargets := 4
-					for _, otherTarget := range sim.Encounter.TargetUnits {
+					for _, otherTarget := range sim.Encounter.ActiveTargetUnits {
 						if otherTarget != target {
 							enh.FlameShock.Cast(sim, otherTarget)
 							numberSpread++
@@ -100,14 +100,14 @@ func (shaman *Shaman) registerMagmaTotemSpell() {
 			TickLength:       time.Second * 2,
 			BonusCoefficient: 0.08,
 			OnTick: func(sim *core.Simulation, target *core.Unit, dot *core.Dot) {
-				results := make([]*core.SpellResult, shaman.Env.GetNumTargets())
+				results := make([]*core.SpellResult, len(sim.Encounter.ActiveTargetUnits))
 				baseDamage := shaman.ClassSpellScaling * 0.26699998975
 				aoeMult := sim.Encounter.AOECapMultiplier()
 				dot.Spell.DamageMultiplier *= aoeMult
-				for i, aoeTarget := range sim.Encounter.TargetUnits {
+				for i, aoeTarget := range sim.Encounter.ActiveTargetUnits {
 					results[i] = dot.Spell.CalcDamage(sim, aoeTarget, baseDamage, dot.Spell.OutcomeMagicHitAndCrit)
 				}
-				for i, _ := range sim.Encounter.TargetUnits {
+				for i, _ := range sim.Encounter.ActiveTargetUnits {
 					dot.Spell.DealDamage(sim, results[i])
 				}
 				dot.Spell.DamageMultiplier /= aoeMult
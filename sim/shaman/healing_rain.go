@@ -0,0 +1,62 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+// Healing Rain is placed on the ground at its target and heals the most injured
+// allies standing in it every 2 seconds.
+func (shaman *Shaman) registerHealingRainSpell() {
+	const numTargets = 6
+	var healingRainTarget *core.Unit
+
+	shaman.HealingRain = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 73920},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: SpellMaskHealingRain,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   0.46,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: time.Second * 2,
+			},
+			CD: core.Cooldown{
+				Timer:    shaman.NewTimer(),
+				Duration: time.Second * 10,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: 0.0953,
+
+		Hot: core.DotConfig{
+			SelfOnly: true,
+			Aura: core.Aura{
+				Label: "Healing Rain",
+			},
+			NumberOfTicks: 5,
+			TickLength:    time.Second * 2,
+
+			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
+				for _, healTarget := range shaman.GetSmartHealTargets(healingRainTarget, numTargets) {
+					dot.Spell.CalcAndDealPeriodicHealing(sim, healTarget, shaman.ClassSpellScaling*0.648, dot.Spell.OutcomeHealingCrit)
+				}
+			},
+		},
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			healingRainTarget = target
+			spell.SelfHot().Apply(sim)
+		},
+	})
+}
@@ -0,0 +1,50 @@
+package shaman
+
+import (
+	"time"
+
+	"github.com/wowsims/cata/sim/core"
+)
+
+func (shaman *Shaman) newHealingWaveSpellConfig(actionID core.ActionID, classMask int64, baseCost float64, castTime time.Duration, coeff float64, variance float64, bonusCoeff float64) core.SpellConfig {
+	return core.SpellConfig{
+		ActionID:       actionID,
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagAPL,
+		ClassSpellMask: classMask,
+
+		ManaCost: core.ManaCostOptions{
+			BaseCost:   baseCost,
+			Multiplier: 1,
+		},
+		Cast: core.CastConfig{
+			DefaultCast: core.Cast{
+				GCD:      core.GCDDefault,
+				CastTime: castTime,
+			},
+		},
+
+		DamageMultiplier: 1,
+		CritMultiplier:   shaman.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
+		BonusCoefficient: bonusCoeff,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			baseHealing := shaman.CalcAndRollDamageRange(sim, coeff, variance)
+			spell.CalcAndDealHealing(sim, target, baseHealing, spell.OutcomeHealingCrit)
+		},
+	}
+}
+
+func (shaman *Shaman) registerHealingWaveSpell() {
+	shaman.HealingWave = shaman.RegisterSpell(shaman.newHealingWaveSpellConfig(core.ActionID{SpellID: 331}, SpellMaskHealingWave, 0.09, time.Second*3, 2.986, 0.15, 0.302))
+}
+
+func (shaman *Shaman) registerGreaterHealingWaveSpell() {
+	shaman.GreaterHealingWave = shaman.RegisterSpell(shaman.newHealingWaveSpellConfig(core.ActionID{SpellID: 77472}, SpellMaskGreaterHealingWave, 0.27, time.Second*3, 7.532, 0.15, 0.755))
+}
+
+func (shaman *Shaman) registerHealingSurgeSpell() {
+	shaman.HealingSurge = shaman.RegisterSpell(shaman.newHealingWaveSpellConfig(core.ActionID{SpellID: 8004}, SpellMaskHealingSurge, 0.27, time.Millisecond*1500, 6.131, 0.15, 0.604))
+}
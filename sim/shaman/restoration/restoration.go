@@ -42,8 +42,12 @@ func NewRestorationShaman(character *core.Character, options *proto.Player) *Res
 
 	if resto.HasMHWeapon() {
 		resto.ApplyEarthlivingImbueToItem(resto.GetMHWeapon())
+		resto.SelfBuffs.ImbueMH = proto.ShamanImbue_EarthlivingWeapon
 	}
 
+	// Meditation
+	resto.PseudoStats.SpiritRegenRateCombat = 0.5
+
 	return resto
 }
 
@@ -58,34 +62,61 @@ func (resto *RestorationShaman) GetShaman() *shaman.Shaman {
 func (resto *RestorationShaman) Reset(sim *core.Simulation) {
 	resto.Shaman.Reset(sim)
 }
-func (resto *RestorationShaman) GetMainTarget() *core.Unit {
-	// TODO: make this just grab first player that isn't self.
-	target := resto.Env.Raid.GetFirstTargetDummy()
-	if target == nil {
-		return &resto.Unit
-	} else {
-		return &target.Unit
-	}
-}
 
 func (resto *RestorationShaman) Initialize() {
-	// resto.CurrentTarget = resto.GetMainTarget()
-
-	// // Has to be here because earthliving can cast hots and needs Env to be set to create the hots.
-	// procMask := core.ProcMaskUnknown
-	// if resto.HasMHWeapon() {
-	// 	procMask |= core.ProcMaskMeleeMH
-	// }
-	// if resto.HasOHWeapon() {
-	// 	procMask |= core.ProcMaskMeleeOH
-	// }
-	// resto.RegisterEarthlivingImbue(procMask)
+	resto.CurrentTarget = resto.GetMainTarget()
+
+	// Has to be here because earthliving can cast hots and needs Env to be set to create the hots.
+	procMask := core.ProcMaskUnknown
+	if resto.HasMHWeapon() {
+		procMask |= core.ProcMaskMeleeMH
+	}
+	if resto.HasOHWeapon() {
+		procMask |= core.ProcMaskMeleeOH
+	}
+	resto.RegisterEarthlivingImbue(procMask)
 
 	resto.Shaman.Initialize()
 	resto.Shaman.RegisterHealingSpells()
+
+	// Purification
+	resto.AddStaticMod(core.SpellModConfig{
+		ClassMask:  shaman.SpellMaskHealing,
+		Kind:       core.SpellMod_DamageDone_Pct,
+		FloatValue: 0.25,
+	})
+
+	resto.applyDeepHealing()
 }
 
 func (resto *RestorationShaman) ApplyTalents() {
 	resto.Shaman.ApplyTalents()
 	resto.ApplyArmorSpecializationEffect(stats.Intellect, proto.ArmorType_ArmorTypeMail, 86529)
 }
+
+func (resto *RestorationShaman) getMasteryBonus() float64 {
+	return 0.24 + 0.03*resto.GetMasteryPoints()
+}
+
+// Mastery: Deep Healing increases healing by up to the mastery bonus, based on
+// how injured the target is.
+func (resto *RestorationShaman) applyDeepHealing() {
+	deepHealingMultiplier := func(_ *core.Simulation, spell *core.Spell, attackTable *core.AttackTable) float64 {
+		target := attackTable.Defender
+		if !spell.Matches(shaman.SpellMaskHealing) || !target.HasHealthBar() {
+			return 1
+		}
+		return 1 + resto.getMasteryBonus()*(1-target.CurrentHealthPercent())
+	}
+
+	core.MakePermanent(resto.RegisterAura(core.Aura{
+		Label:    "Mastery: Deep Healing",
+		ActionID: core.ActionID{SpellID: 77226},
+		OnInit: func(aura *core.Aura, sim *core.Simulation) {
+			for _, unit := range resto.Env.Raid.AllPlayerUnits {
+				attackTable := resto.AttackTables[unit.UnitIndex]
+				attackTable.HealingDoneByCasterMultiplier = append(attackTable.HealingDoneByCasterMultiplier, deepHealingMultiplier)
+			}
+		},
+	}))
+}
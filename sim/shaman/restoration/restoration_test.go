@@ -1,106 +1,90 @@
 package restoration
 
 import (
+	"testing"
+
 	_ "github.com/wowsims/cata/sim/common"
+	"github.com/wowsims/cata/sim/core"
+	"github.com/wowsims/cata/sim/core/proto"
 )
 
 func init() {
 	RegisterRestorationShaman()
 }
 
-// func TestRestoration(t *testing.T) {
-// 	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
-// 		Class: proto.Class_ClassShaman,
-// 		Race:  proto.Race_RaceTroll,
-
-// 		GearSet:     core.GetGearSet("../../../ui/restoration_shaman/gear_sets", "p1"),
-// 		Talents:     StandardTalents,
-// 		Glyphs:      StandardGlyphs,
-// 		Consumes:    FullConsumes,
-// 		SpecOptions: core.SpecOptionsCombo{Label: "Standard", SpecOptions: PlayerOptionsStandard},
-// 		Rotation:    core.RotationCombo{Label: "Default", Rotation: DefaultRotation},
+func TestRestoration(t *testing.T) {
+	core.RunTestSuite(t, t.Name(), core.FullCharacterTestSuiteGenerator(core.CharacterSuiteConfig{
+		Class:      proto.Class_ClassShaman,
+		Race:       proto.Race_RaceTroll,
+		OtherRaces: []proto.Race{proto.Race_RaceOrc},
+		IsHealer:   true,
 
-// 		ItemFilter: core.ItemFilter{
-// 			WeaponTypes: []proto.WeaponType{
-// 				proto.WeaponType_WeaponTypeAxe,
-// 				proto.WeaponType_WeaponTypeDagger,
-// 				proto.WeaponType_WeaponTypeFist,
-// 				proto.WeaponType_WeaponTypeMace,
-// 				proto.WeaponType_WeaponTypeOffHand,
-// 				proto.WeaponType_WeaponTypeShield,
-// 				proto.WeaponType_WeaponTypeStaff,
-// 			},
-// 			ArmorType: proto.ArmorType_ArmorTypeMail,
-// 			RangedWeaponTypes: []proto.RangedWeaponType{
-// 				proto.RangedWeaponType_RangedWeaponTypeRelic,
-// 			},
-// 		},
-// 	}))
-// }
+		GearSet:     core.GetGearSet("../../../ui/shaman/restoration/gear_sets", "p1"),
+		Talents:     StandardTalents,
+		Glyphs:      StandardGlyphs,
+		Consumes:    FullConsumes,
+		SpecOptions: core.SpecOptionsCombo{Label: "Standard", SpecOptions: PlayerOptionsStandard},
+		Rotation:    core.GetAplRotation("../../../ui/shaman/restoration/apls", "default"),
 
-// func BenchmarkSimulate(b *testing.B) {
-// 	rsr := &proto.RaidSimRequest{
-// 		Raid: core.SinglePlayerRaidProto(
-// 			&proto.Player{
-// 				Race:          proto.Race_RaceOrc,
-// 				Class:         proto.Class_ClassShaman,
-// 				Equipment:     core.GetGearSet("../../../ui/restoration_shaman/gear_sets", "p1").GearSet,
-// 				Consumes:      FullConsumes,
-// 				Spec:          PlayerOptionsStandard,
-// 				Buffs:         core.FullIndividualBuffs,
-// 				TalentsString: StandardTalents,
-// 				Glyphs:        StandardGlyphs,
-// 			},
-// 			core.FullPartyBuffs,
-// 			core.FullRaidBuffs,
-// 			core.FullDebuffs),
-// 		Encounter: &proto.Encounter{
-// 			Duration: 300,
-// 			Targets: []*proto.Target{
-// 				core.NewDefaultTarget(),
-// 			},
-// 		},
-// 		SimOptions: core.AverageDefaultSimTestOptions,
-// 	}
+		ItemFilter: core.ItemFilter{
+			WeaponTypes: []proto.WeaponType{
+				proto.WeaponType_WeaponTypeAxe,
+				proto.WeaponType_WeaponTypeDagger,
+				proto.WeaponType_WeaponTypeFist,
+				proto.WeaponType_WeaponTypeMace,
+				proto.WeaponType_WeaponTypeOffHand,
+				proto.WeaponType_WeaponTypeShield,
+				proto.WeaponType_WeaponTypeStaff,
+			},
+			ArmorType: proto.ArmorType_ArmorTypeMail,
+			RangedWeaponTypes: []proto.RangedWeaponType{
+				proto.RangedWeaponType_RangedWeaponTypeRelic,
+			},
+		},
 
-// 	core.RaidBenchmark(b, rsr)
-// }
-
-// var StandardTalents = "-3020503-50005331335310501122331251"
-// var StandardGlyphs = &proto.Glyphs{
-// 	Major1: int32(proto.ShamanMajorGlyph_GlyphOfChainHeal),
-// 	Major2: int32(proto.ShamanMajorGlyph_GlyphOfEarthShield),
-// 	Major3: int32(proto.ShamanMajorGlyph_GlyphOfEarthlivingWeapon),
-// }
+		EPReferenceStat: proto.Stat_StatSpellPower,
+		StatsToWeigh: []proto.Stat{
+			proto.Stat_StatIntellect,
+			proto.Stat_StatSpirit,
+			proto.Stat_StatSpellPower,
+			proto.Stat_StatCritRating,
+			proto.Stat_StatHasteRating,
+			proto.Stat_StatMasteryRating,
+		},
+	}))
+}
 
-// var BasicTotems = &proto.ShamanTotems{
-// 	Earth: proto.EarthTotem_TremorTotem,
-// 	Air:   proto.AirTotem_WrathOfAirTotem,
-// 	Water: proto.WaterTotem_ManaSpringTotem,
-// 	Fire:  proto.FireTotem_FlametongueTotem,
-// }
+var StandardTalents = "302--02322302132103121321"
+var StandardGlyphs = &proto.Glyphs{
+	Prime1: int32(proto.ShamanPrimeGlyph_GlyphOfRiptide),
+	Prime2: int32(proto.ShamanPrimeGlyph_GlyphOfEarthlivingWeapon),
+	Prime3: int32(proto.ShamanPrimeGlyph_GlyphOfWaterShield),
+	Major1: int32(proto.ShamanMajorGlyph_GlyphOfChainHeal),
+	Major2: int32(proto.ShamanMajorGlyph_GlyphOfHealingStreamTotem),
+	Major3: int32(proto.ShamanMajorGlyph_GlyphOfHealingWave),
+}
 
-// var restoShamOptions = &proto.RestorationShaman_Options{
-// 	Shield: proto.ShamanShield_WaterShield,
-// 	Totems: BasicTotems,
-// }
-// var PlayerOptionsStandard = &proto.Player_RestorationShaman{
-// 	RestorationShaman: &proto.RestorationShaman{
-// 		Options: restoShamOptions,
-// 	},
-// }
+var TotemsBasic = &proto.ShamanTotems{
+	Earth: proto.EarthTotem_StoneskinTotem,
+	Air:   proto.AirTotem_WrathOfAirTotem,
+	Water: proto.WaterTotem_ManaSpringTotem,
+	Fire:  proto.FireTotem_FlametongueTotem,
+}
 
-// var FullConsumes = &proto.Consumes{
-// 	Flask:           proto.Flask_FlaskOfBlindingLight,
-// 	Food:            proto.Food_FoodBlackenedBasilisk,
-// 	DefaultPotion:   proto.Potions_SuperManaPotion,
-// 	PrepopPotion:    proto.Potions_DestructionPotion,
-// 	DefaultConjured: proto.Conjured_ConjuredDarkRune,
-// }
+var PlayerOptionsStandard = &proto.Player_RestorationShaman{
+	RestorationShaman: &proto.RestorationShaman{
+		Options: &proto.RestorationShaman_Options{
+			ClassOptions: &proto.ShamanOptions{
+				Shield: proto.ShamanShield_WaterShield,
+				Totems: TotemsBasic,
+			},
+		},
+	},
+}
 
-// var DefaultRotation = core.APLRotationFromJsonString(`{
-// 	"type": "TypeAPL",
-// 	"priorityList": [
-// 		{"action":{"autocastOtherCooldowns":{}}}
-// 	]
-// }`)
+var FullConsumes = &proto.Consumes{
+	Flask:         proto.Flask_FlaskOfTheDraconicMind,
+	Food:          proto.Food_FoodSeafoodFeast,
+	DefaultPotion: proto.Potions_MythicalManaPotion,
+	PrepopPotion:  proto.Potions_VolcanicPotion,
+}
@@ -168,6 +168,7 @@ type Shaman struct {
 	GreaterHealingWave *core.Spell
 	HealingWave        *core.Spell
 	ChainHeal          *core.Spell
+	HealingRain        *core.Spell
 	Riptide            *core.Spell
 	EarthShield        *core.Spell
 
@@ -246,6 +247,10 @@ func (shaman *Shaman) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	}
 }
 
+func (shaman *Shaman) AddRosterBuffs(raidBuffs *proto.RaidBuffs, _ *proto.Debuffs) {
+	raidBuffs.Bloodlust = true
+}
+
 func (shaman *Shaman) Initialize() {
 	shaman.registerChainLightningSpell()
 	shaman.registerFireElementalTotem()
@@ -278,32 +283,12 @@ func (shaman *Shaman) Initialize() {
 }
 
 func (shaman *Shaman) RegisterHealingSpells() {
-	// shaman.registerAncestralHealingSpell()
-	// shaman.registerHealingSurgeSpell()
-	// shaman.registerHealingWaveSpell()
-	// shaman.registerRiptideSpell()
-	// shaman.registerEarthShieldSpell()
-	// shaman.registerChainHealSpell()
-
-	// if shaman.Talents.TidalWaves > 0 {
-	// 	shaman.tidalWaveProc = shaman.GetOrRegisterAura(core.Aura{
-	// 		Label:    "Tidal Wave Proc",
-	// 		ActionID: core.ActionID{SpellID: 53390},
-	// 		Duration: core.NeverExpires,
-	// 		OnReset: func(aura *core.Aura, sim *core.Simulation) {
-	// 			aura.Deactivate(sim)
-	// 		},
-	// 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-	// 			shaman.HealingWave.CastTimeMultiplier *= 0.7
-	// 			shaman.HealingSurge.BonusCritRating += core.CritRatingPerCritChance * 25
-	// 		},
-	// 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-	// 			shaman.HealingWave.CastTimeMultiplier /= 0.7
-	// 			shaman.HealingSurge.BonusCritRating -= core.CritRatingPerCritChance * 25
-	// 		},
-	// 		MaxStacks: 2,
-	// 	})
-	// }
+	shaman.registerChainHealSpell()
+	shaman.registerGreaterHealingWaveSpell()
+	shaman.registerHealingRainSpell()
+	shaman.registerHealingSurgeSpell()
+	shaman.registerHealingWaveSpell()
+	shaman.registerRiptideSpell()
 }
 
 func (shaman *Shaman) Reset(sim *core.Simulation) {
@@ -363,6 +348,15 @@ const (
 	SpellMaskFeralSpirit
 	SpellMaskElementalMastery
 	SpellMaskSpiritwalkersGrace
+	SpellMaskHealingWave
+	SpellMaskGreaterHealingWave
+	SpellMaskHealingSurge
+	SpellMaskRiptide
+	SpellMaskChainHeal
+	SpellMaskHealingRain
+	SpellMaskUnleashLife
+	SpellMaskEarthliving
+	SpellMaskAncestralAwakening
 
 	SpellMaskStormstrike = SpellMaskStormstrikeCast | SpellMaskStormstrikeDamage
 	SpellMaskFlameShock  = SpellMaskFlameShockDirect | SpellMaskFlameShockDot
@@ -370,4 +364,7 @@ const (
 	SpellMaskNature      = SpellMaskLightningBolt | SpellMaskLightningBoltOverload | SpellMaskChainLightning | SpellMaskChainLightningOverload | SpellMaskEarthShock | SpellMaskThunderstorm | SpellMaskFulmination
 	SpellMaskFrost       = SpellMaskUnleashFrost | SpellMaskFrostShock
 	SpellMaskOverload    = SpellMaskLavaBurstOverload | SpellMaskLightningBoltOverload | SpellMaskChainLightningOverload
+
+	SpellMaskDirectHeal = SpellMaskHealingWave | SpellMaskGreaterHealingWave | SpellMaskHealingSurge | SpellMaskRiptide | SpellMaskChainHeal | SpellMaskUnleashLife
+	SpellMaskHealing    = SpellMaskDirectHeal | SpellMaskHealingRain | SpellMaskEarthliving | SpellMaskAncestralAwakening
 )
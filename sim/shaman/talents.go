@@ -1,6 +1,7 @@
 package shaman
 
 import (
+	"slices"
 	"time"
 
 	"github.com/wowsims/cata/sim/core"
@@ -113,6 +114,35 @@ func (shaman *Shaman) ApplyTalents() {
 		shaman.SearingFlamesMultiplier += 0.1 * float64(shaman.Talents.ImprovedLavaLash)
 	}
 
+	if shaman.Talents.TidalFocus > 0 {
+		shaman.AddStaticMod(core.SpellModConfig{
+			ClassMask:  SpellMaskHealing,
+			Kind:       core.SpellMod_PowerCost_Pct,
+			FloatValue: -0.02 * float64(shaman.Talents.TidalFocus),
+		})
+	}
+
+	if shaman.Talents.SparkOfLife > 0 {
+		shaman.PseudoStats.HealingDealtMultiplier *= 1 + 0.02*float64(shaman.Talents.SparkOfLife)
+	}
+
+	if shaman.Talents.SoothingRains > 0 {
+		shaman.AddStaticMod(core.SpellModConfig{
+			ClassMask:  SpellMaskHealingRain,
+			Kind:       core.SpellMod_DamageDone_Flat,
+			FloatValue: 0.15 * float64(shaman.Talents.SoothingRains),
+		})
+	}
+
+	if shaman.Talents.BlessingOfTheEternals > 0 {
+		shaman.AddStat(stats.SpellCritPercent, 2*float64(shaman.Talents.BlessingOfTheEternals))
+	}
+
+	shaman.applyAncestralAwakening()
+	shaman.applyResurgence()
+	shaman.applyTelluricCurrents()
+	shaman.applyTidalWaves()
+
 	shaman.registerElementalMasteryCD()
 	shaman.registerNaturesSwiftnessCD()
 	shaman.registerShamanisticRageCD()
@@ -479,22 +509,36 @@ func (shaman *Shaman) registerNaturesSwiftnessCD() {
 	cdTimer := shaman.NewTimer()
 	cd := time.Minute * 2
 
+	var affectedSpells []*core.Spell
+
 	nsAura := shaman.RegisterAura(core.Aura{
 		Label:    "Natures Swiftness",
 		ActionID: actionID,
 		Duration: core.NeverExpires,
+		OnInit: func(aura *core.Aura, sim *core.Simulation) {
+			affectedSpells = core.FilterSlice([]*core.Spell{
+				shaman.ChainLightning,
+				shaman.LavaBurst,
+				shaman.LightningBolt,
+				shaman.ChainHeal,
+				shaman.GreaterHealingWave,
+				shaman.HealingRain,
+				shaman.HealingSurge,
+				shaman.HealingWave,
+			}, func(spell *core.Spell) bool { return spell != nil })
+		},
 		OnGain: func(aura *core.Aura, sim *core.Simulation) {
-			shaman.ChainLightning.CastTimeMultiplier -= 1
-			shaman.LavaBurst.CastTimeMultiplier -= 1
-			shaman.LightningBolt.CastTimeMultiplier -= 1
+			for _, spell := range affectedSpells {
+				spell.CastTimeMultiplier -= 1
+			}
 		},
 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
-			shaman.ChainLightning.CastTimeMultiplier += 1
-			shaman.LavaBurst.CastTimeMultiplier += 1
-			shaman.LightningBolt.CastTimeMultiplier += 1
+			for _, spell := range affectedSpells {
+				spell.CastTimeMultiplier += 1
+			}
 		},
 		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
-			if spell != shaman.LightningBolt && spell != shaman.ChainLightning && spell != shaman.LavaBurst {
+			if !slices.Contains(affectedSpells, spell) {
 				return
 			}
 
@@ -696,6 +740,136 @@ func (shaman *Shaman) applySearingFlames() {
 
 }
 
+func (shaman *Shaman) applyAncestralAwakening() {
+	if shaman.Talents.AncestralAwakening == 0 {
+		return
+	}
+
+	shaman.AncestralAwakening = shaman.RegisterSpell(core.SpellConfig{
+		ActionID:       core.ActionID{SpellID: 52752},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskSpellHealing,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagNoOnCastComplete | core.SpellFlagIgnoreModifiers,
+		ClassSpellMask: SpellMaskAncestralAwakening,
+
+		DamageMultiplier: 1,
+		ThreatMultiplier: 1,
+
+		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			spell.CalcAndDealHealing(sim, target, shaman.ancestralHealingAmount, spell.OutcomeHealing)
+		},
+	})
+
+	// Direct heal crits also heal the lowest health party or raid member.
+	core.MakeProcTriggerAura(&shaman.Unit, core.ProcTrigger{
+		Name:           "Ancestral Awakening Trigger",
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: SpellMaskDirectHeal,
+		Outcome:        core.OutcomeCrit,
+
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			shaman.ancestralHealingAmount = result.Damage * 0.1 * float64(shaman.Talents.AncestralAwakening)
+			shaman.AncestralAwakening.Cast(sim, shaman.GetLowestHealthAlly())
+		},
+	})
+}
+
+func (shaman *Shaman) applyResurgence() {
+	if shaman.Talents.Resurgence == 0 || shaman.SelfBuffs.Shield != proto.ShamanShield_WaterShield {
+		return
+	}
+
+	manaMetrics := shaman.NewManaMetrics(core.ActionID{SpellID: 101033})
+	manaReturn := 881.5 * float64(shaman.Talents.Resurgence)
+
+	core.MakeProcTriggerAura(&shaman.Unit, core.ProcTrigger{
+		Name:           "Resurgence",
+		Callback:       core.CallbackOnHealDealt,
+		ClassSpellMask: SpellMaskDirectHeal,
+		Outcome:        core.OutcomeCrit,
+
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			multiplier := 1.0
+			if spell.Matches(SpellMaskRiptide | SpellMaskUnleashLife) {
+				multiplier = 0.6
+			} else if spell.Matches(SpellMaskChainHeal) {
+				multiplier = 0.33
+			}
+			shaman.AddMana(sim, manaReturn*multiplier, manaMetrics)
+		},
+	})
+}
+
+func (shaman *Shaman) applyTelluricCurrents() {
+	if shaman.Talents.TelluricCurrents == 0 {
+		return
+	}
+
+	manaMetrics := shaman.NewManaMetrics(core.ActionID{SpellID: 82987})
+	manaReturn := 0.2 * float64(shaman.Talents.TelluricCurrents)
+
+	core.MakeProcTriggerAura(&shaman.Unit, core.ProcTrigger{
+		Name:           "Telluric Currents",
+		Callback:       core.CallbackOnSpellHitDealt,
+		ClassSpellMask: SpellMaskLightningBolt | SpellMaskLightningBoltOverload,
+		Outcome:        core.OutcomeLanded,
+
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			shaman.AddMana(sim, result.Damage*manaReturn, manaMetrics)
+		},
+	})
+}
+
+// Riptide and Chain Heal speed up or empower the next 2 Healing Waves,
+// Greater Healing Waves or Healing Surges.
+func (shaman *Shaman) applyTidalWaves() {
+	if shaman.Talents.TidalWaves == 0 {
+		return
+	}
+
+	castTimeMod := shaman.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  SpellMaskHealingWave | SpellMaskGreaterHealingWave,
+		Kind:       core.SpellMod_CastTime_Pct,
+		FloatValue: -0.1 * float64(shaman.Talents.TidalWaves),
+	})
+	critMod := shaman.AddDynamicMod(core.SpellModConfig{
+		ClassMask:  SpellMaskHealingSurge,
+		Kind:       core.SpellMod_BonusCrit_Percent,
+		FloatValue: 10 * float64(shaman.Talents.TidalWaves),
+	})
+
+	shaman.tidalWaveProc = shaman.RegisterAura(core.Aura{
+		Label:     "Tidal Waves",
+		ActionID:  core.ActionID{SpellID: 53390},
+		Duration:  time.Second * 15,
+		MaxStacks: 2,
+		OnGain: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Activate()
+			critMod.Activate()
+		},
+		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
+			castTimeMod.Deactivate()
+			critMod.Deactivate()
+		},
+		OnCastComplete: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell) {
+			if spell.Matches(SpellMaskHealingWave | SpellMaskGreaterHealingWave | SpellMaskHealingSurge) {
+				aura.RemoveStack(sim)
+			}
+		},
+	})
+
+	core.MakeProcTriggerAura(&shaman.Unit, core.ProcTrigger{
+		Name:           "Tidal Waves Trigger",
+		Callback:       core.CallbackOnCastComplete,
+		ClassSpellMask: SpellMaskRiptide | SpellMaskChainHeal,
+
+		Handler: func(sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
+			shaman.tidalWaveProc.Activate(sim)
+			shaman.tidalWaveProc.SetStacks(sim, 2)
+		},
+	})
+}
+
 func (shaman *Shaman) applyTotemicFocus() {
 	if shaman.Talents.TotemicFocus == 0 {
 		return
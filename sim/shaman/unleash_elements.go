@@ -142,7 +142,7 @@ func (shaman *Shaman) registerUnleashLife() {
 		},
 		OnExpire: func(aura *core.Aura, sim *core.Simulation) {
 			for _, spell := range affectedSpells {
-				spell.CostMultiplier -= 0.2
+				spell.DamageMultiplierAdditive -= 0.2
 			}
 		},
 	})
@@ -152,18 +152,14 @@ func (shaman *Shaman) registerUnleashLife() {
 		SpellSchool:      core.SpellSchoolNature,
 		ProcMask:         core.ProcMaskSpellHealing,
 		Flags:            core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+		ClassSpellMask:   SpellMaskUnleashLife,
+		DamageMultiplier: 1,
 		CritMultiplier:   shaman.DefaultHealingCritMultiplier(),
+		ThreatMultiplier: 1,
 		BonusCoefficient: 0.201,
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseHeal := shaman.ClassSpellScaling * 1.98699998856
-			result := spell.CalcAndDealHealing(sim, target, baseHeal, spell.OutcomeHealingCrit)
-
-			if result.Outcome.Matches(core.OutcomeCrit) {
-				if shaman.Talents.AncestralAwakening > 0 {
-					shaman.ancestralHealingAmount = result.Damage * 0.3
-					shaman.AncestralAwakening.Cast(sim, target)
-				}
-			}
+			spell.CalcAndDealHealing(sim, target, baseHeal, spell.OutcomeHealingCrit)
 			unleashLifeAura.Activate(sim)
 		},
 	})
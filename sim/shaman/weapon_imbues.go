@@ -319,10 +319,11 @@ func (shaman *Shaman) newEarthlivingImbueSpell() *core.Spell {
 	glyphBonus := core.Ternary(shaman.HasPrimeGlyph(proto.ShamanPrimeGlyph_GlyphOfEarthlivingWeapon), 1.2, 1.0)
 
 	return shaman.RegisterSpell(core.SpellConfig{
-		ActionID:    core.ActionID{SpellID: 51730},
-		SpellSchool: core.SpellSchoolNature,
-		ProcMask:    core.ProcMaskEmpty,
-		Flags:       core.SpellFlagPassiveSpell,
+		ActionID:       core.ActionID{SpellID: 51730},
+		SpellSchool:    core.SpellSchoolNature,
+		ProcMask:       core.ProcMaskEmpty,
+		Flags:          core.SpellFlagHelpful | core.SpellFlagPassiveSpell,
+		ClassSpellMask: SpellMaskEarthliving,
 
 		DamageMultiplier: 1,
 		ThreatMultiplier: 1,
@@ -377,6 +378,14 @@ func (shaman *Shaman) RegisterEarthlivingImbue(procMask core.ProcMask) {
 
 	imbueSpell := shaman.newEarthlivingImbueSpell()
 
+	// Blessing of the Eternals makes Earthliving more likely to trigger on targets at or below 35% health.
+	procChance := func(target *core.Unit) float64 {
+		if target.HasHealthBar() && target.CurrentHealthPercent() <= 0.35 {
+			return 0.2 * (1 + 0.4*float64(shaman.Talents.BlessingOfTheEternals))
+		}
+		return 0.2
+	}
+
 	aura := shaman.RegisterAura(core.Aura{
 		Label:    "Earthliving Imbue",
 		Duration: core.NeverExpires,
@@ -384,15 +393,15 @@ func (shaman *Shaman) RegisterEarthlivingImbue(procMask core.ProcMask) {
 			aura.Activate(sim)
 		},
 		OnHealDealt: func(aura *core.Aura, sim *core.Simulation, spell *core.Spell, result *core.SpellResult) {
-			if spell != shaman.ChainHeal && spell != shaman.HealingSurge && spell != shaman.HealingWave && spell != shaman.Riptide {
+			if !spell.Matches(SpellMaskDirectHeal) {
 				return
 			}
 
-			if procMask.Matches(core.ProcMaskMeleeMH) && sim.RandomFloat("earthliving") < 0.2 {
+			if procMask.Matches(core.ProcMaskMeleeMH) && sim.RandomFloat("earthliving") < procChance(result.Target) {
 				imbueSpell.Cast(sim, result.Target)
 			}
 
-			if procMask.Matches(core.ProcMaskMeleeOH) && sim.RandomFloat("earthliving") < 0.2 {
+			if procMask.Matches(core.ProcMaskMeleeOH) && sim.RandomFloat("earthliving") < procChance(result.Target) {
 				imbueSpell.Cast(sim, result.Target)
 			}
 		},
@@ -113,6 +113,11 @@ func (warlock *Warlock) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	raidBuffs.FelIntelligence = warlock.Options.Summon == proto.WarlockOptions_Felhunter
 }
 
+func (warlock *Warlock) AddRosterBuffs(_ *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	debuffs.CurseOfElements = true
+	debuffs.ShadowAndFlame = warlock.Talents.ShadowAndFlame > 0
+}
+
 func (warlock *Warlock) Reset(sim *core.Simulation) {
 	warlock.SoulShards = 3
 }
@@ -12,8 +12,7 @@ func (war *ArmsWarrior) RegisterBladestorm() {
 		return
 	}
 	actionID := core.ActionID{SpellID: 46924}
-	numHits := war.Env.GetNumTargets() // 1 hit per target
-	results := make([]*core.SpellResult, numHits)
+	results := make([]*core.SpellResult, len(war.Env.Encounter.TargetUnits))
 
 	bladestorm := war.RegisterSpell(core.SpellConfig{
 		ActionID:       actionID,
@@ -49,6 +48,7 @@ func (war *ArmsWarrior) RegisterBladestorm() {
 			OnTick: func(sim *core.Simulation, _ *core.Unit, dot *core.Dot) {
 				target := war.CurrentTarget
 				spell := dot.Spell
+				numHits := sim.GetNumTargets() // 1 hit per target
 				curTarget := target
 				for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
 					baseDamage := 1.5 * spell.Unit.MHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
@@ -7,9 +7,7 @@ import (
 )
 
 func (warrior *Warrior) RegisterHeroicLeap() {
-
-	numHits := warrior.Env.GetNumTargets()
-	results := make([]*core.SpellResult, numHits)
+	results := make([]*core.SpellResult, len(warrior.Env.Encounter.TargetUnits))
 
 	warrior.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 6544},
@@ -39,6 +37,7 @@ func (warrior *Warrior) RegisterHeroicLeap() {
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
 			baseDamage := 1 + 0.5*spell.MeleeAttackPower()
+			numHits := sim.GetNumTargets()
 			curTarget := target
 
 			for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
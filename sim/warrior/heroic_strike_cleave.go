@@ -54,8 +54,7 @@ func (warrior *Warrior) RegisterHeroicStrikeSpell() {
 
 func (warrior *Warrior) RegisterCleaveSpell() {
 	targets := core.TernaryInt32(warrior.HasMajorGlyph(proto.WarriorMajorGlyph_GlyphOfCleaving), 3, 2)
-	numHits := min(targets, warrior.Env.GetNumTargets())
-	results := make([]*core.SpellResult, numHits)
+	results := make([]*core.SpellResult, targets)
 
 	warrior.Cleave = warrior.RegisterSpell(core.SpellConfig{
 		ActionID:       core.ActionID{SpellID: 845},
@@ -85,6 +84,7 @@ func (warrior *Warrior) RegisterCleaveSpell() {
 		CritMultiplier:   warrior.DefaultMeleeCritMultiplier(),
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			numHits := min(targets, sim.GetNumTargets())
 			curTarget := target
 			for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
 				baseDamage := 6 + (spell.MeleeAttackPower() * 0.45)
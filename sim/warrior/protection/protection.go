@@ -143,3 +143,10 @@ func (war *ProtectionWarrior) Initialize() {
 func (war *ProtectionWarrior) Reset(sim *core.Simulation) {
 	war.Warrior.Reset(sim)
 }
+
+func (war *ProtectionWarrior) AddRosterBuffs(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	war.Warrior.AddRosterBuffs(raidBuffs, debuffs)
+	raidBuffs.CommandingShout = true
+	debuffs.ThunderClap = true
+	debuffs.DemoralizingShout = true
+}
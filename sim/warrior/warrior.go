@@ -158,6 +158,12 @@ func (warrior *Warrior) AddRaidBuffs(raidBuffs *proto.RaidBuffs) {
 	}
 }
 
+func (warrior *Warrior) AddRosterBuffs(raidBuffs *proto.RaidBuffs, debuffs *proto.Debuffs) {
+	raidBuffs.BattleShout = true
+	debuffs.SunderArmor = true
+	debuffs.BloodFrenzy = warrior.Talents.BloodFrenzy > 0
+}
+
 func (warrior *Warrior) AddPartyBuffs(_ *proto.PartyBuffs) {
 }
 
@@ -9,8 +9,7 @@ import (
 
 func (warrior *Warrior) RegisterWhirlwindSpell() {
 	actionID := core.ActionID{SpellID: 1680}
-	numHits := warrior.Env.GetNumTargets() // Whirlwind is uncapped in Cata
-	results := make([]*core.SpellResult, numHits)
+	results := make([]*core.SpellResult, len(warrior.Env.Encounter.TargetUnits))
 
 	var whirlwindOH *core.Spell
 	if warrior.AutoAttacks.IsDualWielding && warrior.GetOHWeapon().WeaponType != proto.WeaponType_WeaponTypeStaff &&
@@ -29,6 +28,7 @@ func (warrior *Warrior) RegisterWhirlwindSpell() {
 			BonusCoefficient: 1,
 
 			ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+				numHits := sim.GetNumTargets() // Whirlwind is uncapped in Cata
 				curTarget := target
 				for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
 					baseDamage := 0.65 * spell.Unit.OHNormalizedWeaponDamage(sim, spell.MeleeAttackPower())
@@ -77,6 +77,7 @@ func (warrior *Warrior) RegisterWhirlwindSpell() {
 		BonusCoefficient: 1,
 
 		ApplyEffects: func(sim *core.Simulation, target *core.Unit, spell *core.Spell) {
+			numHits := sim.GetNumTargets() // Whirlwind is uncapped in Cata
 			curTarget := target
 			numLandedHits := 0
 			for hitIndex := int32(0); hitIndex < numHits; hitIndex++ {
@@ -36,6 +36,9 @@ func main() {
 	js.Global().Set("statWeightsAsync", js.FuncOf(statWeightsAsync))
 	js.Global().Set("statWeightRequests", js.FuncOf(statWeightRequests))
 	js.Global().Set("statWeightCompute", js.FuncOf(statWeightCompute))
+	js.Global().Set("optimizeGear", js.FuncOf(optimizeGear))
+	js.Global().Set("optimizeRaidComposition", js.FuncOf(optimizeRaidComposition))
+	js.Global().Set("tuneAPL", js.FuncOf(tuneAPL))
 	js.Global().Set("bulkSimAsync", js.FuncOf(bulkSimAsync))
 	js.Global().Set("abortById", js.FuncOf(abortById))
 	js.Global().Set("bulkSimCombos", js.FuncOf(bulkSimCombos))
@@ -204,6 +207,66 @@ func statWeights(this js.Value, args []js.Value) interface{} {
 	return outArray
 }
 
+func optimizeGear(this js.Value, args []js.Value) interface{} {
+	ogr := &proto.OptimizeGearRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), ogr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.OptimizeGear(ogr)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
+func optimizeRaidComposition(this js.Value, args []js.Value) interface{} {
+	orcr := &proto.OptimizeRaidCompositionRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), orcr); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.OptimizeRaidComposition(orcr)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
+func tuneAPL(this js.Value, args []js.Value) interface{} {
+	tar := &proto.TuneAPLRequest{}
+	if err := googleProto.Unmarshal(getArgsBinary(args[0]), tar); err != nil {
+		log.Printf("Failed to parse request: %s", err)
+		return nil
+	}
+	result := core.TuneAPL(tar)
+
+	outbytes, err := googleProto.Marshal(result)
+	if err != nil {
+		log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+		return nil
+	}
+
+	outArray := js.Global().Get("Uint8Array").New(len(outbytes))
+	js.CopyBytesToJS(outArray, outbytes)
+
+	return outArray
+}
+
 func statWeightsAsync(this js.Value, args []js.Value) interface{} {
 	rsr := &proto.StatWeightsRequest{}
 	if err := googleProto.Unmarshal(getArgsBinary(args[0]), rsr); err != nil {
@@ -321,7 +384,9 @@ func raidSimResultCombination(this js.Value, args []js.Value) interface{} {
 				res = &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: errStr}}
 			}
 		}()
-		return core.CombineConcurrentSimResults(combRequest.Results, false)
+		res = core.CombineConcurrentSimResults(combRequest.Results, false)
+		core.ClearQuantileDigests(res)
+		return res
 	}()
 
 	outbytes, err := googleProto.Marshal(combineRes)
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	proto "github.com/wowsims/cata/sim/core/proto"
+	"google.golang.org/protobuf/encoding/protojson"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// Buffered updates per stream subscriber. Intermediate updates are dropped for
+// subscribers that fall this far behind, the final result never is.
+const subscriberBufferSize = 100
+
+type asyncProgress struct {
+	id             string
+	latestProgress atomic.Value
+
+	subMut      sync.Mutex
+	subscribers map[chan *proto.ProgressMetrics]struct{}
+	closed      bool
+}
+
+func newAsyncProgress(id string) *asyncProgress {
+	simProgress := &asyncProgress{
+		id:          id,
+		subscribers: map[chan *proto.ProgressMetrics]struct{}{},
+	}
+	simProgress.latestProgress.Store(&proto.ProgressMetrics{})
+	return simProgress
+}
+
+func isFinalProgress(progMetric *proto.ProgressMetrics) bool {
+	return progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil
+}
+
+func (ap *asyncProgress) latest() *proto.ProgressMetrics {
+	return ap.latestProgress.Load().(*proto.ProgressMetrics)
+}
+
+// Stores a new progress update and pushes it to all subscribers. A final
+// update also closes all subscriptions.
+func (ap *asyncProgress) publish(progMetric *proto.ProgressMetrics) {
+	ap.subMut.Lock()
+	defer ap.subMut.Unlock()
+
+	ap.latestProgress.Store(progMetric)
+	for sub := range ap.subscribers {
+		select {
+		case sub <- progMetric:
+		default:
+		}
+	}
+
+	if isFinalProgress(progMetric) {
+		ap.closeLocked()
+	}
+}
+
+// Ends all subscriptions without a final result, e.g. when a sim stops reporting progress.
+func (ap *asyncProgress) close() {
+	ap.subMut.Lock()
+	defer ap.subMut.Unlock()
+	ap.closeLocked()
+}
+
+func (ap *asyncProgress) closeLocked() {
+	for sub := range ap.subscribers {
+		close(sub)
+	}
+	ap.subscribers = nil
+	ap.closed = true
+}
+
+// Returns a channel receiving the current progress followed by every later
+// update. The channel is closed once the sim finishes.
+func (ap *asyncProgress) subscribe() (chan *proto.ProgressMetrics, func()) {
+	ap.subMut.Lock()
+	defer ap.subMut.Unlock()
+
+	sub := make(chan *proto.ProgressMetrics, subscriberBufferSize)
+	sub <- ap.latest()
+	if ap.closed {
+		close(sub)
+		return sub, func() {}
+	}
+
+	ap.subscribers[sub] = struct{}{}
+	return sub, func() {
+		ap.subMut.Lock()
+		delete(ap.subscribers, sub)
+		ap.subMut.Unlock()
+	}
+}
+
+// handleProgressStream streams every ProgressMetrics update of an async sim as server-sent events.
+//
+// The sim is selected with the progressId query parameter. Each update is sent as a "progress"
+// event, and the final result as a "final" event after which the stream ends. Event data is
+// protojson by default, or base64 encoded protobuf when the format query parameter is "proto".
+func (s *server) handleProgressStream(w http.ResponseWriter, r *http.Request) {
+	progressId := r.URL.Query().Get("progressId")
+	s.progMut.RLock()
+	progress, ok := s.asyncProgresses[progressId]
+	s.progMut.RUnlock()
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	useProto := r.URL.Query().Get("format") == "proto"
+	updates, unsubscribe := progress.subscribe()
+	defer unsubscribe()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case progMetric, ok := <-updates:
+			if !ok {
+				// The final update may have been dropped for a slow subscriber.
+				progMetric = progress.latest()
+				if !isFinalProgress(progMetric) {
+					return
+				}
+			}
+
+			if err := writeProgressEvent(w, progMetric, useProto); err != nil {
+				log.Printf("[ERROR] Failed to write progress event: %s", err.Error())
+				return
+			}
+			flusher.Flush()
+
+			if isFinalProgress(progMetric) {
+				return
+			}
+		}
+	}
+}
+
+func writeProgressEvent(w http.ResponseWriter, progMetric *proto.ProgressMetrics, useProto bool) error {
+	var data string
+	if useProto {
+		outbytes, err := googleProto.Marshal(progMetric)
+		if err != nil {
+			return err
+		}
+		data = base64.StdEncoding.EncodeToString(outbytes)
+	} else {
+		outbytes, err := protojson.Marshal(progMetric)
+		if err != nil {
+			return err
+		}
+		data = string(outbytes)
+	}
+
+	event := "progress"
+	if isFinalProgress(progMetric) {
+		event = "final"
+	}
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+	return err
+}
@@ -0,0 +1,397 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	uuid "github.com/google/uuid"
+	"github.com/wowsims/cata/sim/core"
+	proto "github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+const (
+	// Each worker gets several splits of a sim so faster machines can pick up more of the work.
+	splitsPerRemoteWorker = 4
+
+	// How often a split is attempted, on different workers if possible, before the sim fails.
+	maxRemoteAttempts = 3
+
+	// Workers failing this many requests in a row are skipped until they register
+	// again, or the cooldown has passed and a split is sent to probe them.
+	maxRemoteWorkerFailures = 3
+	remoteWorkerCooldown    = time.Minute
+
+	// How long a worker gets to finish a split before it's retried elsewhere.
+	remoteSplitTimeout = time.Minute * 10
+
+	// How often workers re-register with their coordinator.
+	workerHeartbeatInterval = time.Second * 30
+
+	// Header carrying the -worker-token when registering with a coordinator.
+	workerTokenHeader = "X-Worker-Token"
+)
+
+type remoteWorker struct {
+	address  string
+	inFlight int
+	failures int
+
+	// When a worker which failed too often may be probed again.
+	retryAt time.Time
+}
+
+// Workers are usable unless they failed too often and are still cooling down.
+func (worker *remoteWorker) healthy(now time.Time) bool {
+	return worker.failures < maxRemoteWorkerFailures || !now.Before(worker.retryAt)
+}
+
+// remoteWorkerPool fans sims out to other sim servers, registered by address.
+//
+// Sims are split by iterations with core.SplitSimRequestForConcurrency, sent to
+// the workers' /raidSimConcurrent endpoint as protobuf, and merged back
+// together with core.CombineConcurrentSimResults.
+type remoteWorkerPool struct {
+	mut     sync.Mutex
+	workers map[string]*remoteWorker
+	client  *http.Client
+
+	splitTimeout time.Duration
+}
+
+func newRemoteWorkerPool() *remoteWorkerPool {
+	return &remoteWorkerPool{
+		workers:      map[string]*remoteWorker{},
+		client:       &http.Client{},
+		splitTimeout: remoteSplitTimeout,
+	}
+}
+
+// Adds a worker, or resets the failure count of an already registered one.
+func (p *remoteWorkerPool) register(address string) {
+	address = strings.TrimSuffix(address, "/")
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+
+	p.mut.Lock()
+	defer p.mut.Unlock()
+	if worker, ok := p.workers[address]; ok {
+		worker.failures = 0
+		return
+	}
+	p.workers[address] = &remoteWorker{address: address}
+	log.Printf("Registered remote worker %s", address)
+}
+
+func (p *remoteWorkerPool) numHealthy() int {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	now := time.Now()
+	numHealthy := 0
+	for _, worker := range p.workers {
+		if worker.healthy(now) {
+			numHealthy++
+		}
+	}
+	return numHealthy
+}
+
+// Picks the healthy worker with the least requests in flight, preferring workers not in tried.
+func (p *remoteWorkerPool) acquire(tried map[string]bool) *remoteWorker {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	now := time.Now()
+	var best *remoteWorker
+	for _, worker := range p.workers {
+		if !worker.healthy(now) {
+			continue
+		}
+		if best == nil || (tried[best.address] && !tried[worker.address]) ||
+			(tried[best.address] == tried[worker.address] && worker.inFlight < best.inFlight) {
+			best = worker
+		}
+	}
+	if best != nil {
+		best.inFlight++
+		if best.failures >= maxRemoteWorkerFailures {
+			// Only a single split probes a worker coming out of its cooldown.
+			best.retryAt = now.Add(remoteWorkerCooldown)
+		}
+	}
+	return best
+}
+
+func (p *remoteWorkerPool) release(worker *remoteWorker, failed bool) {
+	p.mut.Lock()
+	defer p.mut.Unlock()
+
+	worker.inFlight--
+	if failed {
+		worker.failures++
+		if worker.failures >= maxRemoteWorkerFailures {
+			worker.retryAt = time.Now().Add(remoteWorkerCooldown)
+		}
+		if worker.failures == maxRemoteWorkerFailures {
+			log.Printf("Remote worker %s failed %d times in a row, skipping it for %s or until it registers again.", worker.address, worker.failures, remoteWorkerCooldown)
+		}
+	} else {
+		worker.failures = 0
+	}
+}
+
+// Runs a sim on a single remote worker, retrying on other workers if a worker can't be reached.
+//
+// The request is given a fresh ID, which the worker registers for aborting.
+// Whenever a request is dropped, because ctx is cancelled or the worker failed
+// or timed out, the abort is forwarded to the worker so it doesn't keep running
+// a split which is retried elsewhere.
+func (p *remoteWorkerPool) runRaidSim(ctx context.Context, request *proto.RaidSimRequest) (*proto.RaidSimResult, error) {
+	request = googleProto.Clone(request).(*proto.RaidSimRequest)
+	request.RequestId = uuid.NewString()
+	msgBytes, err := googleProto.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	tried := map[string]bool{}
+	var lastErr error
+	for attempt := 0; attempt < maxRemoteAttempts; attempt++ {
+		worker := p.acquire(tried)
+		if worker == nil {
+			break
+		}
+		tried[worker.address] = true
+
+		result := &proto.RaidSimResult{}
+		err := p.post(ctx, worker, "/raidSimConcurrent", msgBytes, p.splitTimeout, result)
+		if err != nil {
+			p.abort(worker, request.RequestId)
+		}
+		if ctx.Err() != nil {
+			p.release(worker, false)
+			return nil, ctx.Err()
+		}
+		p.release(worker, err != nil)
+		if err == nil {
+			return result, nil
+		}
+		log.Printf("Remote worker %s failed: %s", worker.address, err.Error())
+		lastErr = err
+	}
+
+	if lastErr == nil {
+		lastErr = errors.New("no remote workers available")
+	}
+	return nil, lastErr
+}
+
+// Tells a worker to stop running the sim with the given request ID.
+func (p *remoteWorkerPool) abort(worker *remoteWorker, requestId string) {
+	msgBytes, err := googleProto.Marshal(&proto.AbortRequest{RequestId: requestId})
+	if err != nil {
+		return
+	}
+	if err := p.post(context.Background(), worker, "/abortById", msgBytes, time.Second*10, &proto.AbortResponse{}); err != nil {
+		log.Printf("Failed to abort sim on remote worker %s: %s", worker.address, err.Error())
+	}
+}
+
+// Posts a proto request to a worker endpoint, decoding the response into result.
+func (p *remoteWorkerPool) post(ctx context.Context, worker *remoteWorker, endpoint string, msgBytes []byte, timeout time.Duration, result googleProto.Message) error {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, worker.address+endpoint, bytes.NewReader(msgBytes))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return googleProto.Unmarshal(body, result)
+}
+
+// Splits a sim across the remote workers and combines their results.
+// Progress, if not nil, receives an update whenever a split finishes.
+func (p *remoteWorkerPool) runRaidSimDistributed(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, signals simsignals.Signals) *proto.RaidSimResult {
+	splitRes := core.SplitSimRequestForConcurrency(request, int32(max(p.numHealthy(), 1)*splitsPerRemoteWorker))
+	if splitRes.ErrorResult != "" {
+		return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: splitRes.ErrorResult}}
+	}
+
+	type splitResult struct {
+		index  int
+		result *proto.RaidSimResult
+		err    error
+	}
+	// Cancelling the context drops the in-flight requests and aborts them on
+	// the workers. The channel is buffered, so splits still finishing after
+	// this returns don't block.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	splitResults := make(chan splitResult, len(splitRes.Requests))
+	for i, splitRequest := range splitRes.Requests {
+		go func(i int, splitRequest *proto.RaidSimRequest) {
+			result, err := p.runRaidSim(ctx, splitRequest)
+			splitResults <- splitResult{index: i, result: result, err: err}
+		}(i, splitRequest)
+	}
+
+	results := make([]*proto.RaidSimResult, len(splitRes.Requests))
+	var iterationsDone int32
+	for range splitRes.Requests {
+		var res splitResult
+		select {
+		case res = <-splitResults:
+		case <-signals.Abort.Done():
+			return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Type: proto.ErrorOutcomeType_ErrorOutcomeAborted}}
+		}
+		if res.err != nil {
+			return &proto.RaidSimResult{Error: &proto.ErrorOutcome{Message: "Remote sim failed: " + res.err.Error()}}
+		}
+		if res.result.Error != nil {
+			return res.result
+		}
+
+		results[res.index] = res.result
+		iterationsDone += splitRes.Requests[res.index].SimOptions.Iterations
+		if progress != nil {
+			progress <- &proto.ProgressMetrics{
+				TotalIterations:     request.SimOptions.Iterations,
+				CompletedIterations: iterationsDone,
+			}
+		}
+	}
+
+	result := core.CombineConcurrentSimResults(results, request.SimOptions.Debug)
+	if !request.SimOptions.SaveDigests {
+		core.ClearQuantileDigests(result)
+	}
+	return result
+}
+
+// Like core.RunRaidSimConcurrentAsync, but runs the sim on the remote workers.
+func (p *remoteWorkerPool) runRaidSimAsync(request *proto.RaidSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	signals, err := simsignals.RegisterWithId(requestId)
+	if err != nil {
+		progress <- &proto.ProgressMetrics{
+			FinalRaidResult: &proto.RaidSimResult{
+				Error: &proto.ErrorOutcome{
+					Message: "Couldn't register for signal API: " + err.Error(),
+				},
+			},
+		}
+		return
+	}
+	go func() {
+		defer simsignals.UnregisterId(requestId)
+		result := p.runRaidSimDistributed(request, progress, signals)
+		progress <- &proto.ProgressMetrics{
+			TotalIterations:     request.SimOptions.Iterations,
+			CompletedIterations: request.SimOptions.Iterations,
+			FinalRaidResult:     result,
+		}
+	}()
+}
+
+// Like core.RunBulkSimAsync, but runs each sim of the bulk sim on the remote workers.
+func (p *remoteWorkerPool) runBulkSimAsync(request *proto.BulkSimRequest, progress chan *proto.ProgressMetrics, requestId string) {
+	// The bulk sim strips the player's extra database before running any sims,
+	// so keep it around for the workers.
+	var database *proto.SimDatabase
+	for _, party := range request.GetBaseSettings().GetRaid().GetParties() {
+		for _, player := range party.GetPlayers() {
+			if player.GetDatabase() != nil {
+				database = googleProto.Clone(player.Database).(*proto.SimDatabase)
+			}
+		}
+	}
+
+	core.RunBulkSimAsyncWithRunner(request, progress, requestId, func(rsr *proto.RaidSimRequest, signals simsignals.Signals) *proto.RaidSimResult {
+		if database != nil {
+			rsr = googleProto.Clone(rsr).(*proto.RaidSimRequest)
+			for _, player := range rsr.Raid.Parties[0].Players {
+				player.Database = database
+			}
+		}
+		return p.runRaidSimDistributed(rsr, nil, signals)
+	})
+}
+
+// Adds the server given by the address query parameter as a remote worker,
+// if the request carries the worker token.
+func (s *server) handleRegisterWorker(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	token := r.Header.Get(workerTokenHeader)
+	if s.workerToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.workerToken)) != 1 {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	address := r.URL.Query().Get("address")
+	if address == "" {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+	s.remote.register(address)
+}
+
+// Registers this server as a worker of the coordinator, and keeps doing so
+// periodically so the coordinator picks it up again after a restart.
+func registerWithCoordinator(coordinator string, advertiseAddress string, token string) {
+	coordinator = strings.TrimSuffix(coordinator, "/")
+	registerURL := coordinator + "/registerWorker?address=" + url.QueryEscape(advertiseAddress)
+	for {
+		if err := sendWorkerRegistration(registerURL, token); err != nil {
+			log.Printf("Failed to register with coordinator %s: %s", coordinator, err.Error())
+		}
+		time.Sleep(workerHeartbeatInterval)
+	}
+}
+
+func sendWorkerRegistration(registerURL string, token string) error {
+	req, err := http.NewRequest(http.MethodPost, registerURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set(workerTokenHeader, token)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return nil
+}
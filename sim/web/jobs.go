@@ -0,0 +1,354 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	proto "github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/simsignals"
+	googleProto "google.golang.org/protobuf/proto"
+)
+
+// An async API request waiting for, or running on, a worker.
+//
+// Exported fields are persisted by the jobStore so that queued and finished
+// jobs survive a server restart.
+type job struct {
+	Id        string `json:"id"`
+	Endpoint  string `json:"endpoint"`
+	RequestId string `json:"requestId"`
+	Priority  int32  `json:"priority"`
+	Owner     string `json:"owner"`
+	Seq       uint64 `json:"seq"`
+	Request   []byte `json:"request"`
+
+	// Marshalled final ProgressMetrics, once the job has finished.
+	Final      []byte    `json:"final,omitempty"`
+	FinishedAt time.Time `json:"finishedAt,omitempty"`
+
+	progress  *asyncProgress
+	cancelled bool // Guarded by jobQueue.mut.
+}
+
+// jobQueue runs async API requests on a bounded pool of workers.
+//
+// Jobs with a higher priority run first. Between jobs of equal priority, the
+// job of the owner with the fewest running jobs goes first so that a single
+// user can't starve everyone else, and after that jobs run in submission order.
+type jobQueue struct {
+	server *server
+	store  *jobStore
+
+	mut            sync.Mutex
+	cond           *sync.Cond
+	queued         []*job
+	running        map[string]*job
+	runningByOwner map[string]int
+	nextSeq        uint64
+}
+
+func newJobQueue(s *server, store *jobStore) *jobQueue {
+	jq := &jobQueue{
+		server:         s,
+		store:          store,
+		running:        map[string]*job{},
+		runningByOwner: map[string]int{},
+	}
+	jq.cond = sync.NewCond(&jq.mut)
+	return jq
+}
+
+// Restores any stored jobs and starts the workers.
+func (jq *jobQueue) start(workers int) {
+	if jq.store != nil {
+		jq.restore()
+	}
+
+	for i := 0; i < max(workers, 1); i++ {
+		go jq.worker()
+	}
+}
+
+// Reloads persisted jobs. Finished jobs become fetchable again, and jobs which
+// were queued or interrupted while running are queued again.
+func (jq *jobQueue) restore() {
+	jobs, err := jq.store.loadAll()
+	if err != nil {
+		log.Printf("[ERROR] Failed to load stored jobs: %s", err.Error())
+	}
+
+	for _, j := range jobs {
+		j.progress = jq.server.addSimWithId(j.Id)
+		jq.nextSeq = max(jq.nextSeq, j.Seq+1)
+
+		if j.Final == nil {
+			jq.queued = append(jq.queued, j)
+			continue
+		}
+
+		final := &proto.ProgressMetrics{}
+		if err := googleProto.Unmarshal(j.Final, final); err != nil {
+			log.Printf("[ERROR] Failed to parse stored result for job %s: %s", j.Id, err.Error())
+			jq.server.removeSim(j.Id)
+			continue
+		}
+		j.progress.publish(final)
+		if jq.server.resultTTL > 0 {
+			jq.server.expireSim(j.Id, time.Until(j.FinishedAt.Add(jq.server.resultTTL)))
+		}
+	}
+
+	jq.sortQueue()
+	jq.publishQueuePositions()
+	if len(jobs) > 0 {
+		log.Printf("Restored %d stored jobs, %d queued.", len(jobs), len(jq.queued))
+	}
+}
+
+func (jq *jobQueue) submit(j *job) {
+	jq.mut.Lock()
+	defer jq.mut.Unlock()
+
+	j.Seq = jq.nextSeq
+	jq.nextSeq++
+	jq.save(j)
+
+	jq.queued = append(jq.queued, j)
+	jq.sortQueue()
+	jq.publishQueuePositions()
+	jq.cond.Signal()
+}
+
+// Cancels a queued or running job. Returns false if the job is unknown or already finished.
+func (jq *jobQueue) cancel(id string) (*job, bool) {
+	jq.mut.Lock()
+	if j, ok := jq.running[id]; ok {
+		// The sim may not have registered its signals yet, in which case run() aborts it once it has.
+		j.cancelled = true
+		jq.mut.Unlock()
+		simsignals.AbortById(j.RequestId)
+		return j, true
+	}
+
+	for i, j := range jq.queued {
+		if j.Id != id {
+			continue
+		}
+		jq.queued = append(jq.queued[:i], jq.queued[i+1:]...)
+		jq.publishQueuePositions()
+		jq.mut.Unlock()
+
+		jq.finish(j, asyncAPIHandlers[j.Endpoint].errorResult(&proto.ErrorOutcome{
+			Type:    proto.ErrorOutcomeType_ErrorOutcomeAborted,
+			Message: "Cancelled while queued",
+		}))
+		return j, true
+	}
+
+	jq.mut.Unlock()
+	return nil, false
+}
+
+func (jq *jobQueue) worker() {
+	for {
+		jq.mut.Lock()
+		for len(jq.queued) == 0 {
+			jq.cond.Wait()
+		}
+		jq.sortQueue()
+		j := jq.queued[0]
+		jq.queued = jq.queued[1:]
+		jq.running[j.Id] = j
+		jq.runningByOwner[j.Owner]++
+		jq.publishQueuePositions()
+		jq.mut.Unlock()
+
+		jq.run(j)
+
+		jq.mut.Lock()
+		delete(jq.running, j.Id)
+		jq.runningByOwner[j.Owner]--
+		if jq.runningByOwner[j.Owner] == 0 {
+			delete(jq.runningByOwner, j.Owner)
+		}
+		jq.mut.Unlock()
+	}
+}
+
+func (jq *jobQueue) run(j *job) {
+	handler, ok := asyncAPIHandlers[j.Endpoint]
+	if !ok {
+		log.Printf("[ERROR] Dropping job %s with unknown endpoint %s", j.Id, j.Endpoint)
+		jq.server.removeSim(j.Id)
+		return
+	}
+
+	msg := handler.msg()
+	if err := googleProto.Unmarshal(j.Request, msg); err != nil {
+		jq.finish(j, handler.errorResult(&proto.ErrorOutcome{Message: "Failed to parse request: " + err.Error()}))
+		return
+	}
+
+	// reporter channel is handed into the core simulation.
+	//  as the simulation advances it will push changes to the channel
+	//  these changes will be consumed below so the asyncProgress endpoints can fetch the results.
+	reporter := make(chan *proto.ProgressMetrics, 100)
+	j.progress.publish(&proto.ProgressMetrics{})
+	if handler.handleRemote != nil && jq.server.remote.numHealthy() > 0 {
+		handler.handleRemote(jq.server.remote, msg, reporter, j.RequestId)
+	} else {
+		handler.handle(msg, reporter, j.RequestId)
+	}
+
+	jq.mut.Lock()
+	if j.cancelled {
+		simsignals.AbortById(j.RequestId)
+	}
+	jq.mut.Unlock()
+
+	for {
+		select {
+		case <-time.After(time.Minute * 10):
+			// if we get no progress after 10 minutes, delete the pending sim and stop waiting for it.
+			simsignals.AbortById(j.RequestId)
+			jq.server.removeSim(j.Id)
+			j.progress.close()
+			return
+		case progMetric := <-reporter:
+			if progMetric == nil {
+				return
+			}
+			if isFinalProgress(progMetric) {
+				jq.finish(j, progMetric)
+				return
+			}
+			j.progress.publish(progMetric)
+		}
+	}
+}
+
+// Publishes the final result of a job, persists it and schedules its expiry.
+func (jq *jobQueue) finish(j *job, final *proto.ProgressMetrics) {
+	j.FinishedAt = time.Now()
+	if jq.store != nil {
+		outbytes, err := googleProto.Marshal(final)
+		if err != nil {
+			log.Printf("[ERROR] Failed to marshal result of job %s: %s", j.Id, err.Error())
+		}
+		j.Final = outbytes
+		jq.save(j)
+	}
+
+	j.progress.publish(final)
+	if jq.server.resultTTL > 0 {
+		jq.server.expireSim(j.Id, jq.server.resultTTL)
+	}
+}
+
+func (jq *jobQueue) save(j *job) {
+	if jq.store == nil {
+		return
+	}
+	if err := jq.store.save(j); err != nil {
+		log.Printf("[ERROR] Failed to store job %s: %s", j.Id, err.Error())
+	}
+}
+
+// Must be called with jq.mut held.
+func (jq *jobQueue) sortQueue() {
+	sort.SliceStable(jq.queued, func(a, b int) bool {
+		ja, jb := jq.queued[a], jq.queued[b]
+		if ja.Priority != jb.Priority {
+			return ja.Priority > jb.Priority
+		}
+		if ra, rb := jq.runningByOwner[ja.Owner], jq.runningByOwner[jb.Owner]; ra != rb {
+			return ra < rb
+		}
+		return ja.Seq < jb.Seq
+	})
+}
+
+// Must be called with jq.mut held.
+func (jq *jobQueue) publishQueuePositions() {
+	for i, j := range jq.queued {
+		j.progress.publish(&proto.ProgressMetrics{QueuePosition: int32(i + 1)})
+	}
+}
+
+func (jq *jobQueue) numQueued() int {
+	jq.mut.Lock()
+	defer jq.mut.Unlock()
+	return len(jq.queued)
+}
+
+// jobStore persists jobs as one JSON file per job in a directory.
+type jobStore struct {
+	dir string
+}
+
+func newJobStore(dir string) (*jobStore, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &jobStore{dir: dir}, nil
+}
+
+func (js *jobStore) path(id string) string {
+	return filepath.Join(js.dir, id+".job")
+}
+
+func (js *jobStore) save(j *job) error {
+	data, err := json.Marshal(j)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temporary file first so a crash never leaves a partial job behind.
+	tmpPath := js.path(j.Id) + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, js.path(j.Id))
+}
+
+func (js *jobStore) remove(id string) {
+	if js == nil {
+		return
+	}
+	if err := os.Remove(js.path(id)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Printf("[ERROR] Failed to remove stored job %s: %s", id, err.Error())
+	}
+}
+
+func (js *jobStore) loadAll() ([]*job, error) {
+	entries, err := os.ReadDir(js.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var jobs []*job
+	for _, entry := range entries {
+		if !strings.HasSuffix(entry.Name(), ".job") {
+			continue
+		}
+
+		data, err := os.ReadFile(filepath.Join(js.dir, entry.Name()))
+		if err != nil {
+			return jobs, err
+		}
+		j := &job{}
+		if err := json.Unmarshal(data, j); err != nil {
+			log.Printf("[ERROR] Skipping unreadable stored job %s: %s", entry.Name(), err.Error())
+			continue
+		}
+		jobs = append(jobs, j)
+	}
+	return jobs, nil
+}
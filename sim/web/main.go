@@ -2,19 +2,21 @@ package main
 
 import (
 	"bufio"
+	"crypto/subtle"
 	"encoding/json"
 	"flag"
 	"fmt"
 	"io"
 	"log"
+	"net"
 	"net/http"
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
 	"runtime/pprof"
+	"strconv"
 	"strings"
 	"sync"
-	"sync/atomic"
 	"syscall"
 	"time"
 
@@ -38,6 +40,9 @@ var (
 	outdated int
 )
 
+// Header carrying the -operator-token on async requests.
+const operatorTokenHeader = "X-Operator-Token"
+
 func main() {
 	if Version == "" {
 		Version = "development"
@@ -48,6 +53,16 @@ func main() {
 	var host = flag.String("host", "localhost:3333", "URL to host the interface on.")
 	var launch = flag.Bool("launch", true, "auto launch browser")
 	var skipVersionCheck = flag.Bool("nvc", false, "set true to skip version check")
+	var resultTTL = flag.Duration("result-ttl", time.Minute*10, "How long finished async results stay retrievable. Set to 0 to delete results once fetched.")
+	var workers = flag.Int("workers", 2, "Maximum number of async sims running at once. Further requests are queued.")
+	var jobDir = flag.String("jobdir", "", "Directory to persist queued and finished async sims in, so they survive restarts. Disabled if empty.")
+	var remoteWorkers = flag.String("remote-workers", "", "Comma separated addresses of other sim servers to distribute async raid and bulk sims to.")
+	var coordinator = flag.String("coordinator", "", "Address of a coordinating sim server to register with as a remote worker.")
+	var advertise = flag.String("advertise", "", "Address the coordinator should use to reach this server. Defaults to -host.")
+	var workerToken = flag.String("worker-token", "", "Shared secret which workers send to register with a coordinator. Registration via -coordinator is disabled if empty.")
+	var operatorToken = flag.String("operator-token", "", "Secret which lets async requests set their priority and owner. Both are ignored if empty.")
+	var cacheSize = flag.Int("cache-size", 0, "Number of raid sim results to keep in memory and reuse for identical requests with a fixed seed. Disabled if 0 and -cache-dir is empty.")
+	var cacheDir = flag.String("cache-dir", "", "Directory to store cached raid sim results in, so they are reused across restarts.")
 
 	flag.Parse()
 
@@ -82,9 +97,35 @@ func main() {
 		}()
 	}
 
+	if *cacheSize > 0 || *cacheDir != "" {
+		cache, err := core.NewRaidSimResultCache(max(*cacheSize, core.DefaultResultCacheSize), *cacheDir, Version)
+		if err != nil {
+			log.Fatalf("Failed to create result cache: %s", err.Error())
+		}
+		core.SetRaidSimResultCache(cache)
+	}
+
 	s := &server{
 		progMut:         sync.RWMutex{},
 		asyncProgresses: map[string]*asyncProgress{},
+		resultTTL:       *resultTTL,
+		workers:         *workers,
+		jobDir:          *jobDir,
+		remote:          newRemoteWorkerPool(),
+		workerToken:     *workerToken,
+		operatorToken:   *operatorToken,
+	}
+	for _, address := range strings.Split(*remoteWorkers, ",") {
+		if address = strings.TrimSpace(address); address != "" {
+			s.remote.register(address)
+		}
+	}
+	if *coordinator != "" {
+		advertiseAddress := *advertise
+		if advertiseAddress == "" {
+			advertiseAddress = *host
+		}
+		go registerWithCoordinator(*coordinator, advertiseAddress, *workerToken)
 	}
 	s.runServer(*useFS, *host, *launch, *simName, *wasm, bufio.NewReader(os.Stdin))
 }
@@ -94,6 +135,9 @@ var handlers = map[string]apiHandler{
 	"/raidSim": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.RunRaidSim(msg.(*proto.RaidSimRequest))
 	}},
+	"/raidSimConcurrent": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.RunRaidSimConcurrent(msg.(*proto.RaidSimRequest))
+	}},
 	"/statWeights": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.StatWeights(msg.(*proto.StatWeightsRequest))
 	}},
@@ -103,6 +147,15 @@ var handlers = map[string]apiHandler{
 	"/statWeightCompute": {msg: func() googleProto.Message { return &proto.StatWeightsCalcRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.StatWeightCompute(msg.(*proto.StatWeightsCalcRequest))
 	}},
+	"/optimizeGear": {msg: func() googleProto.Message { return &proto.OptimizeGearRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.OptimizeGear(msg.(*proto.OptimizeGearRequest))
+	}},
+	"/optimizeRaidComposition": {msg: func() googleProto.Message { return &proto.OptimizeRaidCompositionRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.OptimizeRaidComposition(msg.(*proto.OptimizeRaidCompositionRequest))
+	}},
+	"/tuneAPL": {msg: func() googleProto.Message { return &proto.TuneAPLRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
+		return core.TuneAPL(msg.(*proto.TuneAPLRequest))
+	}},
 	"/computeStats": {msg: func() googleProto.Message { return &proto.ComputeStatsRequest{} }, handle: func(msg googleProto.Message) googleProto.Message {
 		return core.ComputeStats(msg.(*proto.ComputeStatsRequest))
 	}},
@@ -119,18 +172,46 @@ var handlers = map[string]apiHandler{
 var asyncAPIHandlers = map[string]asyncAPIHandler{
 	"/raidSimAsync": {msg: func() googleProto.Message { return &proto.RaidSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunRaidSimConcurrentAsync(msg.(*proto.RaidSimRequest), reporter, requestId)
+	}, handleRemote: func(pool *remoteWorkerPool, msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		pool.runRaidSimAsync(msg.(*proto.RaidSimRequest), reporter, requestId)
+	}, errorResult: func(err *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalRaidResult: &proto.RaidSimResult{Error: err}}
 	}},
 	"/statWeightsAsync": {msg: func() googleProto.Message { return &proto.StatWeightsRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.StatWeightsAsync(msg.(*proto.StatWeightsRequest), reporter, requestId)
+	}, errorResult: func(err *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalWeightResult: &proto.StatWeightsResult{Error: err}}
 	}},
 	"/bulkSimAsync": {msg: func() googleProto.Message { return &proto.BulkSimRequest{} }, handle: func(msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
 		core.RunBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
+	}, handleRemote: func(pool *remoteWorkerPool, msg googleProto.Message, reporter chan *proto.ProgressMetrics, requestId string) {
+		pool.runBulkSimAsync(msg.(*proto.BulkSimRequest), reporter, requestId)
+	}, errorResult: func(err *proto.ErrorOutcome) *proto.ProgressMetrics {
+		return &proto.ProgressMetrics{FinalBulkResult: &proto.BulkSimResult{Error: err}}
 	}},
 }
 
 type server struct {
 	progMut         sync.RWMutex
 	asyncProgresses map[string]*asyncProgress
+
+	// How long finished results are kept. If 0, results are deleted once fetched.
+	resultTTL time.Duration
+
+	workers int
+	jobDir  string
+	jobs    *jobQueue
+
+	// Other sim servers to distribute sims to, if any are registered.
+	remote *remoteWorkerPool
+
+	// Secret which other sim servers need to send to register as workers.
+	// Registration is disabled if empty.
+	workerToken string
+
+	// Secret which async requests need to send to set their priority and owner.
+	// Without it, jobs run at the default priority and are owned by the client's address.
+	operatorToken string
 }
 
 type apiHandler struct {
@@ -140,27 +221,42 @@ type apiHandler struct {
 type asyncAPIHandler struct {
 	msg    func() googleProto.Message
 	handle func(googleProto.Message, chan *proto.ProgressMetrics, string)
+	// Optional, used instead of handle when remote workers are registered.
+	handleRemote func(*remoteWorkerPool, googleProto.Message, chan *proto.ProgressMetrics, string)
+	errorResult  func(*proto.ErrorOutcome) *proto.ProgressMetrics
 }
 
-type asyncProgress struct {
-	id             string
-	latestProgress atomic.Value
+func (s *server) addNewSim() *asyncProgress {
+	return s.addSimWithId(uuid.NewString())
 }
 
-func (s *server) addNewSim() *asyncProgress {
-	newID := uuid.NewString()
-	simProgress := &asyncProgress{
-		id: newID,
-	}
-	simProgress.latestProgress.Store(&proto.ProgressMetrics{})
+func (s *server) addSimWithId(id string) *asyncProgress {
+	simProgress := newAsyncProgress(id)
 
 	s.progMut.Lock()
-	s.asyncProgresses[newID] = simProgress
+	s.asyncProgresses[id] = simProgress
 	s.progMut.Unlock()
 
 	return simProgress
 }
 
+func (s *server) removeSim(id string) {
+	s.progMut.Lock()
+	delete(s.asyncProgresses, id)
+	s.progMut.Unlock()
+
+	s.jobs.store.remove(id)
+}
+
+// Removes a finished sim once its results have been kept for the given duration.
+func (s *server) expireSim(id string, after time.Duration) {
+	if after <= 0 {
+		s.removeSim(id)
+		return
+	}
+	time.AfterFunc(after, func() { s.removeSim(id) })
+}
+
 func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
@@ -181,37 +277,22 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// reporter channel is handed into the core simulation.
-	//  as the simulation advances it will push changes to the channel
-	//  these changes will be consumed by the goroutine below so the asyncProgress endpoint can fetch the results.
-	reporter := make(chan *proto.ProgressMetrics, 100)
-	handler.handle(msg, reporter, r.URL.Query().Get("requestId"))
-
-	// Generate a new async simulation
+	// Generate a new async simulation and queue it up. Its progress is published
+	// into the async progress cache once a worker picks it up.
+	// The request id is generated here rather than taken from the client, so
+	// clients can't abort each other's sims by reusing an id.
 	simProgress := s.addNewSim()
-
-	// Now launch a background process that pulls progress reports off the reporter channel
-	// and pushes it into the async progress cache.
-	go func() {
-		for {
-			select {
-			case <-time.After(time.Minute * 10):
-				// if we get no progress after 10 minutes, delete the pending sim and exit.
-				s.progMut.Lock()
-				delete(s.asyncProgresses, simProgress.id)
-				s.progMut.Unlock()
-				return
-			case progMetric := <-reporter:
-				if progMetric == nil {
-					return
-				}
-				simProgress.latestProgress.Store(progMetric)
-				if progMetric.FinalRaidResult != nil || progMetric.FinalWeightResult != nil || progMetric.FinalBulkResult != nil {
-					return
-				}
-			}
-		}
-	}()
+	priority, owner := s.jobPriorityAndOwner(r)
+
+	s.jobs.submit(&job{
+		Id:        simProgress.id,
+		Endpoint:  endpoint,
+		RequestId: simProgress.id,
+		Priority:  priority,
+		Owner:     owner,
+		Request:   body,
+		progress:  simProgress,
+	})
 
 	protoResult := &proto.AsyncAPIResult{
 		ProgressId: simProgress.id,
@@ -228,7 +309,38 @@ func (s *server) handleAsyncAPI(w http.ResponseWriter, r *http.Request) {
 	w.Write(outbytes)
 }
 
+// Returns the queue priority and owner of an async request. Only requests
+// carrying the operator token may set them via the priority and user query
+// parameters, anyone else is owned by their address at the default priority.
+func (s *server) jobPriorityAndOwner(r *http.Request) (int32, string) {
+	owner, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		owner = r.RemoteAddr
+	}
+
+	token := r.Header.Get(operatorTokenHeader)
+	if s.operatorToken == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.operatorToken)) != 1 {
+		return 0, owner
+	}
+
+	priority, _ := strconv.Atoi(r.URL.Query().Get("priority"))
+	if user := r.URL.Query().Get("user"); user != "" {
+		owner = user
+	}
+	return int32(priority), owner
+}
+
 func (s *server) setupAsyncServer() {
+	var store *jobStore
+	if s.jobDir != "" {
+		var err error
+		if store, err = newJobStore(s.jobDir); err != nil {
+			log.Fatalf("Failed to open job directory %s: %s", s.jobDir, err.Error())
+		}
+	}
+	s.jobs = newJobQueue(s, store)
+	s.jobs.start(s.workers)
+
 	// All async handlers here will call the addNewSim, generating a new UUID and cached progress state.
 	for route := range asyncAPIHandlers {
 		http.Handle(route, corsMiddleware(http.HandlerFunc(s.handleAsyncAPI)))
@@ -255,7 +367,7 @@ func (s *server) setupAsyncServer() {
 			w.WriteHeader(http.StatusNoContent)
 			return
 		}
-		latest := progress.latestProgress.Load().(*proto.ProgressMetrics)
+		latest := progress.latest()
 		outbytes, err := googleProto.Marshal(latest)
 		if err != nil {
 			log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
@@ -263,21 +375,57 @@ func (s *server) setupAsyncServer() {
 			return
 		}
 
-		// If this was the last result, delete the cache for this simulation.
-		if latest.FinalRaidResult != nil || latest.FinalWeightResult != nil || latest.FinalBulkResult != nil {
-			s.progMut.Lock()
-			delete(s.asyncProgresses, msg.ProgressId)
-			s.progMut.Unlock()
+		// If this was the last result and results aren't kept around, delete the cache for this simulation.
+		if isFinalProgress(latest) && s.resultTTL == 0 {
+			s.removeSim(msg.ProgressId)
 		}
 		w.Header().Add("Content-Type", "application/x-protobuf")
 		w.Write(outbytes)
 	})))
+
+	// registerWorker adds another sim server as a remote worker. Only servers
+	// which know the worker token can register, and browsers have no business
+	// calling it, so it isn't wrapped in corsMiddleware.
+	if s.workerToken != "" {
+		http.HandleFunc("/registerWorker", s.handleRegisterWorker)
+	}
+
+	// cancelJob cancels a queued or running simulation by its UUID.
+	http.Handle("/cancelJob", corsMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			return
+		}
+		msg := &proto.AsyncAPIResult{}
+		if err := googleProto.Unmarshal(body, msg); err != nil {
+			log.Printf("Failed to parse request: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		result := &proto.AbortResponse{}
+		if j, triggered := s.jobs.cancel(msg.ProgressId); j != nil {
+			result.RequestId = j.RequestId
+			result.WasTriggered = triggered
+		}
+		outbytes, err := googleProto.Marshal(result)
+		if err != nil {
+			log.Printf("[ERROR] Failed to marshal result: %s", err.Error())
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Header().Add("Content-Type", "application/x-protobuf")
+		w.Write(outbytes)
+	})))
+
+	// asyncProgressStream pushes every progress update of a simulation as server-sent events.
+	http.Handle("/asyncProgressStream", corsMiddleware(http.HandlerFunc(s.handleProgressStream)))
 }
 func corsMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Accept, Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, X-Operator-Token")
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
 			return
@@ -391,16 +539,31 @@ func (s *server) runServer(useFS bool, host string, launchBrowser bool, simName
 			}()
 		case "sims":
 			s.progMut.RLock()
-			fmt.Printf("Total Sims Running: %d\n", len(s.asyncProgresses))
+			fmt.Printf("Total Sims: %d (%d queued)\n", len(s.asyncProgresses), s.jobs.numQueued())
 			for _, v := range s.asyncProgresses {
-				latest := (v.latestProgress.Load()).(*proto.ProgressMetrics)
+				latest := v.latest()
+				if isFinalProgress(latest) {
+					fmt.Printf("Process: %s (finished)\n", v.id)
+					continue
+				}
+				if latest.QueuePosition > 0 {
+					fmt.Printf("Process: %s (queued at position %d)\n", v.id, latest.QueuePosition)
+					continue
+				}
 				fmt.Printf("Process: %s (%d sims)\n\t  Progress: %d/%d\n", v.id, latest.TotalSims, latest.CompletedIterations, latest.TotalIterations)
 			}
 			s.progMut.RUnlock()
+		case "cache":
+			if cache := core.GetRaidSimResultCache(); cache != nil {
+				hits, misses := cache.Stats()
+				fmt.Printf("Cached results: %d in memory, %d hits, %d misses\n", cache.Len(), hits, misses)
+			} else {
+				fmt.Printf("Result cache is disabled.\n")
+			}
 		case "quit":
 			os.Exit(1)
 		case "?":
-			fmt.Printf("Commands:\n\tsims - Lists all active async sims running currently.\n\tprofile - start a CPU profile for debugging performance\n\tquit - exits\n\n")
+			fmt.Printf("Commands:\n\tsims - Lists all active async sims running currently.\n\tcache - Shows result cache statistics.\n\tprofile - start a CPU profile for debugging performance\n\tquit - exits\n\n")
 		case "":
 			// nothing.
 		default:
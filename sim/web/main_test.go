@@ -5,7 +5,13 @@ import (
 	"bytes"
 	"io"
 	"log"
+	"math"
+	"net"
 	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -13,6 +19,8 @@ import (
 	_ "github.com/wowsims/cata/sim/common"
 	"github.com/wowsims/cata/sim/core"
 	"github.com/wowsims/cata/sim/core/proto"
+	"github.com/wowsims/cata/sim/core/simsignals"
+	"google.golang.org/protobuf/encoding/protojson"
 	googleProto "google.golang.org/protobuf/proto"
 )
 
@@ -52,6 +60,8 @@ func init() {
 	s := &server{
 		progMut:         sync.RWMutex{},
 		asyncProgresses: map[string]*asyncProgress{},
+		resultTTL:       time.Minute,
+		remote:          newRemoteWorkerPool(),
 	}
 	go func() {
 		s.runServer(true, "localhost:3339", false, "", false, bufio.NewReader(bytes.NewBuffer([]byte{})))
@@ -112,3 +122,396 @@ func TestIndividualSim(t *testing.T) {
 
 	log.Printf("RESULT: %#v", rsr)
 }
+
+func makeAsyncTestRequest(iterations int32) *proto.RaidSimRequest {
+	return &proto.RaidSimRequest{
+		Raid: core.SinglePlayerRaidProto(
+			&proto.Player{
+				Race:      proto.Race_RaceTroll,
+				Class:     proto.Class_ClassShaman,
+				Equipment: core.GetGearSet("../../ui/shaman/elemental/gear_sets", "p3.default").GearSet,
+				Rotation:  core.GetAplRotation("../../ui/shaman/elemental/apls", "default").Rotation,
+				Spec:      basicSpec,
+			},
+			&proto.PartyBuffs{},
+			&proto.RaidBuffs{},
+			&proto.Debuffs{}),
+		Encounter: &proto.Encounter{
+			Duration: 120,
+			Targets: []*proto.Target{
+				{},
+			},
+		},
+		SimOptions: &proto.SimOptions{
+			Iterations: iterations,
+			RandomSeed: 1,
+		},
+	}
+}
+
+// Starts an async raid sim and returns its progress id, along with the marshalled AsyncAPIResult.
+func startAsyncSim(t *testing.T, req *proto.RaidSimRequest, query string) (string, []byte) {
+	msgBytes, err := googleProto.Marshal(req)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+
+	r, err := http.Post("http://localhost:3339/raidSimAsync?"+query, "application/x-protobuf", bytes.NewReader(msgBytes))
+	if err != nil {
+		t.Fatalf("Failed to POST request: %s", err.Error())
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read result body: %s", err.Error())
+	}
+	asyncResult := &proto.AsyncAPIResult{}
+	if err := googleProto.Unmarshal(body, asyncResult); err != nil {
+		t.Fatalf("Failed to parse async result: %s", err.Error())
+	}
+	return asyncResult.ProgressId, body
+}
+
+func TestAsyncProgressStream(t *testing.T) {
+	progressId, body := startAsyncSim(t, makeAsyncTestRequest(2000), "")
+
+	// Multiple subscribers must all receive the final result.
+	var wg sync.WaitGroup
+	finals := make([]*proto.ProgressMetrics, 3)
+	for i := range finals {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			finals[i] = readProgressStream(t, progressId)
+		}(i)
+	}
+	wg.Wait()
+
+	for i, final := range finals {
+		if final == nil || final.FinalRaidResult == nil {
+			t.Fatalf("Subscriber %d did not receive a final raid result", i)
+		}
+		if final.FinalRaidResult.Error != nil {
+			t.Fatalf("Subscriber %d received a failed sim: %s", i, final.FinalRaidResult.Error.Message)
+		}
+		if final.FinalRaidResult.RaidMetrics.Dps.Avg != finals[0].FinalRaidResult.RaidMetrics.Dps.Avg {
+			t.Fatalf("Subscriber %d received a different final result", i)
+		}
+	}
+
+	// Finished results stay available to late subscribers and pollers.
+	if final := readProgressStream(t, progressId); final == nil || final.FinalRaidResult == nil {
+		t.Fatalf("Late subscriber did not receive the final raid result")
+	}
+	for i := 0; i < 2; i++ {
+		r, err := http.Post("http://localhost:3339/asyncProgress", "application/x-protobuf", bytes.NewReader(body))
+		if err != nil {
+			t.Fatalf("Failed to POST request: %s", err.Error())
+		}
+		if r.StatusCode != http.StatusOK {
+			t.Fatalf("Expected finished result to be retrievable, got status %d", r.StatusCode)
+		}
+		r.Body.Close()
+	}
+}
+
+// Reads server-sent progress events until the final one, which is returned.
+func readProgressStream(t *testing.T, progressId string) *proto.ProgressMetrics {
+	r, err := http.Get("http://localhost:3339/asyncProgressStream?progressId=" + progressId)
+	if err != nil {
+		t.Errorf("Failed to open progress stream: %s", err.Error())
+		return nil
+	}
+	defer r.Body.Close()
+
+	event := ""
+	scanner := bufio.NewScanner(r.Body)
+	scanner.Buffer(make([]byte, 0, 1<<20), 1<<24)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "event: "):
+			event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: ") && event == "final":
+			progress := &proto.ProgressMetrics{}
+			if err := protojson.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), progress); err != nil {
+				t.Errorf("Failed to parse progress event: %s", err.Error())
+				return nil
+			}
+			return progress
+		}
+	}
+	return nil
+}
+
+// Posts a proto message to an endpoint of the test server and parses the response into result.
+func postProto(t *testing.T, endpoint string, msg googleProto.Message, result googleProto.Message) {
+	msgBytes, err := googleProto.Marshal(msg)
+	if err != nil {
+		t.Fatalf("Failed to encode request: %s", err.Error())
+	}
+	r, err := http.Post("http://localhost:3339"+endpoint, "application/x-protobuf", bytes.NewReader(msgBytes))
+	if err != nil {
+		t.Fatalf("Failed to POST request: %s", err.Error())
+	}
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		t.Fatalf("Failed to read result body: %s", err.Error())
+	}
+	if err := googleProto.Unmarshal(body, result); err != nil {
+		t.Fatalf("Failed to parse result: %s", err.Error())
+	}
+}
+
+func TestJobQueue(t *testing.T) {
+	// The test server has a single worker, so the second sim has to wait for the first.
+	runningId, _ := startAsyncSim(t, makeAsyncTestRequest(100000), "")
+	queuedId, _ := startAsyncSim(t, makeAsyncTestRequest(100), "")
+
+	progress := &proto.ProgressMetrics{}
+	postProto(t, "/asyncProgress", &proto.AsyncAPIResult{ProgressId: queuedId}, progress)
+	if progress.QueuePosition != 1 {
+		t.Fatalf("Expected queued sim at queue position 1, got %d", progress.QueuePosition)
+	}
+
+	for _, progressId := range []string{queuedId, runningId} {
+		abortResponse := &proto.AbortResponse{}
+		postProto(t, "/cancelJob", &proto.AsyncAPIResult{ProgressId: progressId}, abortResponse)
+		if !abortResponse.WasTriggered {
+			t.Fatalf("Cancelling %s was not triggered", abortResponse.RequestId)
+		}
+
+		final := readProgressStream(t, progressId)
+		if final == nil || final.FinalRaidResult == nil || final.FinalRaidResult.Error.GetType() != proto.ErrorOutcomeType_ErrorOutcomeAborted {
+			t.Fatalf("Expected an aborted result for %s, got %v", abortResponse.RequestId, final)
+		}
+	}
+}
+
+func TestJobPriorityAndOwner(t *testing.T) {
+	s := &server{operatorToken: "operator"}
+
+	r := httptest.NewRequest(http.MethodPost, "/raidSimAsync?priority=5&user=someone", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	if priority, owner := s.jobPriorityAndOwner(r); priority != 0 || owner != "10.0.0.1" {
+		t.Fatalf("Expected priority 0 owned by 10.0.0.1 without the operator token, got %d owned by %s", priority, owner)
+	}
+
+	r.Header.Set(operatorTokenHeader, "wrong")
+	if priority, owner := s.jobPriorityAndOwner(r); priority != 0 || owner != "10.0.0.1" {
+		t.Fatalf("Expected priority 0 owned by 10.0.0.1 with a wrong operator token, got %d owned by %s", priority, owner)
+	}
+
+	r.Header.Set(operatorTokenHeader, "operator")
+	if priority, owner := s.jobPriorityAndOwner(r); priority != 5 || owner != "someone" {
+		t.Fatalf("Expected priority 5 owned by someone with the operator token, got %d owned by %s", priority, owner)
+	}
+}
+
+func TestJobStoreRestore(t *testing.T) {
+	store, err := newJobStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("Failed to create job store: %s", err.Error())
+	}
+
+	final, _ := googleProto.Marshal(&proto.ProgressMetrics{FinalRaidResult: &proto.RaidSimResult{IterationsDone: 10}})
+	for _, j := range []*job{
+		{Id: "finished", Endpoint: "/raidSimAsync", Seq: 0, Final: final, FinishedAt: time.Now()},
+		{Id: "low", Endpoint: "/raidSimAsync", Seq: 1},
+		{Id: "high", Endpoint: "/raidSimAsync", Seq: 2, Priority: 1},
+	} {
+		if err := store.save(j); err != nil {
+			t.Fatalf("Failed to save job: %s", err.Error())
+		}
+	}
+
+	s := &server{
+		asyncProgresses: map[string]*asyncProgress{},
+		resultTTL:       time.Minute,
+	}
+	s.jobs = newJobQueue(s, store)
+	s.jobs.restore()
+
+	if s.asyncProgresses["finished"].latest().FinalRaidResult.GetIterationsDone() != 10 {
+		t.Fatalf("Finished job result was not restored")
+	}
+	if position := s.asyncProgresses["high"].latest().QueuePosition; position != 1 {
+		t.Fatalf("Expected high priority job at queue position 1, got %d", position)
+	}
+	if position := s.asyncProgresses["low"].latest().QueuePosition; position != 2 {
+		t.Fatalf("Expected low priority job at queue position 2, got %d", position)
+	}
+	if s.jobs.nextSeq != 3 {
+		t.Fatalf("Expected next sequence number 3, got %d", s.jobs.nextSeq)
+	}
+}
+
+func TestDistributedRaidSim(t *testing.T) {
+	// Stand-ins for worker processes, plus one which always fails so splits have to be retried.
+	worker1 := httptest.NewServer(http.HandlerFunc(handleAPI))
+	defer worker1.Close()
+	worker2 := httptest.NewServer(http.HandlerFunc(handleAPI))
+	defer worker2.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer broken.Close()
+
+	pool := newRemoteWorkerPool()
+	pool.register(worker1.URL)
+	pool.register(worker2.URL)
+	pool.register(broken.URL)
+
+	req := makeAsyncTestRequest(1000)
+	result := pool.runRaidSimDistributed(req, nil, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Distributed sim failed: %s", result.Error.Message)
+	}
+
+	expected := core.RunRaidSimConcurrent(req)
+	if result.IterationsDone != expected.IterationsDone {
+		t.Fatalf("Distributed sim ran %d iterations, expected %d", result.IterationsDone, expected.IterationsDone)
+	}
+	if math.Abs(result.RaidMetrics.Dps.Avg-expected.RaidMetrics.Dps.Avg) > 1e-6*expected.RaidMetrics.Dps.Avg {
+		t.Fatalf("Distributed sim DPS %0.3f does not match local DPS %0.3f", result.RaidMetrics.Dps.Avg, expected.RaidMetrics.Dps.Avg)
+	}
+	if pool.numHealthy() != 2 {
+		t.Fatalf("Expected the failing worker to be skipped, got %d healthy workers", pool.numHealthy())
+	}
+
+	// Once the cooldown has passed the failing worker is probed again, even
+	// though it never registers itself.
+	pool.workers[broken.URL].retryAt = time.Now()
+	if pool.numHealthy() != 3 {
+		t.Fatalf("Expected the failing worker to be probed after its cooldown, got %d healthy workers", pool.numHealthy())
+	}
+}
+
+func TestDistributedRaidSimTimeout(t *testing.T) {
+	// A worker which never finishes its splits, but records the aborts it receives.
+	var abortMut sync.Mutex
+	started := map[string]bool{}
+	aborted := map[string]bool{}
+	stalled := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		switch r.URL.Path {
+		case "/raidSimConcurrent":
+			request := &proto.RaidSimRequest{}
+			googleProto.Unmarshal(body, request)
+			abortMut.Lock()
+			started[request.RequestId] = true
+			abortMut.Unlock()
+			<-r.Context().Done()
+		case "/abortById":
+			request := &proto.AbortRequest{}
+			googleProto.Unmarshal(body, request)
+			abortMut.Lock()
+			aborted[request.RequestId] = true
+			abortMut.Unlock()
+			msgBytes, _ := googleProto.Marshal(&proto.AbortResponse{RequestId: request.RequestId, WasTriggered: true})
+			w.Write(msgBytes)
+		}
+	}))
+	defer stalled.Close()
+	worker := httptest.NewServer(http.HandlerFunc(handleAPI))
+	defer worker.Close()
+
+	pool := newRemoteWorkerPool()
+	pool.splitTimeout = time.Second * 5
+	pool.register(stalled.URL)
+	pool.register(worker.URL)
+
+	result := pool.runRaidSimDistributed(makeAsyncTestRequest(1000), nil, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Distributed sim failed: %s", result.Error.Message)
+	}
+
+	abortMut.Lock()
+	defer abortMut.Unlock()
+	if len(started) == 0 {
+		t.Fatalf("Expected the stalled worker to receive splits")
+	}
+	for requestId := range started {
+		if !aborted[requestId] {
+			t.Errorf("Timed out split %s was not aborted on the stalled worker", requestId)
+		}
+	}
+}
+
+func TestDistributedRaidSimProcess(t *testing.T) {
+	if testing.Short() {
+		t.Skip("Builds and starts a separate sim server process")
+	}
+
+	// Build this server and run it as a separate worker process.
+	binary := filepath.Join(t.TempDir(), "wowsimcata")
+	build := exec.Command("go", "build", "--tags=with_db", "-o", binary, ".")
+	if output, err := build.CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build sim server: %s\n%s", err.Error(), output)
+	}
+
+	listener, err := net.Listen("tcp", "localhost:0")
+	if err != nil {
+		t.Fatalf("Failed to find a free port: %s", err.Error())
+	}
+	address := listener.Addr().String()
+	listener.Close()
+
+	worker := exec.Command(binary, "-host", address, "-launch=false", "-nvc")
+	if err := worker.Start(); err != nil {
+		t.Fatalf("Failed to start worker process: %s", err.Error())
+	}
+	defer worker.Process.Kill()
+
+	for i := 0; ; i++ {
+		r, err := http.Get("http://" + address + "/version")
+		if err == nil {
+			r.Body.Close()
+			break
+		}
+		if i == 100 {
+			t.Fatalf("Worker process did not start: %s", err.Error())
+		}
+		time.Sleep(time.Millisecond * 100)
+	}
+
+	pool := newRemoteWorkerPool()
+	pool.register(address)
+
+	req := makeAsyncTestRequest(1000)
+	result := pool.runRaidSimDistributed(req, nil, simsignals.CreateSignals())
+	if result.Error != nil {
+		t.Fatalf("Distributed sim failed: %s", result.Error.Message)
+	}
+
+	expected := core.RunRaidSimConcurrent(req)
+	if math.Abs(result.RaidMetrics.Dps.Avg-expected.RaidMetrics.Dps.Avg) > 1e-6*expected.RaidMetrics.Dps.Avg {
+		t.Fatalf("Distributed sim DPS %0.3f does not match local DPS %0.3f", result.RaidMetrics.Dps.Avg, expected.RaidMetrics.Dps.Avg)
+	}
+}
+
+func TestRegisterWorker(t *testing.T) {
+	s := &server{
+		remote:      newRemoteWorkerPool(),
+		workerToken: "secret",
+	}
+	coordinator := httptest.NewServer(http.HandlerFunc(s.handleRegisterWorker))
+	defer coordinator.Close()
+
+	registerURL := coordinator.URL + "/registerWorker?address=localhost:3340"
+	if err := sendWorkerRegistration(registerURL, "wrong"); err == nil {
+		t.Fatalf("Expected registration with the wrong token to fail")
+	}
+	if err := sendWorkerRegistration(registerURL, ""); err == nil {
+		t.Fatalf("Expected registration without a token to fail")
+	}
+	if s.remote.numHealthy() != 0 {
+		t.Fatalf("Expected no workers after failed registrations, got %d", s.remote.numHealthy())
+	}
+
+	if err := sendWorkerRegistration(registerURL, "secret"); err != nil {
+		t.Fatalf("Registration failed: %s", err.Error())
+	}
+	if s.remote.numHealthy() != 1 {
+		t.Fatalf("Expected 1 registered worker, got %d", s.remote.numHealthy())
+	}
+}
@@ -1,6 +1,6 @@
 import { Encounter } from '../encounter.js';
 import { IndividualSimUI } from '../individual_sim_ui.js';
-import { InputType, MobType, SpellSchool, Stat, Target, Target as TargetProto, TargetInput } from '../proto/common.js';
+import { InputType, MobType, RaidDamageProfileType, SpellSchool, Stat, Target, Target as TargetProto, TargetInput } from '../proto/common.js';
 import { getStatName } from '../proto_utils/names.js';
 import { Stats } from '../proto_utils/stats.js';
 import { Raid } from '../raid.js';
@@ -124,6 +124,50 @@ export class EncounterPicker extends Component {
 						raid.setTargetDummies(eventID, newValue);
 					},
 				});
+				new NumberPicker(this.rootElem, modEncounter, {
+					id: 'encounter-ally-health',
+					label: 'Ally Health',
+					labelTooltip: 'Max health of each allied player. Uses a default raid-buffed value if 0.',
+					changedEvent: (encounter: Encounter) => encounter.raidDamageChangeEmitter,
+					getValue: (encounter: Encounter) => encounter.getRaidDamage().dummyHealth,
+					setValue: (eventID: EventID, encounter: Encounter, newValue: number) => {
+						const raidDamage = encounter.getRaidDamage();
+						raidDamage.dummyHealth = newValue;
+						encounter.setRaidDamage(eventID, raidDamage);
+					},
+				});
+				new NumberPicker(this.rootElem, modEncounter, {
+					id: 'encounter-num-tanks',
+					label: 'Num Tanks',
+					labelTooltip: 'How many of the allied players are tanks.',
+					changedEvent: (encounter: Encounter) => encounter.raidDamageChangeEmitter,
+					getValue: (encounter: Encounter) => encounter.getRaidDamage().numTanks,
+					setValue: (eventID: EventID, encounter: Encounter, newValue: number) => {
+						const raidDamage = encounter.getRaidDamage();
+						raidDamage.numTanks = newValue;
+						encounter.setRaidDamage(eventID, raidDamage);
+					},
+				});
+				new NumberPicker(this.rootElem, modEncounter, {
+					id: 'encounter-raid-dtps',
+					label: 'Raid DTPS',
+					labelTooltip: 'Steady damage taken per second by each non-tank ally.',
+					changedEvent: (encounter: Encounter) => encounter.raidDamageChangeEmitter,
+					getValue: (encounter: Encounter) => encounter.getRaidDamageDps(RaidDamageProfileType.RaidDamageConstant),
+					setValue: (eventID: EventID, encounter: Encounter, newValue: number) => {
+						encounter.setRaidDamageDps(eventID, RaidDamageProfileType.RaidDamageConstant, newValue);
+					},
+				});
+				new NumberPicker(this.rootElem, modEncounter, {
+					id: 'encounter-tank-dtps',
+					label: 'Tank DTPS',
+					labelTooltip: 'Damage taken per second by each tank.',
+					changedEvent: (encounter: Encounter) => encounter.raidDamageChangeEmitter,
+					getValue: (encounter: Encounter) => encounter.getRaidDamageDps(RaidDamageProfileType.RaidDamageTankOnly),
+					setValue: (eventID: EventID, encounter: Encounter, newValue: number) => {
+						encounter.setRaidDamageDps(eventID, RaidDamageProfileType.RaidDamageTankOnly, newValue);
+					},
+				});
 			}
 
 			if (simUI.isIndividualSim() && (simUI as IndividualSimUI<any>).player.getPlayerSpec().isTankSpec) {
@@ -5,6 +5,7 @@ import {
 	APLActionActivateAura,
 	APLActionActivateAuraWithStacks,
 	APLActionAutocastOtherCooldowns,
+	APLActionCallList,
 	APLActionCancelAura,
 	APLActionCastAllStatBuffCooldowns,
 	APLActionCastFriendlySpell,
@@ -20,12 +21,17 @@ import {
 	APLActionMultidot,
 	APLActionMultishield,
 	APLActionResetSequence,
+	APLActionRunList,
 	APLActionSchedule,
+	APLActionSelectTarget,
 	APLActionSequence,
+	APLActionSetVariable,
 	APLActionStrictSequence,
 	APLActionTriggerICD,
 	APLActionWait,
 	APLActionWaitUntil,
+	APLTargetSelector,
+	APLTargetSelector_SelectionMode as SelectionMode,
 	APLValue,
 } from '../../proto/apl.js';
 import { Spec } from '../../proto/common.js';
@@ -273,6 +279,42 @@ function itemSwapSetFieldConfig(field: string): AplHelpers.APLPickerBuilderField
 	};
 }
 
+function targetSelectorFieldConfig(field: string, options?: Partial<AplHelpers.APLPickerBuilderFieldConfig<any, any>>): AplHelpers.APLPickerBuilderFieldConfig<any, any> {
+	return {
+		field: field,
+		label: 'Target Selector',
+		labelTooltip:
+			'If a Value or a Condition is set, picks the enemy target to use instead of Target. Both are evaluated with each target as the current target, and ties go to the first target.',
+		newValue: APLTargetSelector.create,
+		factory: AplHelpers.aplInputBuilder(APLTargetSelector.create, [
+			{
+				field: 'mode',
+				newValue: () => SelectionMode.SelectLowest,
+				factory: (parent, player, config) =>
+					new TextDropdownPicker(parent, player, {
+						id: randomUUID(),
+						...config,
+						defaultLabel: 'Lowest',
+						equals: (a, b) => a == b,
+						values: [
+							{ value: SelectionMode.SelectLowest, label: 'Lowest' },
+							{ value: SelectionMode.SelectHighest, label: 'Highest' },
+						],
+					}),
+			},
+			AplValues.valueFieldConfig('value', {
+				label: 'Value',
+				labelTooltip: 'Value to rank the targets by. If empty, the first target passing the condition is picked.',
+			}),
+			AplValues.valueFieldConfig('condition', {
+				label: 'If',
+				labelTooltip: 'Only targets for which this is true are considered.',
+			}),
+		]),
+		...(options || {}),
+	};
+}
+
 function actionFieldConfig(field: string): AplHelpers.APLPickerBuilderFieldConfig<any, any> {
 	return {
 		field: field,
@@ -342,7 +384,11 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		label: 'Cast',
 		shortDescription: 'Casts the spell if possible, i.e. resource/cooldown/GCD/etc requirements are all met.',
 		newValue: APLActionCastSpell.create,
-		fields: [AplHelpers.actionIdFieldConfig('spellId', 'castable_spells', ''), AplHelpers.unitFieldConfig('target', 'targets')],
+		fields: [
+			AplHelpers.actionIdFieldConfig('spellId', 'castable_spells', ''),
+			AplHelpers.unitFieldConfig('target', 'targets'),
+			targetSelectorFieldConfig('targetSelector'),
+		],
 	}),
 	['castFriendlySpell']: inputBuilder({
 		label: 'Cast at Player',
@@ -378,6 +424,10 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 				label: 'Overlap',
 				labelTooltip: 'Maximum amount of time before a DoT expires when it may be refreshed.',
 			}),
+			targetSelectorFieldConfig('targetSelector', {
+				labelTooltip:
+					'If a Value or a Condition is set, picks which of the targets needing a refresh to cast on. Both are evaluated with each target as the current target, and ties go to the first target.',
+			}),
 		],
 	}),
 	['multishield']: inputBuilder({
@@ -435,6 +485,7 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		fields: [
 			AplHelpers.actionIdFieldConfig('spellId', 'channel_spells', ''),
 			AplHelpers.unitFieldConfig('target', 'targets'),
+			targetSelectorFieldConfig('targetSelector'),
 			AplValues.valueFieldConfig('interruptIf', {
 				label: 'Interrupt If',
 				labelTooltip: 'Condition which must be true to allow the channel to be interrupted.',
@@ -561,6 +612,33 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		newValue: APLActionStrictSequence.create,
 		fields: [actionListFieldConfig('actions')],
 	}),
+	['setVariable']: inputBuilder({
+		label: 'Set Variable',
+		submenu: ['Variables'],
+		shortDescription: 'Overwrites the value of a variable for the rest of the iteration.',
+		fullDescription: `
+			<p>Variables are declared with a name and a value, which is evaluated each time the variable is read until it is overwritten by this action.</p>
+			<p>Like simc's <b>variable</b> action, this is applied as the list is evaluated, and evaluation continues with the next action.</p>
+		`,
+		newValue: APLActionSetVariable.create,
+		fields: [AplHelpers.stringFieldConfig('name'), AplValues.valueFieldConfig('value')],
+	}),
+	['callList']: inputBuilder({
+		label: 'Call Action List',
+		submenu: ['Action Lists'],
+		shortDescription: 'Evaluates the named action list. If none of its actions are ready, continues with the next action after this one.',
+		includeIf: (player: Player<any>, isPrepull: boolean) => !isPrepull,
+		newValue: APLActionCallList.create,
+		fields: [AplHelpers.stringFieldConfig('listName')],
+	}),
+	['runList']: inputBuilder({
+		label: 'Run Action List',
+		submenu: ['Action Lists'],
+		shortDescription: "Evaluates the named action list. Actions after this one are never evaluated, even if none of the list's actions are ready.",
+		includeIf: (player: Player<any>, isPrepull: boolean) => !isPrepull,
+		newValue: APLActionRunList.create,
+		fields: [AplHelpers.stringFieldConfig('listName')],
+	}),
 	['changeTarget']: inputBuilder({
 		label: 'Change Target',
 		submenu: ['Misc'],
@@ -568,6 +646,18 @@ const actionKindFactories: { [f in NonNullable<APLActionKind>]: ActionKindConfig
 		newValue: () => APLActionChangeTarget.create(),
 		fields: [AplHelpers.unitFieldConfig('newTarget', 'targets')],
 	}),
+	['selectTarget']: inputBuilder({
+		label: 'Select Target',
+		submenu: ['Misc'],
+		shortDescription: 'Sets the current target to the enemy target with the lowest or highest value.',
+		fullDescription: `
+			<p>The value and condition are evaluated with each target as the current target, so e.g. <b>Dot Remaining Time</b> with an empty target refers to that target. Ties go to the first target.</p>
+			<p>Like <b>Change Target</b>, this does nothing if the selected target is already the current target.</p>
+		`,
+		includeIf: (player: Player<any>, isPrepull: boolean) => !isPrepull,
+		newValue: APLActionSelectTarget.create,
+		fields: [targetSelectorFieldConfig('selector')],
+	}),
 	['activateAura']: inputBuilder({
 		label: 'Activate Aura',
 		submenu: ['Misc'],
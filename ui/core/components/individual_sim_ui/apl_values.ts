@@ -24,6 +24,7 @@ import {
 	APLValueCompare,
 	APLValueCompare_ComparisonOperator as ComparisonOperator,
 	APLValueConst,
+	APLValueCountTargets,
 	APLValueCurrentComboPoints,
 	APLValueCurrentEclipsePhase,
 	APLValueCurrentEnergy,
@@ -70,6 +71,7 @@ import {
 	APLValueNumEquippedStatProcTrinkets,
 	APLValueNumStatBuffCooldowns,
 	APLValueOr,
+	APLValuePaladinIsBeaconTarget,
 	APLValueRemainingTime,
 	APLValueRemainingTimePercent,
 	APLValueRuneCooldown,
@@ -93,6 +95,7 @@ import {
 	APLValueTrinketProcsMaxRemainingICD,
 	APLValueTrinketProcsMinRemainingTime,
 	APLValueUnitIsMoving,
+	APLValueVariable,
 	APLValueWarlockShouldRecastDrainSoul,
 	APLValueWarlockShouldRefreshCorruption,
 } from '../../proto/apl.js';
@@ -616,6 +619,16 @@ const valueKindFactories: { [f in NonNullable<APLValueKind>]: ValueKindConfig<AP
 		newValue: APLValueNumberTargets.create,
 		fields: [],
 	}),
+	countTargets: inputBuilder({
+		label: 'Count Targets',
+		submenu: ['Encounter'],
+		shortDescription: 'Count of targets for which the condition is true.',
+		fullDescription: `
+			<p>The condition is evaluated with each target as the current target, so e.g. <b>Dot Is Active</b> with an empty target refers to that target.</p>
+		`,
+		newValue: APLValueCountTargets.create,
+		fields: [valueFieldConfig('condition')],
+	}),
 	frontOfTarget: inputBuilder({
 		label: 'Front of Target',
 		submenu: ['Encounter'],
@@ -1244,6 +1257,15 @@ const valueKindFactories: { [f in NonNullable<APLValueKind>]: ValueKindConfig<AP
 		fields: [AplHelpers.stringFieldConfig('sequenceName')],
 	}),
 
+	// Variables
+	variable: inputBuilder({
+		label: 'Variable',
+		submenu: ['Variables'],
+		shortDescription: 'Returns the current value of the named variable.',
+		newValue: APLValueVariable.create,
+		fields: [AplHelpers.stringFieldConfig('name')],
+	}),
+
 	// Class/spec specific values
 	totemRemainingTime: inputBuilder({
 		label: 'Totem Remaining Time',
@@ -1269,6 +1291,14 @@ const valueKindFactories: { [f in NonNullable<APLValueKind>]: ValueKindConfig<AP
 		includeIf: (player: Player<any>, _isPrepull: boolean) => player.getClass() == Class.ClassShaman,
 		fields: [],
 	}),
+	paladinIsBeaconTarget: inputBuilder({
+		label: 'Is Beacon Target',
+		submenu: ['Holy Paladin'],
+		shortDescription: '<b>True</b> if the specified unit currently has your Beacon of Light, otherwise <b>False</b>.',
+		newValue: APLValuePaladinIsBeaconTarget.create,
+		includeIf: (player: Player<any>, _isPrepull: boolean) => player.getSpec() == Spec.SpecHolyPaladin,
+		fields: [AplHelpers.unitFieldConfig('targetUnit', 'players')],
+	}),
 	catExcessEnergy: inputBuilder({
 		label: 'Excess Energy',
 		submenu: ['Feral Druid'],
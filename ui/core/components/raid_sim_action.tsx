@@ -66,6 +66,7 @@ export interface ResultMetrics {
 	tmi: string;
 	dur: string;
 	hps: string;
+	ehps: string;
 	tps: string;
 	tto: string;
 	oom: string;
@@ -93,6 +94,7 @@ export class RaidSimResultsManager {
 		cod: 'threat',
 		tto: 'healing',
 		hps: 'healing',
+		ehps: 'healing',
 	};
 
 	static resultMetricClasses: { [ResultMetrics: string]: string } = {
@@ -102,6 +104,7 @@ export class RaidSimResultsManager {
 		tmi: 'results-sim-tmi',
 		dur: 'results-sim-dur',
 		hps: 'results-sim-hps',
+		ehps: 'results-sim-ehps',
 		tps: 'results-sim-tps',
 		tto: 'results-sim-tto',
 		oom: 'results-sim-oom',
@@ -201,6 +204,7 @@ export class RaidSimResultsManager {
 		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['dps']}`, 'Damage Per Second');
 		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['tto']}`, 'Time To OOM');
 		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['hps']}`, 'Healing+Shielding Per Second, including overhealing.');
+		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['ehps']}`, 'Effective Healing+Shielding Per Second, excluding overhealing and expired absorbs.');
 		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['tps']}`, 'Threat Per Second');
 		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['dtps']}`, 'Damage Taken Per Second');
 		setResultTooltip(`.${RaidSimResultsManager.resultMetricClasses['dur']}`, 'Average Fight Duration');
@@ -232,6 +236,7 @@ export class RaidSimResultsManager {
 		if (!this.simUI.isIndividualSim()) {
 			[...this.simUI.resultsViewer.contentElem.querySelectorAll(`.${RaidSimResultsManager.resultMetricClasses['tto']}`)].forEach(e => e.remove());
 			[...this.simUI.resultsViewer.contentElem.querySelectorAll(`.${RaidSimResultsManager.resultMetricClasses['hps']}`)].forEach(e => e.remove());
+			[...this.simUI.resultsViewer.contentElem.querySelectorAll(`.${RaidSimResultsManager.resultMetricClasses['ehps']}`)].forEach(e => e.remove());
 			[...this.simUI.resultsViewer.contentElem.querySelectorAll(`.${RaidSimResultsManager.resultMetricClasses['tps']}`)].forEach(e => e.remove());
 			[...this.simUI.resultsViewer.contentElem.querySelectorAll(`.${RaidSimResultsManager.resultMetricClasses['dtps']}`)].forEach(e => e.remove());
 			[...this.simUI.resultsViewer.contentElem.querySelectorAll(`.${RaidSimResultsManager.resultMetricClasses['tmi']}`)].forEach(e => e.remove());
@@ -319,6 +324,7 @@ export class RaidSimResultsManager {
 		this.formatToplineResult(`.${RaidSimResultsManager.resultMetricClasses['dps']} .results-reference-diff`, res => res.raidMetrics.dps, 2);
 		if (this.simUI.isIndividualSim()) {
 			this.formatToplineResult(`.${RaidSimResultsManager.resultMetricClasses['hps']} .results-reference-diff`, res => res.raidMetrics.hps, 2);
+			this.formatToplineResult(`.${RaidSimResultsManager.resultMetricClasses['ehps']} .results-reference-diff`, res => res.getFirstPlayer()!.ehps, 2);
 			this.formatToplineResult(`.${RaidSimResultsManager.resultMetricClasses['tto']} .results-reference-diff`, res => res.getFirstPlayer()!.tto, 2);
 			this.formatToplineResult(`.${RaidSimResultsManager.resultMetricClasses['tps']} .results-reference-diff`, res => res.getFirstPlayer()!.tps, 2);
 			this.formatToplineResult(
@@ -565,6 +571,13 @@ export class RaidSimResultsManager {
 					stdev: playerMetrics.hps.stdev,
 					classes: this.getResultsLineClasses('hps'),
 				});
+
+				resultColumns.push({
+					name: 'EHPS',
+					average: playerMetrics.ehps.avg,
+					stdev: playerMetrics.ehps.stdev,
+					classes: this.getResultsLineClasses('ehps'),
+				});
 			}
 		} else {
 			const dpsMetrics = simResult.raidMetrics.dps;
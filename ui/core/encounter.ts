@@ -1,7 +1,19 @@
 import * as Mechanics from './constants/mechanics';
 import { CURRENT_API_VERSION } from './constants/other';
 import { UnitMetadataList } from './player';
-import { Encounter as EncounterProto, MobType, PresetEncounter, PresetTarget, SpellSchool, Stat, Target as TargetProto, TargetInput } from './proto/common';
+import {
+	Encounter as EncounterProto,
+	MobType,
+	PresetEncounter,
+	PresetTarget,
+	RaidDamageModel,
+	RaidDamageProfile,
+	RaidDamageProfileType,
+	SpellSchool,
+	Stat,
+	Target as TargetProto,
+	TargetInput,
+} from './proto/common';
 import { Stats } from './proto_utils/stats';
 import { Sim } from './sim';
 import { EventID, TypedEvent } from './typed_event';
@@ -17,12 +29,14 @@ export class Encounter {
 	private executeProportion35 = 0.35;
 	private executeProportion90 = 0.9;
 	private useHealth = false;
+	private raidDamage: RaidDamageModel = RaidDamageModel.create();
 	targets: Array<TargetProto>;
 	targetsMetadata: UnitMetadataList;
 
 	readonly targetsChangeEmitter = new TypedEvent<void>();
 	readonly durationChangeEmitter = new TypedEvent<void>();
 	readonly executeProportionChangeEmitter = new TypedEvent<void>();
+	readonly raidDamageChangeEmitter = new TypedEvent<void>();
 
 	// Emits when any of the above emitters emit.
 	readonly changeEmitter = new TypedEvent<void>();
@@ -32,7 +46,7 @@ export class Encounter {
 		this.targets = [Encounter.defaultTargetProto()];
 		this.targetsMetadata = new UnitMetadataList();
 
-		[this.targetsChangeEmitter, this.durationChangeEmitter, this.executeProportionChangeEmitter].forEach(emitter =>
+		[this.targetsChangeEmitter, this.durationChangeEmitter, this.executeProportionChangeEmitter, this.raidDamageChangeEmitter].forEach(emitter =>
 			emitter.on(eventID => this.changeEmitter.emit(eventID)),
 		);
 	}
@@ -108,6 +122,34 @@ export class Encounter {
 		this.executeProportionChangeEmitter.emit(eventID);
 	}
 
+	getRaidDamage(): RaidDamageModel {
+		// Make a defensive copy
+		return RaidDamageModel.clone(this.raidDamage);
+	}
+	setRaidDamage(eventID: EventID, newRaidDamage: RaidDamageModel) {
+		if (RaidDamageModel.equals(this.raidDamage, newRaidDamage)) return;
+
+		// Make a defensive copy
+		this.raidDamage = RaidDamageModel.clone(newRaidDamage);
+		this.raidDamageChangeEmitter.emit(eventID);
+	}
+
+	// Average DPS of the first raid damage profile with the given type, or 0 if there is none.
+	getRaidDamageDps(type: RaidDamageProfileType): number {
+		return this.raidDamage.profiles.find(profile => profile.type == type)?.dps ?? 0;
+	}
+	setRaidDamageDps(eventID: EventID, type: RaidDamageProfileType, newDps: number) {
+		const raidDamage = this.getRaidDamage();
+		const profile = raidDamage.profiles.find(profile => profile.type == type);
+		if (profile) {
+			profile.dps = newDps;
+		} else {
+			raidDamage.profiles.push(RaidDamageProfile.create({ type, dps: newDps }));
+		}
+		raidDamage.profiles = raidDamage.profiles.filter(profile => profile.dps > 0);
+		this.setRaidDamage(eventID, raidDamage);
+	}
+
 	matchesPreset(preset: PresetEncounter): boolean {
 		return preset.targets.length == this.targets.length && this.targets.every((t, i) => TargetProto.equals(t, preset.targets[i].target));
 	}
@@ -132,6 +174,7 @@ export class Encounter {
 			executeProportion90: this.executeProportion90,
 			useHealth: this.useHealth,
 			targets: this.targets,
+			raidDamage: this.raidDamage,
 			apiVersion: CURRENT_API_VERSION,
 		});
 	}
@@ -148,6 +191,7 @@ export class Encounter {
 			this.setExecuteProportion35(eventID, proto.executeProportion35);
 			this.setExecuteProportion90(eventID, proto.executeProportion90);
 			this.setUseHealth(eventID, proto.useHealth);
+			this.setRaidDamage(eventID, proto.raidDamage || RaidDamageModel.create());
 			this.targets = proto.targets;
 			this.targetsChangeEmitter.emit(eventID);
 		});
@@ -134,6 +134,14 @@ export class ActionId {
 				baseName = 'Prepull';
 				iconUrl = 'https://wow.zamimg.com/images/wow/icons/medium/inv_misc_pocketwatch_02.jpg';
 				break;
+			case OtherAction.OtherActionRaidDamage:
+				baseName = 'Raid Damage';
+				iconUrl = 'https://wow.zamimg.com/images/wow/icons/large/spell_shadow_shadowbolt.jpg';
+				break;
+			case OtherAction.OtherActionScriptedCast:
+				baseName = 'Boss Ability';
+				iconUrl = 'https://wow.zamimg.com/images/wow/icons/large/spell_fire_selfdestruct.jpg';
+				break;
 		}
 		this.baseName = baseName;
 		this.name = name || baseName;
@@ -322,6 +322,7 @@ export class UnitMetrics {
 	readonly classColor: string;
 	readonly dps: DistributionMetricsProto;
 	readonly hps: DistributionMetricsProto;
+	readonly ehps: DistributionMetricsProto;
 	readonly tps: DistributionMetricsProto;
 	readonly dtps: DistributionMetricsProto;
 	readonly tmi: DistributionMetricsProto;
@@ -376,6 +377,7 @@ export class UnitMetrics {
 					.replace(/\s/g, '-') ?? '';
 		this.dps = this.metrics.dps!;
 		this.hps = this.metrics.hps!;
+		this.ehps = this.metrics.ehps ?? DistributionMetricsProto.create();
 		this.tps = this.metrics.threat!;
 		this.dtps = this.metrics.dtps!;
 		this.tmi = this.metrics.tmi!;
@@ -964,6 +966,10 @@ export class ActionMetrics {
 		return this.combinedMetrics.shielding;
 	}
 
+	get overhealingPercent() {
+		return this.combinedMetrics.overhealingPercent;
+	}
+
 	get avgCast() {
 		if (this.isPassiveAction) return 0;
 		return this.combinedMetrics.avgCast;
@@ -1336,6 +1342,14 @@ export class TargetedActionMetrics {
 		return this.data.shielding;
 	}
 
+	get overhealing() {
+		return this.data.overhealing;
+	}
+
+	get overhealingPercent() {
+		return (this.data.overhealing / this.data.healing) * 100;
+	}
+
 	get hps() {
 		return (this.data.healing + this.data.shielding) / this.iterations / this.duration;
 	}
@@ -1540,6 +1554,7 @@ export class TargetedActionMetrics {
 				healing: sum(actions.map(a => a.data.healing)),
 				critHealing: sum(actions.map(a => a.data.critHealing)),
 				shielding: sum(actions.map(a => a.data.shielding)),
+				overhealing: sum(actions.map(a => a.data.overhealing)),
 				castTimeMs: sum(actions.map(a => a.data.castTimeMs)),
 			}),
 			{
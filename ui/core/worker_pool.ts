@@ -9,6 +9,10 @@ import {
 	BulkSimResult,
 	ComputeStatsRequest,
 	ComputeStatsResult,
+	OptimizeGearRequest,
+	OptimizeGearResult,
+	OptimizeRaidCompositionRequest,
+	OptimizeRaidCompositionResult,
 	ProgressMetrics,
 	RaidSimRequest,
 	RaidSimRequestSplitRequest,
@@ -19,6 +23,8 @@ import {
 	StatWeightsCalcRequest,
 	StatWeightsRequest,
 	StatWeightsResult,
+	TuneAPLRequest,
+	TuneAPLResult,
 } from './proto/api.js';
 import { SimSignals } from './sim_signal_manager';
 import { isDevMode, noop } from './utils';
@@ -88,6 +94,21 @@ export class WorkerPool {
 		return ComputeStatsResult.fromBinary(result);
 	}
 
+	async optimizeGear(request: OptimizeGearRequest): Promise<OptimizeGearResult> {
+		const result = await this.makeApiCall(SimRequest.optimizeGear, OptimizeGearRequest.toBinary(request));
+		return OptimizeGearResult.fromBinary(result);
+	}
+
+	async optimizeRaidComposition(request: OptimizeRaidCompositionRequest): Promise<OptimizeRaidCompositionResult> {
+		const result = await this.makeApiCall(SimRequest.optimizeRaidComposition, OptimizeRaidCompositionRequest.toBinary(request));
+		return OptimizeRaidCompositionResult.fromBinary(result);
+	}
+
+	async tuneAPL(request: TuneAPLRequest): Promise<TuneAPLResult> {
+		const result = await this.makeApiCall(SimRequest.tuneAPL, TuneAPLRequest.toBinary(request));
+		return TuneAPLResult.fromBinary(result);
+	}
+
 	private getProgressName(id: string) {
 		return `${id}progress`;
 	}
@@ -10,7 +10,9 @@ import {
 } from '../../core/proto/paladin.js';
 import { SavedTalents } from '../../core/proto/ui.js';
 import { Stats } from '../../core/proto_utils/stats';
+import DefaultApl from './apls/default.apl.json';
 import P1Gear from './gear_sets/p1.gear.json';
+import P1MasteryGear from './gear_sets/p1_mastery.gear.json';
 import PreraidGear from './gear_sets/preraid.gear.json';
 
 // Preset options for this spec.
@@ -19,10 +21,13 @@ import PreraidGear from './gear_sets/preraid.gear.json';
 
 export const PRERAID_PRESET = PresetUtils.makePresetGear('PreRaid', PreraidGear);
 export const P1_PRESET = PresetUtils.makePresetGear('P1 Preset', P1Gear);
+export const P1_MASTERY_PRESET = PresetUtils.makePresetGear('P1 Mastery', P1MasteryGear);
 // export const P2_PRESET = PresetUtils.makePresetGear('P2 Preset', P2Gear);
 // export const P3_PRESET = PresetUtils.makePresetGear('P3 Preset', P3Gear);
 // export const P4_PRESET = PresetUtils.makePresetGear('P4 Preset', P4Gear);
 
+export const ROTATION_PRESET_DEFAULT = PresetUtils.makePresetAPLRotation('Default', DefaultApl);
+
 // Preset options for EP weights
 export const P1_EP_PRESET = PresetUtils.makePresetEpWeights(
 	'P1',
@@ -47,9 +52,9 @@ export const StandardTalents = {
 			prime1: PrimeGlyph.GlyphOfHolyShock,
 			prime2: PrimeGlyph.GlyphOfSealOfInsight,
 			prime3: PrimeGlyph.GlyphOfDivineFavor,
-			major1: MajorGlyph.GlyphOfDivinePlea,
-			major2: MajorGlyph.GlyphOfDivinity,
-			major3: MajorGlyph.GlyphOfTheAsceticCrusader,
+			major1: MajorGlyph.GlyphOfBeaconOfLight,
+			major2: MajorGlyph.GlyphOfDivinePlea,
+			major3: MajorGlyph.GlyphOfLightOfDawn,
 			minor1: MinorGlyph.GlyphOfInsight,
 			minor2: MinorGlyph.GlyphOfBlessingOfKings,
 			minor3: MinorGlyph.GlyphOfBlessingOfMight,
@@ -17,6 +17,9 @@ declare global {
 	const statWeightsAsync: SimRequestAsync;
 	const statWeightRequests: SimRequestSync;
 	const statWeightCompute: SimRequestSync;
+	const optimizeGear: SimRequestSync;
+	const optimizeRaidComposition: SimRequestSync;
+	const tuneAPL: SimRequestSync;
 	const raidSimResultCombination: SimRequestSync;
 	const raidSimRequestSplit: SimRequestSync;
 	const abortById: SimRequestSync;
@@ -37,6 +40,9 @@ globalThis.wasmready = function () {
 		statWeightsAsync: statWeightsAsync,
 		statWeightRequests: statWeightRequests,
 		statWeightCompute: statWeightCompute,
+		optimizeGear: optimizeGear,
+		optimizeRaidComposition: optimizeRaidComposition,
+		tuneAPL: tuneAPL,
 		raidSimRequestSplit: raidSimRequestSplit,
 		raidSimResultCombination: raidSimResultCombination,
 		abortById: abortById,
@@ -13,6 +13,9 @@ export enum SimRequest {
 	statWeightsAsync = 'statWeightsAsync',
 	statWeightRequests = 'statWeightRequests',
 	statWeightCompute = 'statWeightCompute',
+	optimizeGear = 'optimizeGear',
+	optimizeRaidComposition = 'optimizeRaidComposition',
+	tuneAPL = 'tuneAPL',
 	raidSimRequestSplit = 'raidSimRequestSplit',
 	raidSimResultCombination = 'raidSimResultCombination',
 	abortById = 'abortById',
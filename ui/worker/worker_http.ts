@@ -1,3 +1,4 @@
+import { AbortRequest } from '../core/proto/api';
 import { noop, sleep } from '../core/utils';
 import { HandlerFunction, WorkerInterface } from './worker_interface';
 
@@ -9,23 +10,28 @@ const defaultRequestOptions = {
 };
 
 export const setupHttpWorker = (baseURL: string) => {
-	const makeHttpApiRequest = (endPoint: string, inputData: Uint8Array, requestId: string) =>
-		fetch(`${baseURL}/${endPoint}?requestId=${requestId}`, {
+	// The server assigns its own ids to async sims, so running sims are tracked by
+	// their request id here to cancel them via their AsyncAPIResult.
+	const runningAsyncResults = new Map<string, Uint8Array>();
+
+	const makeHttpApiRequest = (endPoint: string, inputData: Uint8Array) =>
+		fetch(`${baseURL}/${endPoint}`, {
 			...defaultRequestOptions,
 			body: inputData,
 		});
 
 	const syncHandler: HandlerFunction = async (inputData, _, id, msg) => {
-		const response = await makeHttpApiRequest(msg, inputData, id);
+		const response = await makeHttpApiRequest(msg, inputData);
 		const ab = await response.arrayBuffer();
 		return new Uint8Array(ab);
 	};
 
 	const asyncHandler: HandlerFunction = async (inputData, progress, id, msg) => {
 		const asyncApiResult = await syncHandler(inputData, noop, id, msg);
+		runningAsyncResults.set(id, asyncApiResult);
 		let outputData = new Uint8Array();
 		while (true) {
-			const progressResponse = await makeHttpApiRequest('asyncProgress', asyncApiResult, id);
+			const progressResponse = await makeHttpApiRequest('asyncProgress', asyncApiResult);
 
 			// If no new data available, stop querying.
 			if ([204, 404].includes(progressResponse.status)) {
@@ -37,9 +43,20 @@ export const setupHttpWorker = (baseURL: string) => {
 			progress(outputData);
 			await sleep(500);
 		}
+		runningAsyncResults.delete(id);
 		return outputData;
 	};
 
+	const abortHandler: HandlerFunction = async (inputData, progress, id, msg) => {
+		const asyncApiResult = runningAsyncResults.get(AbortRequest.fromBinary(inputData).requestId);
+		if (!asyncApiResult) {
+			return syncHandler(inputData, progress, id, msg);
+		}
+		const response = await makeHttpApiRequest('cancelJob', asyncApiResult);
+		const ab = await response.arrayBuffer();
+		return new Uint8Array(ab);
+	};
+
 	const noWasmConcurrency: HandlerFunction = (inputData, progress, msg) => {
 		const errmsg = `Tried to use ${msg} while using a http worker! This is only supported for wasm!`;
 		console.error(errmsg);
@@ -58,8 +75,11 @@ export const setupHttpWorker = (baseURL: string) => {
 		statWeightsAsync: asyncHandler,
 		statWeightRequests: syncHandler,
 		statWeightCompute: syncHandler,
+		optimizeGear: syncHandler,
+		optimizeRaidComposition: syncHandler,
+		tuneAPL: syncHandler,
 		raidSimRequestSplit: noWasmConcurrency,
 		raidSimResultCombination: noWasmConcurrency,
-		abortById: syncHandler,
+		abortById: abortHandler,
 	}).ready(false);
 };